@@ -3,15 +3,19 @@ package main
 import (
 	"context"
 	"log"
+	"math/big"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 
 	"bitbridge/internal/bitcoin"
 	"bitbridge/internal/contracts"
 	"bitbridge/internal/ethereum"
 	"bitbridge/internal/fusion"
+	"bitbridge/internal/headers"
 	"bitbridge/internal/proof"
+	"bitbridge/internal/store"
 	"bitbridge/pkg/config"
 	"bitbridge/pkg/types"
 
@@ -30,29 +34,47 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 	
+	// Open the persistent store, which keeps the nonce manager's pending
+	// transactions across restarts
+	dataStore, err := store.OpenBolt(filepath.Join(cfg.Bridge.DataDir, "bridge.db"))
+	if err != nil {
+		log.Fatalf("Failed to open data store: %v", err)
+	}
+	defer dataStore.Close()
+	
 	// Initialize services
 	var ethClient *ethereum.Client
+	var nonceManager *ethereum.NonceManager
 	var ethService *ethereum.Service
 	var fusionService *fusion.Service
 	var proofService *proof.Service
 	var contractsService *contracts.Service
 	var bitcoinClient *bitcoin.Client
 	
-	if cfg.Ethereum.PrivateKey != "" {
-		client, err := ethereum.NewClient(ethereum.Config{
-			RpcURL:     cfg.Ethereum.RPCEndpoint,
-			PrivateKey: cfg.Ethereum.PrivateKey,
-			ChainID:    cfg.Ethereum.ChainID,
-		})
+	if cfg.Ethereum.HasSigner() {
+		client, err := ethereum.NewClient(ethereumClientConfig(&cfg.Ethereum))
 		if err != nil {
 			log.Printf("Warning: Failed to initialize Ethereum client: %v", err)
 		} else {
 			ethClient = client
-			ethService = ethereum.NewService(ethereum.ServiceConfig{
+			nonceManager = ethereum.NewNonceManager(ethereum.NonceManagerConfig{
+				Client:             client,
+				Store:              dataStore,
+				Fees:               newFeeOracle(client, &cfg.Ethereum),
+				GasLimitMultiplier: cfg.Ethereum.GasLimitMultiplier,
+				ReplaceAfter:       cfg.Ethereum.TxReplaceAfter,
+				FeeBumpPercent:     cfg.Ethereum.TxFeeBumpPercent,
+			})
+			nonceManager.Start()
+			ethService, err = ethereum.NewService(ethereum.ServiceConfig{
 				Client:           client,
+				Nonces:           nonceManager,
 				UTXORegistryAddr: cfg.Ethereum.UTXORegistryAddr,
 				TokenFactoryAddr: cfg.Ethereum.TokenFactoryAddr,
 			})
+			if err != nil {
+				log.Fatalf("Failed to initialize Ethereum service: %v", err)
+			}
 			log.Println("Ethereum client initialized successfully")
 			
 			// Initialize Fusion+ service if enabled
@@ -63,18 +85,22 @@ func main() {
 					ChainID: cfg.Ethereum.ChainID,
 				})
 				
-				fusionService = fusion.NewService(fusion.ServiceConfig{
+				fusionService, err = fusion.NewService(fusion.ServiceConfig{
 					Client:    fusionClient,
 					EthClient: client,
+					Nonces:    nonceManager,
 					ChainID:   cfg.Ethereum.ChainID,
 				})
+				if err != nil {
+					log.Fatalf("Failed to initialize 1inch Fusion+ service: %v", err)
+				}
 				log.Println("1inch Fusion+ service initialized successfully")
 			} else {
 				log.Println("Warning: 1inch Fusion+ service disabled (missing API key or disabled)")
 			}
 		}
 	} else {
-		log.Println("Warning: Ethereum private key not provided, Ethereum functionality disabled")
+		log.Println("Warning: Ethereum signer not configured, Ethereum functionality disabled")
 	}
 
 	// Initialize Bitcoin client and proof service
@@ -92,13 +118,23 @@ func main() {
 			bitcoinClient = btcClient
 			log.Println("Bitcoin client initialized successfully")
 
-			// Initialize SPV proof service
+			// Initialize header chain and SPV proof service
+			headerChain, err := headers.NewChain(headers.Config{
+				BlockSource: btcClient,
+				StartHeight: cfg.Bitcoin.HeaderStartHeight,
+			})
+			if err != nil {
+				log.Fatalf("Failed to initialize header chain: %v", err)
+			}
+			headerChain.Start()
+
 			proofService = proof.NewService(proof.ServiceConfig{
 				BitcoinClient:    btcClient,
 				RPCClient:        btcClient.GetRPCClient(),
 				MinConfirmations: 6,
 				MaxCacheSize:     1000,
 				CacheExpiration:  24 * time.Hour,
+				HeaderChain:      headerChain,
 			})
 			log.Println("SPV proof service initialized successfully")
 		}
@@ -112,6 +148,7 @@ func main() {
 		contractsService, err = contracts.NewService(contracts.ServiceConfig{
 			EthereumClient:  ethClient.GetClient(),
 			EthereumConfig:  &cfg.Ethereum,
+			Nonces:          nonceManager,
 			ContractAddress: cfg.Ethereum.SPVVerifierAddr,
 		})
 		if err != nil {
@@ -469,4 +506,37 @@ func main() {
 	if err := r.Run(":" + cfg.Server.Port); err != nil {
 		log.Fatal("Failed to start server:", err)
 	}
-}
\ No newline at end of file
+}
+
+// ethereumClientConfig selects the RPC endpoint and operator signer
+func ethereumClientConfig(cfg *config.EthereumConfig) ethereum.Config {
+	return ethereum.Config{
+		RpcURL:               cfg.RPCEndpoint,
+		PrivateKey:           cfg.PrivateKey,
+		KeystorePath:         cfg.KeystorePath,
+		KeystorePasswordFile: cfg.KeystorePasswordFile,
+		RemoteSignerURL:      cfg.RemoteSignerURL,
+		SignerAddress:        cfg.SignerAddress,
+		ChainID:              cfg.ChainID,
+	}
+}
+
+// newFeeOracle creates the EIP-1559 fee oracle for operator transactions
+func newFeeOracle(client *ethereum.Client, cfg *config.EthereumConfig) *ethereum.FeeOracle {
+	return ethereum.NewFeeOracle(ethereum.FeeOracleConfig{
+		Source:        client.GetBackend(),
+		HistoryBlocks: uint64(cfg.FeeHistoryBlocks),
+		TipPercentile: cfg.PriorityFeePercentile,
+		MaxFeeCap:     gweiToWei(cfg.MaxFeeGwei),
+		MaxTipCap:     gweiToWei(cfg.MaxPriorityFeeGwei),
+	})
+}
+
+// gweiToWei converts a gwei amount to wei, or nil for zero
+func gweiToWei(gwei float64) *big.Int {
+	if gwei <= 0 {
+		return nil
+	}
+	wei, _ := new(big.Float).Mul(big.NewFloat(gwei), big.NewFloat(1e9)).Int(nil)
+	return wei
+}
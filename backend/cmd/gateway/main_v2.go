@@ -3,16 +3,23 @@ package main
 import (
 	"context"
 	"log"
+	"path/filepath"
 	"time"
 
 	"bitbridge/internal/api"
 	"bitbridge/internal/bitcoin"
+	"bitbridge/internal/bridge"
 	"bitbridge/internal/contracts"
 	"bitbridge/internal/ethereum"
+	"bitbridge/internal/events"
 	"bitbridge/internal/fusion"
+	"bitbridge/internal/headers"
+	"bitbridge/internal/indexer"
 	"bitbridge/internal/proof"
+	"bitbridge/internal/store"
 	"bitbridge/pkg/config"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 )
 
@@ -27,16 +34,26 @@ func mainV2() {
 	ctx := context.Background()
 	wsManager.Start(ctx)
 	
+	// Open the persistent store
+	dataStore, err := store.OpenBolt(filepath.Join(cfg.Bridge.DataDir, "bridge.db"))
+	if err != nil {
+		log.Fatalf("Failed to open data store: %v", err)
+	}
+	defer dataStore.Close()
+	
 	// Initialize services
 	var bitcoinService *bitcoin.Service
 	var ethereumService *ethereum.Service
 	var fusionService *fusion.Service
 	var proofService *proof.Service
 	var contractsService *contracts.Service
+	var eventIndexer *events.Indexer
+	var intentService *bridge.IntentService
+	var orchestrator *bridge.Orchestrator
 	
 	// Initialize Bitcoin service
 	if cfg.Bitcoin.RPCUser != "" && cfg.Bitcoin.RPCPassword != "" {
-		service, err := bitcoin.NewService(&cfg.Bitcoin)
+		service, err := bitcoin.NewService(&cfg.Bitcoin, dataStore)
 		if err != nil {
 			log.Printf("Warning: Failed to initialize Bitcoin service: %v", err)
 		} else {
@@ -54,20 +71,31 @@ func mainV2() {
 	}
 	
 	// Initialize Ethereum and related services
-	if cfg.Ethereum.PrivateKey != "" {
-		ethClient, err := ethereum.NewClient(ethereum.Config{
-			RpcURL:     cfg.Ethereum.RPCEndpoint,
-			PrivateKey: cfg.Ethereum.PrivateKey,
-			ChainID:    cfg.Ethereum.ChainID,
-		})
+	if cfg.Ethereum.HasSigner() {
+		ethClient, err := ethereum.NewClient(ethereumClientConfig(&cfg.Ethereum))
 		if err != nil {
 			log.Printf("Warning: Failed to initialize Ethereum client: %v", err)
 		} else {
-			ethereumService = ethereum.NewService(ethereum.ServiceConfig{
+			// All operator transactions go through one nonce manager
+			nonceManager := ethereum.NewNonceManager(ethereum.NonceManagerConfig{
+				Client:             ethClient,
+				Store:              dataStore,
+				Fees:               newFeeOracle(ethClient, &cfg.Ethereum),
+				GasLimitMultiplier: cfg.Ethereum.GasLimitMultiplier,
+				ReplaceAfter:       cfg.Ethereum.TxReplaceAfter,
+				FeeBumpPercent:     cfg.Ethereum.TxFeeBumpPercent,
+			})
+			nonceManager.Start()
+			
+			ethereumService, err = ethereum.NewService(ethereum.ServiceConfig{
 				Client:           ethClient,
+				Nonces:           nonceManager,
 				UTXORegistryAddr: cfg.Ethereum.UTXORegistryAddr,
 				TokenFactoryAddr: cfg.Ethereum.TokenFactoryAddr,
 			})
+			if err != nil {
+				log.Fatalf("Failed to initialize Ethereum service: %v", err)
+			}
 			log.Println("Ethereum service initialized successfully")
 			
 			// Initialize Fusion+ service if enabled
@@ -78,11 +106,15 @@ func mainV2() {
 					ChainID: cfg.Ethereum.ChainID,
 				})
 				
-				fusionService = fusion.NewService(fusion.ServiceConfig{
+				fusionService, err = fusion.NewService(fusion.ServiceConfig{
 					Client:    fusionClient,
 					EthClient: ethClient,
+					Nonces:    nonceManager,
 					ChainID:   cfg.Ethereum.ChainID,
 				})
+				if err != nil {
+					log.Fatalf("Failed to initialize 1inch Fusion+ service: %v", err)
+				}
 				log.Println("1inch Fusion+ service initialized successfully")
 			} else {
 				log.Println("Warning: 1inch Fusion+ service disabled (missing API key or disabled)")
@@ -92,6 +124,7 @@ func mainV2() {
 			contractsService, err = contracts.NewService(contracts.ServiceConfig{
 				EthereumClient:  ethClient.GetClient(),
 				EthereumConfig:  &cfg.Ethereum,
+				Nonces:          nonceManager,
 				ContractAddress: cfg.Ethereum.SPVVerifierAddr,
 			})
 			if err != nil {
@@ -99,12 +132,39 @@ func mainV2() {
 			} else {
 				log.Println("Smart contracts service initialized successfully")
 			}
+			
+			// Initialize contract event indexer
+			if cfg.Ethereum.UTXORegistryAddr != "" || cfg.Ethereum.SPVVerifierAddr != "" {
+				eventIndexer, err = events.NewIndexer(events.Config{
+					ChainSource:     ethClient.GetBackend(),
+					Store:           dataStore,
+					RegistryAddress: common.HexToAddress(cfg.Ethereum.UTXORegistryAddr),
+					VerifierAddress: common.HexToAddress(cfg.Ethereum.SPVVerifierAddr),
+					StartBlock:      uint64(cfg.Bridge.EventStartBlock),
+					PollInterval:    cfg.Bridge.EventPollInterval,
+					ReorgDepth:      cfg.Bridge.EventReorgDepth,
+				})
+				if err != nil {
+					log.Printf("Warning: Failed to initialize contract event indexer: %v", err)
+					eventIndexer = nil
+				} else {
+					eventIndexer.Start()
+					log.Println("Contract event indexer initialized successfully")
+				}
+			}
 		}
 	} else {
-		log.Println("Warning: Ethereum private key not provided, Ethereum functionality disabled")
+		log.Println("Warning: Ethereum signer not configured, Ethereum functionality disabled")
+	}
+	
+	// With pushed notifications the chain followers only poll as a backstop
+	chainPollInterval := cfg.Bridge.IndexerPollInterval
+	if len(cfg.Bitcoin.ZMQEndpoints) > 0 {
+		chainPollInterval = cfg.Bitcoin.ZMQPollInterval
 	}
 	
-	// Initialize SPV proof service
+	// Initialize header chain and SPV proof service
+	var headerChain *headers.Chain
 	if bitcoinService != nil {
 		btcClient, err := bitcoin.NewClient(bitcoin.Config{
 			Host:     cfg.Bitcoin.RPCHost,
@@ -116,17 +176,230 @@ func mainV2() {
 		if err != nil {
 			log.Printf("Warning: Failed to create Bitcoin client for proofs: %v", err)
 		} else {
+			headerChain, err = headers.NewChain(headers.Config{
+				BlockSource:  btcClient,
+				Store:        dataStore,
+				StartHeight:  cfg.Bitcoin.HeaderStartHeight,
+				PollInterval: chainPollInterval,
+			})
+			if err != nil {
+				log.Fatalf("Failed to initialize header chain: %v", err)
+			}
+			headerChain.Start()
+
 			proofService = proof.NewService(proof.ServiceConfig{
 				BitcoinClient:    btcClient,
 				RPCClient:        btcClient.GetRPCClient(),
 				MinConfirmations: 6,
 				MaxCacheSize:     1000,
 				CacheExpiration:  24 * time.Hour,
+				Store:            dataStore,
+				HeaderChain:      headerChain,
 			})
 			log.Println("SPV proof service initialized successfully")
 		}
 	}
 	
+	// Initialize header relayer
+	if headerChain != nil && contractsService != nil && cfg.Ethereum.SPVVerifierAddr != "" {
+		relayer, err := contracts.NewRelayer(contracts.RelayerConfig{
+			Service:      contractsService,
+			Headers:      headerChain,
+			BatchSize:    cfg.Bridge.RelayBatchSize,
+			PollInterval: cfg.Bridge.RelayPollInterval,
+			StartHeight:  cfg.Bitcoin.HeaderStartHeight,
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to initialize header relayer: %v", err)
+		} else {
+			contractsService.SetRelayer(relayer)
+			relayer.Start()
+			log.Println("Header relayer initialized successfully")
+		}
+	}
+	
+	// Initialize deposit intents
+	if bitcoinService != nil {
+		intentService, err = bridge.NewIntentService(bridge.IntentConfig{
+			Addresses: bitcoinService,
+			Store:     dataStore,
+			TTL:       cfg.Bridge.IntentTTL,
+			MinAmount: cfg.Bridge.MinDepositAmount,
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize deposit intents: %v", err)
+		}
+	}
+	
+	// Initialize deposit orchestrator
+	var utxoMonitor *indexer.UTXOMonitor
+	if bitcoinService != nil && proofService != nil && ethereumService != nil && contractsService != nil {
+		monitor, err := indexer.NewUTXOMonitor(indexer.MonitorConfig{
+			BlockSource:  bitcoinService.GetClient(),
+			Store:        dataStore,
+			StartHeight:  cfg.Bridge.IndexerStartHeight,
+			PollInterval: chainPollInterval,
+			ReorgDepth:   cfg.Bridge.ReorgDepth,
+			MemoAddress:  cfg.Bitcoin.MemoAddress,
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize UTXO monitor: %v", err)
+		}
+		bitcoinService.AddAddressCallback(func(address string) {
+			if err := monitor.AddWatchAddress(address); err != nil {
+				log.Printf("Warning: Failed to monitor deposit address %s: %v", address, err)
+			}
+		})
+		
+		orchestrator, err = bridge.NewOrchestrator(bridge.Config{
+			Monitor:               monitor,
+			ProofService:          proofService,
+			ContractsService:      contractsService,
+			EthereumService:       ethereumService,
+			Store:                 dataStore,
+			RecipientResolver:     intentService.RecipientResolver(bridge.AddressRecipientResolver(bitcoinService.RecipientOf)),
+			RequiredConfirmations: cfg.Bridge.RequiredConfirmations,
+			MaxAttempts:           cfg.Bridge.MaxAttempts,
+			RetryInterval:         cfg.Bridge.RetryInterval,
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to initialize bridge orchestrator: %v", err)
+		} else {
+			monitor.AddCallback(bitcoinService.HandleUTXOEvent)
+			orchestrator.Start()
+			monitor.Start()
+			utxoMonitor = monitor
+			log.Println("Bridge orchestrator initialized successfully")
+		}
+	}
+	
+	// Initialize fee estimator
+	var feeEstimator *bitcoin.FeeEstimator
+	if bitcoinService != nil {
+		feeEstimator, err = bitcoin.NewFeeEstimator(bitcoin.FeeEstimatorConfig{
+			Source:      bitcoinService.GetClient(),
+			FloorRate:   cfg.Bitcoin.FeeFloor,
+			CeilingRate: cfg.Bitcoin.FeeCeiling,
+			CacheTTL:    cfg.Bitcoin.FeeCacheTTL,
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize fee estimator: %v", err)
+		}
+	}
+	
+	// Initialize withdrawal service
+	var withdrawalService *bridge.WithdrawalService
+	if bitcoinService != nil && ethereumService != nil {
+		var builder *bitcoin.WithdrawalBuilder
+		if cfg.Bitcoin.PSBTWithdrawals {
+			if utxoMonitor == nil {
+				log.Fatal("PSBT withdrawals need the UTXO monitor of the deposit orchestrator")
+			}
+			if bitcoinService.ChangeScriptType() == "" {
+				log.Fatal("PSBT withdrawals need BITCOIN_DEPOSIT_ACCOUNT_KEY for change addresses")
+			}
+			builder, err = bitcoin.NewWithdrawalBuilder(bitcoin.BuilderConfig{
+				Coins:            utxoMonitor,
+				Change:           bitcoinService,
+				ChangeType:       bitcoinService.ChangeScriptType(),
+				Signer:           bitcoin.NewNodeWalletSigner(bitcoinService.GetClient()),
+				Network:          bitcoinService.GetClient().GetNetworkParams(),
+				DustLimit:        cfg.Bitcoin.DustLimit,
+				MinConfirmations: cfg.Bridge.RequiredConfirmations,
+			})
+			if err != nil {
+				log.Fatalf("Failed to initialize withdrawal builder: %v", err)
+			}
+		}
+
+		if cfg.Bridge.WithdrawalBatchInterval > 0 && builder == nil {
+			log.Fatal("Batched withdrawals need BITCOIN_PSBT_WITHDRAWALS")
+		}
+
+		tracker, err := bitcoin.NewPayoutTracker(bitcoin.TrackerConfig{
+			Chain:       bitcoinService.GetClient(),
+			Builder:     builder,
+			StuckBlocks: int64(cfg.Bitcoin.PayoutStuckBlocks),
+			MinFeeRate:  cfg.Bitcoin.WithdrawalFeeRate,
+			MaxFeeRate:  cfg.Bitcoin.MaxFeeRate,
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize payout tracker: %v", err)
+		}
+
+		withdrawalService, err = bridge.NewWithdrawalService(bridge.WithdrawalConfig{
+			BitcoinClient:         bitcoinService.GetClient(),
+			Builder:               builder,
+			Tracker:               tracker,
+			FeeRate:               cfg.Bitcoin.WithdrawalFeeRate,
+			FeeEstimator:          feeEstimator,
+			FeeTarget:             cfg.Bitcoin.FeeTarget,
+			BatchInterval:         cfg.Bridge.WithdrawalBatchInterval,
+			BatchSize:             cfg.Bridge.WithdrawalBatchSize,
+			EthereumService:       ethereumService,
+			Store:                 dataStore,
+			RequiredConfirmations: cfg.Bridge.RequiredConfirmations,
+			MaxAttempts:           cfg.Bridge.MaxAttempts,
+			PollInterval:          cfg.Bridge.EventPollInterval,
+			StartBlock:            uint64(cfg.Bridge.WithdrawalStartBlock),
+			FinalityDepth:         uint64(cfg.Bridge.FinalityDepth),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to initialize withdrawal service: %v", err)
+			withdrawalService = nil
+		} else {
+			withdrawalService.Start()
+			log.Println("Withdrawal service initialized successfully")
+		}
+	}
+	
+	// Subscribe to block notifications pushed by the node
+	if bitcoinService != nil && len(cfg.Bitcoin.ZMQEndpoints) > 0 {
+		subscriber, err := bitcoin.NewZMQSubscriber(bitcoin.ZMQConfig{
+			Endpoints:    cfg.Bitcoin.ZMQEndpoints,
+			PollInterval: cfg.Bridge.IndexerPollInterval,
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize ZMQ subscriber: %v", err)
+		}
+		
+		refreshProofs := func() {
+			if proofService == nil || headerChain == nil {
+				return
+			}
+			if tip, _, ok := headerChain.BestTip(); ok {
+				proofService.RefreshConfirmations(tip)
+			}
+		}
+		subscriber.AddCallback(func(n *bitcoin.Notification) {
+			if !n.BlockConnected() && !n.BlockDisconnected() {
+				return
+			}
+			if headerChain != nil {
+				if n.Block != nil {
+					headerChain.HandleHeader(&n.Block.Header)
+				} else {
+					headerChain.Notify()
+				}
+			}
+			if utxoMonitor != nil {
+				utxoMonitor.Notify()
+			}
+			refreshProofs()
+		})
+		subscriber.AddResyncCallback(func() {
+			if headerChain != nil {
+				headerChain.Notify()
+			}
+			if utxoMonitor != nil {
+				utxoMonitor.Notify()
+			}
+			refreshProofs()
+		})
+		subscriber.Start()
+		log.Println("ZMQ subscriber initialized successfully")
+	}
+	
 	// Create API server
 	apiServer := api.NewAPIServer(
 		bitcoinService,
@@ -137,6 +410,31 @@ func mainV2() {
 		wsManager,
 	)
 	
+	if feeEstimator != nil {
+		apiServer.SetFeeEstimator(feeEstimator)
+	}
+	if intentService != nil {
+		apiServer.SetIntentService(intentService)
+	}
+	if withdrawalService != nil {
+		apiServer.SetWithdrawalService(withdrawalService)
+	}
+	if orchestrator != nil {
+		apiServer.SetOrchestrator(orchestrator)
+	}
+	apiServer.SetAdminToken(cfg.Server.AdminToken)
+	
+	if eventIndexer != nil {
+		apiServer.SetEventIndexer(eventIndexer)
+		
+		contractEvents, _ := eventIndexer.Subscribe(events.Filter{})
+		go func() {
+			for event := range contractEvents {
+				wsManager.BroadcastToTopic(api.TopicContractEvents, api.EventTypeTransaction, event.Name, event)
+			}
+		}()
+	}
+	
 	// Setup Gin router
 	r := gin.Default()
 	
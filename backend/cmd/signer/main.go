@@ -0,0 +1,40 @@
+// Command signer is a stand-in remote signer for development. It holds the
+// operator key from a keystore file or ETHEREUM_PRIVATE_KEY and serves
+// eth_accounts and eth_signTransaction over HTTP JSON-RPC, so the gateway
+// can run with ETHEREUM_REMOTE_SIGNER_URL pointing at it.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"bitbridge/internal/ethereum"
+)
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:8550", "address to serve JSON-RPC on")
+	keystorePath := flag.String("keystore", os.Getenv("ETHEREUM_KEYSTORE_PATH"), "encrypted JSON keystore file")
+	passwordFile := flag.String("password-file", os.Getenv("ETHEREUM_KEYSTORE_PASSWORD_FILE"), "file holding the keystore passphrase")
+	flag.Parse()
+
+	signer, err := ethereum.NewSigner(ethereum.Config{
+		KeystorePath:         *keystorePath,
+		KeystorePasswordFile: *passwordFile,
+		PrivateKey:           os.Getenv("ETHEREUM_PRIVATE_KEY"),
+	})
+	if err != nil {
+		log.Fatalf("Failed to load signing key: %v", err)
+	}
+
+	server, err := ethereum.NewSignerServer(signer)
+	if err != nil {
+		log.Fatalf("Failed to create signer server: %v", err)
+	}
+
+	log.Printf("Signing for %s on %s", signer.Address().Hex(), *listen)
+	if err := http.ListenAndServe(*listen, server); err != nil {
+		log.Fatalf("Signer server failed: %v", err)
+	}
+}
@@ -11,11 +11,14 @@ contract SPVVerifier {
     // Events
     event ProofVerified(bytes32 indexed txHash, bytes32 indexed blockHash, uint256 blockHeight);
     event BlockHeaderStored(bytes32 indexed blockHash, uint256 blockHeight);
+    event HeadersRelayed(uint256 startHeight, uint256 endHeight);
     
     // Storage
     mapping(bytes32 => bool) public verifiedTransactions;
     mapping(bytes32 => BlockHeader) public blockHeaders;
     mapping(uint256 => bytes32) public blocksByHeight;
+    address public relayer;
+    uint256 public latestHeight;
     
     struct BlockHeader {
         uint32 version;
@@ -31,10 +34,20 @@ contract SPVVerifier {
     struct MerkleProof {
         bytes32[] proof;
         uint256 index;
+        uint256 totalTxs;
         bytes32 txHash;
         bytes32 merkleRoot;
     }
     
+    modifier onlyRelayer() {
+        require(msg.sender == relayer, "Only relayer");
+        _;
+    }
+    
+    constructor() {
+        relayer = msg.sender;
+    }
+    
     /**
      * @dev Parse Bitcoin block header from raw bytes
      * @param headerBytes Raw block header bytes (80 bytes)
@@ -83,40 +96,116 @@ contract SPVVerifier {
         
         // Parse block header
         BlockHeader memory header = parseBlockHeader(headerBytes);
-        header.height = blockHeight;
         
         // Calculate block hash (double SHA256 of header)
         bytes32 blockHash = doubleSha256(headerBytes);
         
-        // Verify merkle root matches header
-        require(header.merkleRoot == merkleProof.merkleRoot, "Merkle root mismatch");
+        // Only headers relayed through submitBlockHeaders have had their
+        // proof of work and chain linkage checked
+        require(
+            blockHeaders[blockHash].exists && blocksByHeight[blockHeight] == blockHash,
+            "Header not relayed"
+        );
+        
+        // Verify merkle root matches header; the parsed root is in display
+        // order while proofs are hashed in internal order
+        require(reverseBytes32(header.merkleRoot) == merkleProof.merkleRoot, "Merkle root mismatch");
         
         // Verify merkle proof
         require(verifyMerkleProof(merkleProof), "Invalid merkle proof");
         
-        // Store block header and mark transaction as verified
-        blockHeaders[blockHash] = header;
-        blocksByHeight[blockHeight] = blockHash;
+        // Mark transaction as verified
         verifiedTransactions[merkleProof.txHash] = true;
         
-        emit BlockHeaderStored(blockHash, blockHeight);
         emit ProofVerified(merkleProof.txHash, blockHash, blockHeight);
         
         return true;
     }
     
     /**
-     * @dev Verify a Merkle proof
+     * @dev Store a contiguous run of block headers starting at startHeight.
+     * A run starting at or below latestHeight replaces the stored branch from
+     * that height up, which is how the relayer follows a Bitcoin reorg.
+     * @param headers Raw 80-byte block headers in chain order
+     * @param startHeight Height of the first header
+     */
+    function submitBlockHeaders(bytes[] memory headers, uint256 startHeight) public onlyRelayer {
+        require(headers.length > 0, "No headers");
+        require(latestHeight == 0 || startHeight <= latestHeight + 1, "Gap in relayed headers");
+        
+        bytes32 prevHash = startHeight > 0 ? blocksByHeight[startHeight - 1] : bytes32(0);
+        
+        for (uint256 i = 0; i < headers.length; i++) {
+            BlockHeader memory header = parseBlockHeader(headers[i]);
+            bytes32 blockHash = doubleSha256(headers[i]);
+            
+            // prevBlock is parsed in display order, blockHash is in internal order
+            if (prevHash != bytes32(0)) {
+                require(reverseBytes32(header.prevBlock) == prevHash, "Header does not extend chain");
+            }
+            require(uint256(reverseBytes32(blockHash)) <= bitsToTarget(header.bits), "Insufficient proof of work");
+            
+            header.height = startHeight + i;
+            blockHeaders[blockHash] = header;
+            blocksByHeight[header.height] = blockHash;
+            emit BlockHeaderStored(blockHash, header.height);
+            
+            prevHash = blockHash;
+        }
+        
+        // Drop heights left over from a longer branch that was replaced
+        uint256 endHeight = startHeight + headers.length - 1;
+        for (uint256 h = endHeight + 1; h <= latestHeight; h++) {
+            delete blocksByHeight[h];
+        }
+        latestHeight = endHeight;
+        
+        emit HeadersRelayed(startHeight, endHeight);
+    }
+    
+    /**
+     * @dev Expand compact difficulty bits into a 256-bit target
+     * @param bits Compact target from a block header
+     * @return The target a block hash must not exceed
+     */
+    function bitsToTarget(uint32 bits) public pure returns (uint256) {
+        uint256 exponent = bits >> 24;
+        uint256 mantissa = bits & 0x007fffff;
+        
+        if (exponent <= 3) {
+            return mantissa >> (8 * (3 - exponent));
+        }
+        return mantissa << (8 * (exponent - 3));
+    }
+    
+    /**
+     * @dev Verify a Merkle proof. The proof length must match the depth of a
+     * tree of totalTxs leaves, and a hash may only be paired with itself as
+     * the last node of an odd-length level; any other identical pair comes
+     * from a mutated tree (CVE-2012-2459).
      * @param merkleProof The merkle proof to verify
      * @return True if the proof is valid
      */
     function verifyMerkleProof(MerkleProof memory merkleProof) public pure returns (bool) {
+        if (merkleProof.totalTxs == 0 || merkleProof.index >= merkleProof.totalTxs) {
+            return false;
+        }
+        if (merkleProof.proof.length != merkleDepth(merkleProof.totalTxs)) {
+            return false;
+        }
+        
         bytes32 computedHash = merkleProof.txHash;
         uint256 index = merkleProof.index;
+        uint256 width = merkleProof.totalTxs;
         
         for (uint256 i = 0; i < merkleProof.proof.length; i++) {
             bytes32 proofElement = merkleProof.proof[i];
             
+            bool selfPaired = index % 2 == 0 && index == width - 1;
+            if ((proofElement == computedHash) != selfPaired) {
+                return false;
+            }
+            
             if (index % 2 == 0) {
                 // If index is even, proof element goes on the right
                 computedHash = doubleSha256(abi.encodePacked(computedHash, proofElement));
@@ -126,11 +215,25 @@ contract SPVVerifier {
             }
             
             index = index / 2;
+            width = (width + 1) / 2;
         }
         
         return computedHash == merkleProof.merkleRoot;
     }
     
+    /**
+     * @dev Number of levels above the leaves in a Bitcoin Merkle tree
+     * @param totalTxs Number of transactions in the block
+     * @return Tree depth
+     */
+    function merkleDepth(uint256 totalTxs) public pure returns (uint256) {
+        uint256 depth = 0;
+        for (uint256 width = totalTxs; width > 1; width = (width + 1) / 2) {
+            depth++;
+        }
+        return depth;
+    }
+    
     /**
      * @dev Check if a Bitcoin transaction has been verified
      * @param txHash The transaction hash to check
@@ -160,7 +263,8 @@ contract SPVVerifier {
     }
     
     /**
-     * @dev Verify multiple proofs in a single transaction (batch verification)
+     * @dev Verify multiple proofs in a single transaction (batch verification).
+     * Every header must already have been relayed, as for verifyProof.
      * @param headerBytesArray Array of block headers
      * @param merkleProofs Array of merkle proofs
      * @param blockHeights Array of block heights
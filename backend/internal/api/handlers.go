@@ -5,8 +5,10 @@ import (
 	"time"
 
 	"bitbridge/internal/bitcoin"
+	"bitbridge/internal/bridge"
 	"bitbridge/internal/contracts"
 	"bitbridge/internal/ethereum"
+	"bitbridge/internal/events"
 	"bitbridge/internal/fusion"
 	"bitbridge/internal/proof"
 
@@ -16,10 +18,16 @@ import (
 // APIServer represents the main API server
 type APIServer struct {
 	bitcoinService   *bitcoin.Service
+	feeEstimator     *bitcoin.FeeEstimator
 	ethereumService  *ethereum.Service
 	fusionService    *fusion.Service
 	proofService     *proof.Service
 	contractsService *contracts.Service
+	eventIndexer     *events.Indexer
+	intentService    *bridge.IntentService
+	withdrawals      *bridge.WithdrawalService
+	orchestrator     *bridge.Orchestrator
+	adminToken       string
 	wsManager        *WebSocketManager
 	startTime        time.Time
 }
@@ -44,6 +52,39 @@ func NewAPIServer(
 	}
 }
 
+// SetFeeEstimator attaches the fee estimates served by /v1/bitcoin/fees
+func (s *APIServer) SetFeeEstimator(estimator *bitcoin.FeeEstimator) {
+	s.feeEstimator = estimator
+}
+
+// SetEventIndexer attaches the contract event index served by
+// /v1/contracts/events
+func (s *APIServer) SetEventIndexer(indexer *events.Indexer) {
+	s.eventIndexer = indexer
+}
+
+// SetIntentService attaches the deposit intents served by
+// /v1/deposit-intents
+func (s *APIServer) SetIntentService(intents *bridge.IntentService) {
+	s.intentService = intents
+}
+
+// SetWithdrawalService attaches the withdrawals and batches served by
+// /v1/withdrawals
+func (s *APIServer) SetWithdrawalService(withdrawals *bridge.WithdrawalService) {
+	s.withdrawals = withdrawals
+}
+
+// SetOrchestrator attaches the deposit orchestrator served by /v1/admin
+func (s *APIServer) SetOrchestrator(orchestrator *bridge.Orchestrator) {
+	s.orchestrator = orchestrator
+}
+
+// SetAdminToken enables the /v1/admin endpoints for callers presenting token
+func (s *APIServer) SetAdminToken(token string) {
+	s.adminToken = token
+}
+
 // RegisterRoutes registers all API routes
 func (s *APIServer) RegisterRoutes(r *gin.Engine) {
 	// Apply global middleware
@@ -75,6 +116,9 @@ func (s *APIServer) RegisterRoutes(r *gin.Engine) {
 		s.registerFusionRoutes(v1)
 		s.registerProofRoutes(v1)
 		s.registerContractRoutes(v1)
+		s.registerDepositIntentRoutes(v1)
+		s.registerWithdrawalRoutes(v1)
+		s.registerAdminRoutes(v1)
 		s.registerUtilityRoutes(v1)
 	}
 	
@@ -92,6 +136,7 @@ func (s *APIServer) registerBitcoinRoutes(rg *gin.RouterGroup) {
 	{
 		bitcoin.GET("/status", s.bitcoinStatus)
 		bitcoin.GET("/network-info", s.bitcoinNetworkInfo)
+		bitcoin.GET("/fees", s.getBitcoinFees)
 		bitcoin.POST("/generate-address", s.generateBitcoinAddress)
 		bitcoin.GET("/addresses", s.getBitcoinAddresses)
 		bitcoin.GET("/address/:address/utxos", s.getAddressUTXOs)
@@ -175,6 +220,42 @@ func (s *APIServer) registerContractRoutes(rg *gin.RouterGroup) {
 	}
 }
 
+// registerDepositIntentRoutes registers deposit intent routes
+func (s *APIServer) registerDepositIntentRoutes(rg *gin.RouterGroup) {
+	intents := rg.Group("/deposit-intents")
+	{
+		intents.POST("", s.createDepositIntent)
+		intents.GET("/:id", s.getDepositIntent)
+		intents.GET("/address/:address", s.getDepositIntentByAddress)
+		intents.GET("/recipient/:recipient", s.getDepositIntentsByRecipient)
+	}
+}
+
+// registerWithdrawalRoutes registers withdrawal and batch routes
+func (s *APIServer) registerWithdrawalRoutes(rg *gin.RouterGroup) {
+	withdrawals := rg.Group("/withdrawals")
+	{
+		withdrawals.GET("", s.listWithdrawals)
+		withdrawals.GET("/batches", s.listWithdrawalBatches)
+		withdrawals.GET("/batches/:txid", s.getWithdrawalBatch)
+		withdrawals.GET("/token/:address", s.getWithdrawal)
+	}
+}
+
+// registerAdminRoutes registers operator routes, which require the admin
+// token
+func (s *APIServer) registerAdminRoutes(rg *gin.RouterGroup) {
+	if s.adminToken == "" {
+		return
+	}
+	
+	admin := rg.Group("/admin", AdminAuthMiddleware(s.adminToken))
+	{
+		admin.GET("/deposits/unattributed", s.listUnattributedDeposits)
+		admin.POST("/deposits/unattributed/:id/resolve", s.resolveUnattributedDeposit)
+	}
+}
+
 // registerUtilityRoutes registers utility routes
 func (s *APIServer) registerUtilityRoutes(rg *gin.RouterGroup) {
 	utils := rg.Group("/utils")
@@ -340,7 +421,26 @@ func (s *APIServer) generateBitcoinAddress(c *gin.Context) {
 		return
 	}
 	
-	address, err := s.bitcoinService.GenerateDepositAddress()
+	// The recipient is optional so that node wallet addresses keep working
+	var req struct {
+		Recipient string `json:"recipient"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			BadRequestError(c, "Invalid request format", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+	if req.Recipient != "" && !validateEthereumAddress(req.Recipient) {
+		BadRequestError(c, "Invalid Ethereum recipient address", map[string]interface{}{
+			"recipient": req.Recipient,
+		})
+		return
+	}
+	
+	address, err := s.bitcoinService.GenerateDepositAddress(req.Recipient)
 	if err != nil {
 		InternalServerError(c, "Failed to generate address", map[string]interface{}{
 			"error": err.Error(),
@@ -354,7 +454,8 @@ func (s *APIServer) generateBitcoinAddress(c *gin.Context) {
 	})
 	
 	CreatedResponse(c, map[string]interface{}{
-		"address": address,
+		"address":   address,
+		"recipient": req.Recipient,
 	})
 }
 
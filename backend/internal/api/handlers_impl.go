@@ -2,13 +2,19 @@ package api
 
 import (
 	"context"
+	"errors"
 	"strconv"
 	"time"
 
+	"bitbridge/internal/bitcoin"
+	"bitbridge/internal/bridge"
 	"bitbridge/internal/contracts"
+	"bitbridge/internal/events"
 	"bitbridge/internal/proof"
+	"bitbridge/internal/store"
 	"bitbridge/pkg/types"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 )
 
@@ -163,6 +169,23 @@ func (s *APIServer) getAllUTXOs(c *gin.Context) {
 	})
 }
 
+func (s *APIServer) getBitcoinFees(c *gin.Context) {
+	if s.feeEstimator == nil {
+		ServiceUnavailableError(c, "Fee estimator not available")
+		return
+	}
+
+	estimates, err := s.feeEstimator.Estimates()
+	if err != nil {
+		InternalServerError(c, "Failed to estimate fees", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	SuccessResponse(c, estimates)
+}
+
 func (s *APIServer) validateBitcoinAddress(c *gin.Context) {
 	var req struct {
 		Address string `json:"address" binding:"required"`
@@ -180,12 +203,8 @@ func (s *APIServer) validateBitcoinAddress(c *gin.Context) {
 		return
 	}
 	
-	isValid := s.bitcoinService.IsValidBitcoinAddress(req.Address)
-	
-	SuccessResponse(c, map[string]interface{}{
-		"address": req.Address,
-		"valid":   isValid,
-	})
+	info, err := s.bitcoinService.ParseAddress(req.Address)
+	SuccessResponse(c, addressValidationResult(req.Address, info, err))
 }
 
 func (s *APIServer) watchBitcoinAddress(c *gin.Context) {
@@ -276,9 +295,20 @@ func (s *APIServer) getEthereumGasPrice(c *gin.Context) {
 		return
 	}
 	
-	// Implementation would call ethereum service
+	fees, err := s.ethereumService.SuggestFees(c.Request.Context())
+	if err != nil {
+		InternalServerError(c, "Failed to get fee suggestion", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	
+	// gas_price is the most a transaction would pay per gas
 	SuccessResponse(c, map[string]interface{}{
-		"gas_price": "0", // Placeholder
+		"gas_price":                fees.GasFeeCap.String(),
+		"base_fee":                 fees.BaseFee.String(),
+		"max_fee_per_gas":          fees.GasFeeCap.String(),
+		"max_priority_fee_per_gas": fees.GasTipCap.String(),
 	})
 }
 
@@ -876,10 +906,64 @@ func (s *APIServer) estimateContractGas(c *gin.Context) {
 	})
 }
 
+// getContractEvents serves indexed contract events, optionally filtered by
+// event name, contract address and block range
 func (s *APIServer) getContractEvents(c *gin.Context) {
-	// Implementation would get contract events
-	SuccessResponse(c, map[string]interface{}{
-		"events": []interface{}{}, // Placeholder
+	if s.eventIndexer == nil {
+		ServiceUnavailableError(c, "Contract event indexer not available")
+		return
+	}
+
+	filter := events.Filter{Name: c.Query("name")}
+	if address := c.Query("address"); address != "" {
+		if !common.IsHexAddress(address) {
+			BadRequestError(c, "Invalid contract address", nil)
+			return
+		}
+		filter.Address = common.HexToAddress(address)
+	}
+	for param, block := range map[string]*uint64{"from_block": &filter.FromBlock, "to_block": &filter.ToBlock} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			BadRequestError(c, "Invalid "+param, map[string]interface{}{
+				"error": err.Error(),
+			})
+			return
+		}
+		*block = parsed
+	}
+
+	found, err := s.eventIndexer.Events(filter)
+	if err != nil {
+		InternalServerError(c, "Failed to get contract events", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	page, perPage := getPaginationParams(c)
+	start := (page - 1) * perPage
+	if start > len(found) {
+		start = len(found)
+	}
+	end := start + perPage
+	if end > len(found) {
+		end = len(found)
+	}
+
+	lastBlock, _ := s.eventIndexer.LastBlock()
+	PaginatedResponse(c, map[string]interface{}{
+		"events":     found[start:end],
+		"last_block": lastBlock,
+	}, PaginationInfo{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      len(found),
+		TotalPages: (len(found) + perPage - 1) / perPage,
 	})
 }
 
@@ -887,6 +971,7 @@ func (s *APIServer) getContractEvents(c *gin.Context) {
 func (s *APIServer) validateBitcoinAddressUtil(c *gin.Context) {
 	var req struct {
 		Address string `json:"address" binding:"required"`
+		Network string `json:"network"` // mainnet, testnet or regtest; defaults to the node's network
 	}
 	
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -896,12 +981,49 @@ func (s *APIServer) validateBitcoinAddressUtil(c *gin.Context) {
 		return
 	}
 	
-	isValid := validateBitcoinAddress(req.Address)
+	var info *bitcoin.AddressInfo
+	var err error
+	switch {
+	case req.Network != "":
+		params, paramsErr := bitcoin.NetworkParams(req.Network)
+		if paramsErr != nil {
+			BadRequestError(c, "Invalid network", map[string]interface{}{
+				"error": paramsErr.Error(),
+			})
+			return
+		}
+		info, err = bitcoin.ParseAddress(req.Address, params)
+	case s.bitcoinService != nil:
+		info, err = s.bitcoinService.ParseAddress(req.Address)
+	default:
+		info, err = bitcoin.DetectAddress(req.Address)
+	}
 	
-	SuccessResponse(c, map[string]interface{}{
-		"address": req.Address,
-		"valid":   isValid,
-	})
+	SuccessResponse(c, addressValidationResult(req.Address, info, err))
+}
+
+// addressValidationResult reports a decoded address's network and script
+// type, or why it did not decode
+func addressValidationResult(address string, info *bitcoin.AddressInfo, err error) map[string]interface{} {
+	if err != nil {
+		return map[string]interface{}{
+			"address": address,
+			"valid":   false,
+			"error":   err.Error(),
+		}
+	}
+	
+	result := map[string]interface{}{
+		"address":       address,
+		"valid":         true,
+		"network":       info.Network,
+		"type":          info.Type,
+		"script_pubkey": info.ScriptPubKey,
+	}
+	if info.WitnessVersion != nil {
+		result["witness_version"] = *info.WitnessVersion
+	}
+	return result
 }
 
 func (s *APIServer) validateEthereumAddressUtil(c *gin.Context) {
@@ -990,4 +1112,270 @@ func (s *APIServer) getWebSocketStats(c *gin.Context) {
 	}
 	
 	SuccessResponse(c, stats)
-}
\ No newline at end of file
+}
+// Deposit intent handlers
+
+func (s *APIServer) createDepositIntent(c *gin.Context) {
+	if s.intentService == nil {
+		ServiceUnavailableError(c, "Deposit intents not available")
+		return
+	}
+
+	var req struct {
+		Recipient string `json:"recipient" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestError(c, "Invalid request format", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if !validateEthereumAddress(req.Recipient) {
+		BadRequestError(c, "Invalid Ethereum recipient address", map[string]interface{}{
+			"recipient": req.Recipient,
+		})
+		return
+	}
+
+	intent, err := s.intentService.CreateIntent(req.Recipient)
+	if err != nil {
+		InternalServerError(c, "Failed to create deposit intent", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	s.wsManager.BroadcastToTopic(TopicBitcoinTransactions, EventTypeTransaction, "deposit_intent_created", intent)
+	CreatedResponse(c, intent)
+}
+
+func (s *APIServer) getDepositIntent(c *gin.Context) {
+	if s.intentService == nil {
+		ServiceUnavailableError(c, "Deposit intents not available")
+		return
+	}
+
+	intent, err := s.intentService.GetIntent(c.Param("id"))
+	if errors.Is(err, store.ErrNotFound) {
+		NotFoundError(c, "Deposit intent not found")
+		return
+	}
+	if err != nil {
+		InternalServerError(c, "Failed to get deposit intent", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	SuccessResponse(c, intent)
+}
+
+func (s *APIServer) getDepositIntentByAddress(c *gin.Context) {
+	if s.intentService == nil {
+		ServiceUnavailableError(c, "Deposit intents not available")
+		return
+	}
+
+	intent, err := s.intentService.IntentByAddress(c.Param("address"))
+	if errors.Is(err, store.ErrNotFound) {
+		NotFoundError(c, "No deposit intent for address")
+		return
+	}
+	if err != nil {
+		InternalServerError(c, "Failed to get deposit intent", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	SuccessResponse(c, intent)
+}
+
+func (s *APIServer) getDepositIntentsByRecipient(c *gin.Context) {
+	if s.intentService == nil {
+		ServiceUnavailableError(c, "Deposit intents not available")
+		return
+	}
+
+	recipient := c.Param("recipient")
+	if !validateEthereumAddress(recipient) {
+		BadRequestError(c, "Invalid Ethereum recipient address", nil)
+		return
+	}
+
+	intents, err := s.intentService.IntentsByRecipient(recipient)
+	if err != nil {
+		InternalServerError(c, "Failed to get deposit intents", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	SuccessResponse(c, map[string]interface{}{
+		"recipient": recipient,
+		"intents":   intents,
+		"count":     len(intents),
+	})
+}
+
+// Withdrawal handlers
+
+func (s *APIServer) listWithdrawals(c *gin.Context) {
+	if s.withdrawals == nil {
+		ServiceUnavailableError(c, "Withdrawals not available")
+		return
+	}
+
+	withdrawals, err := s.withdrawals.ListWithdrawals()
+	if err != nil {
+		InternalServerError(c, "Failed to list withdrawals", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if status := c.Query("status"); status != "" {
+		filtered := withdrawals[:0]
+		for _, tx := range withdrawals {
+			if tx.Status == status {
+				filtered = append(filtered, tx)
+			}
+		}
+		withdrawals = filtered
+	}
+
+	SuccessResponse(c, map[string]interface{}{
+		"withdrawals": withdrawals,
+		"count":       len(withdrawals),
+	})
+}
+
+func (s *APIServer) getWithdrawal(c *gin.Context) {
+	if s.withdrawals == nil {
+		ServiceUnavailableError(c, "Withdrawals not available")
+		return
+	}
+
+	address := c.Param("address")
+	if !validateEthereumAddress(address) {
+		BadRequestError(c, "Invalid token address", nil)
+		return
+	}
+
+	withdrawal, err := s.withdrawals.GetWithdrawal(address)
+	if errors.Is(err, store.ErrNotFound) {
+		NotFoundError(c, "Withdrawal not found")
+		return
+	}
+	if err != nil {
+		InternalServerError(c, "Failed to get withdrawal", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	SuccessResponse(c, withdrawal)
+}
+
+func (s *APIServer) listWithdrawalBatches(c *gin.Context) {
+	if s.withdrawals == nil {
+		ServiceUnavailableError(c, "Withdrawals not available")
+		return
+	}
+
+	batches, err := s.withdrawals.ListBatches()
+	if err != nil {
+		InternalServerError(c, "Failed to list withdrawal batches", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	SuccessResponse(c, map[string]interface{}{
+		"batches": batches,
+		"count":   len(batches),
+	})
+}
+
+func (s *APIServer) getWithdrawalBatch(c *gin.Context) {
+	if s.withdrawals == nil {
+		ServiceUnavailableError(c, "Withdrawals not available")
+		return
+	}
+
+	batch, err := s.withdrawals.GetBatch(c.Param("txid"))
+	if errors.Is(err, store.ErrNotFound) {
+		NotFoundError(c, "Withdrawal batch not found")
+		return
+	}
+	if err != nil {
+		InternalServerError(c, "Failed to get withdrawal batch", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	SuccessResponse(c, batch)
+}
+
+// Admin handlers
+
+func (s *APIServer) listUnattributedDeposits(c *gin.Context) {
+	if s.orchestrator == nil {
+		ServiceUnavailableError(c, "Bridge orchestrator not available")
+		return
+	}
+
+	deposits, err := s.orchestrator.ListUnattributed()
+	if err != nil {
+		InternalServerError(c, "Failed to list unattributed deposits", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	SuccessResponse(c, map[string]interface{}{
+		"deposits": deposits,
+		"count":    len(deposits),
+	})
+}
+
+func (s *APIServer) resolveUnattributedDeposit(c *gin.Context) {
+	if s.orchestrator == nil {
+		ServiceUnavailableError(c, "Bridge orchestrator not available")
+		return
+	}
+
+	var req struct {
+		Recipient string `json:"recipient" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestError(c, "Invalid request format", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if !validateEthereumAddress(req.Recipient) {
+		BadRequestError(c, "Invalid Ethereum recipient address", map[string]interface{}{
+			"recipient": req.Recipient,
+		})
+		return
+	}
+
+	deposit, err := s.orchestrator.ResolveUnattributed(c.Param("id"), req.Recipient)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		NotFoundError(c, "Deposit not found")
+		return
+	case errors.Is(err, bridge.ErrNotUnattributed):
+		BadRequestError(c, "Deposit is not unattributed", nil)
+		return
+	case err != nil:
+		InternalServerError(c, "Failed to resolve deposit", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	SuccessResponse(c, deposit)
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -114,11 +115,23 @@ func ContentTypeMiddleware() gin.HandlerFunc {
 	}
 }
 
+// AdminAuthMiddleware requires the admin bearer token
+func AdminAuthMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			UnauthorizedError(c, "Admin token required")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 // ValidationMiddleware provides request validation utilities
 func ValidationMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Add validation helper functions to context
-		c.Set("validate_bitcoin_address", validateBitcoinAddress)
 		c.Set("validate_ethereum_address", validateEthereumAddress)
 		c.Set("validate_transaction_hash", validateTransactionHash)
 		
@@ -127,23 +140,6 @@ func ValidationMiddleware() gin.HandlerFunc {
 }
 
 // Validation helper functions
-func validateBitcoinAddress(address string) bool {
-	// Basic Bitcoin address validation
-	if len(address) < 26 || len(address) > 62 {
-		return false
-	}
-	
-	// Check for valid prefixes
-	validPrefixes := []string{"1", "3", "bc1", "tb1", "2", "m", "n", "bcrt1"}
-	for _, prefix := range validPrefixes {
-		if strings.HasPrefix(address, prefix) {
-			return true
-		}
-	}
-	
-	return false
-}
-
 func validateEthereumAddress(address string) bool {
 	// Ethereum address validation (42 characters with 0x prefix)
 	if len(address) != 42 {
@@ -0,0 +1,114 @@
+package bitcoin
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// ScriptType is the standard output script an address pays to
+type ScriptType string
+
+const (
+	ScriptP2PKH  ScriptType = "p2pkh"
+	ScriptP2SH   ScriptType = "p2sh"
+	ScriptP2WPKH ScriptType = "p2wpkh" // BIP84
+	ScriptP2WSH  ScriptType = "p2wsh"
+	ScriptP2TR   ScriptType = "p2tr" // BIP86
+)
+
+// AddressInfo describes a decoded address
+type AddressInfo struct {
+	Address        string     `json:"address"`
+	Network        string     `json:"network"`
+	Type           ScriptType `json:"type"`
+	WitnessVersion *int       `json:"witness_version,omitempty"` // set for segwit addresses
+	ScriptPubKey   string     `json:"script_pubkey"`
+}
+
+// addressNetworks are the networks an address is matched against when the
+// network is not known. Signet shares testnet's encodings.
+var addressNetworks = []*chaincfg.Params{
+	&chaincfg.MainNetParams,
+	&chaincfg.TestNet3Params,
+	&chaincfg.RegressionNetParams,
+}
+
+// NetworkParams returns the chain parameters for a configured network name
+func NetworkParams(network string) (*chaincfg.Params, error) {
+	switch network {
+	case "mainnet":
+		return &chaincfg.MainNetParams, nil
+	case "testnet":
+		return &chaincfg.TestNet3Params, nil
+	case "regtest":
+		return &chaincfg.RegressionNetParams, nil
+	default:
+		return nil, fmt.Errorf("unsupported network: %s", network)
+	}
+}
+
+// ParseAddress decodes an address for a network, verifying its base58check
+// or bech32/bech32m checksum, and reports the script it pays to
+func ParseAddress(address string, params *chaincfg.Params) (*AddressInfo, error) {
+	decoded, err := btcutil.DecodeAddress(address, params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+	if !decoded.IsForNet(params) {
+		return nil, fmt.Errorf("address is not for the %s network", params.Name)
+	}
+
+	info := &AddressInfo{
+		Address: decoded.EncodeAddress(),
+		Network: params.Name,
+	}
+	switch decoded.(type) {
+	case *btcutil.AddressPubKeyHash:
+		info.Type = ScriptP2PKH
+	case *btcutil.AddressScriptHash:
+		info.Type = ScriptP2SH
+	case *btcutil.AddressWitnessPubKeyHash:
+		info.Type = ScriptP2WPKH
+	case *btcutil.AddressWitnessScriptHash:
+		info.Type = ScriptP2WSH
+	case *btcutil.AddressTaproot:
+		info.Type = ScriptP2TR
+	default:
+		// DecodeAddress also accepts hex public keys, which are not addresses
+		return nil, fmt.Errorf("invalid address: unsupported address type %T", decoded)
+	}
+
+	if witness, ok := decoded.(interface{ WitnessVersion() byte }); ok {
+		version := int(witness.WitnessVersion())
+		info.WitnessVersion = &version
+	}
+
+	script, err := txscript.PayToAddrScript(decoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+	info.ScriptPubKey = hex.EncodeToString(script)
+
+	return info, nil
+}
+
+// DetectAddress decodes an address for whichever known network it belongs
+// to. Testnet, signet and regtest share base58 prefixes, so legacy test
+// network addresses are reported as testnet.
+func DetectAddress(address string) (*AddressInfo, error) {
+	var firstErr error
+	for _, params := range addressNetworks {
+		info, err := ParseAddress(address, params)
+		if err == nil {
+			return info, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
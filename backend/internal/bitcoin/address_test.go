@@ -0,0 +1,82 @@
+package bitcoin
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		address        string
+		params         *chaincfg.Params
+		scriptType     ScriptType
+		witnessVersion int // -1 for legacy addresses
+	}{
+		{"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", &chaincfg.MainNetParams, ScriptP2PKH, -1},
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", &chaincfg.MainNetParams, ScriptP2SH, -1},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", &chaincfg.MainNetParams, ScriptP2WPKH, 0},
+		{"bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3", &chaincfg.MainNetParams, ScriptP2WSH, 0},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", &chaincfg.MainNetParams, ScriptP2TR, 1},
+		{"tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", &chaincfg.TestNet3Params, ScriptP2WPKH, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			info, err := ParseAddress(tt.address, tt.params)
+			if err != nil {
+				t.Fatalf("Failed to parse address: %v", err)
+			}
+			if info.Type != tt.scriptType {
+				t.Errorf("Expected type %s, got %s", tt.scriptType, info.Type)
+			}
+			switch {
+			case tt.witnessVersion < 0 && info.WitnessVersion != nil:
+				t.Errorf("Expected no witness version, got %d", *info.WitnessVersion)
+			case tt.witnessVersion >= 0 && (info.WitnessVersion == nil || *info.WitnessVersion != tt.witnessVersion):
+				t.Errorf("Expected witness version %d, got %v", tt.witnessVersion, info.WitnessVersion)
+			}
+			if info.ScriptPubKey == "" {
+				t.Error("Expected the output script to be reported")
+			}
+		})
+	}
+}
+
+func TestParseAddressRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		params  *chaincfg.Params
+	}{
+		{"base58 checksum", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3", &chaincfg.MainNetParams},
+		{"prefix only", "1xyzxyzxyzxyzxyzxyzxyzxyzxyz", &chaincfg.MainNetParams},
+		{"bech32 checksum", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", &chaincfg.MainNetParams},
+		{"taproot with bech32 checksum", "bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7k7grplx", &chaincfg.MainNetParams},
+		{"wrong network", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", &chaincfg.TestNet3Params},
+		{"legacy wrong network", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", &chaincfg.RegressionNetParams},
+		{"public key", "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", &chaincfg.MainNetParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if info, err := ParseAddress(tt.address, tt.params); err == nil {
+				t.Errorf("Expected %s to be rejected, got %+v", tt.address, info)
+			}
+		})
+	}
+}
+
+func TestDetectAddress(t *testing.T) {
+	info, err := DetectAddress("tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx")
+	if err != nil {
+		t.Fatalf("Failed to detect address: %v", err)
+	}
+	if info.Network != chaincfg.TestNet3Params.Name {
+		t.Errorf("Expected network %s, got %s", chaincfg.TestNet3Params.Name, info.Network)
+	}
+
+	if _, err := DetectAddress("1xyzxyzxyzxyzxyzxyzxyzxyzxyz"); err == nil {
+		t.Error("Expected garbage to be rejected")
+	}
+}
@@ -0,0 +1,349 @@
+package bitcoin
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"bitbridge/pkg/types"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ErrDustOutput is returned when a payment would be below the dust limit
+// once its share of the fee is deducted
+var ErrDustOutput = errors.New("payment below dust limit")
+
+// DustPaymentError names the payment that is below the dust limit, so that
+// a batch can drop it and pay the others. It unwraps to ErrDustOutput.
+type DustPaymentError struct {
+	Index    int   // payment index in the build request
+	Amount   int64 // sats left after the fee share
+	FeeShare int64 // 0 if the payment is dust before any fee
+}
+
+func (e *DustPaymentError) Error() string {
+	if e.FeeShare == 0 {
+		return fmt.Sprintf("%v: payment %d of %d sats", ErrDustOutput, e.Index, e.Amount)
+	}
+	return fmt.Sprintf("%v: payment %d is %d sats after its %d sat fee share", ErrDustOutput, e.Index, e.Amount, e.FeeShare)
+}
+
+func (e *DustPaymentError) Unwrap() error {
+	return ErrDustOutput
+}
+
+// rbfSequence signals BIP125 replaceability so stuck payouts can be bumped
+const rbfSequence = wire.MaxTxInSequenceNum - 2
+
+// CoinSource lists the UTXOs the bridge tracks; *indexer.UTXOMonitor
+// satisfies it
+type CoinSource interface {
+	GetAllUTXOs() []*types.UTXO
+}
+
+// ChangeSource hands out change addresses; *Service satisfies it
+type ChangeSource interface {
+	ChangeAddress() (string, error)
+}
+
+// BuilderConfig for the withdrawal builder
+type BuilderConfig struct {
+	Coins            CoinSource
+	Change           ChangeSource
+	ChangeType       ScriptType // script type of change addresses, p2wpkh by default
+	Signer           PSBTSigner
+	Network          *chaincfg.Params
+	DustLimit        int64 // 0 for DefaultDustLimit
+	MinConfirmations int   // confirmations a UTXO needs to be spent, 0 for 1
+}
+
+// Payment is an output of a withdrawal transaction
+type Payment struct {
+	Address string `json:"address"`
+	Amount  int64  `json:"amount"` // satoshis
+}
+
+// BuildRequest describes a withdrawal transaction to build
+type BuildRequest struct {
+	Payments    []Payment
+	FeeRate     int64           // sat/vB
+	SubtractFee bool            // recipients pay the fee, shared equally
+	Exclude     map[string]bool // txid:vout of UTXOs reserved by unconfirmed payouts
+	Seed        int64           // knapsack seed
+}
+
+// UnsignedWithdrawal is a built withdrawal awaiting signatures
+type UnsignedWithdrawal struct {
+	Packet        *PSBT
+	Selection     *Selection
+	Payments      []Payment // amounts paid, in output order, after fee deduction
+	FeeShares     []int64   // fee deducted from each payment
+	ChangeAddress string
+	ChangeOutput  int // index of the change output, -1 without change
+}
+
+// Outpoints returns the spent UTXOs as txid:vout
+func (w *UnsignedWithdrawal) Outpoints() []string {
+	outpoints := make([]string, len(w.Selection.Coins))
+	for i, coin := range w.Selection.Coins {
+		outpoints[i] = coin.OutPoint.String()
+	}
+	return outpoints
+}
+
+// WithdrawalBuilder builds withdrawal transactions from the bridge's
+// tracked UTXO set rather than the node wallet. Inputs are picked by coin
+// selection, change goes to a fresh derived address, and the transaction is
+// handed to the signer as a BIP174 PSBT.
+type WithdrawalBuilder struct {
+	coins            CoinSource
+	change           ChangeSource
+	changeType       ScriptType
+	signer           PSBTSigner
+	network          *chaincfg.Params
+	dustLimit        int64
+	minConfirmations int
+}
+
+// NewWithdrawalBuilder creates a withdrawal builder
+func NewWithdrawalBuilder(config BuilderConfig) (*WithdrawalBuilder, error) {
+	if config.Coins == nil || config.Change == nil || config.Signer == nil {
+		return nil, fmt.Errorf("coin source, change source and signer are required")
+	}
+	if config.Network == nil {
+		return nil, fmt.Errorf("network is required")
+	}
+	if config.ChangeType == "" {
+		config.ChangeType = ScriptP2WPKH
+	}
+	if config.DustLimit == 0 {
+		config.DustLimit = DefaultDustLimit
+	}
+	if config.MinConfirmations == 0 {
+		config.MinConfirmations = 1
+	}
+
+	return &WithdrawalBuilder{
+		coins:            config.Coins,
+		change:           config.Change,
+		changeType:       config.ChangeType,
+		signer:           config.Signer,
+		network:          config.Network,
+		dustLimit:        config.DustLimit,
+		minConfirmations: config.MinConfirmations,
+	}, nil
+}
+
+// Build selects inputs and creates the unsigned PSBT for a withdrawal
+func (b *WithdrawalBuilder) Build(req BuildRequest) (*UnsignedWithdrawal, error) {
+	if len(req.Payments) == 0 {
+		return nil, fmt.Errorf("at least one payment is required")
+	}
+	if req.FeeRate <= 0 {
+		return nil, fmt.Errorf("fee rate must be positive")
+	}
+
+	var target int64
+	baseVSize := int64(txOverheadVSize)
+	scripts := make([][]byte, len(req.Payments))
+	for i, payment := range req.Payments {
+		info, err := ParseAddress(payment.Address, b.network)
+		if err != nil {
+			return nil, fmt.Errorf("payment %d: %w", i, err)
+		}
+		if payment.Amount < b.dustLimit {
+			return nil, &DustPaymentError{Index: i, Amount: payment.Amount}
+		}
+		if scripts[i], err = hex.DecodeString(info.ScriptPubKey); err != nil {
+			return nil, err
+		}
+		target += payment.Amount
+		baseVSize += outputVSize(info.Type)
+	}
+
+	changeSpendVSize, err := inputVSize(b.changeType)
+	if err != nil {
+		return nil, err
+	}
+
+	selection, err := SelectCoins(b.spendableCoins(req.Exclude), SelectionParams{
+		Target:           target,
+		FeeRate:          req.FeeRate,
+		BaseVSize:        baseVSize,
+		ChangeVSize:      outputVSize(b.changeType),
+		ChangeSpendVSize: changeSpendVSize,
+		DustLimit:        b.dustLimit,
+		SubtractFee:      req.SubtractFee,
+		Seed:             req.Seed,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	withdrawal := &UnsignedWithdrawal{
+		Selection:    selection,
+		Payments:     make([]Payment, len(req.Payments)),
+		FeeShares:    make([]int64, len(req.Payments)),
+		ChangeOutput: -1,
+	}
+	copy(withdrawal.Payments, req.Payments)
+	if req.SubtractFee {
+		withdrawal.FeeShares = splitFee(selection.Deducted, len(req.Payments))
+		for i, share := range withdrawal.FeeShares {
+			withdrawal.Payments[i].Amount -= share
+			if withdrawal.Payments[i].Amount < b.dustLimit {
+				return nil, &DustPaymentError{Index: i, Amount: withdrawal.Payments[i].Amount, FeeShare: share}
+			}
+		}
+	}
+
+	tx := wire.NewMsgTx(2)
+	for _, coin := range selection.Coins {
+		outPoint := coin.OutPoint
+		in := wire.NewTxIn(&outPoint, nil, nil)
+		in.Sequence = rbfSequence
+		tx.AddTxIn(in)
+	}
+	for i, payment := range withdrawal.Payments {
+		tx.AddTxOut(wire.NewTxOut(payment.Amount, scripts[i]))
+	}
+	if selection.Change > 0 {
+		address, err := b.change.ChangeAddress()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get change address: %w", err)
+		}
+		decoded, err := btcutil.DecodeAddress(address, b.network)
+		if err != nil {
+			return nil, fmt.Errorf("invalid change address: %w", err)
+		}
+		script, err := txscript.PayToAddrScript(decoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid change address: %w", err)
+		}
+		withdrawal.ChangeAddress = address
+		withdrawal.ChangeOutput = len(tx.TxOut)
+		tx.AddTxOut(wire.NewTxOut(selection.Change, script))
+	}
+
+	packet, err := NewPSBT(tx)
+	if err != nil {
+		return nil, err
+	}
+	for i, coin := range selection.Coins {
+		packet.Inputs[i].WitnessUtxo = wire.NewTxOut(coin.Value, coin.PkScript)
+	}
+	withdrawal.Packet = packet
+
+	return withdrawal, nil
+}
+
+// Sign has the signer sign the PSBT, finalizes it and checks every input
+// script before returning the transaction ready for broadcast
+func (b *WithdrawalBuilder) Sign(ctx context.Context, withdrawal *UnsignedWithdrawal) (*wire.MsgTx, error) {
+	return b.signPacket(ctx, withdrawal.Packet)
+}
+
+// signPacket has the signer sign a PSBT carrying the outputs its inputs
+// spend, then finalizes it and verifies the result
+func (b *WithdrawalBuilder) signPacket(ctx context.Context, packet *PSBT) (*wire.MsgTx, error) {
+	txHash := packet.UnsignedTx.TxHash()
+	prevOuts, err := packet.PrevOutputs()
+	if err != nil {
+		return nil, err
+	}
+
+	signed, err := b.signer.SignPSBT(ctx, packet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign withdrawal: %w", err)
+	}
+	if signed.UnsignedTx.TxHash() != txHash {
+		return nil, fmt.Errorf("signer changed the withdrawal transaction")
+	}
+	if err := signed.Finalize(); err != nil {
+		return nil, err
+	}
+	tx, err := signed.Extract()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyInputs(tx, prevOuts); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// spendableCoins returns the confirmed, unspent and unreserved UTXOs that
+// the builder knows how to spend
+func (b *WithdrawalBuilder) spendableCoins(exclude map[string]bool) []Coin {
+	var coins []Coin
+	for _, utxo := range b.coins.GetAllUTXOs() {
+		if utxo.SpentTxID != "" || utxo.BlockHeight == 0 || utxo.Confirmations < b.minConfirmations {
+			continue
+		}
+		if exclude[fmt.Sprintf("%s:%d", utxo.TxID, utxo.Vout)] {
+			continue
+		}
+
+		hash, err := chainhash.NewHashFromStr(utxo.TxID)
+		if err != nil {
+			continue
+		}
+		script, err := hex.DecodeString(utxo.ScriptPubKey)
+		if err != nil {
+			continue
+		}
+		// Legacy inputs would need the full previous transaction in the PSBT
+		switch scriptType, _ := ScriptTypeOf(script); scriptType {
+		case ScriptP2WPKH, ScriptP2TR:
+		default:
+			continue
+		}
+
+		coins = append(coins, Coin{
+			OutPoint: wire.OutPoint{Hash: *hash, Index: utxo.Vout},
+			Value:    utxo.Amount,
+			PkScript: script,
+		})
+	}
+	return coins
+}
+
+// verifyInputs runs every input script of a signed transaction
+func verifyInputs(tx *wire.MsgTx, prevOuts map[wire.OutPoint]*wire.TxOut) error {
+	fetcher := txscript.NewMultiPrevOutFetcher(prevOuts)
+	sigHashes := txscript.NewTxSigHashes(tx, fetcher)
+	for i, in := range tx.TxIn {
+		prevOut := prevOuts[in.PreviousOutPoint]
+		engine, err := txscript.NewEngine(prevOut.PkScript, tx, i, txscript.StandardVerifyFlags,
+			nil, sigHashes, prevOut.Value, fetcher)
+		if err != nil {
+			return fmt.Errorf("input %d: %w", i, err)
+		}
+		if err := engine.Execute(); err != nil {
+			return fmt.Errorf("input %d has an invalid signature: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// splitFee divides a fee between n payments. The remainder that does not
+// divide evenly goes one satoshi each to the first payments, so the shares
+// always add up to the fee exactly.
+func splitFee(fee int64, n int) []int64 {
+	shares := make([]int64, n)
+	each, remainder := fee/int64(n), fee%int64(n)
+	for i := range shares {
+		shares[i] = each
+		if int64(i) < remainder {
+			shares[i]++
+		}
+	}
+	return shares
+}
@@ -0,0 +1,254 @@
+package bitcoin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"bitbridge/pkg/types"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+type fixtureCoinSource []*types.UTXO
+
+func (s fixtureCoinSource) GetAllUTXOs() []*types.UTXO {
+	return s
+}
+
+type fixtureChangeSource struct {
+	address string
+	calls   int
+}
+
+func (s *fixtureChangeSource) ChangeAddress() (string, error) {
+	s.calls++
+	return s.address, nil
+}
+
+func testKey(seed byte) *btcec.PrivateKey {
+	key, _ := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{seed}, 32))
+	return key
+}
+
+func keyAddress(t *testing.T, key *btcec.PrivateKey, scriptType ScriptType) string {
+	t.Helper()
+
+	var address btcutil.Address
+	var err error
+	switch scriptType {
+	case ScriptP2TR:
+		outputKey := txscript.ComputeTaprootKeyNoScript(key.PubKey())
+		address, err = btcutil.NewAddressTaproot(schnorr.SerializePubKey(outputKey), &chaincfg.RegressionNetParams)
+	default:
+		address, err = btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(key.PubKey().SerializeCompressed()), &chaincfg.RegressionNetParams)
+	}
+	if err != nil {
+		t.Fatalf("Failed to encode address: %v", err)
+	}
+	return address.EncodeAddress()
+}
+
+func fixtureUTXO(t *testing.T, seed byte, address string, amount int64, confirmations int) *types.UTXO {
+	t.Helper()
+
+	info, err := ParseAddress(address, &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("Failed to parse address: %v", err)
+	}
+	return &types.UTXO{
+		TxID:          chainhash.Hash{seed}.String(),
+		Vout:          0,
+		Amount:        amount,
+		ScriptPubKey:  info.ScriptPubKey,
+		Address:       address,
+		Confirmations: confirmations,
+		BlockHeight:   100,
+	}
+}
+
+func newTestBuilder(t *testing.T, coins fixtureCoinSource, change *fixtureChangeSource, keys ...*btcec.PrivateKey) *WithdrawalBuilder {
+	t.Helper()
+
+	signer, err := NewKeyPSBTSigner(keys...)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+	builder, err := NewWithdrawalBuilder(BuilderConfig{
+		Coins:            coins,
+		Change:           change,
+		Signer:           signer,
+		Network:          &chaincfg.RegressionNetParams,
+		MinConfirmations: 3,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create builder: %v", err)
+	}
+	return builder
+}
+
+func TestWithdrawalBuilderBuildsAndSigns(t *testing.T) {
+	wpkhKey, trKey := testKey(1), testKey(2)
+	change := &fixtureChangeSource{address: keyAddress(t, testKey(3), ScriptP2WPKH)}
+	coins := fixtureCoinSource{
+		fixtureUTXO(t, 1, keyAddress(t, wpkhKey, ScriptP2WPKH), 60000, 6),
+		fixtureUTXO(t, 2, keyAddress(t, trKey, ScriptP2TR), 50000, 6),
+		fixtureUTXO(t, 3, keyAddress(t, wpkhKey, ScriptP2WPKH), 500000, 1), // not deep enough
+	}
+	builder := newTestBuilder(t, coins, change, wpkhKey, trKey)
+
+	payments := []Payment{
+		{Address: keyAddress(t, testKey(4), ScriptP2WPKH), Amount: 40000},
+		{Address: keyAddress(t, testKey(5), ScriptP2TR), Amount: 35000},
+	}
+	withdrawal, err := builder.Build(BuildRequest{Payments: payments, FeeRate: 3, SubtractFee: true})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	tx := withdrawal.Packet.UnsignedTx
+	if len(tx.TxIn) != 2 {
+		t.Fatalf("Expected both confirmed coins to be spent, got %d inputs", len(tx.TxIn))
+	}
+	for i, in := range tx.TxIn {
+		if in.Sequence != rbfSequence {
+			t.Errorf("Expected input %d to signal replaceability, got sequence %x", i, in.Sequence)
+		}
+	}
+	if withdrawal.ChangeOutput != 2 || change.calls != 1 {
+		t.Fatalf("Expected change as the third output, got output %d after %d calls", withdrawal.ChangeOutput, change.calls)
+	}
+
+	// Recipients share the fee exactly and everything balances
+	var shares int64
+	for i, share := range withdrawal.FeeShares {
+		shares += share
+		if got := tx.TxOut[i].Value; got != payments[i].Amount-share {
+			t.Errorf("Expected payment %d of %d, got %d", i, payments[i].Amount-share, got)
+		}
+	}
+	if shares != withdrawal.Selection.Fee {
+		t.Errorf("Expected fee shares to add up to %d, got %d", withdrawal.Selection.Fee, shares)
+	}
+	var outputs int64
+	for _, out := range tx.TxOut {
+		outputs += out.Value
+	}
+	if inputs := withdrawal.Selection.Total(); inputs != outputs+withdrawal.Selection.Fee {
+		t.Errorf("Inputs of %d do not balance outputs of %d and a %d sat fee", inputs, outputs, withdrawal.Selection.Fee)
+	}
+
+	signed, err := builder.Sign(context.Background(), withdrawal)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if signed.TxHash() != tx.TxHash() {
+		t.Error("Expected signing to keep the txid")
+	}
+	for i, in := range signed.TxIn {
+		if len(in.Witness) == 0 {
+			t.Errorf("Expected input %d to carry a witness", i)
+		}
+	}
+}
+
+func TestWithdrawalBuilderSkipsExcludedCoins(t *testing.T) {
+	key := testKey(1)
+	reserved := fixtureUTXO(t, 1, keyAddress(t, key, ScriptP2WPKH), 90000, 6)
+	free := fixtureUTXO(t, 2, keyAddress(t, key, ScriptP2WPKH), 80000, 6)
+	builder := newTestBuilder(t, fixtureCoinSource{reserved, free}, &fixtureChangeSource{address: keyAddress(t, testKey(3), ScriptP2WPKH)}, key)
+
+	withdrawal, err := builder.Build(BuildRequest{
+		Payments: []Payment{{Address: keyAddress(t, testKey(4), ScriptP2WPKH), Amount: 50000}},
+		FeeRate:  1,
+		Exclude:  map[string]bool{reserved.TxID + ":0": true},
+	})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	outpoints := withdrawal.Outpoints()
+	if len(outpoints) != 1 || outpoints[0] != free.TxID+":0" {
+		t.Errorf("Expected only the unreserved coin to be spent, got %v", outpoints)
+	}
+}
+
+func TestWithdrawalBuilderRejectsDust(t *testing.T) {
+	key := testKey(1)
+	coins := fixtureCoinSource{fixtureUTXO(t, 1, keyAddress(t, key, ScriptP2WPKH), 90000, 6)}
+	builder := newTestBuilder(t, coins, &fixtureChangeSource{address: keyAddress(t, testKey(3), ScriptP2WPKH)}, key)
+	recipient := keyAddress(t, testKey(4), ScriptP2WPKH)
+
+	// Below the limit outright
+	_, err := builder.Build(BuildRequest{Payments: []Payment{{Address: recipient, Amount: 500}}, FeeRate: 1})
+	if !errors.Is(err, ErrDustOutput) {
+		t.Errorf("Expected ErrDustOutput, got %v", err)
+	}
+
+	// Above the limit until the fee is deducted
+	_, err = builder.Build(BuildRequest{Payments: []Payment{{Address: recipient, Amount: 700}}, FeeRate: 5, SubtractFee: true})
+	if !errors.Is(err, ErrDustOutput) {
+		t.Errorf("Expected ErrDustOutput after the fee share, got %v", err)
+	}
+
+	// The error names the payment so a batch can drop it
+	_, err = builder.Build(BuildRequest{
+		Payments:    []Payment{{Address: recipient, Amount: 20000}, {Address: recipient, Amount: 700}},
+		FeeRate:     5,
+		SubtractFee: true,
+	})
+	var dust *DustPaymentError
+	if !errors.As(err, &dust) || dust.Index != 1 || dust.FeeShare == 0 {
+		t.Errorf("Expected payment 1 to be reported as dust after its fee share, got %v", err)
+	}
+}
+
+func TestPSBTRoundTrip(t *testing.T) {
+	key := testKey(1)
+	coins := fixtureCoinSource{fixtureUTXO(t, 1, keyAddress(t, key, ScriptP2WPKH), 90000, 6)}
+	builder := newTestBuilder(t, coins, &fixtureChangeSource{address: keyAddress(t, testKey(3), ScriptP2WPKH)}, key)
+
+	withdrawal, err := builder.Build(BuildRequest{
+		Payments: []Payment{{Address: keyAddress(t, testKey(4), ScriptP2WPKH), Amount: 50000}},
+		FeeRate:  1,
+	})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	packet := withdrawal.Packet
+	packet.Outputs[0].Unknown = []PSBTPair{{Key: []byte{0xfc, 0x01}, Value: []byte("proprietary")}}
+
+	encoded, err := packet.Base64()
+	if err != nil {
+		t.Fatalf("Failed to encode psbt: %v", err)
+	}
+	decoded, err := ParsePSBTBase64(encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode psbt: %v", err)
+	}
+	reencoded, err := decoded.Base64()
+	if err != nil {
+		t.Fatalf("Failed to re-encode psbt: %v", err)
+	}
+	if reencoded != encoded {
+		t.Error("Expected the psbt to round-trip unchanged")
+	}
+
+	if _, err := decoded.Extract(); !errors.Is(err, ErrIncompletePSBT) {
+		t.Errorf("Expected an unsigned psbt to be incomplete, got %v", err)
+	}
+
+	raw, err := packet.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize psbt: %v", err)
+	}
+	if _, err := ParsePSBT(raw[:len(raw)-1]); err == nil {
+		t.Error("Expected a truncated psbt to be rejected")
+	}
+}
@@ -1,6 +1,7 @@
 package bitcoin
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/btcsuite/btcd/wire"
 )
 
 type Client struct {
@@ -47,16 +49,9 @@ func NewClient(config Config) (*Client, error) {
 		return nil, fmt.Errorf("failed to create RPC client: %v", err)
 	}
 
-	var netParams *chaincfg.Params
-	switch config.Network {
-	case "mainnet":
-		netParams = &chaincfg.MainNetParams
-	case "testnet":
-		netParams = &chaincfg.TestNet3Params
-	case "regtest":
-		netParams = &chaincfg.RegressionNetParams
-	default:
-		return nil, fmt.Errorf("unsupported network: %s", config.Network)
+	netParams, err := NetworkParams(config.Network)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Client{
@@ -128,6 +123,29 @@ func (c *Client) GetBlockHash(height int64) (string, error) {
 	return hash.String(), nil
 }
 
+// GetBlock fetches a full block by its hash
+func (c *Client) GetBlock(blockHash string) (*wire.MsgBlock, error) {
+	hash, err := chainhash.NewHashFromStr(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	return c.rpcClient.GetBlock(hash)
+}
+
+// GetBlockHeader fetches a block header by its hash
+func (c *Client) GetBlockHeader(blockHash string) (*wire.BlockHeader, error) {
+	hash, err := chainhash.NewHashFromStr(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	return c.rpcClient.GetBlockHeader(hash)
+}
+
+// GetNetworkParams returns the chain parameters the client was configured for
+func (c *Client) GetNetworkParams() *chaincfg.Params {
+	return c.network
+}
+
 func (c *Client) WatchAddress(address string) error {
 	addr, err := btcutil.DecodeAddress(address, c.network)
 	if err != nil {
@@ -156,18 +174,18 @@ func (c *Client) GenerateDepositAddress() (string, error) {
 	return address.String(), nil
 }
 
-func (c *Client) SendBitcoin(toAddress string, amount float64) (string, error) {
+// SendBitcoin pays amount satoshis to toAddress from the node wallet
+func (c *Client) SendBitcoin(toAddress string, amount int64) (string, error) {
 	addr, err := btcutil.DecodeAddress(toAddress, c.network)
 	if err != nil {
 		return "", fmt.Errorf("invalid address: %v", err)
 	}
 
-	amountBTC, err := btcutil.NewAmount(amount)
-	if err != nil {
-		return "", fmt.Errorf("invalid amount: %v", err)
+	if amount <= 0 || amount > btcutil.MaxSatoshi {
+		return "", fmt.Errorf("invalid amount: %d sats", amount)
 	}
 
-	txHash, err := c.rpcClient.SendToAddress(addr, amountBTC)
+	txHash, err := c.rpcClient.SendToAddress(addr, btcutil.Amount(amount))
 	if err != nil {
 		return "", fmt.Errorf("failed to send bitcoin: %v", err)
 	}
@@ -175,6 +193,197 @@ func (c *Client) SendBitcoin(toAddress string, amount float64) (string, error) {
 	return txHash.String(), nil
 }
 
+// ValidateAddress checks that an address decodes and belongs to the
+// configured network
+func (c *Client) ValidateAddress(address string) error {
+	addr, err := btcutil.DecodeAddress(address, c.network)
+	if err != nil {
+		return fmt.Errorf("invalid address: %v", err)
+	}
+
+	if !addr.IsForNet(c.network) {
+		return fmt.Errorf("address %s is not valid for %s", address, c.network.Name)
+	}
+
+	return nil
+}
+
+// SendBitcoinSubtractFee pays amount to toAddress from the node wallet, with
+// the network fee deducted from the amount rather than added on top. The
+// comment is stored in the wallet alongside the transaction.
+func (c *Client) SendBitcoinSubtractFee(toAddress string, amount btcutil.Amount, comment string) (string, error) {
+	if err := c.ValidateAddress(toAddress); err != nil {
+		return "", err
+	}
+
+	params := []json.RawMessage{
+		mustMarshal(toAddress),
+		mustMarshal(amount.ToBTC()),
+		mustMarshal(comment),
+		mustMarshal(""),
+		mustMarshal(true),
+	}
+
+	result, err := c.rpcClient.RawRequest("sendtoaddress", params)
+	if err != nil {
+		return "", fmt.Errorf("failed to send bitcoin: %v", err)
+	}
+
+	var txid string
+	if err := json.Unmarshal(result, &txid); err != nil {
+		return "", fmt.Errorf("failed to decode sendtoaddress result: %v", err)
+	}
+
+	return txid, nil
+}
+
+// GetTransactionConfirmations returns the number of confirmations of a
+// transaction, or 0 while it is still in the mempool. Wallet transactions are
+// looked up in the wallet, which also reports conflicts as negative
+// confirmations; others, such as payouts built from derived addresses, fall
+// back to getrawtransaction.
+func (c *Client) GetTransactionConfirmations(txid string) (int64, error) {
+	hash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := c.rpcClient.GetTransaction(hash)
+	if err == nil {
+		return tx.Confirmations, nil
+	}
+
+	raw, rawErr := c.rpcClient.GetRawTransactionVerbose(hash)
+	if rawErr != nil {
+		return 0, err
+	}
+	return int64(raw.Confirmations), nil
+}
+
+// BroadcastTransaction submits a signed transaction to the node's mempool
+func (c *Client) BroadcastTransaction(tx *wire.MsgTx) (string, error) {
+	hash, err := c.rpcClient.SendRawTransaction(tx, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to broadcast transaction: %v", err)
+	}
+	return hash.String(), nil
+}
+
+// EstimateSmartFee returns the node's fee rate estimate in sat/vB for
+// confirmation within target blocks, rounded up
+func (c *Client) EstimateSmartFee(target int64) (int64, error) {
+	result, err := c.rpcClient.EstimateSmartFee(target, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate fee: %v", err)
+	}
+	if result.FeeRate == nil {
+		return 0, fmt.Errorf("no fee estimate for %d blocks: %v", target, result.Errors)
+	}
+	return btcPerKvBToSatPerVB(*result.FeeRate), nil
+}
+
+// mempoolEntryResult is an entry of the verbose getrawmempool response
+type mempoolEntryResult struct {
+	VSize int64 `json:"vsize"`
+	Fees  struct {
+		Base float64 `json:"base"`
+	} `json:"fees"`
+}
+
+// GetMempoolEntries returns the size and fee of every transaction in the
+// node's mempool
+func (c *Client) GetMempoolEntries() ([]MempoolEntry, error) {
+	result, err := c.rpcClient.RawRequest("getrawmempool", []json.RawMessage{mustMarshal(true)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mempool: %v", err)
+	}
+
+	var entries map[string]mempoolEntryResult
+	if err := json.Unmarshal(result, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode getrawmempool result: %v", err)
+	}
+
+	mempool := make([]MempoolEntry, 0, len(entries))
+	for _, entry := range entries {
+		fee, err := btcutil.NewAmount(entry.Fees.Base)
+		if err != nil {
+			continue
+		}
+		mempool = append(mempool, MempoolEntry{VSize: entry.VSize, Fee: int64(fee)})
+	}
+	return mempool, nil
+}
+
+// bumpFeeResult is the bumpfee response
+type bumpFeeResult struct {
+	TxID string `json:"txid"`
+}
+
+// BumpFee replaces a node wallet transaction with one paying feeRate sat/vB,
+// or a rate of the wallet's choosing when feeRate is 0. It returns the txid
+// of the replacement.
+func (c *Client) BumpFee(txid string, feeRate int64) (string, error) {
+	params := []json.RawMessage{mustMarshal(txid)}
+	if feeRate > 0 {
+		params = append(params, mustMarshal(map[string]interface{}{"fee_rate": feeRate}))
+	}
+
+	result, err := c.rpcClient.RawRequest("bumpfee", params)
+	if err != nil {
+		return "", fmt.Errorf("failed to bump fee: %v", err)
+	}
+
+	var bumped bumpFeeResult
+	if err := json.Unmarshal(result, &bumped); err != nil {
+		return "", fmt.Errorf("failed to decode bumpfee result: %v", err)
+	}
+	return bumped.TxID, nil
+}
+
+// GetRawChangeAddress returns a new change address from the node wallet
+func (c *Client) GetRawChangeAddress() (string, error) {
+	// The rpcclient helper sends an account argument, which Bitcoin Core
+	// reads as the address type
+	result, err := c.rpcClient.RawRequest("getrawchangeaddress", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get change address: %v", err)
+	}
+
+	var address string
+	if err := json.Unmarshal(result, &address); err != nil {
+		return "", fmt.Errorf("failed to decode getrawchangeaddress result: %v", err)
+	}
+	return address, nil
+}
+
+// processPSBTResult is the walletprocesspsbt response
+type processPSBTResult struct {
+	PSBT     string `json:"psbt"`
+	Complete bool   `json:"complete"`
+}
+
+// ProcessPSBT signs and finalizes the inputs of a base64 PSBT that the node
+// wallet has keys for
+func (c *Client) ProcessPSBT(encoded string) (string, bool, error) {
+	params := []json.RawMessage{
+		mustMarshal(encoded),
+		mustMarshal(true),  // sign
+		mustMarshal("ALL"), // sighash type
+		mustMarshal(true),  // include BIP32 derivations
+	}
+
+	result, err := c.rpcClient.RawRequest("walletprocesspsbt", params)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to sign psbt: %v", err)
+	}
+
+	var processed processPSBTResult
+	if err := json.Unmarshal(result, &processed); err != nil {
+		return "", false, fmt.Errorf("failed to decode walletprocesspsbt result: %v", err)
+	}
+	return processed.PSBT, processed.Complete, nil
+}
+
 func (c *Client) GetNetworkInfo() (string, error) {
 	info, err := c.rpcClient.GetBlockChainInfo()
 	if err != nil {
@@ -191,4 +400,12 @@ func (c *Client) Close() {
 
 func (c *Client) GetRPCClient() *rpcclient.Client {
 	return c.rpcClient
+}
+
+func mustMarshal(v interface{}) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return data
 }
\ No newline at end of file
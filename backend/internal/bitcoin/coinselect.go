@@ -0,0 +1,419 @@
+package bitcoin
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ErrInsufficientFunds is returned when the spendable coins cannot cover the
+// payments and the fee
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// Coin selection algorithms
+const (
+	SelectionBnB      = "bnb"
+	SelectionKnapsack = "knapsack"
+)
+
+const (
+	// DefaultDustLimit is the dust threshold of a P2PKH output at the
+	// default 3 sat/vB dust relay fee; anything below it is not relayed
+	DefaultDustLimit = 546
+
+	// bnbMaxTries bounds the branch-and-bound search, as in Bitcoin Core
+	bnbMaxTries = 100000
+
+	// knapsackIterations is the number of random subsets tried per target
+	knapsackIterations = 1000
+
+	// txOverheadVSize covers version, locktime, the input and output
+	// counts and the segwit marker and flag, rounded up
+	txOverheadVSize = 11
+)
+
+// Coin is an output that coin selection may spend
+type Coin struct {
+	OutPoint wire.OutPoint
+	Value    int64 // satoshis
+	PkScript []byte
+}
+
+// SelectionParams describe what a selection has to pay for. All amounts are
+// satoshis and sizes are vbytes.
+type SelectionParams struct {
+	Target           int64 // sum of the payment outputs
+	FeeRate          int64 // sat/vB
+	BaseVSize        int64 // transaction overhead plus payment outputs
+	ChangeVSize      int64 // size a change output adds
+	ChangeSpendVSize int64 // size of spending the change output later
+	DustLimit        int64 // smallest change output worth creating
+	SubtractFee      bool  // take the fee out of the payments instead of adding it on top
+	Seed             int64 // seeds the knapsack fallback so selections are reproducible
+}
+
+// Selection is the result of coin selection
+type Selection struct {
+	Coins     []Coin
+	Algorithm string
+	Fee       int64 // total fee paid to miners
+	Deducted  int64 // part of the fee taken out of the payments, when subtracting the fee
+	Change    int64 // change output value, 0 when the excess is too small and goes to the fee
+}
+
+// Total returns the value of the selected coins
+func (s *Selection) Total() int64 {
+	var total int64
+	for _, coin := range s.Coins {
+		total += coin.Value
+	}
+	return total
+}
+
+// candidate is a coin with the value it contributes after paying for its own
+// input
+type candidate struct {
+	coin      Coin
+	effective int64
+	vsize     int64
+}
+
+// SelectCoins picks coins to fund a transaction. It first searches for a
+// changeless solution with branch-and-bound and falls back to the knapsack
+// solver. The result only depends on the coins and the parameters.
+func SelectCoins(coins []Coin, params SelectionParams) (*Selection, error) {
+	if params.Target <= 0 {
+		return nil, fmt.Errorf("selection target must be positive")
+	}
+	if params.FeeRate < 0 {
+		return nil, fmt.Errorf("fee rate must not be negative")
+	}
+	if params.DustLimit <= 0 {
+		params.DustLimit = DefaultDustLimit
+	}
+
+	candidates := make([]candidate, 0, len(coins))
+	for _, coin := range coins {
+		scriptType, err := ScriptTypeOf(coin.PkScript)
+		if err != nil {
+			return nil, fmt.Errorf("coin %s: %w", coin.OutPoint, err)
+		}
+		vsize, err := inputVSize(scriptType)
+		if err != nil {
+			return nil, fmt.Errorf("coin %s: %w", coin.OutPoint, err)
+		}
+
+		c := candidate{coin: coin, effective: coin.Value, vsize: vsize}
+		if !params.SubtractFee {
+			c.effective -= vsize * params.FeeRate
+		}
+		// Coins worth less than their input fee only make the payment smaller
+		if c.effective <= 0 {
+			continue
+		}
+		candidates = append(candidates, c)
+	}
+
+	// Largest first, with the outpoint as a tie-break for determinism
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.effective != b.effective {
+			return a.effective > b.effective
+		}
+		if a.coin.Value != b.coin.Value {
+			return a.coin.Value > b.coin.Value
+		}
+		return a.coin.OutPoint.String() < b.coin.OutPoint.String()
+	})
+
+	// With the fee added on top the selection also pays for the transaction
+	// without inputs; when it is subtracted the payments cover all of it
+	target := params.Target
+	if !params.SubtractFee {
+		target += params.BaseVSize * params.FeeRate
+	}
+	costOfChange := (params.ChangeVSize+params.ChangeSpendVSize)*params.FeeRate + params.DustLimit
+
+	algorithm := SelectionBnB
+	selected := selectBnB(candidates, target, costOfChange)
+	if selected == nil {
+		algorithm = SelectionKnapsack
+		minChange := params.DustLimit + params.ChangeVSize*params.FeeRate
+		selected = selectKnapsack(candidates, target, minChange, rand.New(rand.NewSource(params.Seed)))
+	}
+	if selected == nil {
+		return nil, ErrInsufficientFunds
+	}
+
+	return finishSelection(selected, algorithm, params)
+}
+
+// finishSelection decides between a change output and adding the excess to
+// the fee, and works out the fee
+func finishSelection(selected []candidate, algorithm string, params SelectionParams) (*Selection, error) {
+	selection := &Selection{Algorithm: algorithm}
+
+	var total, inputsVSize int64
+	for _, c := range selected {
+		selection.Coins = append(selection.Coins, c.coin)
+		total += c.coin.Value
+		inputsVSize += c.vsize
+	}
+
+	feeWithoutChange := (params.BaseVSize + inputsVSize) * params.FeeRate
+	changeFee := params.ChangeVSize * params.FeeRate
+
+	if params.SubtractFee {
+		excess := total - params.Target
+		if excess < 0 {
+			return nil, ErrInsufficientFunds
+		}
+		if excess >= params.DustLimit {
+			selection.Change = excess
+			selection.Deducted = feeWithoutChange + changeFee
+			selection.Fee = selection.Deducted
+		} else {
+			// The excess already pays part of the fee
+			if excess < feeWithoutChange {
+				selection.Deducted = feeWithoutChange - excess
+			}
+			selection.Fee = selection.Deducted + excess
+		}
+		return selection, nil
+	}
+
+	excess := total - params.Target - feeWithoutChange
+	if excess < 0 {
+		return nil, ErrInsufficientFunds
+	}
+	if change := excess - changeFee; change >= params.DustLimit {
+		selection.Change = change
+		selection.Fee = feeWithoutChange + changeFee
+	} else {
+		selection.Fee = feeWithoutChange + excess
+	}
+	return selection, nil
+}
+
+// selectBnB runs Bitcoin Core's branch-and-bound search for a set of coins
+// whose effective value lands between target and target+costOfChange, so
+// that no change output is needed. Among the solutions found the one wasting
+// the least excess wins. Candidates must be sorted largest first.
+func selectBnB(candidates []candidate, target, costOfChange int64) []candidate {
+	var available int64
+	for _, c := range candidates {
+		available += c.effective
+	}
+	if available < target {
+		return nil
+	}
+
+	var (
+		current   []int // indexes of included candidates
+		best      []int
+		bestWaste int64 = -1
+		value     int64
+		index     int
+	)
+	for try := 0; try < bnbMaxTries; try, index = try+1, index+1 {
+		backtrack := false
+		switch {
+		case value+available < target || value > target+costOfChange:
+			backtrack = true
+		case value >= target:
+			if waste := value - target; bestWaste < 0 || waste <= bestWaste {
+				best = append(best[:0], current...)
+				bestWaste = waste
+			}
+			backtrack = true
+		}
+
+		if backtrack {
+			if len(current) == 0 {
+				break
+			}
+			// Put the omitted candidates back and try leaving out the
+			// last included one instead
+			last := current[len(current)-1]
+			for index--; index > last; index-- {
+				available += candidates[index].effective
+			}
+			value -= candidates[last].effective
+			current = current[:len(current)-1]
+			continue
+		}
+
+		c := candidates[index]
+		available -= c.effective
+		// Skip a candidate equal to one just left out, it leads to the same
+		// subsets
+		if len(current) == 0 || index-1 == current[len(current)-1] ||
+			c.effective != candidates[index-1].effective {
+			current = append(current, index)
+			value += c.effective
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+	selected := make([]candidate, len(best))
+	for i, index := range best {
+		selected[i] = candidates[index]
+	}
+	return selected
+}
+
+// selectKnapsack is Bitcoin Core's knapsack solver: an exact match if there
+// is one, otherwise the better of the smallest single coin above the target
+// and a random approximation of the best subset of smaller coins. Candidates
+// must be sorted largest first.
+func selectKnapsack(candidates []candidate, target, minChange int64, rng *rand.Rand) []candidate {
+	var (
+		smaller      []candidate
+		totalSmaller int64
+		lowestLarger *candidate
+	)
+	for i := range candidates {
+		c := candidates[i]
+		switch {
+		case c.effective == target:
+			return []candidate{c}
+		case c.effective < target+minChange:
+			smaller = append(smaller, c)
+			totalSmaller += c.effective
+		case lowestLarger == nil || c.effective < lowestLarger.effective:
+			lowestLarger = &candidates[i]
+		}
+	}
+
+	if totalSmaller == target {
+		return smaller
+	}
+	if totalSmaller < target {
+		if lowestLarger == nil {
+			return nil
+		}
+		return []candidate{*lowestLarger}
+	}
+
+	included, bestValue := approximateBestSubset(smaller, totalSmaller, target, rng)
+	if bestValue != target && totalSmaller >= target+minChange {
+		included, bestValue = approximateBestSubset(smaller, totalSmaller, target+minChange, rng)
+	}
+
+	// Prefer the single larger coin if the subset would leave change too
+	// small to keep, or is not smaller anyway
+	if lowestLarger != nil &&
+		((bestValue != target && bestValue < target+minChange) || lowestLarger.effective <= bestValue) {
+		return []candidate{*lowestLarger}
+	}
+
+	var selected []candidate
+	for i, ok := range included {
+		if ok {
+			selected = append(selected, smaller[i])
+		}
+	}
+	return selected
+}
+
+// approximateBestSubset looks for the subset with the smallest value that
+// still reaches target by repeatedly including coins at random
+func approximateBestSubset(candidates []candidate, total, target int64, rng *rand.Rand) ([]bool, int64) {
+	best := make([]bool, len(candidates))
+	for i := range best {
+		best[i] = true
+	}
+	bestValue := total
+
+	included := make([]bool, len(candidates))
+	for rep := 0; rep < knapsackIterations && bestValue != target; rep++ {
+		for i := range included {
+			included[i] = false
+		}
+		var value int64
+		reached := false
+
+		for pass := 0; pass < 2 && !reached; pass++ {
+			for i, c := range candidates {
+				// The first pass takes coins at random, the second adds the
+				// ones left out until the target is reached
+				take := !included[i]
+				if pass == 0 {
+					take = rng.Intn(2) == 1
+				}
+				if !take {
+					continue
+				}
+
+				value += c.effective
+				included[i] = true
+				if value >= target {
+					reached = true
+					if value < bestValue {
+						bestValue = value
+						copy(best, included)
+					}
+					value -= c.effective
+					included[i] = false
+				}
+			}
+		}
+	}
+	return best, bestValue
+}
+
+// ScriptTypeOf returns the standard type of an output script
+func ScriptTypeOf(pkScript []byte) (ScriptType, error) {
+	switch txscript.GetScriptClass(pkScript) {
+	case txscript.PubKeyHashTy:
+		return ScriptP2PKH, nil
+	case txscript.ScriptHashTy:
+		return ScriptP2SH, nil
+	case txscript.WitnessV0PubKeyHashTy:
+		return ScriptP2WPKH, nil
+	case txscript.WitnessV0ScriptHashTy:
+		return ScriptP2WSH, nil
+	case txscript.WitnessV1TaprootTy:
+		return ScriptP2TR, nil
+	default:
+		return "", fmt.Errorf("unsupported output script %x", pkScript)
+	}
+}
+
+// inputVSize is the size of spending an output of a script type with a
+// single signature, rounded up. P2SH is assumed to wrap P2WPKH and P2TR to
+// be spent through the key path.
+func inputVSize(scriptType ScriptType) (int64, error) {
+	switch scriptType {
+	case ScriptP2PKH:
+		return 148, nil
+	case ScriptP2SH:
+		return 91, nil
+	case ScriptP2WPKH:
+		return 68, nil
+	case ScriptP2TR:
+		return 58, nil
+	default:
+		return 0, fmt.Errorf("cannot estimate the size of spending a %s output", scriptType)
+	}
+}
+
+// outputVSize is the size of an output of a script type
+func outputVSize(scriptType ScriptType) int64 {
+	switch scriptType {
+	case ScriptP2PKH:
+		return 34
+	case ScriptP2SH:
+		return 32
+	case ScriptP2WPKH:
+		return 31
+	default: // P2WSH and P2TR both commit to 32 bytes
+		return 43
+	}
+}
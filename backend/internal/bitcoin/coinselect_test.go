@@ -0,0 +1,162 @@
+package bitcoin
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// p2wpkhScript is a P2WPKH output script; selection only looks at its type
+var p2wpkhScript = append([]byte{0x00, 0x14}, make([]byte, 20)...)
+
+// fixtureCoins creates P2WPKH coins with distinct outpoints
+func fixtureCoins(values ...int64) []Coin {
+	coins := make([]Coin, len(values))
+	for i, value := range values {
+		coins[i] = Coin{
+			OutPoint: wire.OutPoint{Hash: chainhash.Hash{byte(i + 1)}, Index: uint32(i)},
+			Value:    value,
+			PkScript: p2wpkhScript,
+		}
+	}
+	return coins
+}
+
+// singlePayment are the selection parameters of one P2WPKH payment with
+// P2WPKH change at 1 sat/vB
+func singlePayment(target int64) SelectionParams {
+	return SelectionParams{
+		Target:           target,
+		FeeRate:          1,
+		BaseVSize:        txOverheadVSize + 31,
+		ChangeVSize:      31,
+		ChangeSpendVSize: 68,
+		DustLimit:        DefaultDustLimit,
+	}
+}
+
+func selectedValues(selection *Selection) []int64 {
+	var values []int64
+	for _, coin := range selection.Coins {
+		values = append(values, coin.Value)
+	}
+	return values
+}
+
+func TestSelectCoinsBranchAndBoundFindsChangelessMatch(t *testing.T) {
+	// 50000 and 30000 pay 79822 plus the fee of a one-output, two-input
+	// transaction exactly
+	selection, err := SelectCoins(fixtureCoins(100000, 50000, 30000, 20000), singlePayment(79822))
+	if err != nil {
+		t.Fatalf("Selection failed: %v", err)
+	}
+
+	if selection.Algorithm != SelectionBnB {
+		t.Errorf("Expected branch-and-bound, got %s", selection.Algorithm)
+	}
+	if got := selectedValues(selection); !reflect.DeepEqual(got, []int64{50000, 30000}) {
+		t.Errorf("Expected 50000 and 30000 to be selected, got %v", got)
+	}
+	if selection.Change != 0 || selection.Fee != 178 {
+		t.Errorf("Expected no change and a 178 sat fee, got change %d fee %d", selection.Change, selection.Fee)
+	}
+}
+
+func TestSelectCoinsDropsDustChange(t *testing.T) {
+	// 490 sats over the fee is not worth a change output
+	selection, err := SelectCoins(fixtureCoins(70600), singlePayment(70000))
+	if err != nil {
+		t.Fatalf("Selection failed: %v", err)
+	}
+
+	if selection.Change != 0 {
+		t.Errorf("Expected the excess to go to the fee, got %d sats change", selection.Change)
+	}
+	if selection.Fee != 600 {
+		t.Errorf("Expected a 600 sat fee, got %d", selection.Fee)
+	}
+}
+
+func TestSelectCoinsFallsBackToKnapsack(t *testing.T) {
+	selection, err := SelectCoins(fixtureCoins(100000, 60000), singlePayment(70000))
+	if err != nil {
+		t.Fatalf("Selection failed: %v", err)
+	}
+
+	if selection.Algorithm != SelectionKnapsack {
+		t.Errorf("Expected the knapsack fallback, got %s", selection.Algorithm)
+	}
+	if got := selectedValues(selection); !reflect.DeepEqual(got, []int64{100000}) {
+		t.Errorf("Expected the single larger coin, got %v", got)
+	}
+	if selection.Change != 29859 || selection.Fee != 141 {
+		t.Errorf("Expected 29859 change and a 141 sat fee, got change %d fee %d", selection.Change, selection.Fee)
+	}
+	if total := selection.Total(); total != 70000+selection.Change+selection.Fee {
+		t.Errorf("Inputs of %d do not balance payment, change and fee", total)
+	}
+}
+
+func TestSelectCoinsKnapsackIsDeterministic(t *testing.T) {
+	values := []int64{12000, 7300, 5100, 4400, 3900, 3300, 2800, 2600, 1900, 1200}
+	params := singlePayment(21000)
+	params.Seed = 42
+
+	first, err := SelectCoins(fixtureCoins(values...), params)
+	if err != nil {
+		t.Fatalf("Selection failed: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		again, err := SelectCoins(fixtureCoins(values...), params)
+		if err != nil {
+			t.Fatalf("Selection failed: %v", err)
+		}
+		if !reflect.DeepEqual(first, again) {
+			t.Fatalf("Expected identical selections, got %+v and %+v", first, again)
+		}
+	}
+
+	if total := first.Total(); total != 21000+first.Change+first.Fee {
+		t.Errorf("Inputs of %d do not balance payment, change and fee", total)
+	}
+}
+
+func TestSelectCoinsSubtractFee(t *testing.T) {
+	params := singlePayment(100000)
+	params.FeeRate = 2
+	params.SubtractFee = true
+
+	selection, err := SelectCoins(fixtureCoins(100000, 40000), params)
+	if err != nil {
+		t.Fatalf("Selection failed: %v", err)
+	}
+
+	if got := selectedValues(selection); !reflect.DeepEqual(got, []int64{100000}) {
+		t.Errorf("Expected the exact coin, got %v", got)
+	}
+	// 42 vB base and a 68 vB input at 2 sat/vB, all paid by the payment
+	if selection.Deducted != 220 || selection.Fee != 220 || selection.Change != 0 {
+		t.Errorf("Expected a 220 sat fee deducted without change, got %+v", selection)
+	}
+}
+
+func TestSelectCoinsInsufficientFunds(t *testing.T) {
+	// At 100 sat/vB the smaller coins cost more to spend than they are worth
+	params := singlePayment(10000)
+	params.FeeRate = 100
+
+	_, err := SelectCoins(fixtureCoins(9000, 6000, 5000), params)
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("Expected ErrInsufficientFunds, got %v", err)
+	}
+}
+
+func TestSplitFee(t *testing.T) {
+	shares := splitFee(100, 3)
+	if !reflect.DeepEqual(shares, []int64{34, 33, 33}) {
+		t.Errorf("Expected [34 33 33], got %v", shares)
+	}
+}
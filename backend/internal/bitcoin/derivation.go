@@ -0,0 +1,649 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"bitbridge/internal/store"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// ErrGapLimit is returned when deriving another address would leave more
+// unused addresses after the last funded one than the gap limit allows.
+// Wallets restoring the account stop scanning at the gap limit, so funds
+// sent past it would not be found.
+var ErrGapLimit = errors.New("deposit address gap limit reached")
+
+const derivationStateKey = "deposit_derivation"
+
+// SLIP-132 version bytes of BIP84 account keys
+var (
+	zpubVersion = [4]byte{0x04, 0xb2, 0x47, 0x46}
+	vpubVersion = [4]byte{0x04, 0x5f, 0x1c, 0xf6}
+)
+
+// DeriverConfig for deposit address derivation
+type DeriverConfig struct {
+	// AccountKey is an account-level extended public key (xpub, tpub, zpub or
+	// vpub) or an output descriptor such as wpkh([fp/84'/0'/0']xpub.../0/*)
+	// or tr(xpub.../0/*)
+	AccountKey string
+	ScriptType ScriptType // output type for plain xpub/tpub keys, p2wpkh by default
+	Network    *chaincfg.Params
+	Store      store.StateRepository // optional, keeps assigned indexes across restarts
+	GapLimit   uint32                // 0 for the BIP44 default of 20
+}
+
+// derivationState is the persisted assignment of indexes to recipients
+type derivationState struct {
+	NextIndex       uint32            `json:"next_index"`
+	UsedIndex       *uint32           `json:"used_index,omitempty"` // highest index that received funds
+	Recipients      map[string]uint32 `json:"recipients"`
+	NextChangeIndex uint32            `json:"next_change_index,omitempty"`
+	Released        map[uint32]bool   `json:"released,omitempty"` // unfunded indexes not counted against the gap limit
+}
+
+// AddressDeriver derives deposit addresses from an account extended public
+// key. Each Ethereum recipient is assigned the next unused index once and
+// keeps it, so the recipient of a deposit is recomputed from the account key
+// and the assignment alone, without any node wallet state. Change addresses
+// for withdrawals come from the internal chain next to the deposit chain.
+type AddressDeriver struct {
+	chain       *hdkeychain.ExtendedKey // parent of the address keys
+	path        string                  // derivation path of the address keys below the account
+	changeChain *hdkeychain.ExtendedKey // parent of the change keys, nil if the path has no internal chain
+	changePath  string
+	scriptType  ScriptType
+	network     *chaincfg.Params
+	store       store.StateRepository
+	gapLimit    uint32
+	state       derivationState
+	addresses   map[string]uint32 // derived address -> index
+	byIndex     map[uint32]string // index -> recipient
+	change      map[string]uint32 // change address -> index
+	mu          sync.Mutex
+}
+
+// NewAddressDeriver parses the account key and restores assigned indexes
+func NewAddressDeriver(config DeriverConfig) (*AddressDeriver, error) {
+	if config.Network == nil {
+		return nil, fmt.Errorf("network is required")
+	}
+	if config.GapLimit == 0 {
+		config.GapLimit = 20
+	}
+
+	key, scriptType, path, err := parseAccountKey(config.AccountKey, config.ScriptType, config.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := deriveChain(key, path)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &AddressDeriver{
+		chain:      chain,
+		path:       formatPath(path),
+		scriptType: scriptType,
+		network:    config.Network,
+		store:      config.Store,
+		gapLimit:   config.GapLimit,
+		state:      derivationState{Recipients: make(map[string]uint32)},
+		addresses:  make(map[string]uint32),
+		byIndex:    make(map[uint32]string),
+		change:     make(map[string]uint32),
+	}
+
+	// By BIP44 convention receive addresses are on chain 0 and change on 1
+	if len(path) > 0 && path[len(path)-1] == 0 {
+		changePath := append(append([]uint32(nil), path[:len(path)-1]...), 1)
+		if d.changeChain, err = deriveChain(key, changePath); err != nil {
+			return nil, err
+		}
+		d.changePath = formatPath(changePath)
+	}
+
+	if err := d.load(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// ScriptType returns the output type of derived addresses
+func (d *AddressDeriver) ScriptType() ScriptType {
+	return d.scriptType
+}
+
+// AddressFor returns the deposit address of an Ethereum recipient, assigning
+// it the next index the first time it is seen
+func (d *AddressDeriver) AddressFor(recipient string) (string, uint32, error) {
+	recipient = normalizeRecipient(recipient)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if index, ok := d.state.Recipients[recipient]; ok {
+		// A released address counts against the gap again once handed out,
+		// unless it has been funded since
+		if d.state.Released[index] {
+			funded := d.state.UsedIndex != nil && index <= *d.state.UsedIndex
+			if !funded && d.unusedLocked() >= d.gapLimit {
+				return "", 0, ErrGapLimit
+			}
+			delete(d.state.Released, index)
+			if err := d.saveLocked(); err != nil {
+				d.state.Released[index] = true
+				return "", 0, err
+			}
+		}
+		address, err := d.deriveLocked(index)
+		return address, index, err
+	}
+
+	index := d.state.NextIndex
+	if d.unusedLocked() >= d.gapLimit {
+		return "", 0, ErrGapLimit
+	}
+
+	address, err := d.deriveLocked(index)
+	if err != nil {
+		return "", 0, err
+	}
+
+	d.state.Recipients[recipient] = index
+	d.state.NextIndex = index + 1
+	if err := d.saveLocked(); err != nil {
+		delete(d.state.Recipients, recipient)
+		d.state.NextIndex = index
+		return "", 0, err
+	}
+	d.byIndex[index] = recipient
+
+	return address, index, nil
+}
+
+// RecipientOf returns the Ethereum recipient a derived address belongs to
+func (d *AddressDeriver) RecipientOf(address string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	index, ok := d.addresses[address]
+	if !ok {
+		return "", false
+	}
+	recipient, ok := d.byIndex[index]
+	return recipient, ok
+}
+
+// MarkUsed records that an address received funds, which moves the gap
+// limit window past it
+func (d *AddressDeriver) MarkUsed(address string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	index, ok := d.addresses[address]
+	if !ok {
+		return nil
+	}
+	if d.state.UsedIndex != nil && *d.state.UsedIndex >= index {
+		return nil
+	}
+
+	previous := d.state.UsedIndex
+	d.state.UsedIndex = &index
+	if err := d.saveLocked(); err != nil {
+		d.state.UsedIndex = previous
+		return err
+	}
+	return nil
+}
+
+// Release stops counting an assigned address that never received funds
+// against the gap limit, for addresses whose deposit intents expired. The
+// recipient keeps the address and it stays in Addresses; handing it out
+// again counts it again. A wallet restoring the account from the key alone
+// may need a larger gap limit to find late deposits past released
+// addresses.
+func (d *AddressDeriver) Release(address string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	index, ok := d.addresses[address]
+	if !ok {
+		return nil
+	}
+	if _, assigned := d.byIndex[index]; !assigned || d.state.Released[index] {
+		return nil
+	}
+	if d.state.UsedIndex != nil && index <= *d.state.UsedIndex {
+		return nil
+	}
+
+	if d.state.Released == nil {
+		d.state.Released = make(map[uint32]bool)
+	}
+	d.state.Released[index] = true
+	if err := d.saveLocked(); err != nil {
+		delete(d.state.Released, index)
+		return err
+	}
+	return nil
+}
+
+// Addresses returns every assigned deposit address
+func (d *AddressDeriver) Addresses() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	addresses := make([]string, 0, len(d.state.Recipients))
+	for address, index := range d.addresses {
+		if _, ok := d.byIndex[index]; ok {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+// ChangeAddress returns a new address on the internal chain. Every call
+// hands out the next index, so each withdrawal gets its own change address.
+func (d *AddressDeriver) ChangeAddress() (string, uint32, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.changeChain == nil {
+		return "", 0, fmt.Errorf("derivation path %s has no change chain", d.path)
+	}
+
+	index := d.state.NextChangeIndex
+	address, err := d.deriveChangeLocked(index)
+	if err != nil {
+		return "", 0, err
+	}
+
+	d.state.NextChangeIndex = index + 1
+	if err := d.saveLocked(); err != nil {
+		d.state.NextChangeIndex = index
+		delete(d.change, address)
+		return "", 0, err
+	}
+	return address, index, nil
+}
+
+// ChangeAddresses returns every change address handed out
+func (d *AddressDeriver) ChangeAddresses() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	addresses := make([]string, 0, len(d.change))
+	for address := range d.change {
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// ChangePath returns the derivation path of a change index below the
+// account key
+func (d *AddressDeriver) ChangePath(index uint32) string {
+	return fmt.Sprintf("%s/%d", d.changePath, index)
+}
+
+// Derive returns the address at an index without assigning it
+func (d *AddressDeriver) Derive(index uint32) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.deriveLocked(index)
+}
+
+// Path returns the derivation path of an index below the account key
+func (d *AddressDeriver) Path(index uint32) string {
+	return fmt.Sprintf("%s/%d", d.path, index)
+}
+
+func (d *AddressDeriver) deriveLocked(index uint32) (string, error) {
+	address, err := d.deriveAddress(d.chain, index)
+	if err != nil {
+		return "", err
+	}
+	d.addresses[address] = index
+	return address, nil
+}
+
+func (d *AddressDeriver) deriveChangeLocked(index uint32) (string, error) {
+	address, err := d.deriveAddress(d.changeChain, index)
+	if err != nil {
+		return "", err
+	}
+	d.change[address] = index
+	return address, nil
+}
+
+func (d *AddressDeriver) deriveAddress(chain *hdkeychain.ExtendedKey, index uint32) (string, error) {
+	if index >= hdkeychain.HardenedKeyStart {
+		return "", fmt.Errorf("derivation index %d out of range", index)
+	}
+
+	child, err := chain.Derive(index)
+	if err != nil {
+		// Probability 1 in 2^127, BIP32 says to skip the index
+		return "", fmt.Errorf("failed to derive index %d: %w", index, err)
+	}
+	pubKey, err := child.ECPubKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to derive index %d: %w", index, err)
+	}
+
+	var address btcutil.Address
+	switch d.scriptType {
+	case ScriptP2TR:
+		outputKey := txscript.ComputeTaprootKeyNoScript(pubKey)
+		address, err = btcutil.NewAddressTaproot(schnorr.SerializePubKey(outputKey), d.network)
+	default:
+		address, err = btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(pubKey.SerializeCompressed()), d.network)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to encode address for index %d: %w", index, err)
+	}
+	return address.EncodeAddress(), nil
+}
+
+// unusedLocked counts assigned indexes after the last funded one, leaving
+// out released ones
+func (d *AddressDeriver) unusedLocked() uint32 {
+	unused := d.state.NextIndex
+	if d.state.UsedIndex != nil {
+		unused = d.state.NextIndex - *d.state.UsedIndex - 1
+	}
+	for index := range d.state.Released {
+		if d.state.UsedIndex == nil || index > *d.state.UsedIndex {
+			unused--
+		}
+	}
+	return unused
+}
+
+func (d *AddressDeriver) load() error {
+	if d.store == nil {
+		return nil
+	}
+
+	data, err := d.store.GetState(derivationStateKey)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load derivation state: %w", err)
+	}
+
+	var state derivationState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to decode derivation state: %w", err)
+	}
+	if state.Recipients == nil {
+		state.Recipients = make(map[string]uint32)
+	}
+	d.state = state
+
+	for recipient, index := range state.Recipients {
+		if _, err := d.deriveLocked(index); err != nil {
+			return err
+		}
+		d.byIndex[index] = recipient
+	}
+	if d.changeChain != nil {
+		for index := uint32(0); index < state.NextChangeIndex; index++ {
+			if _, err := d.deriveChangeLocked(index); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *AddressDeriver) saveLocked() error {
+	if d.store == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(d.state)
+	if err != nil {
+		return fmt.Errorf("failed to encode derivation state: %w", err)
+	}
+	if err := d.store.SaveState(derivationStateKey, data); err != nil {
+		return fmt.Errorf("failed to persist derivation state: %w", err)
+	}
+	return nil
+}
+
+func normalizeRecipient(recipient string) string {
+	return strings.ToLower(strings.TrimSpace(recipient))
+}
+
+// parseAccountKey returns the account key, output type and the
+// non-hardened path from it to the parent of the address keys
+func parseAccountKey(value string, scriptType ScriptType, network *chaincfg.Params) (*hdkeychain.ExtendedKey, ScriptType, []uint32, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, "", nil, fmt.Errorf("account key is required")
+	}
+
+	switch scriptType {
+	case "":
+		scriptType = ScriptP2WPKH
+	case ScriptP2WPKH, ScriptP2TR:
+	default:
+		return nil, "", nil, fmt.Errorf("unsupported script type: %s", scriptType)
+	}
+
+	// A plain key derives receive addresses on the external chain
+	keyExpr, path := value, []uint32{0}
+	if strings.Contains(value, "(") {
+		descriptor, err := stripChecksum(value)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		if keyExpr, scriptType, err = unwrapDescriptor(descriptor); err != nil {
+			return nil, "", nil, err
+		}
+		if keyExpr, path, err = splitKeyPath(keyExpr); err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	key, slip132, err := decodeAccountKey(keyExpr, network)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if slip132 {
+		if strings.Contains(value, "(") {
+			return nil, "", nil, fmt.Errorf("descriptors take xpub or tpub keys, not zpub or vpub")
+		}
+		scriptType = ScriptP2WPKH
+	}
+
+	return key, scriptType, path, nil
+}
+
+// unwrapDescriptor returns the key expression of a wpkh() or tr() descriptor
+func unwrapDescriptor(descriptor string) (string, ScriptType, error) {
+	var scriptType ScriptType
+	var inner string
+	switch {
+	case strings.HasPrefix(descriptor, "wpkh(") && strings.HasSuffix(descriptor, ")"):
+		scriptType, inner = ScriptP2WPKH, descriptor[len("wpkh("):len(descriptor)-1]
+	case strings.HasPrefix(descriptor, "tr(") && strings.HasSuffix(descriptor, ")"):
+		scriptType, inner = ScriptP2TR, descriptor[len("tr("):len(descriptor)-1]
+	default:
+		return "", "", fmt.Errorf("unsupported descriptor %q: only wpkh() and key-path tr() are supported", descriptor)
+	}
+	if strings.ContainsAny(inner, "(),") {
+		return "", "", fmt.Errorf("unsupported descriptor %q: only a single key is supported", descriptor)
+	}
+
+	// Drop the key origin, it only describes how the account key was derived
+	if strings.HasPrefix(inner, "[") {
+		end := strings.Index(inner, "]")
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated key origin in descriptor")
+		}
+		inner = inner[end+1:]
+	}
+	return inner, scriptType, nil
+}
+
+// splitKeyPath splits xpub/0/* into the key and its non-hardened path
+func splitKeyPath(keyExpr string) (string, []uint32, error) {
+	parts := strings.Split(keyExpr, "/")
+	if len(parts) < 2 || parts[len(parts)-1] != "*" {
+		return "", nil, fmt.Errorf("descriptor key must end in a /* wildcard")
+	}
+
+	path := make([]uint32, 0, len(parts)-2)
+	for _, part := range parts[1 : len(parts)-1] {
+		if strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") {
+			return "", nil, fmt.Errorf("hardened derivation %q cannot be done from a public key", part)
+		}
+		index, err := strconv.ParseUint(part, 10, 31)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid derivation step %q", part)
+		}
+		path = append(path, uint32(index))
+	}
+	return parts[0], path, nil
+}
+
+// decodeAccountKey decodes an extended public key for the network. SLIP-132
+// zpub and vpub keys are re-encoded with the network's xpub version.
+func decodeAccountKey(encoded string, network *chaincfg.Params) (*hdkeychain.ExtendedKey, bool, error) {
+	key, err := hdkeychain.NewKeyFromString(encoded)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid extended key: %w", err)
+	}
+	if key.IsPrivate() {
+		return nil, false, fmt.Errorf("deposit derivation takes an extended public key, not a private key")
+	}
+
+	version := key.Version()
+	switch {
+	case bytes.Equal(version, network.HDPublicKeyID[:]):
+		return key, false, nil
+	case network.Net == chaincfg.MainNetParams.Net && bytes.Equal(version, zpubVersion[:]),
+		network.Net != chaincfg.MainNetParams.Net && bytes.Equal(version, vpubVersion[:]):
+		key, err := key.CloneWithVersion(network.HDPublicKeyID[:])
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid extended key: %w", err)
+		}
+		return key, true, nil
+	default:
+		return nil, false, fmt.Errorf("extended key is not for the %s network", network.Name)
+	}
+}
+
+// deriveChain derives the non-hardened path below the account key
+func deriveChain(key *hdkeychain.ExtendedKey, path []uint32) (*hdkeychain.ExtendedKey, error) {
+	chain := key
+	for _, component := range path {
+		var err error
+		if chain, err = chain.Derive(component); err != nil {
+			return nil, fmt.Errorf("failed to derive chain key: %w", err)
+		}
+	}
+	return chain, nil
+}
+
+func formatPath(path []uint32) string {
+	var b strings.Builder
+	b.WriteString("m")
+	for _, index := range path {
+		b.WriteString("/")
+		b.WriteString(strconv.FormatUint(uint64(index), 10))
+	}
+	return b.String()
+}
+
+// Output descriptor checksums, as specified in BIP380
+const (
+	descriptorInputCharset    = "0123456789()[],'/*abcdefgh@:$%{}IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+	descriptorChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+// stripChecksum verifies and removes a trailing #checksum, if present
+func stripChecksum(descriptor string) (string, error) {
+	body, checksum, found := strings.Cut(descriptor, "#")
+	if !found {
+		return descriptor, nil
+	}
+
+	expected, err := DescriptorChecksum(body)
+	if err != nil {
+		return "", err
+	}
+	if checksum != expected {
+		return "", fmt.Errorf("descriptor checksum mismatch: got %s, expected %s", checksum, expected)
+	}
+	return body, nil
+}
+
+// DescriptorChecksum computes the 8-character checksum of a descriptor
+func DescriptorChecksum(descriptor string) (string, error) {
+	c := uint64(1)
+	class, classCount := uint64(0), 0
+	for _, ch := range descriptor {
+		pos := strings.IndexRune(descriptorInputCharset, ch)
+		if pos < 0 {
+			return "", fmt.Errorf("invalid character %q in descriptor", ch)
+		}
+		c = descriptorPolymod(c, uint64(pos&31))
+		class = class*3 + uint64(pos>>5)
+		classCount++
+		if classCount == 3 {
+			c = descriptorPolymod(c, class)
+			class, classCount = 0, 0
+		}
+	}
+	if classCount > 0 {
+		c = descriptorPolymod(c, class)
+	}
+	for i := 0; i < 8; i++ {
+		c = descriptorPolymod(c, 0)
+	}
+	c ^= 1
+
+	checksum := make([]byte, 8)
+	for i := range checksum {
+		checksum[i] = descriptorChecksumCharset[(c>>(5*(7-i)))&31]
+	}
+	return string(checksum), nil
+}
+
+func descriptorPolymod(c, value uint64) uint64 {
+	top := c >> 35
+	c = ((c & 0x7ffffffff) << 5) ^ value
+	if top&1 != 0 {
+		c ^= 0xf5dee51989
+	}
+	if top&2 != 0 {
+		c ^= 0xa9fdca3312
+	}
+	if top&4 != 0 {
+		c ^= 0x1bab10e32d
+	}
+	if top&8 != 0 {
+		c ^= 0x3706b1677a
+	}
+	if top&16 != 0 {
+		c ^= 0x644d626ffd
+	}
+	return c
+}
@@ -0,0 +1,263 @@
+package bitcoin
+
+import (
+	"errors"
+	"testing"
+
+	"bitbridge/internal/store/storetest"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// Account keys of the "abandon ... about" mnemonic from the BIP84 and BIP86
+// test vectors
+const (
+	bip84AccountZpub = "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+	bip86AccountXpub = "xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjSxarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ"
+)
+
+func TestAddressDeriverTestVectors(t *testing.T) {
+	tests := []struct {
+		name       string
+		accountKey string
+		scriptType ScriptType
+		first      string
+		second     string
+	}{
+		{
+			name:       "BIP84 zpub",
+			accountKey: bip84AccountZpub,
+			first:      "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu",
+			second:     "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g",
+		},
+		{
+			name:       "BIP86 xpub",
+			accountKey: bip86AccountXpub,
+			scriptType: ScriptP2TR,
+			first:      "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr",
+			second:     "bc1p4qhjn9zdvkux4e44uhx8tc55attvtyu358kutcqkudyccelu0was9fqzwh",
+		},
+		{
+			name:       "BIP86 descriptor",
+			accountKey: "tr([73c5da0a/86'/0'/0']" + bip86AccountXpub + "/0/*)",
+			first:      "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr",
+			second:     "bc1p4qhjn9zdvkux4e44uhx8tc55attvtyu358kutcqkudyccelu0was9fqzwh",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deriver, err := NewAddressDeriver(DeriverConfig{
+				AccountKey: tt.accountKey,
+				ScriptType: tt.scriptType,
+				Network:    &chaincfg.MainNetParams,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create deriver: %v", err)
+			}
+
+			for index, expected := range []string{tt.first, tt.second} {
+				address, err := deriver.Derive(uint32(index))
+				if err != nil {
+					t.Fatalf("Failed to derive index %d: %v", index, err)
+				}
+				if address != expected {
+					t.Errorf("Index %d: expected %s, got %s", index, expected, address)
+				}
+			}
+		})
+	}
+}
+
+func TestAddressDeriverDescriptorChecksum(t *testing.T) {
+	checksum, err := DescriptorChecksum("raw(deadbeef)")
+	if err != nil {
+		t.Fatalf("Failed to compute checksum: %v", err)
+	}
+	if checksum != "89f8spxm" {
+		t.Fatalf("Expected checksum 89f8spxm, got %s", checksum)
+	}
+
+	descriptor := "wpkh(" + bip86AccountXpub + "/0/*)"
+	checksum, err = DescriptorChecksum(descriptor)
+	if err != nil {
+		t.Fatalf("Failed to compute checksum: %v", err)
+	}
+
+	config := DeriverConfig{AccountKey: descriptor + "#" + checksum, Network: &chaincfg.MainNetParams}
+	if _, err := NewAddressDeriver(config); err != nil {
+		t.Fatalf("Descriptor with valid checksum rejected: %v", err)
+	}
+	config.AccountKey = descriptor + "#qqqqqqqq"
+	if _, err := NewAddressDeriver(config); err == nil {
+		t.Fatal("Expected descriptor with a bad checksum to be rejected")
+	}
+}
+
+func TestAddressDeriverRejectsWrongNetwork(t *testing.T) {
+	_, err := NewAddressDeriver(DeriverConfig{AccountKey: bip84AccountZpub, Network: &chaincfg.TestNet3Params})
+	if err == nil {
+		t.Fatal("Expected a mainnet key to be rejected on testnet")
+	}
+}
+
+func TestAddressDeriverAssignsRecipientsAcrossRestarts(t *testing.T) {
+	repo := storetest.Open(t)
+
+	config := DeriverConfig{
+		AccountKey: bip84AccountZpub,
+		Network:    &chaincfg.MainNetParams,
+		Store:      repo,
+		GapLimit:   2,
+	}
+	deriver, err := NewAddressDeriver(config)
+	if err != nil {
+		t.Fatalf("Failed to create deriver: %v", err)
+	}
+
+	alice := "0x00000000000000000000000000000000000000Aa"
+	bob := "0x00000000000000000000000000000000000000bb"
+	carol := "0x00000000000000000000000000000000000000cc"
+
+	aliceAddress, aliceIndex, err := deriver.AddressFor(alice)
+	if err != nil {
+		t.Fatalf("Failed to derive address: %v", err)
+	}
+	if aliceIndex != 0 || aliceAddress != "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu" {
+		t.Fatalf("Unexpected first assignment %d %s", aliceIndex, aliceAddress)
+	}
+	if _, _, err := deriver.AddressFor(bob); err != nil {
+		t.Fatalf("Failed to derive address: %v", err)
+	}
+
+	// Two unused addresses fill the gap
+	if _, _, err := deriver.AddressFor(carol); !errors.Is(err, ErrGapLimit) {
+		t.Fatalf("Expected ErrGapLimit, got %v", err)
+	}
+	if err := deriver.MarkUsed(aliceAddress); err != nil {
+		t.Fatalf("Failed to mark address used: %v", err)
+	}
+	_, carolIndex, err := deriver.AddressFor(carol)
+	if err != nil {
+		t.Fatalf("Failed to derive address after a deposit: %v", err)
+	}
+	if carolIndex != 2 {
+		t.Errorf("Expected index 2, got %d", carolIndex)
+	}
+
+	restarted, err := NewAddressDeriver(config)
+	if err != nil {
+		t.Fatalf("Failed to restore deriver: %v", err)
+	}
+	again, index, err := restarted.AddressFor("0x00000000000000000000000000000000000000aa")
+	if err != nil {
+		t.Fatalf("Failed to derive address: %v", err)
+	}
+	if again != aliceAddress || index != aliceIndex {
+		t.Errorf("Recipient got %s at %d after restart, expected %s at %d", again, index, aliceAddress, aliceIndex)
+	}
+	recipient, ok := restarted.RecipientOf(aliceAddress)
+	if !ok || recipient != "0x00000000000000000000000000000000000000aa" {
+		t.Errorf("Expected deposit address to resolve to its recipient, got %q", recipient)
+	}
+	if n := len(restarted.Addresses()); n != 3 {
+		t.Errorf("Expected 3 assigned addresses, got %d", n)
+	}
+}
+
+func TestAddressDeriverReleasesUnfundedAddresses(t *testing.T) {
+	repo := storetest.Open(t)
+
+	config := DeriverConfig{
+		AccountKey: bip84AccountZpub,
+		Network:    &chaincfg.MainNetParams,
+		Store:      repo,
+		GapLimit:   1,
+	}
+	deriver, err := NewAddressDeriver(config)
+	if err != nil {
+		t.Fatalf("Failed to create deriver: %v", err)
+	}
+
+	alice := "0x00000000000000000000000000000000000000aa"
+	bob := "0x00000000000000000000000000000000000000bb"
+
+	aliceAddress, _, err := deriver.AddressFor(alice)
+	if err != nil {
+		t.Fatalf("Failed to derive address: %v", err)
+	}
+	if _, _, err := deriver.AddressFor(bob); !errors.Is(err, ErrGapLimit) {
+		t.Fatalf("Expected ErrGapLimit, got %v", err)
+	}
+
+	if err := deriver.Release(aliceAddress); err != nil {
+		t.Fatalf("Failed to release address: %v", err)
+	}
+	_, bobIndex, err := deriver.AddressFor(bob)
+	if err != nil {
+		t.Fatalf("Failed to derive address after a release: %v", err)
+	}
+	if bobIndex != 1 {
+		t.Errorf("Expected index 1, got %d", bobIndex)
+	}
+
+	// Handing the released address out again counts it against the gap
+	if _, _, err := deriver.AddressFor(alice); !errors.Is(err, ErrGapLimit) {
+		t.Errorf("Expected ErrGapLimit for the released address, got %v", err)
+	}
+
+	// Releases survive a restart
+	restarted, err := NewAddressDeriver(config)
+	if err != nil {
+		t.Fatalf("Failed to restore deriver: %v", err)
+	}
+	if err := restarted.MarkUsed(aliceAddress); err != nil {
+		t.Fatalf("Failed to mark address used: %v", err)
+	}
+	again, _, err := restarted.AddressFor(alice)
+	if err != nil || again != aliceAddress {
+		t.Errorf("Expected alice to get %s back, got %s (%v)", aliceAddress, again, err)
+	}
+}
+
+func TestAddressDeriverChangeAddresses(t *testing.T) {
+	repo := storetest.Open(t)
+
+	config := DeriverConfig{AccountKey: bip84AccountZpub, Network: &chaincfg.MainNetParams, Store: repo}
+	deriver, err := NewAddressDeriver(config)
+	if err != nil {
+		t.Fatalf("Failed to create deriver: %v", err)
+	}
+
+	// BIP84 vector for m/84'/0'/0'/1/0
+	address, index, err := deriver.ChangeAddress()
+	if err != nil {
+		t.Fatalf("Failed to derive change address: %v", err)
+	}
+	if index != 0 || address != "bc1q8c6fshw2dlwun7ekn9qwf37cu2rn755upcp6el" {
+		t.Errorf("Expected the first change address, got %s at %d", address, index)
+	}
+	if path := deriver.ChangePath(index); path != "m/1/0" {
+		t.Errorf("Expected path m/1/0, got %s", path)
+	}
+
+	restarted, err := NewAddressDeriver(config)
+	if err != nil {
+		t.Fatalf("Failed to restore deriver: %v", err)
+	}
+	if _, index, err := restarted.ChangeAddress(); err != nil || index != 1 {
+		t.Errorf("Expected the next change index after a restart, got %d (%v)", index, err)
+	}
+	if changes := restarted.ChangeAddresses(); len(changes) != 2 {
+		t.Errorf("Expected both change addresses to be known, got %v", changes)
+	}
+
+	// A descriptor without a receive chain has no change chain to go with it
+	flat, err := NewAddressDeriver(DeriverConfig{AccountKey: "wpkh(" + bip86AccountXpub + "/*)", Network: &chaincfg.MainNetParams})
+	if err != nil {
+		t.Fatalf("Failed to create deriver: %v", err)
+	}
+	if _, _, err := flat.ChangeAddress(); err == nil {
+		t.Error("Expected change derivation to fail without a change chain")
+	}
+}
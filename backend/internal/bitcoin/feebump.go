@@ -0,0 +1,171 @@
+package bitcoin
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ErrCannotBump is returned when a payout cannot be fee bumped the way asked
+var ErrCannotBump = errors.New("cannot bump payout fee")
+
+// minRelayFeeRate is the default incremental relay fee in sat/vB. A BIP125
+// replacement has to pay at least this much more than what it replaces.
+const minRelayFeeRate = 1
+
+// SignalsRBF reports whether a transaction opts in to BIP125 replacement
+func SignalsRBF(tx *wire.MsgTx) bool {
+	for _, in := range tx.TxIn {
+		if in.Sequence < wire.MaxTxInSequenceNum-1 {
+			return true
+		}
+	}
+	return false
+}
+
+// VirtualSize returns the size of a signed transaction in vbytes
+func VirtualSize(tx *wire.MsgTx) int64 {
+	weight := int64(tx.SerializeSizeStripped()*3 + tx.SerializeSize())
+	return (weight + 3) / 4
+}
+
+// Replace builds and signs a BIP125 replacement of a payout paying feeRate.
+// Inputs and payments stay the same and the extra fee comes out of the
+// change output, so recipients are paid exactly what the payout promised.
+// It returns the replacement and its fee.
+func (b *WithdrawalBuilder) Replace(ctx context.Context, payout *wire.MsgTx, fee int64, changeOutput int, feeRate int64) (*wire.MsgTx, int64, error) {
+	if !SignalsRBF(payout) {
+		return nil, 0, fmt.Errorf("%w: payout does not signal replaceability", ErrCannotBump)
+	}
+	if changeOutput < 0 || changeOutput >= len(payout.TxOut) {
+		return nil, 0, fmt.Errorf("%w: payout has no change output to pay the fee from", ErrCannotBump)
+	}
+
+	prevOuts, err := b.trackedPrevOuts(payout)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	vsize := VirtualSize(payout)
+	newFee := feeRate * vsize
+	if min := fee + minRelayFeeRate*vsize; newFee < min {
+		newFee = min
+	}
+
+	replacement := payout.Copy()
+	for _, in := range replacement.TxIn {
+		in.SignatureScript = nil
+		in.Witness = nil
+	}
+	change := replacement.TxOut[changeOutput]
+	change.Value -= newFee - fee
+	if change.Value < b.dustLimit {
+		return nil, 0, fmt.Errorf("%w: change of %d sats cannot pay a %d sat fee", ErrCannotBump,
+			payout.TxOut[changeOutput].Value, newFee)
+	}
+
+	signed, err := b.signTx(ctx, replacement, prevOuts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return signed, newFee, nil
+}
+
+// ChildPays builds and signs a child spending the change output of an
+// unconfirmed payout, paying enough that parent and child together reach
+// feeRate. The child sends what is left of the change to a fresh change
+// address. prevChildFee is the fee of an earlier child this one replaces, 0
+// for the first. It returns the child and its fee.
+func (b *WithdrawalBuilder) ChildPays(ctx context.Context, parent *wire.MsgTx, parentFee int64, changeOutput int, feeRate, prevChildFee int64) (*wire.MsgTx, int64, error) {
+	if changeOutput < 0 || changeOutput >= len(parent.TxOut) {
+		return nil, 0, fmt.Errorf("%w: payout has no change output to spend", ErrCannotBump)
+	}
+	change := parent.TxOut[changeOutput]
+
+	changeSpendType, err := ScriptTypeOf(change.PkScript)
+	if err != nil {
+		return nil, 0, err
+	}
+	spendVSize, err := inputVSize(changeSpendType)
+	if err != nil {
+		return nil, 0, err
+	}
+	childVSize := txOverheadVSize + spendVSize + outputVSize(b.changeType)
+
+	childFee := feeRate*(VirtualSize(parent)+childVSize) - parentFee
+	if min := minRelayFeeRate * childVSize; childFee < min {
+		childFee = min
+	}
+	if min := prevChildFee + minRelayFeeRate*childVSize; prevChildFee > 0 && childFee < min {
+		childFee = min
+	}
+	if change.Value-childFee < b.dustLimit {
+		return nil, 0, fmt.Errorf("%w: change of %d sats cannot pay a %d sat child fee", ErrCannotBump, change.Value, childFee)
+	}
+
+	address, err := b.change.ChangeAddress()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get change address: %w", err)
+	}
+	decoded, err := btcutil.DecodeAddress(address, b.network)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid change address: %w", err)
+	}
+	script, err := txscript.PayToAddrScript(decoded)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid change address: %w", err)
+	}
+
+	outPoint := wire.OutPoint{Hash: parent.TxHash(), Index: uint32(changeOutput)}
+	child := wire.NewMsgTx(2)
+	in := wire.NewTxIn(&outPoint, nil, nil)
+	in.Sequence = rbfSequence
+	child.AddTxIn(in)
+	child.AddTxOut(wire.NewTxOut(change.Value-childFee, script))
+
+	signed, err := b.signTx(ctx, child, map[wire.OutPoint]*wire.TxOut{outPoint: change})
+	if err != nil {
+		return nil, 0, err
+	}
+	return signed, childFee, nil
+}
+
+// trackedPrevOuts looks up the outputs a payout spends in the tracked UTXO
+// set. They stay there until the payout confirms.
+func (b *WithdrawalBuilder) trackedPrevOuts(tx *wire.MsgTx) (map[wire.OutPoint]*wire.TxOut, error) {
+	utxos := make(map[string]*wire.TxOut)
+	for _, utxo := range b.coins.GetAllUTXOs() {
+		script, err := hex.DecodeString(utxo.ScriptPubKey)
+		if err != nil {
+			continue
+		}
+		utxos[fmt.Sprintf("%s:%d", utxo.TxID, utxo.Vout)] = wire.NewTxOut(utxo.Amount, script)
+	}
+
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut, len(tx.TxIn))
+	for _, in := range tx.TxIn {
+		prevOut, ok := utxos[in.PreviousOutPoint.String()]
+		if !ok {
+			return nil, fmt.Errorf("%w: input %s is not a tracked UTXO", ErrCannotBump, in.PreviousOutPoint)
+		}
+		prevOuts[in.PreviousOutPoint] = prevOut
+	}
+	return prevOuts, nil
+}
+
+// signTx signs an unsigned transaction spending prevOuts
+func (b *WithdrawalBuilder) signTx(ctx context.Context, tx *wire.MsgTx, prevOuts map[wire.OutPoint]*wire.TxOut) (*wire.MsgTx, error) {
+	packet, err := NewPSBT(tx)
+	if err != nil {
+		return nil, err
+	}
+	for i, in := range tx.TxIn {
+		packet.Inputs[i].WitnessUtxo = prevOuts[in.PreviousOutPoint]
+	}
+	return b.signPacket(ctx, packet)
+}
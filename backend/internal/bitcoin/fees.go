@@ -0,0 +1,275 @@
+package bitcoin
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// blockVSize is the vbytes of transactions a block holds
+	blockVSize = 1000000
+
+	// TypicalWithdrawalVSize is the size of a payout spending one P2WPKH
+	// coin to one P2WPKH recipient with P2WPKH change
+	TypicalWithdrawalVSize = txOverheadVSize + 68 + 2*31
+)
+
+// Fee estimate sources
+const (
+	FeeSourceSmart   = "smart"
+	FeeSourceMempool = "mempool"
+	FeeSourceFloor   = "floor"
+)
+
+// feeHistogramEdges are the lower bounds in sat/vB of the mempool histogram
+// bins
+var feeHistogramEdges = []int64{1, 2, 3, 4, 5, 6, 8, 10, 12, 15, 20, 30, 40, 50, 60, 80, 100, 125, 150, 200, 300, 500, 1000}
+
+// MempoolEntry is a transaction waiting in the mempool
+type MempoolEntry struct {
+	VSize int64 // vbytes
+	Fee   int64 // satoshis
+}
+
+// FeeSource is the node access the fee estimator needs; *Client satisfies it
+type FeeSource interface {
+	EstimateSmartFee(target int64) (int64, error)
+	GetMempoolEntries() ([]MempoolEntry, error)
+}
+
+// FeeEstimatorConfig for the fee estimator
+type FeeEstimatorConfig struct {
+	Source      FeeSource
+	Targets     []int64       // confirmation targets in blocks, 1, 3, 6 and 144 by default
+	FloorRate   int64         // lowest sat/vB ever returned, 1 by default
+	CeilingRate int64         // highest sat/vB ever returned, 500 by default
+	CacheTTL    time.Duration // how long estimates are reused, a minute by default
+}
+
+// FeeEstimate is the fee rate expected to confirm within Target blocks
+type FeeEstimate struct {
+	Target         int64  `json:"target"`                     // blocks
+	FeeRate        int64  `json:"fee_rate"`                   // sat/vB
+	Source         string `json:"source"`                     // smart, mempool or floor
+	SmartFeeRate   int64  `json:"smart_fee_rate,omitempty"`   // estimatesmartfee, 0 when the node has none
+	MempoolFeeRate int64  `json:"mempool_fee_rate,omitempty"` // rate clearing the mempool backlog in Target blocks
+	WithdrawalFee  int64  `json:"withdrawal_fee"`             // satoshis for a typical single payout
+}
+
+// FeeHistogramBin counts the mempool transactions paying at least MinRate
+// and less than the next bin
+type FeeHistogramBin struct {
+	MinRate int64 `json:"min_rate"` // sat/vB
+	Count   int   `json:"count"`
+	VSize   int64 `json:"vsize"`
+}
+
+// FeeEstimates are the estimates for every configured target together with
+// the mempool they were taken against
+type FeeEstimates struct {
+	Estimates    []FeeEstimate     `json:"estimates"`
+	Histogram    []FeeHistogramBin `json:"histogram"`
+	MempoolCount int               `json:"mempool_count"`
+	MempoolVSize int64             `json:"mempool_vsize"`
+	FloorRate    int64             `json:"floor_rate"`
+	CeilingRate  int64             `json:"ceiling_rate"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// FeeEstimator combines the node's estimatesmartfee, which learns from the
+// blocks it has seen, with the current mempool backlog. Each target gets the
+// higher of the two, so a sudden backlog is priced in before the smart
+// estimate catches up. Results are cached for CacheTTL.
+type FeeEstimator struct {
+	source      FeeSource
+	targets     []int64
+	floorRate   int64
+	ceilingRate int64
+	cacheTTL    time.Duration
+
+	mu     sync.Mutex
+	cached *FeeEstimates
+}
+
+// NewFeeEstimator creates a fee estimator
+func NewFeeEstimator(config FeeEstimatorConfig) (*FeeEstimator, error) {
+	if config.Source == nil {
+		return nil, fmt.Errorf("fee source is required")
+	}
+	if len(config.Targets) == 0 {
+		config.Targets = []int64{1, 3, 6, 144}
+	}
+	if config.FloorRate == 0 {
+		config.FloorRate = 1
+	}
+	if config.CeilingRate == 0 {
+		config.CeilingRate = 500
+	}
+	if config.CeilingRate < config.FloorRate {
+		return nil, fmt.Errorf("fee ceiling %d is below the floor %d", config.CeilingRate, config.FloorRate)
+	}
+	if config.CacheTTL == 0 {
+		config.CacheTTL = time.Minute
+	}
+
+	targets := append([]int64(nil), config.Targets...)
+	sort.Slice(targets, func(i, j int) bool { return targets[i] < targets[j] })
+	for _, target := range targets {
+		if target < 1 {
+			return nil, fmt.Errorf("invalid confirmation target %d", target)
+		}
+	}
+
+	return &FeeEstimator{
+		source:      config.Source,
+		targets:     targets,
+		floorRate:   config.FloorRate,
+		ceilingRate: config.CeilingRate,
+		cacheTTL:    config.CacheTTL,
+	}, nil
+}
+
+// Estimates returns the fee estimates, refreshing them once the cache has
+// expired. A failed refresh serves the previous estimates if there are any.
+func (e *FeeEstimator) Estimates() (*FeeEstimates, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.cached != nil && time.Since(e.cached.UpdatedAt) < e.cacheTTL {
+		return e.cached, nil
+	}
+
+	estimates, err := e.estimate()
+	if err != nil {
+		if e.cached != nil {
+			log.Printf("Failed to refresh fee estimates, serving estimates from %s: %v", e.cached.UpdatedAt.Format(time.RFC3339), err)
+			return e.cached, nil
+		}
+		return nil, err
+	}
+	e.cached = estimates
+	return estimates, nil
+}
+
+// FeeRate returns the sat/vB to confirm within target blocks, from the
+// smallest configured target that is at least target
+func (e *FeeEstimator) FeeRate(target int64) (int64, error) {
+	estimates, err := e.Estimates()
+	if err != nil {
+		return 0, err
+	}
+	for _, estimate := range estimates.Estimates {
+		if estimate.Target >= target {
+			return estimate.FeeRate, nil
+		}
+	}
+	return estimates.Estimates[len(estimates.Estimates)-1].FeeRate, nil
+}
+
+// estimate queries the node and combines the two estimates per target
+func (e *FeeEstimator) estimate() (*FeeEstimates, error) {
+	entries, err := e.source.GetMempoolEntries()
+	if err != nil {
+		return nil, err
+	}
+	estimates := &FeeEstimates{
+		Histogram:   feeHistogram(entries),
+		FloorRate:   e.floorRate,
+		CeilingRate: e.ceilingRate,
+		UpdatedAt:   time.Now(),
+	}
+	for _, entry := range entries {
+		estimates.MempoolCount++
+		estimates.MempoolVSize += entry.VSize
+	}
+
+	for _, target := range e.targets {
+		estimate := FeeEstimate{
+			Target:         target,
+			MempoolFeeRate: mempoolFeeRate(entries, target),
+		}
+		// Young or regtest nodes have no smart estimate; the mempool still
+		// gives a rate
+		if rate, err := e.source.EstimateSmartFee(target); err == nil {
+			estimate.SmartFeeRate = rate
+		}
+
+		estimate.FeeRate, estimate.Source = e.floorRate, FeeSourceFloor
+		if estimate.SmartFeeRate > estimate.FeeRate {
+			estimate.FeeRate, estimate.Source = estimate.SmartFeeRate, FeeSourceSmart
+		}
+		if estimate.MempoolFeeRate > estimate.FeeRate {
+			estimate.FeeRate, estimate.Source = estimate.MempoolFeeRate, FeeSourceMempool
+		}
+		if estimate.FeeRate > e.ceilingRate {
+			estimate.FeeRate = e.ceilingRate
+		}
+
+		// Waiting longer never costs more
+		if n := len(estimates.Estimates); n > 0 && estimate.FeeRate > estimates.Estimates[n-1].FeeRate {
+			estimate.FeeRate = estimates.Estimates[n-1].FeeRate
+		}
+		estimate.WithdrawalFee = estimate.FeeRate * TypicalWithdrawalVSize
+		estimates.Estimates = append(estimates.Estimates, estimate)
+	}
+
+	return estimates, nil
+}
+
+// mempoolFeeRate is the rate a transaction needs to be mined within target
+// blocks if the mempool were mined best paying first: the rate of the
+// transaction at the edge of the first target blocks' worth of vbytes. It is
+// 0 when the whole mempool fits. Ancestor packages are not taken into
+// account.
+func mempoolFeeRate(entries []MempoolEntry, target int64) int64 {
+	rates := make([]MempoolEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.VSize > 0 {
+			rates = append(rates, entry)
+		}
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].Fee*rates[j].VSize > rates[j].Fee*rates[i].VSize
+	})
+
+	capacity := target * blockVSize
+	var filled int64
+	for _, entry := range rates {
+		filled += entry.VSize
+		if filled > capacity {
+			// Outbid the transaction left out, rounding up
+			return (entry.Fee+entry.VSize-1)/entry.VSize + 1
+		}
+	}
+	return 0
+}
+
+// feeHistogram buckets the mempool by fee rate
+func feeHistogram(entries []MempoolEntry) []FeeHistogramBin {
+	bins := make([]FeeHistogramBin, len(feeHistogramEdges))
+	for i, edge := range feeHistogramEdges {
+		bins[i].MinRate = edge
+	}
+	for _, entry := range entries {
+		if entry.VSize <= 0 {
+			continue
+		}
+		rate := entry.Fee / entry.VSize
+		i := sort.Search(len(feeHistogramEdges), func(i int) bool { return feeHistogramEdges[i] > rate }) - 1
+		if i < 0 {
+			i = 0
+		}
+		bins[i].Count++
+		bins[i].VSize += entry.VSize
+	}
+	return bins
+}
+
+// btcPerKvBToSatPerVB converts a BTC/kvB fee rate to sat/vB, rounding up
+func btcPerKvBToSatPerVB(rate float64) int64 {
+	return int64(math.Ceil(rate*1e5 - 1e-9))
+}
@@ -0,0 +1,170 @@
+package bitcoin
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fixtureFeeSource serves fixed smart estimates and mempool
+type fixtureFeeSource struct {
+	smart   map[int64]int64
+	mempool []MempoolEntry
+	fail    bool
+	calls   int
+}
+
+func (s *fixtureFeeSource) EstimateSmartFee(target int64) (int64, error) {
+	rate, ok := s.smart[target]
+	if !ok {
+		return 0, errors.New("insufficient data")
+	}
+	return rate, nil
+}
+
+func (s *fixtureFeeSource) GetMempoolEntries() ([]MempoolEntry, error) {
+	s.calls++
+	if s.fail {
+		return nil, errors.New("connection refused")
+	}
+	return s.mempool, nil
+}
+
+// backlog is a mempool of vsize vbytes paying rate sat/vB
+func backlog(rate, vsize int64) []MempoolEntry {
+	var entries []MempoolEntry
+	for ; vsize > 0; vsize -= 100000 {
+		entries = append(entries, MempoolEntry{VSize: 100000, Fee: rate * 100000})
+	}
+	return entries
+}
+
+func TestFeeEstimatorCombinesSources(t *testing.T) {
+	// Two blocks at 40 sat/vB and one at 10 waiting
+	source := &fixtureFeeSource{
+		smart:   map[int64]int64{1: 25, 3: 12, 6: 30},
+		mempool: append(backlog(40, 2000000), backlog(10, 1000000)...),
+	}
+	estimator, err := NewFeeEstimator(FeeEstimatorConfig{
+		Source:      source,
+		Targets:     []int64{6, 1, 3, 144},
+		FloorRate:   2,
+		CeilingRate: 30,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create fee estimator: %v", err)
+	}
+
+	estimates, err := estimator.Estimates()
+	if err != nil {
+		t.Fatalf("Estimates failed: %v", err)
+	}
+
+	expected := []struct {
+		target  int64
+		feeRate int64
+		source  string
+	}{
+		{1, 30, FeeSourceMempool}, // the backlog outbids the node, capped at the ceiling
+		{3, 12, FeeSourceSmart},   // the whole backlog fits in 3 blocks
+		{6, 12, FeeSourceSmart},   // never more than a shorter target
+		{144, 2, FeeSourceFloor},  // no smart estimate and no backlog
+	}
+	if len(estimates.Estimates) != len(expected) {
+		t.Fatalf("Expected %d estimates, got %+v", len(expected), estimates.Estimates)
+	}
+	for i, want := range expected {
+		got := estimates.Estimates[i]
+		if got.Target != want.target || got.FeeRate != want.feeRate || got.Source != want.source {
+			t.Errorf("Expected %d blocks at %d sat/vB from %s, got %+v", want.target, want.feeRate, want.source, got)
+		}
+		if got.WithdrawalFee != got.FeeRate*TypicalWithdrawalVSize {
+			t.Errorf("Expected a %d sat withdrawal fee, got %d", got.FeeRate*TypicalWithdrawalVSize, got.WithdrawalFee)
+		}
+	}
+	if estimates.Estimates[0].MempoolFeeRate != 41 {
+		t.Errorf("Expected the mempool to ask 41 sat/vB for the next block, got %d", estimates.Estimates[0].MempoolFeeRate)
+	}
+
+	if estimates.MempoolCount != 30 || estimates.MempoolVSize != 3000000 {
+		t.Errorf("Expected 30 transactions of 3000000 vB, got %d of %d", estimates.MempoolCount, estimates.MempoolVSize)
+	}
+	for _, bin := range estimates.Histogram {
+		switch bin.MinRate {
+		case 40:
+			if bin.Count != 20 {
+				t.Errorf("Expected 20 transactions at 40 sat/vB, got %d", bin.Count)
+			}
+		case 10:
+			if bin.Count != 10 {
+				t.Errorf("Expected 10 transactions at 10 sat/vB, got %d", bin.Count)
+			}
+		default:
+			if bin.Count != 0 {
+				t.Errorf("Expected no transactions from %d sat/vB, got %d", bin.MinRate, bin.Count)
+			}
+		}
+	}
+
+	// Targets between the configured ones round up
+	rate, err := estimator.FeeRate(2)
+	if err != nil || rate != 12 {
+		t.Errorf("Expected 12 sat/vB within 2 blocks, got %d: %v", rate, err)
+	}
+	rate, err = estimator.FeeRate(1008)
+	if err != nil || rate != 2 {
+		t.Errorf("Expected the longest target's 2 sat/vB, got %d: %v", rate, err)
+	}
+}
+
+func TestFeeEstimatorCachesEstimates(t *testing.T) {
+	source := &fixtureFeeSource{smart: map[int64]int64{6: 8}}
+	estimator, err := NewFeeEstimator(FeeEstimatorConfig{Source: source, Targets: []int64{6}, CacheTTL: time.Hour})
+	if err != nil {
+		t.Fatalf("Failed to create fee estimator: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := estimator.FeeRate(6); err != nil {
+			t.Fatalf("FeeRate failed: %v", err)
+		}
+	}
+	if source.calls != 1 {
+		t.Errorf("Expected one node query within the cache TTL, got %d", source.calls)
+	}
+
+	// An expired cache is refreshed, and served stale when the node is down
+	estimator.cached.UpdatedAt = time.Now().Add(-2 * time.Hour)
+	source.fail = true
+	rate, err := estimator.FeeRate(6)
+	if err != nil || rate != 8 {
+		t.Errorf("Expected the stale 8 sat/vB, got %d: %v", rate, err)
+	}
+	if source.calls != 2 {
+		t.Errorf("Expected a refresh attempt after expiry, got %d queries", source.calls)
+	}
+
+	fresh, err := NewFeeEstimator(FeeEstimatorConfig{Source: source})
+	if err != nil {
+		t.Fatalf("Failed to create fee estimator: %v", err)
+	}
+	if _, err := fresh.Estimates(); err == nil {
+		t.Error("Expected an error without any estimates to fall back on")
+	}
+}
+
+func TestBTCPerKvBToSatPerVB(t *testing.T) {
+	for _, tc := range []struct {
+		rate float64
+		want int64
+	}{
+		{0.00001, 1},
+		{0.00001001, 2},
+		{0.0002, 20},
+		{0.00012345, 13},
+	} {
+		if got := btcPerKvBToSatPerVB(tc.rate); got != tc.want {
+			t.Errorf("btcPerKvBToSatPerVB(%v) = %d, want %d", tc.rate, got, tc.want)
+		}
+	}
+}
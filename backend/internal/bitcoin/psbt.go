@@ -0,0 +1,379 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// BIP174 key types used by the bridge. Other key-value pairs are carried
+// through unchanged so that signers can add their own.
+const (
+	psbtGlobalUnsignedTx = 0x00
+
+	psbtInWitnessUtxo        = 0x01
+	psbtInPartialSig         = 0x02
+	psbtInFinalScriptSig     = 0x07
+	psbtInFinalScriptWitness = 0x08
+	psbtInTaprootKeySig      = 0x13
+)
+
+// psbtMaxValue bounds a single key or value while parsing
+const psbtMaxValue = 4_000_000
+
+var psbtMagic = []byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+// ErrIncompletePSBT is returned when a PSBT is finalized or extracted before
+// every input carries the signatures it needs
+var ErrIncompletePSBT = errors.New("psbt is not fully signed")
+
+// PSBT is a BIP174 partially signed bitcoin transaction
+type PSBT struct {
+	UnsignedTx *wire.MsgTx
+	Inputs     []PSBTInput
+	Outputs    []PSBTOutput
+	Unknown    []PSBTPair // global pairs other than the unsigned transaction
+}
+
+// PSBTInput holds the per-input fields
+type PSBTInput struct {
+	WitnessUtxo        *wire.TxOut
+	PartialSigs        []PartialSig
+	TaprootKeySig      []byte
+	FinalScriptSig     []byte
+	FinalScriptWitness wire.TxWitness
+	Unknown            []PSBTPair
+}
+
+// PSBTOutput holds the per-output fields
+type PSBTOutput struct {
+	Unknown []PSBTPair
+}
+
+// PartialSig is an ECDSA signature for one public key of an input
+type PartialSig struct {
+	PubKey    []byte
+	Signature []byte // DER with the sighash type appended
+}
+
+// PSBTPair is a raw key-value pair; the key includes its type byte
+type PSBTPair struct {
+	Key   []byte
+	Value []byte
+}
+
+// NewPSBT creates a PSBT for an unsigned transaction
+func NewPSBT(tx *wire.MsgTx) (*PSBT, error) {
+	for i, in := range tx.TxIn {
+		if len(in.SignatureScript) > 0 || len(in.Witness) > 0 {
+			return nil, fmt.Errorf("input %d is already signed", i)
+		}
+	}
+	return &PSBT{
+		UnsignedTx: tx,
+		Inputs:     make([]PSBTInput, len(tx.TxIn)),
+		Outputs:    make([]PSBTOutput, len(tx.TxOut)),
+	}, nil
+}
+
+// ParsePSBT decodes a binary PSBT
+func ParsePSBT(data []byte) (*PSBT, error) {
+	r := bytes.NewReader(data)
+
+	magic := make([]byte, len(psbtMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || !bytes.Equal(magic, psbtMagic) {
+		return nil, fmt.Errorf("invalid psbt magic")
+	}
+
+	global, err := readPSBTMap(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid psbt global map: %w", err)
+	}
+
+	p := &PSBT{}
+	for _, pair := range global {
+		if pair.Key[0] != psbtGlobalUnsignedTx {
+			p.Unknown = append(p.Unknown, pair)
+			continue
+		}
+		if len(pair.Key) != 1 {
+			return nil, fmt.Errorf("invalid psbt unsigned transaction key")
+		}
+		tx := wire.NewMsgTx(wire.TxVersion)
+		if err := tx.DeserializeNoWitness(bytes.NewReader(pair.Value)); err != nil {
+			return nil, fmt.Errorf("invalid psbt unsigned transaction: %w", err)
+		}
+		p.UnsignedTx = tx
+	}
+	if p.UnsignedTx == nil {
+		return nil, fmt.Errorf("psbt has no unsigned transaction")
+	}
+
+	p.Inputs = make([]PSBTInput, len(p.UnsignedTx.TxIn))
+	for i := range p.Inputs {
+		pairs, err := readPSBTMap(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid psbt input %d: %w", i, err)
+		}
+		if err := p.Inputs[i].decode(pairs); err != nil {
+			return nil, fmt.Errorf("invalid psbt input %d: %w", i, err)
+		}
+	}
+
+	p.Outputs = make([]PSBTOutput, len(p.UnsignedTx.TxOut))
+	for i := range p.Outputs {
+		pairs, err := readPSBTMap(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid psbt output %d: %w", i, err)
+		}
+		p.Outputs[i].Unknown = pairs
+	}
+
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("invalid psbt: %d trailing bytes", r.Len())
+	}
+	return p, nil
+}
+
+// ParsePSBTBase64 decodes a base64 PSBT as used by the node RPC
+func ParsePSBTBase64(encoded string) (*PSBT, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid psbt encoding: %w", err)
+	}
+	return ParsePSBT(data)
+}
+
+// Serialize encodes the PSBT in the BIP174 binary format
+func (p *PSBT) Serialize() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(psbtMagic)
+
+	var tx bytes.Buffer
+	if err := p.UnsignedTx.SerializeNoWitness(&tx); err != nil {
+		return nil, err
+	}
+	global := append([]PSBTPair{{Key: []byte{psbtGlobalUnsignedTx}, Value: tx.Bytes()}}, p.Unknown...)
+	if err := writePSBTMap(&buf, global); err != nil {
+		return nil, err
+	}
+
+	for i := range p.Inputs {
+		pairs, err := p.Inputs[i].encode()
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %w", i, err)
+		}
+		if err := writePSBTMap(&buf, pairs); err != nil {
+			return nil, err
+		}
+	}
+	for i := range p.Outputs {
+		if err := writePSBTMap(&buf, p.Outputs[i].Unknown); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Base64 encodes the PSBT for the node RPC
+func (p *PSBT) Base64() (string, error) {
+	data, err := p.Serialize()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// PrevOutputs returns the outputs spent by the inputs, keyed by outpoint
+func (p *PSBT) PrevOutputs() (map[wire.OutPoint]*wire.TxOut, error) {
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut, len(p.Inputs))
+	for i, in := range p.Inputs {
+		if in.WitnessUtxo == nil {
+			return nil, fmt.Errorf("input %d has no witness utxo", i)
+		}
+		prevOuts[p.UnsignedTx.TxIn[i].PreviousOutPoint] = in.WitnessUtxo
+	}
+	return prevOuts, nil
+}
+
+// Finalize turns the signatures of each input into its final witness.
+// Inputs that a signer already finalized are left alone.
+func (p *PSBT) Finalize() error {
+	for i := range p.Inputs {
+		in := &p.Inputs[i]
+		if in.isFinal() {
+			continue
+		}
+		if in.WitnessUtxo == nil {
+			return fmt.Errorf("input %d has no witness utxo", i)
+		}
+
+		switch txscript.GetScriptClass(in.WitnessUtxo.PkScript) {
+		case txscript.WitnessV0PubKeyHashTy:
+			if len(in.PartialSigs) != 1 {
+				return fmt.Errorf("%w: input %d", ErrIncompletePSBT, i)
+			}
+			sig := in.PartialSigs[0]
+			in.FinalScriptWitness = wire.TxWitness{sig.Signature, sig.PubKey}
+		case txscript.WitnessV1TaprootTy:
+			if len(in.TaprootKeySig) == 0 {
+				return fmt.Errorf("%w: input %d", ErrIncompletePSBT, i)
+			}
+			in.FinalScriptWitness = wire.TxWitness{in.TaprootKeySig}
+		default:
+			return fmt.Errorf("input %d: cannot finalize output script %x", i, in.WitnessUtxo.PkScript)
+		}
+
+		// BIP174 finalizers drop everything but the UTXO and final fields
+		in.PartialSigs = nil
+		in.TaprootKeySig = nil
+		in.Unknown = nil
+	}
+	return nil
+}
+
+// Extract returns the signed transaction of a finalized PSBT
+func (p *PSBT) Extract() (*wire.MsgTx, error) {
+	tx := p.UnsignedTx.Copy()
+	for i, in := range p.Inputs {
+		if !in.isFinal() {
+			return nil, fmt.Errorf("%w: input %d", ErrIncompletePSBT, i)
+		}
+		tx.TxIn[i].SignatureScript = in.FinalScriptSig
+		tx.TxIn[i].Witness = in.FinalScriptWitness
+	}
+	return tx, nil
+}
+
+func (in *PSBTInput) isFinal() bool {
+	return len(in.FinalScriptSig) > 0 || len(in.FinalScriptWitness) > 0
+}
+
+func (in *PSBTInput) decode(pairs []PSBTPair) error {
+	for _, pair := range pairs {
+		keyType, keyData := pair.Key[0], pair.Key[1:]
+		switch keyType {
+		case psbtInWitnessUtxo:
+			out := &wire.TxOut{}
+			if err := wire.ReadTxOut(bytes.NewReader(pair.Value), 0, 0, out); err != nil {
+				return fmt.Errorf("invalid witness utxo: %w", err)
+			}
+			in.WitnessUtxo = out
+		case psbtInPartialSig:
+			in.PartialSigs = append(in.PartialSigs, PartialSig{PubKey: keyData, Signature: pair.Value})
+		case psbtInTaprootKeySig:
+			in.TaprootKeySig = pair.Value
+		case psbtInFinalScriptSig:
+			in.FinalScriptSig = pair.Value
+		case psbtInFinalScriptWitness:
+			witness, err := readWitness(pair.Value)
+			if err != nil {
+				return fmt.Errorf("invalid final script witness: %w", err)
+			}
+			in.FinalScriptWitness = witness
+		default:
+			in.Unknown = append(in.Unknown, pair)
+		}
+	}
+	return nil
+}
+
+func (in *PSBTInput) encode() ([]PSBTPair, error) {
+	var pairs []PSBTPair
+	if in.WitnessUtxo != nil {
+		var buf bytes.Buffer
+		if err := wire.WriteTxOut(&buf, 0, 0, in.WitnessUtxo); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, PSBTPair{Key: []byte{psbtInWitnessUtxo}, Value: buf.Bytes()})
+	}
+	for _, sig := range in.PartialSigs {
+		pairs = append(pairs, PSBTPair{Key: append([]byte{psbtInPartialSig}, sig.PubKey...), Value: sig.Signature})
+	}
+	if len(in.FinalScriptSig) > 0 {
+		pairs = append(pairs, PSBTPair{Key: []byte{psbtInFinalScriptSig}, Value: in.FinalScriptSig})
+	}
+	if len(in.FinalScriptWitness) > 0 {
+		var buf bytes.Buffer
+		if err := wire.WriteVarInt(&buf, 0, uint64(len(in.FinalScriptWitness))); err != nil {
+			return nil, err
+		}
+		for _, item := range in.FinalScriptWitness {
+			if err := wire.WriteVarBytes(&buf, 0, item); err != nil {
+				return nil, err
+			}
+		}
+		pairs = append(pairs, PSBTPair{Key: []byte{psbtInFinalScriptWitness}, Value: buf.Bytes()})
+	}
+	if len(in.TaprootKeySig) > 0 {
+		pairs = append(pairs, PSBTPair{Key: []byte{psbtInTaprootKeySig}, Value: in.TaprootKeySig})
+	}
+	return append(pairs, in.Unknown...), nil
+}
+
+// readPSBTMap reads key-value pairs up to the 0x00 separator, rejecting
+// duplicate keys
+func readPSBTMap(r *bytes.Reader) ([]PSBTPair, error) {
+	var pairs []PSBTPair
+	seen := make(map[string]bool)
+	for {
+		key, err := wire.ReadVarBytes(r, 0, psbtMaxValue, "psbt key")
+		if err != nil {
+			return nil, err
+		}
+		if len(key) == 0 {
+			return pairs, nil
+		}
+		if seen[string(key)] {
+			return nil, fmt.Errorf("duplicate key %x", key)
+		}
+		seen[string(key)] = true
+
+		value, err := wire.ReadVarBytes(r, 0, psbtMaxValue, "psbt value")
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, PSBTPair{Key: key, Value: value})
+	}
+}
+
+func writePSBTMap(w io.Writer, pairs []PSBTPair) error {
+	for _, pair := range pairs {
+		if err := wire.WriteVarBytes(w, 0, pair.Key); err != nil {
+			return err
+		}
+		if err := wire.WriteVarBytes(w, 0, pair.Value); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write([]byte{0x00})
+	return err
+}
+
+func readWitness(data []byte) (wire.TxWitness, error) {
+	r := bytes.NewReader(data)
+	count, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	if count > uint64(len(data)) {
+		return nil, fmt.Errorf("witness item count %d too large", count)
+	}
+
+	witness := make(wire.TxWitness, count)
+	for i := range witness {
+		if witness[i], err = wire.ReadVarBytes(r, 0, psbtMaxValue, "witness item"); err != nil {
+			return nil, err
+		}
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after witness", r.Len())
+	}
+	return witness, nil
+}
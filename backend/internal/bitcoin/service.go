@@ -3,19 +3,30 @@ package bitcoin
 import (
 	"fmt"
 	"log"
-	"strings"
+	"sync"
 
+	"bitbridge/internal/store"
 	"bitbridge/pkg/config"
 	"bitbridge/pkg/types"
+
+	"github.com/btcsuite/btcd/btcutil"
 )
 
 type Service struct {
 	client           *Client
 	config           *config.BitcoinConfig
-	depositAddresses map[string]bool
+	addressStore     store.AddressRepository
+	deriver          *AddressDeriver
+	depositAddresses map[string]bool // guarded by mu, with addressCallbacks
+	addressCallbacks []AddressCallback
+	mu               sync.RWMutex
 }
 
-func NewService(cfg *config.BitcoinConfig) (*Service, error) {
+// AddressCallback is invoked whenever the service starts tracking a new
+// deposit or change address, so that monitors can begin watching it.
+type AddressCallback func(address string)
+
+func NewService(cfg *config.BitcoinConfig, dataStore store.Store) (*Service, error) {
 	client, err := NewClient(Config{
 		Host:     cfg.RPCHost,
 		Port:     cfg.RPCPort,
@@ -36,9 +47,71 @@ func NewService(cfg *config.BitcoinConfig) (*Service, error) {
 	service := &Service{
 		client:           client,
 		config:           cfg,
+		addressStore:     dataStore,
 		depositAddresses: make(map[string]bool),
 	}
 
+	if cfg.DepositAccountKey != "" {
+		service.deriver, err = NewAddressDeriver(DeriverConfig{
+			AccountKey: cfg.DepositAccountKey,
+			ScriptType: ScriptType(cfg.DepositScriptType),
+			Network:    client.GetNetworkParams(),
+			Store:      dataStore,
+			GapLimit:   uint32(cfg.DepositGapLimit),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up deposit address derivation: %v", err)
+		}
+		log.Printf("Deriving %s deposit addresses from the configured account key", service.deriver.ScriptType())
+	}
+
+	// Restore deposit addresses tracked before the last restart
+	addresses, err := dataStore.ListWatchedAddresses()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load deposit addresses: %v", err)
+	}
+	change := make(map[string]bool)
+	if service.deriver != nil {
+		for _, address := range service.deriver.ChangeAddresses() {
+			change[address] = true
+		}
+	}
+	for _, address := range addresses {
+		if !change[address] {
+			service.depositAddresses[address] = true
+		}
+	}
+
+	// Deposits to the shared memo address name their recipient on-chain
+	if cfg.MemoAddress != "" {
+		if err := client.WatchAddress(cfg.MemoAddress); err != nil {
+			return nil, fmt.Errorf("invalid memo address: %v", err)
+		}
+		if err := dataStore.AddWatchedAddress(cfg.MemoAddress); err != nil {
+			return nil, fmt.Errorf("failed to persist memo address: %v", err)
+		}
+		service.depositAddresses[cfg.MemoAddress] = true
+		log.Printf("Accepting OP_RETURN memo deposits to %s", cfg.MemoAddress)
+	}
+
+	// Derived addresses can be recomputed even if the watch list was lost
+	if service.deriver != nil {
+		for _, address := range service.deriver.Addresses() {
+			if service.depositAddresses[address] {
+				continue
+			}
+			if err := dataStore.AddWatchedAddress(address); err != nil {
+				return nil, fmt.Errorf("failed to persist deposit address: %v", err)
+			}
+			service.depositAddresses[address] = true
+		}
+		for address := range change {
+			if err := dataStore.AddWatchedAddress(address); err != nil {
+				return nil, fmt.Errorf("failed to persist change address: %v", err)
+			}
+		}
+	}
+
 	log.Printf("Bitcoin service initialized for network: %s", cfg.Network)
 	return service, nil
 }
@@ -62,16 +135,111 @@ func (s *Service) Stop() {
 	s.client.Close()
 }
 
-func (s *Service) GenerateDepositAddress() (string, error) {
-	address, err := s.client.GenerateDepositAddress()
+// GenerateDepositAddress returns the deposit address for an Ethereum
+// recipient. With an account key configured the address is derived and
+// always the same for a recipient; otherwise the node wallet hands out a new
+// address and the recipient is not recorded.
+func (s *Service) GenerateDepositAddress(recipient string) (string, error) {
+	if s.deriver == nil {
+		address, err := s.client.GenerateDepositAddress()
+		if err != nil {
+			return "", err
+		}
+		return address, s.trackDepositAddress(address)
+	}
+
+	if recipient == "" {
+		return "", fmt.Errorf("recipient is required for derived deposit addresses")
+	}
+	address, index, err := s.deriver.AddressFor(recipient)
 	if err != nil {
 		return "", err
 	}
+	if s.isDepositAddress(address) {
+		return address, nil
+	}
+
+	// Import into the node wallet as watch-only for GetAddressUTXOs
+	if err := s.client.WatchAddress(address); err != nil {
+		log.Printf("Warning: failed to watch address %s: %v", address, err)
+	}
+	log.Printf("Derived deposit address %s at %s for %s", address, s.deriver.Path(index), recipient)
+	return address, s.trackDepositAddress(address)
+}
+
+// ReleaseDepositAddress stops counting a derived deposit address that never
+// received funds against the gap limit, once its deposit intent expired.
+// The address stays watched and assigned to its recipient.
+func (s *Service) ReleaseDepositAddress(address string) error {
+	if s.deriver == nil {
+		return nil
+	}
+	if err := s.deriver.Release(address); err != nil {
+		return fmt.Errorf("failed to release deposit address: %v", err)
+	}
+	return nil
+}
+
+// ChangeAddress derives a new change address for a withdrawal on the
+// internal chain of the deposit account and starts watching it, so that the
+// change can be spent by later withdrawals
+func (s *Service) ChangeAddress() (string, error) {
+	if s.deriver == nil {
+		return "", fmt.Errorf("change addresses require a deposit account key")
+	}
 
+	address, index, err := s.deriver.ChangeAddress()
+	if err != nil {
+		return "", err
+	}
+	if err := s.client.WatchAddress(address); err != nil {
+		log.Printf("Warning: failed to watch change address %s: %v", address, err)
+	}
+	if err := s.addressStore.AddWatchedAddress(address); err != nil {
+		return "", fmt.Errorf("failed to persist change address: %v", err)
+	}
+
+	log.Printf("Derived change address %s at %s", address, s.deriver.ChangePath(index))
+	s.notifyAddressCallbacks(address)
+	return address, nil
+}
+
+// ChangeScriptType returns the script type of change addresses, empty
+// without an account key
+func (s *Service) ChangeScriptType() ScriptType {
+	if s.deriver == nil {
+		return ""
+	}
+	return s.deriver.ScriptType()
+}
+
+// MemoAddress returns the shared deposit address for OP_RETURN memo
+// deposits, empty if memo deposits are disabled
+func (s *Service) MemoAddress() string {
+	return s.config.MemoAddress
+}
+
+// RecipientOf returns the Ethereum recipient a derived deposit address was
+// assigned to
+func (s *Service) RecipientOf(address string) (string, bool) {
+	if s.deriver == nil {
+		return "", false
+	}
+	return s.deriver.RecipientOf(address)
+}
+
+func (s *Service) trackDepositAddress(address string) error {
+	if err := s.addressStore.AddWatchedAddress(address); err != nil {
+		return fmt.Errorf("failed to persist deposit address: %v", err)
+	}
+
+	s.mu.Lock()
 	s.depositAddresses[address] = true
+	s.mu.Unlock()
 	log.Printf("Generated new deposit address: %s", address)
+	s.notifyAddressCallbacks(address)
 	
-	return address, nil
+	return nil
 }
 
 func (s *Service) WatchAddress(address string) error {
@@ -80,10 +248,46 @@ func (s *Service) WatchAddress(address string) error {
 		return err
 	}
 
+	if err := s.addressStore.AddWatchedAddress(address); err != nil {
+		return fmt.Errorf("failed to persist watched address: %v", err)
+	}
+
+	s.mu.Lock()
 	s.depositAddresses[address] = true
+	s.mu.Unlock()
+	s.notifyAddressCallbacks(address)
 	return nil
 }
 
+// isDepositAddress reports whether address is a tracked deposit address
+func (s *Service) isDepositAddress(address string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.depositAddresses[address]
+}
+
+// AddAddressCallback registers a callback for newly tracked deposit addresses
+func (s *Service) AddAddressCallback(callback AddressCallback) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addressCallbacks = append(s.addressCallbacks, callback)
+}
+
+func (s *Service) notifyAddressCallbacks(address string) {
+	s.mu.RLock()
+	callbacks := s.addressCallbacks
+	s.mu.RUnlock()
+
+	for _, callback := range callbacks {
+		callback(address)
+	}
+}
+
+// GetClient returns the underlying Bitcoin RPC client
+func (s *Service) GetClient() *Client {
+	return s.client
+}
+
 func (s *Service) GetAddressUTXOs(address string) ([]*types.UTXO, error) {
 	// Direct RPC call without monitor
 	utxoInfos, err := s.client.GetAddressUTXOs(address)
@@ -96,7 +300,7 @@ func (s *Service) GetAddressUTXOs(address string) ([]*types.UTXO, error) {
 		utxo := &types.UTXO{
 			TxID:         info.TxID,
 			Vout:         info.Vout,
-			Amount:       int64(info.Amount * 100000000), // Convert to satoshis
+			Amount:       amountSats(info.Amount),
 			Address:      info.Address,
 			ScriptPubKey: info.ScriptPubKey,
 			Confirmations: int(info.Confirmations),
@@ -135,7 +339,7 @@ func (s *Service) GetAllWatchedUTXOs() []*types.UTXO {
 	var allUTXOs []*types.UTXO
 	
 	// Get UTXOs for all watched addresses
-	for address := range s.depositAddresses {
+	for _, address := range s.GetDepositAddresses() {
 		utxos, err := s.GetAddressUTXOs(address)
 		if err != nil {
 			log.Printf("Error getting UTXOs for address %s: %v", address, err)
@@ -164,7 +368,9 @@ func (s *Service) ValidateTransaction(txid string, vout uint32, expectedAmount i
 	return nil
 }
 
-func (s *Service) SendBitcoin(toAddress string, amount float64) (string, error) {
+// SendBitcoin pays amount satoshis from the node wallet. Withdrawals go
+// through the WithdrawalBuilder instead.
+func (s *Service) SendBitcoin(toAddress string, amount int64) (string, error) {
 	if s.config.Network == "mainnet" {
 		return "", fmt.Errorf("Bitcoin sending disabled on mainnet for safety")
 	}
@@ -174,11 +380,14 @@ func (s *Service) SendBitcoin(toAddress string, amount float64) (string, error)
 		return "", err
 	}
 
-	log.Printf("Sent %.8f BTC to %s, txid: %s", amount, toAddress, txid)
+	log.Printf("Sent %d sats to %s, txid: %s", amount, toAddress, txid)
 	return txid, nil
 }
 
 func (s *Service) GetDepositAddresses() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	addresses := make([]string, 0, len(s.depositAddresses))
 	for addr := range s.depositAddresses {
 		addresses = append(addresses, addr)
@@ -186,17 +395,16 @@ func (s *Service) GetDepositAddresses() []string {
 	return addresses
 }
 
+// IsValidBitcoinAddress reports whether address decodes for the configured
+// network
 func (s *Service) IsValidBitcoinAddress(address string) bool {
-	// Basic validation - check if it's a valid format for the network
-	switch s.config.Network {
-	case "mainnet":
-		return strings.HasPrefix(address, "1") || strings.HasPrefix(address, "3") || strings.HasPrefix(address, "bc1")
-	case "testnet":
-		return strings.HasPrefix(address, "m") || strings.HasPrefix(address, "n") || strings.HasPrefix(address, "2") || strings.HasPrefix(address, "tb1")
-	case "regtest":
-		return strings.HasPrefix(address, "m") || strings.HasPrefix(address, "n") || strings.HasPrefix(address, "2") || strings.HasPrefix(address, "bcrt1")
-	}
-	return false
+	_, err := s.ParseAddress(address)
+	return err == nil
+}
+
+// ParseAddress decodes an address for the configured network
+func (s *Service) ParseAddress(address string) (*AddressInfo, error) {
+	return ParseAddress(address, s.client.GetNetworkParams())
 }
 
 // handleUTXOEvent would be called by external monitoring system
@@ -205,14 +413,26 @@ func (s *Service) HandleUTXOEvent(utxo *types.UTXO, event string) {
 		event, utxo.TxID, utxo.Vout, float64(utxo.Amount)/100000000, utxo.Confirmations)
 
 	// Check if this is a deposit to one of our watched addresses
-	if s.depositAddresses[utxo.Address] {
+	if s.isDepositAddress(utxo.Address) && !utxo.Change {
+		// Token creation itself is driven by the bridge orchestrator, which
+		// subscribes to the same monitor events.
 		if event == "new" {
 			log.Printf("New deposit detected! UTXO: %s:%d", utxo.TxID, utxo.Vout)
-			// TODO: Trigger token creation process
+			if utxo.Address == s.config.MemoAddress {
+				if utxo.Recipient != "" {
+					log.Printf("Memo deposit %s:%d names recipient %s", utxo.TxID, utxo.Vout, utxo.Recipient)
+				} else {
+					log.Printf("Memo deposit %s:%d is unattributed: %s", utxo.TxID, utxo.Vout, utxo.MemoError)
+				}
+			}
+			if s.deriver != nil {
+				if err := s.deriver.MarkUsed(utxo.Address); err != nil {
+					log.Printf("Warning: failed to mark deposit address %s used: %v", utxo.Address, err)
+				}
+			}
 		} else if event == "confirmation_update" && utxo.Confirmations >= 3 {
 			log.Printf("Deposit confirmed! UTXO: %s:%d (%d confirmations)", 
 				utxo.TxID, utxo.Vout, utxo.Confirmations)
-			// TODO: Finalize token creation if not already done
 		}
 	}
 }
@@ -229,4 +449,14 @@ func (s *Service) GetNetworkInfo() (string, int64, error) {
 	}
 
 	return network, blockCount, nil
-}
\ No newline at end of file
+}
+
+// amountSats converts a BTC amount reported by the node to satoshis,
+// rounding rather than truncating the float
+func amountSats(btc float64) int64 {
+	amount, err := btcutil.NewAmount(btc)
+	if err != nil {
+		return 0
+	}
+	return int64(amount)
+}
@@ -0,0 +1,124 @@
+package bitcoin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// PSBTSigner adds signatures to the inputs of a PSBT it holds keys for. It
+// may also finalize them; the builder finalizes whatever is left.
+type PSBTSigner interface {
+	SignPSBT(ctx context.Context, packet *PSBT) (*PSBT, error)
+}
+
+// NodeWalletSigner signs with the keys of the node wallet through
+// walletprocesspsbt. Deposits to derived addresses can only be spent if the
+// wallet holds the account's private descriptor.
+type NodeWalletSigner struct {
+	client *Client
+}
+
+// NewNodeWalletSigner creates a signer backed by the node wallet
+func NewNodeWalletSigner(client *Client) *NodeWalletSigner {
+	return &NodeWalletSigner{client: client}
+}
+
+// SignPSBT implements PSBTSigner
+func (s *NodeWalletSigner) SignPSBT(ctx context.Context, packet *PSBT) (*PSBT, error) {
+	encoded, err := packet.Base64()
+	if err != nil {
+		return nil, err
+	}
+
+	signed, complete, err := s.client.ProcessPSBT(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if !complete {
+		return nil, fmt.Errorf("%w: the node wallet lacks keys for some inputs", ErrIncompletePSBT)
+	}
+	return ParsePSBTBase64(signed)
+}
+
+// KeyPSBTSigner signs P2WPKH and BIP86 key-path P2TR inputs with private
+// keys held in memory. It is meant for regtest setups and tests; production
+// keys belong in the node wallet or an external signer.
+type KeyPSBTSigner struct {
+	keys map[string]*btcec.PrivateKey // output script -> key
+}
+
+// NewKeyPSBTSigner creates a signer for the outputs paying to keys
+func NewKeyPSBTSigner(keys ...*btcec.PrivateKey) (*KeyPSBTSigner, error) {
+	s := &KeyPSBTSigner{keys: make(map[string]*btcec.PrivateKey)}
+	for _, key := range keys {
+		pubKey := key.PubKey()
+
+		wpkh, err := txscript.NewScriptBuilder().
+			AddOp(txscript.OP_0).
+			AddData(btcutil.Hash160(pubKey.SerializeCompressed())).
+			Script()
+		if err != nil {
+			return nil, err
+		}
+		tr, err := txscript.PayToTaprootScript(txscript.ComputeTaprootKeyNoScript(pubKey))
+		if err != nil {
+			return nil, err
+		}
+
+		s.keys[string(wpkh)] = key
+		s.keys[string(tr)] = key
+	}
+	return s, nil
+}
+
+// SignPSBT implements PSBTSigner
+func (s *KeyPSBTSigner) SignPSBT(ctx context.Context, packet *PSBT) (*PSBT, error) {
+	prevOuts, err := packet.PrevOutputs()
+	if err != nil {
+		return nil, err
+	}
+	tx := packet.UnsignedTx
+	sigHashes := txscript.NewTxSigHashes(tx, txscript.NewMultiPrevOutFetcher(prevOuts))
+
+	for i := range packet.Inputs {
+		in := &packet.Inputs[i]
+		utxo := in.WitnessUtxo
+		key, ok := s.keys[string(utxo.PkScript)]
+		if !ok || in.isFinal() {
+			continue
+		}
+
+		switch txscript.GetScriptClass(utxo.PkScript) {
+		case txscript.WitnessV0PubKeyHashTy:
+			sig, err := txscript.RawTxInWitnessSignature(tx, sigHashes, i, utxo.Value, utxo.PkScript, txscript.SigHashAll, key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to sign input %d: %w", i, err)
+			}
+			pubKey := key.PubKey().SerializeCompressed()
+			if !hasPartialSig(in.PartialSigs, pubKey) {
+				in.PartialSigs = append(in.PartialSigs, PartialSig{PubKey: pubKey, Signature: sig})
+			}
+		case txscript.WitnessV1TaprootTy:
+			sig, err := txscript.RawTxInTaprootSignature(tx, sigHashes, i, utxo.Value, utxo.PkScript, []byte{}, txscript.SigHashDefault, key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to sign input %d: %w", i, err)
+			}
+			in.TaprootKeySig = sig
+		}
+	}
+	return packet, nil
+}
+
+func hasPartialSig(sigs []PartialSig, pubKey []byte) bool {
+	for _, sig := range sigs {
+		if bytes.Equal(sig.PubKey, pubKey) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,304 @@
+package bitcoin
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"bitbridge/pkg/types"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// PayoutChain is the node access the payout tracker needs; *Client
+// satisfies it
+type PayoutChain interface {
+	GetBlockCount() (int64, error)
+	GetTransactionConfirmations(txid string) (int64, error)
+	BroadcastTransaction(tx *wire.MsgTx) (string, error)
+	BumpFee(txid string, feeRate int64) (string, error)
+}
+
+// TrackerConfig for the payout tracker
+type TrackerConfig struct {
+	Chain       PayoutChain
+	Builder     *WithdrawalBuilder // re-signs built payouts; nil when every payout comes from the node wallet
+	StuckBlocks int64              // blocks a payout may stay unconfirmed before it is bumped, 0 never bumps
+	MinFeeRate  int64              // sat/vB a bump pays at least
+	MaxFeeRate  int64              // sat/vB a bump never goes above, 200 by default
+}
+
+// Payout is a broadcast bridge payout as the tracker sees it. Track
+// records the height tracking started at and appends fee bumps in place.
+type Payout struct {
+	TxID         string // original transaction
+	RawTx        string // signed original, empty for node wallet payouts
+	Fee          int64  // satoshis paid by the original
+	ChangeOutput int    // -1 without change
+	Height       int64  // Bitcoin tip when tracking started
+	Bumps        []types.FeeBump
+}
+
+// PayoutStatus is where a payout stands
+type PayoutStatus struct {
+	TxID          string // version of the payout that confirmed, or the newest one
+	Confirmations int64  // negative when every version conflicts with another transaction
+	Bumped        bool   // a fee bump was broadcast
+}
+
+// PayoutTracker follows bridge payouts until they confirm. A payout still
+// unconfirmed StuckBlocks after it was broadcast or last bumped is replaced
+// through BIP125 at a higher fee rate, with the extra fee taken from its
+// change. When it cannot be replaced, a child spending the change pays for
+// both instead. Replacements keep the inputs and outputs of the payout, so
+// any version confirming pays the recipients.
+type PayoutTracker struct {
+	chain       PayoutChain
+	builder     *WithdrawalBuilder
+	stuckBlocks int64
+	minFeeRate  int64
+	maxFeeRate  int64
+}
+
+// NewPayoutTracker creates a payout tracker
+func NewPayoutTracker(config TrackerConfig) (*PayoutTracker, error) {
+	if config.Chain == nil {
+		return nil, fmt.Errorf("chain is required")
+	}
+	if config.MinFeeRate == 0 {
+		config.MinFeeRate = minRelayFeeRate
+	}
+	if config.MaxFeeRate == 0 {
+		config.MaxFeeRate = 200
+	}
+	if config.MaxFeeRate < config.MinFeeRate {
+		return nil, fmt.Errorf("maximum fee rate %d is below the minimum %d", config.MaxFeeRate, config.MinFeeRate)
+	}
+
+	return &PayoutTracker{
+		chain:       config.Chain,
+		builder:     config.Builder,
+		stuckBlocks: config.StuckBlocks,
+		minFeeRate:  config.MinFeeRate,
+		maxFeeRate:  config.MaxFeeRate,
+	}, nil
+}
+
+// Track checks the confirmations of every version of a payout and bumps its
+// fee when it is stuck. The status is returned along with any error bumping,
+// so confirmations are never lost to a failed bump.
+func (t *PayoutTracker) Track(ctx context.Context, payout *Payout) (*PayoutStatus, error) {
+	tip, err := t.chain.GetBlockCount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block count: %w", err)
+	}
+	if payout.Height == 0 {
+		payout.Height = tip
+	}
+
+	// Newest first: a replacement normally wins, but the original may still
+	// confirm if it was mined before the replacement propagated
+	versions := payout.versions()
+	status := &PayoutStatus{TxID: versions[len(versions)-1], Confirmations: -1}
+	var lastErr error
+	known := false
+	for i := len(versions) - 1; i >= 0; i-- {
+		confirmations, err := t.chain.GetTransactionConfirmations(versions[i])
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		known = true
+		if confirmations > 0 {
+			return &PayoutStatus{TxID: versions[i], Confirmations: confirmations}, nil
+		}
+		if confirmations == 0 && status.Confirmations < 0 {
+			status.TxID = versions[i]
+			status.Confirmations = 0
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("failed to get payout confirmations: %w", lastErr)
+	}
+	if status.Confirmations < 0 || t.stuckBlocks == 0 || tip-payout.lastHeight() < t.stuckBlocks {
+		return status, nil
+	}
+
+	stuck := tip - payout.lastHeight()
+	bump, err := t.bump(ctx, payout, tip)
+	if err != nil {
+		return status, fmt.Errorf("payout %s is stuck: %w", payout.TxID, err)
+	}
+	payout.Bumps = append(payout.Bumps, *bump)
+	status.Bumped = true
+	if bump.Method == types.FeeBumpRBF {
+		status.TxID = bump.TxID
+	}
+
+	log.Printf("Payout %s unconfirmed for %d blocks, bumped by %s in %s at %d sat/vB",
+		payout.TxID, stuck, bump.Method, bump.TxID, bump.FeeRate)
+	return status, nil
+}
+
+// bump broadcasts a replacement of the newest version of a payout, or a
+// child of it when it cannot be replaced
+func (t *PayoutTracker) bump(ctx context.Context, payout *Payout, tip int64) (*types.FeeBump, error) {
+	current, currentRaw, currentFee := payout.current()
+
+	// The node wallet knows the change of its own payouts and bumps them itself
+	if currentRaw == "" {
+		txid, err := t.chain.BumpFee(current, 0)
+		if err != nil {
+			return nil, err
+		}
+		return &types.FeeBump{
+			Method:    types.FeeBumpRBF,
+			TxID:      txid,
+			Replaces:  current,
+			Height:    tip,
+			CreatedAt: time.Now(),
+		}, nil
+	}
+	if t.builder == nil {
+		return nil, fmt.Errorf("no withdrawal builder to sign fee bumps")
+	}
+
+	parent, err := decodeTx(currentRaw)
+	if err != nil {
+		return nil, err
+	}
+	feeRate := t.nextFeeRate(payout.feeRate(parent, currentFee))
+	if feeRate == 0 {
+		return nil, fmt.Errorf("already paying the maximum fee rate of %d sat/vB", t.maxFeeRate)
+	}
+
+	// Replacing a parent would evict its child, so once a child pays for the
+	// payout later bumps replace the child instead
+	child := payout.lastChild()
+	if child == nil {
+		replacement, fee, err := t.builder.Replace(ctx, parent, currentFee, payout.ChangeOutput, feeRate)
+		if err == nil {
+			_, err = t.chain.BroadcastTransaction(replacement)
+		}
+		if err == nil {
+			return newFeeBump(types.FeeBumpRBF, replacement, current, "", feeRate, fee, tip)
+		}
+		log.Printf("Payout %s cannot be replaced, paying for it with a child instead: %v", current, err)
+	}
+
+	var prevChildFee int64
+	if child != nil {
+		prevChildFee = child.Fee
+	}
+	childTx, fee, err := t.builder.ChildPays(ctx, parent, currentFee, payout.ChangeOutput, feeRate, prevChildFee)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := t.chain.BroadcastTransaction(childTx); err != nil {
+		return nil, fmt.Errorf("failed to broadcast child: %w", err)
+	}
+	spends := wire.OutPoint{Hash: parent.TxHash(), Index: uint32(payout.ChangeOutput)}
+	return newFeeBump(types.FeeBumpCPFP, childTx, current, spends.String(), feeRate, fee, tip)
+}
+
+// nextFeeRate is the rate of the next bump: a quarter more than the current
+// rate, within the configured bounds. It returns 0 when there is no room
+// left under the maximum.
+func (t *PayoutTracker) nextFeeRate(current int64) int64 {
+	rate := current + (current+3)/4
+	if rate < current+minRelayFeeRate {
+		rate = current + minRelayFeeRate
+	}
+	if rate < t.minFeeRate {
+		rate = t.minFeeRate
+	}
+	if rate > t.maxFeeRate {
+		rate = t.maxFeeRate
+	}
+	if rate <= current {
+		return 0
+	}
+	return rate
+}
+
+// versions returns the txids of the payout and its replacements, oldest
+// first
+func (p *Payout) versions() []string {
+	versions := []string{p.TxID}
+	for _, bump := range p.Bumps {
+		if bump.Method == types.FeeBumpRBF {
+			versions = append(versions, bump.TxID)
+		}
+	}
+	return versions
+}
+
+// current returns the txid, signed transaction and fee of the newest version
+func (p *Payout) current() (string, string, int64) {
+	txid, raw, fee := p.TxID, p.RawTx, p.Fee
+	for _, bump := range p.Bumps {
+		if bump.Method == types.FeeBumpRBF {
+			txid, raw, fee = bump.TxID, bump.RawTx, bump.Fee
+		}
+	}
+	return txid, raw, fee
+}
+
+// feeRate returns the rate the payout currently pays, counting its child
+func (p *Payout) feeRate(current *wire.MsgTx, fee int64) int64 {
+	if len(p.Bumps) > 0 {
+		return p.Bumps[len(p.Bumps)-1].FeeRate
+	}
+	return fee / VirtualSize(current)
+}
+
+// lastChild returns the newest child paying for the payout
+func (p *Payout) lastChild() *types.FeeBump {
+	for i := len(p.Bumps) - 1; i >= 0; i-- {
+		if p.Bumps[i].Method == types.FeeBumpCPFP {
+			return &p.Bumps[i]
+		}
+	}
+	return nil
+}
+
+// lastHeight is the height the payout was last broadcast or bumped at
+func (p *Payout) lastHeight() int64 {
+	if len(p.Bumps) > 0 {
+		return p.Bumps[len(p.Bumps)-1].Height
+	}
+	return p.Height
+}
+
+func newFeeBump(method string, tx *wire.MsgTx, replaces, spends string, feeRate, fee, height int64) (*types.FeeBump, error) {
+	var raw bytes.Buffer
+	if err := tx.Serialize(&raw); err != nil {
+		return nil, fmt.Errorf("failed to serialize fee bump: %w", err)
+	}
+	return &types.FeeBump{
+		Method:    method,
+		TxID:      tx.TxHash().String(),
+		Replaces:  replaces,
+		Spends:    spends,
+		FeeRate:   feeRate,
+		Fee:       fee,
+		RawTx:     hex.EncodeToString(raw.Bytes()),
+		Height:    height,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func decodeTx(rawHex string) (*wire.MsgTx, error) {
+	raw, err := hex.DecodeString(rawHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stored transaction: %w", err)
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	if err := tx.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil, fmt.Errorf("invalid stored transaction: %w", err)
+	}
+	return tx, nil
+}
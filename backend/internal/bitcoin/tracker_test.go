@@ -0,0 +1,194 @@
+package bitcoin
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"bitbridge/pkg/types"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// fixtureChain is a node that accepts or rejects broadcasts on request
+type fixtureChain struct {
+	tip           int64
+	confirmations map[string]int64
+	rejectRBF     bool
+	broadcast     []*wire.MsgTx
+	bumped        []string
+}
+
+func (c *fixtureChain) GetBlockCount() (int64, error) {
+	return c.tip, nil
+}
+
+func (c *fixtureChain) GetTransactionConfirmations(txid string) (int64, error) {
+	confirmations, ok := c.confirmations[txid]
+	if !ok {
+		return 0, errors.New("transaction not found")
+	}
+	return confirmations, nil
+}
+
+func (c *fixtureChain) BroadcastTransaction(tx *wire.MsgTx) (string, error) {
+	// A replacement spends the same coins as a payout already known
+	if c.rejectRBF && len(tx.TxIn) > 1 {
+		return "", errors.New("insufficient fee")
+	}
+	c.broadcast = append(c.broadcast, tx)
+	c.confirmations[tx.TxHash().String()] = 0
+	return tx.TxHash().String(), nil
+}
+
+func (c *fixtureChain) BumpFee(txid string, feeRate int64) (string, error) {
+	c.bumped = append(c.bumped, txid)
+	replacement := "bumped-" + txid
+	c.confirmations[replacement] = 0
+	return replacement, nil
+}
+
+// newTrackedPayout builds and signs a two-input payout with change and
+// returns it in mempool at height 100
+func newTrackedPayout(t *testing.T, rejectRBF bool) (*PayoutTracker, *fixtureChain, *Payout, *wire.MsgTx) {
+	t.Helper()
+
+	key := testKey(1)
+	coins := fixtureCoinSource{
+		fixtureUTXO(t, 1, keyAddress(t, key, ScriptP2WPKH), 60000, 6),
+		fixtureUTXO(t, 2, keyAddress(t, key, ScriptP2WPKH), 50000, 6),
+	}
+	builder := newTestBuilder(t, coins, &fixtureChangeSource{address: keyAddress(t, key, ScriptP2WPKH)}, key)
+
+	withdrawal, err := builder.Build(BuildRequest{
+		Payments: []Payment{{Address: keyAddress(t, testKey(4), ScriptP2WPKH), Amount: 90000}},
+		FeeRate:  2,
+	})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	signed, err := builder.Sign(context.Background(), withdrawal)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	var raw bytes.Buffer
+	if err := signed.Serialize(&raw); err != nil {
+		t.Fatalf("Failed to serialize payout: %v", err)
+	}
+
+	chain := &fixtureChain{tip: 100, confirmations: map[string]int64{signed.TxHash().String(): 0}, rejectRBF: rejectRBF}
+	tracker, err := NewPayoutTracker(TrackerConfig{Chain: chain, Builder: builder, StuckBlocks: 3, MinFeeRate: 2})
+	if err != nil {
+		t.Fatalf("Failed to create tracker: %v", err)
+	}
+	payout := &Payout{
+		TxID:         signed.TxHash().String(),
+		RawTx:        hex.EncodeToString(raw.Bytes()),
+		Fee:          withdrawal.Selection.Fee,
+		ChangeOutput: withdrawal.ChangeOutput,
+	}
+	return tracker, chain, payout, signed
+}
+
+func TestPayoutTrackerReplacesStuckPayout(t *testing.T) {
+	tracker, chain, payout, original := newTrackedPayout(t, false)
+
+	status, err := tracker.Track(context.Background(), payout)
+	if err != nil {
+		t.Fatalf("Track failed: %v", err)
+	}
+	if status.Bumped || payout.Height != 100 {
+		t.Fatalf("Expected tracking to start at height 100 without a bump, got %+v at %d", status, payout.Height)
+	}
+
+	chain.tip = 103
+	status, err = tracker.Track(context.Background(), payout)
+	if err != nil {
+		t.Fatalf("Track failed: %v", err)
+	}
+	if !status.Bumped || len(payout.Bumps) != 1 || payout.Bumps[0].Method != types.FeeBumpRBF {
+		t.Fatalf("Expected a replacement after 3 blocks, got %+v", payout.Bumps)
+	}
+
+	bump := payout.Bumps[0]
+	replacement := chain.broadcast[0]
+	if bump.Replaces != payout.TxID || bump.TxID != replacement.TxHash().String() || status.TxID != bump.TxID {
+		t.Errorf("Expected the replacement to be linked to %s, got %+v", payout.TxID, bump)
+	}
+	if bump.Fee <= payout.Fee || bump.FeeRate != 3 {
+		t.Errorf("Expected a higher fee at 3 sat/vB, got %d at %d sat/vB after %d", bump.Fee, bump.FeeRate, payout.Fee)
+	}
+	// Same coins and payment, with the extra fee out of the change
+	if replacement.TxIn[0].PreviousOutPoint != original.TxIn[0].PreviousOutPoint ||
+		replacement.TxOut[0].Value != original.TxOut[0].Value {
+		t.Error("Expected the replacement to spend the same coins to the same payment")
+	}
+	change := payout.ChangeOutput
+	if diff := original.TxOut[change].Value - replacement.TxOut[change].Value; diff != bump.Fee-payout.Fee {
+		t.Errorf("Expected the change to pay the %d sat difference, got %d", bump.Fee-payout.Fee, diff)
+	}
+
+	// The original confirming still settles the payout
+	chain.confirmations[payout.TxID] = 2
+	chain.confirmations[bump.TxID] = -2
+	status, err = tracker.Track(context.Background(), payout)
+	if err != nil {
+		t.Fatalf("Track failed: %v", err)
+	}
+	if status.TxID != payout.TxID || status.Confirmations != 2 {
+		t.Errorf("Expected the original to be reported confirmed, got %+v", status)
+	}
+}
+
+func TestPayoutTrackerFallsBackToChild(t *testing.T) {
+	tracker, chain, payout, original := newTrackedPayout(t, true)
+	payout.Height = 100
+	chain.tip = 103
+
+	if _, err := tracker.Track(context.Background(), payout); err != nil {
+		t.Fatalf("Track failed: %v", err)
+	}
+	if len(payout.Bumps) != 1 || payout.Bumps[0].Method != types.FeeBumpCPFP {
+		t.Fatalf("Expected a child when the replacement is rejected, got %+v", payout.Bumps)
+	}
+
+	child := chain.broadcast[0]
+	bump := payout.Bumps[0]
+	if child.TxIn[0].PreviousOutPoint.Hash != original.TxHash() ||
+		bump.Spends != child.TxIn[0].PreviousOutPoint.String() {
+		t.Errorf("Expected the child to spend the payout change, got %+v", bump)
+	}
+	// Parent and child together pay the new rate
+	packageVSize := VirtualSize(original) + VirtualSize(child)
+	if rate := (payout.Fee + bump.Fee) / packageVSize; rate < bump.FeeRate {
+		t.Errorf("Expected the package to pay %d sat/vB, got %d", bump.FeeRate, rate)
+	}
+
+	// A later bump replaces the child rather than the parent
+	chain.tip = 106
+	if _, err := tracker.Track(context.Background(), payout); err != nil {
+		t.Fatalf("Track failed: %v", err)
+	}
+	if len(payout.Bumps) != 2 || payout.Bumps[1].Method != types.FeeBumpCPFP || payout.Bumps[1].Fee <= bump.Fee {
+		t.Errorf("Expected a second, richer child, got %+v", payout.Bumps)
+	}
+}
+
+func TestPayoutTrackerBumpsWalletPayout(t *testing.T) {
+	chain := &fixtureChain{tip: 200, confirmations: map[string]int64{"wallet-tx": 0}}
+	tracker, err := NewPayoutTracker(TrackerConfig{Chain: chain, StuckBlocks: 2})
+	if err != nil {
+		t.Fatalf("Failed to create tracker: %v", err)
+	}
+	payout := &Payout{TxID: "wallet-tx", ChangeOutput: -1, Height: 198}
+
+	status, err := tracker.Track(context.Background(), payout)
+	if err != nil {
+		t.Fatalf("Track failed: %v", err)
+	}
+	if len(chain.bumped) != 1 || status.TxID != "bumped-wallet-tx" || payout.Bumps[0].Replaces != "wallet-tx" {
+		t.Errorf("Expected the node wallet to replace the payout, got %+v", payout.Bumps)
+	}
+}
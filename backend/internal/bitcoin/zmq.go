@@ -0,0 +1,429 @@
+package bitcoin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ZMQ topics published by bitcoind
+const (
+	TopicRawBlock  = "rawblock"
+	TopicRawTx     = "rawtx"
+	TopicHashBlock = "hashblock"
+	TopicSequence  = "sequence"
+)
+
+// Labels of sequence topic events
+const (
+	SequenceBlockConnected    = 'C'
+	SequenceBlockDisconnected = 'D'
+	SequenceTxAdded           = 'A'
+	SequenceTxRemoved         = 'R'
+)
+
+const (
+	zmqHandshakeTimeout    = 10 * time.Second
+	zmqMaxReconnectBackoff = time.Minute
+)
+
+// Notification is a message pushed by the node
+type Notification struct {
+	Endpoint        string
+	Topic           string
+	Sequence        uint32         // per topic counter of the publisher
+	Hash            string         // block hash, or txid for rawtx and mempool sequence events
+	Block           *wire.MsgBlock // rawblock
+	Tx              *wire.MsgTx    // rawtx
+	Label           byte           // sequence: C, D, A or R
+	MempoolSequence uint64         // sequence A and R events
+}
+
+// BlockConnected reports whether the notification announces a new block
+func (n *Notification) BlockConnected() bool {
+	switch n.Topic {
+	case TopicRawBlock, TopicHashBlock:
+		return true
+	case TopicSequence:
+		return n.Label == SequenceBlockConnected
+	}
+	return false
+}
+
+// BlockDisconnected reports whether the notification announces a block
+// leaving the best chain
+func (n *Notification) BlockDisconnected() bool {
+	return n.Topic == TopicSequence && n.Label == SequenceBlockDisconnected
+}
+
+// NotificationCallback receives pushed notifications. It runs on the
+// connection's reader and should return quickly.
+type NotificationCallback func(n *Notification)
+
+// ZMQConfig for the ZMQ subscriber
+type ZMQConfig struct {
+	Endpoints         []string      // tcp:// addresses of the node's zmqpub notifiers
+	Topics            []string      // rawblock, rawtx, hashblock and sequence by default
+	PollInterval      time.Duration // how often resync callbacks run while notifications may be missed, 10s by default
+	ReconnectInterval time.Duration // first reconnect delay, doubling up to a minute, 1s by default
+}
+
+// ZMQSubscriber receives bitcoind's ZMQ notifications so that chain
+// followers react to blocks as they arrive instead of on their next poll.
+// ZMQ drops messages when a subscriber falls behind or is disconnected,
+// so every topic's sequence number is checked. While a gap is unresolved
+// or an endpoint is down, resync callbacks run every PollInterval so that
+// followers poll the node until in-order notifications resume.
+type ZMQSubscriber struct {
+	endpoints         []string
+	topics            []string
+	pollInterval      time.Duration
+	reconnectInterval time.Duration
+	callbacks         []NotificationCallback
+	resyncCallbacks   []func()
+	streams           map[string]*zmqStream
+	mu                sync.Mutex
+	wg                sync.WaitGroup
+	ctx               context.Context
+	cancel            context.CancelFunc
+}
+
+// zmqStream is the state of one endpoint
+type zmqStream struct {
+	conn      net.Conn
+	connected bool
+	trusted   bool              // no notification missed since the last resync
+	sequences map[string]uint32 // last sequence number seen per topic
+}
+
+// NewZMQSubscriber creates a ZMQ subscriber
+func NewZMQSubscriber(config ZMQConfig) (*ZMQSubscriber, error) {
+	if len(config.Endpoints) == 0 {
+		return nil, fmt.Errorf("at least one ZMQ endpoint is required")
+	}
+	if len(config.Topics) == 0 {
+		config.Topics = []string{TopicRawBlock, TopicRawTx, TopicHashBlock, TopicSequence}
+	}
+	if config.PollInterval == 0 {
+		config.PollInterval = 10 * time.Second
+	}
+	if config.ReconnectInterval == 0 {
+		config.ReconnectInterval = time.Second
+	}
+
+	for _, topic := range config.Topics {
+		switch topic {
+		case TopicRawBlock, TopicRawTx, TopicHashBlock, TopicSequence:
+		default:
+			return nil, fmt.Errorf("unsupported ZMQ topic %q", topic)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	subscriber := &ZMQSubscriber{
+		topics:            config.Topics,
+		pollInterval:      config.PollInterval,
+		reconnectInterval: config.ReconnectInterval,
+		streams:           make(map[string]*zmqStream),
+		ctx:               ctx,
+		cancel:            cancel,
+	}
+	for _, endpoint := range config.Endpoints {
+		address, ok := strings.CutPrefix(endpoint, "tcp://")
+		if !ok {
+			cancel()
+			return nil, fmt.Errorf("unsupported ZMQ endpoint %q, only tcp:// is supported", endpoint)
+		}
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid ZMQ endpoint %q: %v", endpoint, err)
+		}
+		subscriber.endpoints = append(subscriber.endpoints, address)
+		subscriber.streams[address] = &zmqStream{sequences: make(map[string]uint32)}
+	}
+
+	return subscriber, nil
+}
+
+// AddCallback registers a callback for every notification
+func (s *ZMQSubscriber) AddCallback(callback NotificationCallback) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.callbacks = append(s.callbacks, callback)
+}
+
+// AddResyncCallback registers a callback run whenever notifications may have
+// been missed: on connecting, on a sequence gap and every PollInterval
+// until the stream can be trusted again
+func (s *ZMQSubscriber) AddResyncCallback(callback func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resyncCallbacks = append(s.resyncCallbacks, callback)
+}
+
+func (s *ZMQSubscriber) Start() {
+	log.Printf("Starting ZMQ subscriber for %s...", strings.Join(s.topics, ", "))
+
+	for _, endpoint := range s.endpoints {
+		s.wg.Add(1)
+		go s.run(endpoint)
+	}
+	s.wg.Add(1)
+	go s.pollLoop()
+}
+
+func (s *ZMQSubscriber) Stop() {
+	log.Println("Stopping ZMQ subscriber...")
+	s.cancel()
+
+	s.mu.Lock()
+	for _, stream := range s.streams {
+		if stream.conn != nil {
+			stream.conn.Close()
+		}
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// Healthy reports whether every endpoint is connected with no missed
+// notifications, so that followers need not poll
+func (s *ZMQSubscriber) Healthy() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, stream := range s.streams {
+		if !stream.connected || !stream.trusted {
+			return false
+		}
+	}
+	return true
+}
+
+// run keeps an endpoint connected until Stop is called
+func (s *ZMQSubscriber) run(endpoint string) {
+	defer s.wg.Done()
+
+	backoff := s.reconnectInterval
+	for {
+		connected, err := s.listen(endpoint)
+		s.disconnected(endpoint)
+		if s.ctx.Err() != nil {
+			return
+		}
+		if connected {
+			backoff = s.reconnectInterval
+		}
+
+		log.Printf("ZMQ endpoint tcp://%s unavailable, polling and reconnecting in %s: %v", endpoint, backoff, err)
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > zmqMaxReconnectBackoff {
+			backoff = zmqMaxReconnectBackoff
+		}
+	}
+}
+
+// listen connects to an endpoint and delivers its notifications until the
+// connection fails. It reports whether the handshake succeeded.
+func (s *ZMQSubscriber) listen(endpoint string) (bool, error) {
+	dialer := net.Dialer{Timeout: zmqHandshakeTimeout}
+	conn, err := dialer.DialContext(s.ctx, "tcp", endpoint)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	conn.SetDeadline(time.Now().Add(zmqHandshakeTimeout))
+	if err := zmtpHandshake(conn, reader, s.topics); err != nil {
+		return false, fmt.Errorf("handshake failed: %w", err)
+	}
+	conn.SetDeadline(time.Time{})
+
+	if !s.connected(endpoint, conn) {
+		return false, s.ctx.Err()
+	}
+	log.Printf("Subscribed to ZMQ notifications at tcp://%s", endpoint)
+
+	// Anything published while disconnected was dropped
+	s.resync()
+
+	for {
+		parts, err := zmtpReadMessage(conn, reader)
+		if err != nil {
+			return true, err
+		}
+		s.handle(endpoint, parts)
+	}
+}
+
+// handle checks the sequence number of a message and delivers it
+func (s *ZMQSubscriber) handle(endpoint string, parts [][]byte) {
+	if len(parts) != 3 || len(parts[2]) != 4 {
+		log.Printf("Ignoring malformed ZMQ message with %d parts from tcp://%s", len(parts), endpoint)
+		return
+	}
+	topic := string(parts[0])
+	sequence := binary.LittleEndian.Uint32(parts[2])
+
+	s.mu.Lock()
+	stream := s.streams[endpoint]
+	last, seen := stream.sequences[topic]
+	stream.sequences[topic] = sequence
+	gap := seen && sequence != last+1
+	recovered := false
+	if gap {
+		stream.trusted = false
+	} else if seen && !stream.trusted {
+		stream.trusted = true
+		recovered = true
+	}
+	callbacks := append([]NotificationCallback(nil), s.callbacks...)
+	s.mu.Unlock()
+
+	if gap {
+		log.Printf("ZMQ %s notifications from tcp://%s skipped from %d to %d, polling until they resume",
+			topic, endpoint, last, sequence)
+		s.resync()
+	}
+	if recovered {
+		log.Printf("ZMQ notifications from tcp://%s back in sequence", endpoint)
+	}
+
+	notification, err := decodeNotification(topic, parts[1])
+	if err != nil {
+		log.Printf("Ignoring ZMQ %s notification %d from tcp://%s: %v", topic, sequence, endpoint, err)
+		return
+	}
+	notification.Endpoint = "tcp://" + endpoint
+	notification.Sequence = sequence
+
+	for _, callback := range callbacks {
+		callback(notification)
+	}
+}
+
+// pollLoop runs the resync callbacks while notifications cannot be trusted
+func (s *ZMQSubscriber) pollLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.Healthy() {
+				s.resync()
+			}
+		}
+	}
+}
+
+func (s *ZMQSubscriber) resync() {
+	s.mu.Lock()
+	callbacks := append([]func(){}, s.resyncCallbacks...)
+	s.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback()
+	}
+}
+
+// connected marks an endpoint up, unless the subscriber is stopping
+func (s *ZMQSubscriber) connected(endpoint string, conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx.Err() != nil {
+		return false
+	}
+	stream := s.streams[endpoint]
+	stream.conn = conn
+	stream.connected = true
+	stream.trusted = true
+	// The resync on connecting covers whatever the counters skipped meanwhile
+	stream.sequences = make(map[string]uint32)
+	return true
+}
+
+func (s *ZMQSubscriber) disconnected(endpoint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stream := s.streams[endpoint]
+	stream.conn = nil
+	stream.connected = false
+}
+
+// decodeNotification parses the body of a bitcoind notification
+func decodeNotification(topic string, body []byte) (*Notification, error) {
+	notification := &Notification{Topic: topic}
+
+	switch topic {
+	case TopicRawBlock:
+		block := new(wire.MsgBlock)
+		if err := block.Deserialize(bytes.NewReader(body)); err != nil {
+			return nil, fmt.Errorf("invalid block: %v", err)
+		}
+		notification.Block = block
+		notification.Hash = block.BlockHash().String()
+
+	case TopicRawTx:
+		tx := wire.NewMsgTx(wire.TxVersion)
+		if err := tx.Deserialize(bytes.NewReader(body)); err != nil {
+			return nil, fmt.Errorf("invalid transaction: %v", err)
+		}
+		notification.Tx = tx
+		notification.Hash = tx.TxHash().String()
+
+	case TopicHashBlock:
+		if len(body) != 32 {
+			return nil, fmt.Errorf("invalid block hash of %d bytes", len(body))
+		}
+		// Hashes are sent in display order
+		notification.Hash = hex.EncodeToString(body)
+
+	case TopicSequence:
+		if len(body) < 33 {
+			return nil, fmt.Errorf("invalid sequence event of %d bytes", len(body))
+		}
+		notification.Hash = hex.EncodeToString(body[:32])
+		notification.Label = body[32]
+		switch notification.Label {
+		case SequenceBlockConnected, SequenceBlockDisconnected:
+		case SequenceTxAdded, SequenceTxRemoved:
+			if len(body) != 41 {
+				return nil, fmt.Errorf("invalid mempool sequence event of %d bytes", len(body))
+			}
+			notification.MempoolSequence = binary.LittleEndian.Uint64(body[33:])
+		default:
+			return nil, fmt.Errorf("unknown sequence label %q", notification.Label)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported topic")
+	}
+
+	return notification, nil
+}
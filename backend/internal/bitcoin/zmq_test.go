@@ -0,0 +1,331 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// fixturePublisher stands in for bitcoind's ZMQ PUB socket, speaking ZMTP
+// 3.0 byte by byte
+type fixturePublisher struct {
+	t        *testing.T
+	listener net.Listener
+	conns    chan net.Conn
+}
+
+func newFixturePublisher(t *testing.T) *fixturePublisher {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	p := &fixturePublisher{t: t, listener: listener, conns: make(chan net.Conn, 4)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			p.conns <- conn
+		}
+	}()
+	return p
+}
+
+func (p *fixturePublisher) endpoint() string {
+	return "tcp://" + p.listener.Addr().String()
+}
+
+// accept completes the handshake of the next subscriber and returns the
+// connection and its subscriptions
+func (p *fixturePublisher) accept(topics int) (net.Conn, []string) {
+	p.t.Helper()
+
+	var conn net.Conn
+	select {
+	case conn = <-p.conns:
+	case <-time.After(5 * time.Second):
+		p.t.Fatal("Timed out waiting for the subscriber to connect")
+	}
+	p.t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	defer conn.SetDeadline(time.Time{})
+
+	// Signature, version 3.1, NULL mechanism, as-server
+	greeting := make([]byte, 64)
+	greeting[0], greeting[9], greeting[10], greeting[11] = 0xff, 0x7f, 3, 1
+	copy(greeting[12:], "NULL")
+	greeting[32] = 1
+	if _, err := conn.Write(greeting); err != nil {
+		p.t.Fatalf("Failed to send greeting: %v", err)
+	}
+
+	peer := make([]byte, 64)
+	if _, err := io.ReadFull(conn, peer); err != nil {
+		p.t.Fatalf("Failed to read greeting: %v", err)
+	}
+	if peer[0] != 0xff || peer[9] != 0x7f || peer[10] != 3 || string(peer[12:16]) != "NULL" {
+		p.t.Fatalf("Unexpected greeting % x", peer)
+	}
+
+	flags, ready := p.readFrame(conn)
+	if flags != 0x04 || !bytes.Contains(ready, []byte("\x05READY\x0bSocket-Type\x00\x00\x00\x03SUB")) {
+		p.t.Fatalf("Expected a SUB READY command, got %#x %q", flags, ready)
+	}
+	reply := []byte("\x05READY\x0bSocket-Type\x00\x00\x00\x03PUB")
+	if _, err := conn.Write(append([]byte{0x04, byte(len(reply))}, reply...)); err != nil {
+		p.t.Fatalf("Failed to send READY: %v", err)
+	}
+
+	var subscriptions []string
+	for len(subscriptions) < topics {
+		flags, body := p.readFrame(conn)
+		if flags != 0 || len(body) == 0 || body[0] != 1 {
+			p.t.Fatalf("Expected a subscription, got %#x %q", flags, body)
+		}
+		subscriptions = append(subscriptions, string(body[1:]))
+	}
+	return conn, subscriptions
+}
+
+func (p *fixturePublisher) readFrame(conn net.Conn) (byte, []byte) {
+	p.t.Helper()
+
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		p.t.Fatalf("Failed to read frame: %v", err)
+	}
+	body := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, body); err != nil {
+		p.t.Fatalf("Failed to read frame: %v", err)
+	}
+	return header[0], body
+}
+
+// publish sends a three part notification the way bitcoind does
+func (p *fixturePublisher) publish(conn net.Conn, topic string, body []byte, sequence uint32) {
+	p.t.Helper()
+
+	var msg bytes.Buffer
+	msg.Write([]byte{0x01, byte(len(topic))})
+	msg.WriteString(topic)
+	if len(body) > 255 {
+		msg.WriteByte(0x03)
+		binary.Write(&msg, binary.BigEndian, uint64(len(body)))
+	} else {
+		msg.Write([]byte{0x01, byte(len(body))})
+	}
+	msg.Write(body)
+	msg.Write([]byte{0x00, 4})
+	binary.Write(&msg, binary.LittleEndian, sequence)
+
+	if _, err := conn.Write(msg.Bytes()); err != nil {
+		p.t.Fatalf("Failed to publish %s: %v", topic, err)
+	}
+}
+
+func newTestSubscriber(t *testing.T, endpoint string) (*ZMQSubscriber, chan *Notification, chan struct{}) {
+	t.Helper()
+
+	subscriber, err := NewZMQSubscriber(ZMQConfig{
+		Endpoints:         []string{endpoint},
+		PollInterval:      20 * time.Millisecond,
+		ReconnectInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create subscriber: %v", err)
+	}
+
+	notifications := make(chan *Notification, 16)
+	resyncs := make(chan struct{}, 256)
+	subscriber.AddCallback(func(n *Notification) { notifications <- n })
+	subscriber.AddResyncCallback(func() {
+		select {
+		case resyncs <- struct{}{}:
+		default:
+		}
+	})
+	subscriber.Start()
+	t.Cleanup(subscriber.Stop)
+	return subscriber, notifications, resyncs
+}
+
+func nextNotification(t *testing.T, notifications chan *Notification) *Notification {
+	t.Helper()
+
+	select {
+	case n := <-notifications:
+		return n
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a notification")
+		return nil
+	}
+}
+
+// drain empties resyncs and reports whether there were any
+func drain(resyncs chan struct{}) bool {
+	drained := false
+	for {
+		select {
+		case <-resyncs:
+			drained = true
+		default:
+			return drained
+		}
+	}
+}
+
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestZMQSubscriberDeliversNotifications(t *testing.T) {
+	publisher := newFixturePublisher(t)
+	subscriber, notifications, _ := newTestSubscriber(t, publisher.endpoint())
+
+	conn, topics := publisher.accept(4)
+	want := []string{TopicRawBlock, TopicRawTx, TopicHashBlock, TopicSequence}
+	for i, topic := range want {
+		if topics[i] != topic {
+			t.Fatalf("Expected subscriptions to %v, got %v", want, topics)
+		}
+	}
+	waitFor(t, "the subscriber to be healthy", subscriber.Healthy)
+
+	// The genesis block needs a long frame
+	block := chaincfg.RegressionNetParams.GenesisBlock
+	var raw bytes.Buffer
+	if err := block.Serialize(&raw); err != nil {
+		t.Fatalf("Failed to serialize block: %v", err)
+	}
+	publisher.publish(conn, TopicRawBlock, raw.Bytes(), 7)
+
+	n := nextNotification(t, notifications)
+	if n.Topic != TopicRawBlock || n.Sequence != 7 || !n.BlockConnected() ||
+		n.Hash != block.BlockHash().String() || len(n.Block.Transactions) != 1 {
+		t.Errorf("Unexpected rawblock notification: %+v", n)
+	}
+
+	tx := block.Transactions[0]
+	raw.Reset()
+	if err := tx.Serialize(&raw); err != nil {
+		t.Fatalf("Failed to serialize transaction: %v", err)
+	}
+	publisher.publish(conn, TopicRawTx, raw.Bytes(), 0)
+	n = nextNotification(t, notifications)
+	if n.Topic != TopicRawTx || n.Hash != tx.TxHash().String() || n.BlockConnected() {
+		t.Errorf("Unexpected rawtx notification: %+v", n)
+	}
+
+	// Hashes are published in display order
+	hash := block.BlockHash()
+	display := make([]byte, chainhash.HashSize)
+	for i := range display {
+		display[i] = hash[chainhash.HashSize-1-i]
+	}
+	publisher.publish(conn, TopicHashBlock, display, 3)
+	n = nextNotification(t, notifications)
+	if n.Topic != TopicHashBlock || n.Hash != hash.String() || !n.BlockConnected() {
+		t.Errorf("Unexpected hashblock notification: %+v", n)
+	}
+
+	publisher.publish(conn, TopicSequence, append(append([]byte{}, display...), 'D'), 10)
+	n = nextNotification(t, notifications)
+	if n.Hash != hash.String() || !n.BlockDisconnected() || n.BlockConnected() {
+		t.Errorf("Unexpected block disconnect: %+v", n)
+	}
+
+	added := append(append([]byte{}, display...), 'A')
+	added = binary.LittleEndian.AppendUint64(added, 42)
+	publisher.publish(conn, TopicSequence, added, 11)
+	n = nextNotification(t, notifications)
+	if n.Label != SequenceTxAdded || n.MempoolSequence != 42 || n.BlockDisconnected() {
+		t.Errorf("Unexpected mempool event: %+v", n)
+	}
+}
+
+func TestZMQSubscriberPollsAfterGapsAndReconnects(t *testing.T) {
+	publisher := newFixturePublisher(t)
+	subscriber, notifications, resyncs := newTestSubscriber(t, publisher.endpoint())
+
+	// Polls until the first connection
+	select {
+	case <-resyncs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected resyncs before connecting")
+	}
+
+	conn, _ := publisher.accept(4)
+	waitFor(t, "the subscriber to be healthy", subscriber.Healthy)
+
+	hash := make([]byte, chainhash.HashSize)
+	for _, sequence := range []uint32{1, 2} {
+		publisher.publish(conn, TopicHashBlock, hash, sequence)
+		nextNotification(t, notifications)
+	}
+	drain(resyncs)
+	time.Sleep(50 * time.Millisecond)
+	if drain(resyncs) {
+		t.Error("Expected no polling while notifications arrive in order")
+	}
+
+	// A dropped notification falls back to polling until the next in-order one
+	publisher.publish(conn, TopicHashBlock, hash, 4)
+	nextNotification(t, notifications)
+	if subscriber.Healthy() {
+		t.Error("Expected a sequence gap to make the subscriber unhealthy")
+	}
+	drain(resyncs)
+	time.Sleep(50 * time.Millisecond)
+	if !drain(resyncs) {
+		t.Error("Expected polling after a sequence gap")
+	}
+
+	publisher.publish(conn, TopicHashBlock, hash, 5)
+	nextNotification(t, notifications)
+	if !subscriber.Healthy() {
+		t.Error("Expected the next in-order notification to end polling")
+	}
+
+	// Dropping the connection polls until the subscriber is back
+	conn.Close()
+	waitFor(t, "the subscriber to notice the disconnect", func() bool { return !subscriber.Healthy() })
+	conn, _ = publisher.accept(4)
+	waitFor(t, "the subscriber to reconnect", subscriber.Healthy)
+
+	// Counters are not compared across connections
+	publisher.publish(conn, TopicHashBlock, hash, 9)
+	nextNotification(t, notifications)
+	if !subscriber.Healthy() {
+		t.Error("Expected the first notification after reconnecting to be accepted")
+	}
+}
+
+func TestNewZMQSubscriberRejectsEndpoints(t *testing.T) {
+	for _, endpoint := range []string{"ipc:///tmp/bitcoind", "tcp://localhost", "localhost:28332"} {
+		if _, err := NewZMQSubscriber(ZMQConfig{Endpoints: []string{endpoint}}); err == nil {
+			t.Errorf("Expected %s to be rejected", endpoint)
+		}
+	}
+	if _, err := NewZMQSubscriber(ZMQConfig{Endpoints: []string{"tcp://127.0.0.1:28332"}, Topics: []string{"hashtx"}}); err == nil {
+		t.Error("Expected an unsupported topic to be rejected")
+	}
+}
@@ -0,0 +1,226 @@
+package bitcoin
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Just enough of ZMTP 3.0 (https://rfc.zeromq.org/spec/23/) to subscribe to
+// bitcoind's PUB sockets: the NULL security mechanism, READY and
+// subscription messages. Peers speaking 3.1 fall back to 3.0 for us.
+
+const (
+	zmtpGreetingSize = 64
+	zmtpMaxFrameSize = 64 << 20 // well above the largest block
+
+	zmtpFlagMore    = 0x01
+	zmtpFlagLong    = 0x02
+	zmtpFlagCommand = 0x04
+)
+
+var errZMTPProtocol = errors.New("zmtp protocol error")
+
+// zmtpGreeting is the greeting of a ZMTP 3.0 client with the NULL mechanism
+func zmtpGreeting() []byte {
+	greeting := make([]byte, zmtpGreetingSize)
+	greeting[0] = 0xff
+	greeting[9] = 0x7f
+	greeting[10] = 3 // major version
+	greeting[11] = 0 // minor version
+	copy(greeting[12:32], "NULL")
+	return greeting
+}
+
+// zmtpHandshake exchanges greetings and READY commands with a publisher and
+// subscribes to topics
+func zmtpHandshake(rw io.ReadWriter, r *bufio.Reader, topics []string) error {
+	if _, err := rw.Write(zmtpGreeting()); err != nil {
+		return err
+	}
+
+	greeting := make([]byte, zmtpGreetingSize)
+	if _, err := io.ReadFull(r, greeting); err != nil {
+		return err
+	}
+	if greeting[0] != 0xff || greeting[9]&0x01 == 0 {
+		return fmt.Errorf("%w: peer is not a ZMTP 3 socket", errZMTPProtocol)
+	}
+	if greeting[10] < 3 {
+		return fmt.Errorf("%w: peer speaks ZMTP %d.%d", errZMTPProtocol, greeting[10], greeting[11])
+	}
+	if mechanism := string(bytes.TrimRight(greeting[12:32], "\x00")); mechanism != "NULL" {
+		return fmt.Errorf("%w: unsupported security mechanism %q", errZMTPProtocol, mechanism)
+	}
+
+	ready := zmtpCommand("READY", zmtpProperty("Socket-Type", "SUB"))
+	if err := zmtpWriteFrame(rw, zmtpFlagCommand, ready); err != nil {
+		return err
+	}
+
+	flags, body, err := zmtpReadFrame(r)
+	if err != nil {
+		return err
+	}
+	name, data, err := zmtpParseCommand(flags, body)
+	if err != nil {
+		return err
+	}
+	if name == "ERROR" {
+		return fmt.Errorf("%w: peer refused handshake: %s", errZMTPProtocol, zmtpErrorReason(data))
+	}
+	if name != "READY" {
+		return fmt.Errorf("%w: expected READY, got %s", errZMTPProtocol, name)
+	}
+	properties, err := zmtpParseProperties(data)
+	if err != nil {
+		return err
+	}
+	if socketType := properties["Socket-Type"]; socketType != "PUB" && socketType != "XPUB" {
+		return fmt.Errorf("%w: peer is a %s socket, not a publisher", errZMTPProtocol, socketType)
+	}
+
+	// ZMTP 3.0 subscriptions are messages starting with 1
+	for _, topic := range topics {
+		if err := zmtpWriteFrame(rw, 0, append([]byte{1}, topic...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// zmtpReadMessage reads the frames of the next message, answering
+// heartbeats that arrive before it
+func zmtpReadMessage(w io.Writer, r *bufio.Reader) ([][]byte, error) {
+	var parts [][]byte
+	for {
+		flags, body, err := zmtpReadFrame(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if flags&zmtpFlagCommand != 0 {
+			name, data, err := zmtpParseCommand(flags, body)
+			if err != nil {
+				return nil, err
+			}
+			switch name {
+			case "PING":
+				// TTL then the context to echo back
+				if len(data) < 2 {
+					return nil, fmt.Errorf("%w: short PING", errZMTPProtocol)
+				}
+				if err := zmtpWriteFrame(w, zmtpFlagCommand, zmtpCommand("PONG", data[2:])); err != nil {
+					return nil, err
+				}
+			case "ERROR":
+				return nil, fmt.Errorf("%w: peer error: %s", errZMTPProtocol, zmtpErrorReason(data))
+			}
+			continue
+		}
+
+		parts = append(parts, body)
+		if flags&zmtpFlagMore == 0 {
+			return parts, nil
+		}
+	}
+}
+
+func zmtpReadFrame(r *bufio.Reader) (byte, []byte, error) {
+	flags, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	if flags&^(zmtpFlagMore|zmtpFlagLong|zmtpFlagCommand) != 0 {
+		return 0, nil, fmt.Errorf("%w: invalid frame flags %#x", errZMTPProtocol, flags)
+	}
+
+	var size uint64
+	if flags&zmtpFlagLong != 0 {
+		var long [8]byte
+		if _, err := io.ReadFull(r, long[:]); err != nil {
+			return 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(long[:])
+	} else {
+		short, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		size = uint64(short)
+	}
+	if size > zmtpMaxFrameSize {
+		return 0, nil, fmt.Errorf("%w: %d byte frame", errZMTPProtocol, size)
+	}
+
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return flags, body, nil
+}
+
+func zmtpWriteFrame(w io.Writer, flags byte, body []byte) error {
+	var header []byte
+	if len(body) > 255 {
+		header = make([]byte, 9)
+		header[0] = flags | zmtpFlagLong
+		binary.BigEndian.PutUint64(header[1:], uint64(len(body)))
+	} else {
+		header = []byte{flags, byte(len(body))}
+	}
+	_, err := w.Write(append(header, body...))
+	return err
+}
+
+func zmtpCommand(name string, data []byte) []byte {
+	command := append([]byte{byte(len(name))}, name...)
+	return append(command, data...)
+}
+
+func zmtpParseCommand(flags byte, body []byte) (string, []byte, error) {
+	if flags&zmtpFlagCommand == 0 {
+		return "", nil, fmt.Errorf("%w: expected a command", errZMTPProtocol)
+	}
+	if len(body) == 0 || len(body) < 1+int(body[0]) {
+		return "", nil, fmt.Errorf("%w: truncated command", errZMTPProtocol)
+	}
+	return string(body[1 : 1+body[0]]), body[1+body[0]:], nil
+}
+
+func zmtpProperty(name, value string) []byte {
+	property := append([]byte{byte(len(name))}, name...)
+	property = binary.BigEndian.AppendUint32(property, uint32(len(value)))
+	return append(property, value...)
+}
+
+func zmtpParseProperties(data []byte) (map[string]string, error) {
+	properties := make(map[string]string)
+	for len(data) > 0 {
+		nameLen := int(data[0])
+		if len(data) < 1+nameLen+4 {
+			return nil, fmt.Errorf("%w: truncated property", errZMTPProtocol)
+		}
+		name := string(data[1 : 1+nameLen])
+		data = data[1+nameLen:]
+
+		valueLen := binary.BigEndian.Uint32(data)
+		data = data[4:]
+		if uint64(len(data)) < uint64(valueLen) {
+			return nil, fmt.Errorf("%w: truncated property %s", errZMTPProtocol, name)
+		}
+		properties[name] = string(data[:valueLen])
+		data = data[valueLen:]
+	}
+	return properties, nil
+}
+
+func zmtpErrorReason(data []byte) string {
+	if len(data) == 0 || len(data) < 1+int(data[0]) {
+		return "unknown"
+	}
+	return string(data[1 : 1+data[0]])
+}
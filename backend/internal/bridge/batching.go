@@ -0,0 +1,386 @@
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"bitbridge/internal/bitcoin"
+	"bitbridge/pkg/types"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// Batched withdrawals are queued instead of paid one by one. A batch is
+// flushed into a single transaction with one output per withdrawal once
+// enough are queued or the oldest has waited long enough. The fee is
+// deducted from the payments in equal satoshi shares. The batch record is
+// persisted with its signed transaction before any member is marked batched,
+// so a crash at any point either leaves the members queued or resumes the
+// same batch.
+
+// GetBatch returns the withdrawal batch paid by a bitcoin transaction
+func (w *WithdrawalService) GetBatch(txid string) (*types.WithdrawalBatch, error) {
+	return w.store.GetBatch(batchID(txid))
+}
+
+// ListBatches returns all withdrawal batches
+func (w *WithdrawalService) ListBatches() ([]*types.WithdrawalBatch, error) {
+	return w.store.ListBatches()
+}
+
+// queueWithdrawal moves a pending withdrawal into the batch queue
+func (w *WithdrawalService) queueWithdrawal(tx *types.Transaction) error {
+	tx.Status = types.TransactionStatusQueued
+	tx.UpdatedAt = time.Now()
+	if err := w.store.SaveTransaction(tx); err != nil {
+		tx.Status = types.TransactionStatusPending
+		return fmt.Errorf("failed to persist withdrawal: %w", err)
+	}
+
+	log.Printf("Withdrawal %s queued for batching", tx.ID)
+	return nil
+}
+
+// flushBatch pays the queued withdrawals in one transaction when the batch
+// is due
+func (w *WithdrawalService) flushBatch() {
+	withdrawals, err := w.store.ListTransactions(types.TransactionTypeWithdrawal)
+	if err != nil {
+		log.Printf("Failed to list withdrawals: %v", err)
+		return
+	}
+	batches, err := w.store.ListBatches()
+	if err != nil {
+		log.Printf("Failed to list withdrawal batches: %v", err)
+		return
+	}
+
+	queued := nextBatch(withdrawals, batches, w.batchSize)
+	if !batchDue(queued, w.batchSize, w.batchInterval, time.Now()) {
+		return
+	}
+
+	batch := w.assembleBatch(queued)
+	if batch == nil {
+		return
+	}
+	if err := w.broadcastBatch(batch); err != nil {
+		log.Printf("Withdrawal batch %s: %v", batch.ID, err)
+	}
+}
+
+// assembleBatch builds the batch for the queued withdrawals, leaving out
+// any that would be dust after its fee share, and returns nil if no batch
+// was built. Only a withdrawal that is itself too small is charged an
+// attempt: running out of funds or failing to sign is no member's fault,
+// so the batch is simply tried again on the next flush.
+func (w *WithdrawalService) assembleBatch(queued []*types.Transaction) *types.WithdrawalBatch {
+	for len(queued) > 0 {
+		batch, err := w.buildBatch(queued)
+		if err == nil {
+			return batch
+		}
+
+		var dust *bitcoin.DustPaymentError
+		if !errors.As(err, &dust) || dust.Index >= len(queued) {
+			log.Printf("Withdrawal batch of %d withdrawals not built, retrying: %v", len(queued), err)
+			return nil
+		}
+
+		// Fee shares change with the batch, so the rest is built again
+		w.recordFailure(queued[dust.Index], err)
+		queued = append(queued[:dust.Index:dust.Index], queued[dust.Index+1:]...)
+	}
+	return nil
+}
+
+// nextBatch returns the queued withdrawals to pay next, oldest first and at
+// most size of them. Withdrawals already in a batch that has not failed are
+// left out even if a crash kept them from being marked.
+func nextBatch(withdrawals []*types.Transaction, batches []*types.WithdrawalBatch, size int) []*types.Transaction {
+	batched := make(map[string]bool)
+	for _, batch := range batches {
+		if batch.Status == types.TransactionStatusFailed {
+			continue
+		}
+		for _, id := range batch.Withdrawals {
+			batched[id] = true
+		}
+	}
+
+	var queued []*types.Transaction
+	for _, tx := range withdrawals {
+		if tx.Status == types.TransactionStatusQueued && !batched[tx.ID] {
+			queued = append(queued, tx)
+		}
+	}
+
+	sort.Slice(queued, func(i, j int) bool {
+		if !queued[i].CreatedAt.Equal(queued[j].CreatedAt) {
+			return queued[i].CreatedAt.Before(queued[j].CreatedAt)
+		}
+		return queued[i].ID < queued[j].ID
+	})
+	if len(queued) > size {
+		queued = queued[:size]
+	}
+	return queued
+}
+
+// batchDue reports whether a batch should be flushed: it is full, or its
+// oldest withdrawal has waited for the interval. queued is oldest first.
+func batchDue(queued []*types.Transaction, size int, interval time.Duration, now time.Time) bool {
+	if len(queued) == 0 {
+		return false
+	}
+	return len(queued) >= size || now.Sub(queued[0].CreatedAt) >= interval
+}
+
+// buildBatch builds and signs the payout for the queued withdrawals and
+// persists it as a batch. The members are marked by broadcastBatch.
+func (w *WithdrawalService) buildBatch(queued []*types.Transaction) (*types.WithdrawalBatch, error) {
+	exclude, err := w.reservedInputs()
+	if err != nil {
+		return nil, err
+	}
+
+	payments := make([]bitcoin.Payment, len(queued))
+	for i, tx := range queued {
+		payments[i] = bitcoin.Payment{Address: tx.ToAddress, Amount: tx.Amount}
+	}
+
+	feeRate := w.payoutFeeRate()
+	unsigned, err := w.builder.Build(bitcoin.BuildRequest{
+		Payments:    payments,
+		FeeRate:     feeRate,
+		SubtractFee: true,
+		Exclude:     exclude,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build batch payout: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(w.ctx, time.Minute)
+	defer cancel()
+	signed, err := w.builder.Sign(ctx, unsigned)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw bytes.Buffer
+	if err := signed.Serialize(&raw); err != nil {
+		return nil, fmt.Errorf("failed to serialize batch payout: %w", err)
+	}
+
+	now := time.Now()
+	txid := signed.TxHash().String()
+	batch := &types.WithdrawalBatch{
+		ID:           batchID(txid),
+		Status:       types.TransactionStatusBatched,
+		TxID:         txid,
+		RawTx:        hex.EncodeToString(raw.Bytes()),
+		Inputs:       unsigned.Outpoints(),
+		Fee:          unsigned.Selection.Fee,
+		FeeRate:      feeRate,
+		ChangeOutput: unsigned.ChangeOutput,
+		Change:       unsigned.Selection.Change,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	for i, tx := range queued {
+		batch.Withdrawals = append(batch.Withdrawals, tx.ID)
+		batch.Outputs = append(batch.Outputs, types.BatchOutput{
+			WithdrawalID: tx.ID,
+			Vout:         uint32(i),
+			Address:      unsigned.Payments[i].Address,
+			Amount:       unsigned.Payments[i].Amount,
+			FeeShare:     unsigned.FeeShares[i],
+		})
+	}
+
+	if err := w.store.SaveBatch(batch); err != nil {
+		return nil, fmt.Errorf("failed to persist withdrawal batch: %w", err)
+	}
+
+	log.Printf("Withdrawal batch %s pays %d withdrawals with a %d sat fee from %d inputs",
+		batch.ID, len(batch.Outputs), batch.Fee, len(batch.Inputs))
+	return batch, nil
+}
+
+// processBatches advances every batch that is not yet final: signed batches
+// are broadcast until accepted and broadcast ones are tracked until deep
+// enough
+func (w *WithdrawalService) processBatches() {
+	batches, err := w.store.ListBatches()
+	if err != nil {
+		log.Printf("Failed to list withdrawal batches: %v", err)
+		return
+	}
+
+	for _, batch := range batches {
+		if w.ctx.Err() != nil {
+			return
+		}
+
+		var stepErr error
+		switch batch.Status {
+		case types.TransactionStatusBatched:
+			stepErr = w.broadcastBatch(batch)
+		case types.TransactionStatusBroadcast:
+			stepErr = w.settleBatch(batch)
+		default:
+			continue
+		}
+
+		if stepErr != nil {
+			log.Printf("Withdrawal batch %s: %v", batch.ID, stepErr)
+		}
+	}
+}
+
+// broadcastBatch marks the members of a signed batch and submits its
+// transaction. Unlike a single payout a batch is never failed for not being
+// accepted, since its members cannot be paid again without double paying;
+// the error is kept on the batch for an operator instead.
+func (w *WithdrawalService) broadcastBatch(batch *types.WithdrawalBatch) error {
+	if err := w.syncMembers(batch); err != nil {
+		return err
+	}
+
+	raw, err := hex.DecodeString(batch.RawTx)
+	if err != nil {
+		return fmt.Errorf("invalid stored batch payout: %w", err)
+	}
+	msgTx := wire.NewMsgTx(wire.TxVersion)
+	if err := msgTx.Deserialize(bytes.NewReader(raw)); err != nil {
+		return fmt.Errorf("invalid stored batch payout: %w", err)
+	}
+
+	if _, err := w.btcClient.BroadcastTransaction(msgTx); err != nil {
+		if _, confErr := w.btcClient.GetTransactionConfirmations(batch.TxID); confErr != nil {
+			batch.Error = err.Error()
+			batch.UpdatedAt = time.Now()
+			if saveErr := w.store.SaveBatch(batch); saveErr != nil {
+				return fmt.Errorf("failed to persist withdrawal batch: %w", saveErr)
+			}
+			return fmt.Errorf("failed to broadcast batch payout: %w", err)
+		}
+	}
+
+	batch.Status = types.TransactionStatusBroadcast
+	batch.Error = ""
+	batch.UpdatedAt = time.Now()
+	if err := w.store.SaveBatch(batch); err != nil {
+		return fmt.Errorf("failed to persist withdrawal batch: %w", err)
+	}
+
+	log.Printf("Withdrawal batch %s broadcast", batch.ID)
+	return w.syncMembers(batch)
+}
+
+// settleBatch tracks the confirmations of a broadcast batch, bumping its fee
+// when it is stuck. Once it is deep enough its members are confirmed and
+// redeemed one by one.
+func (w *WithdrawalService) settleBatch(batch *types.WithdrawalBatch) error {
+	payout := &bitcoin.Payout{
+		TxID:         batch.TxID,
+		RawTx:        batch.RawTx,
+		Fee:          batch.Fee,
+		ChangeOutput: batch.ChangeOutput,
+		Height:       batch.BroadcastHeight,
+		Bumps:        batch.FeeBumps,
+	}
+	status, trackErr := w.tracker.Track(w.ctx, payout)
+	if status == nil {
+		return trackErr
+	}
+
+	trackErrText := ""
+	if trackErr != nil {
+		trackErrText = trackErr.Error()
+	}
+	confirmedTxID := ""
+	if status.Confirmations > 0 {
+		confirmedTxID = status.TxID
+	}
+
+	switch {
+	case status.Confirmations < 0:
+		batch.Status = types.TransactionStatusFailed
+		batch.Error = fmt.Sprintf("batch payout %s conflicts with another transaction", status.TxID)
+		log.Printf("Withdrawal batch %s failed: %s", batch.ID, batch.Error)
+	case int(status.Confirmations) != batch.Confirmations || confirmedTxID != batch.ConfirmedTxID ||
+		payout.Height != batch.BroadcastHeight || status.Bumped || trackErrText != batch.Error:
+		batch.Confirmations = int(status.Confirmations)
+		batch.ConfirmedTxID = confirmedTxID
+		batch.Error = trackErrText
+		if batch.Confirmations >= w.requiredConfirms {
+			batch.Status = types.TransactionStatusConfirmed
+			log.Printf("Withdrawal batch %s confirmed", batch.ID)
+		}
+	default:
+		return w.syncMembers(batch)
+	}
+
+	batch.BroadcastHeight = payout.Height
+	batch.FeeBumps = payout.Bumps
+	batch.UpdatedAt = time.Now()
+	if err := w.store.SaveBatch(batch); err != nil {
+		return fmt.Errorf("failed to persist withdrawal batch: %w", err)
+	}
+	if err := w.syncMembers(batch); err != nil {
+		return err
+	}
+	return trackErr
+}
+
+// syncMembers brings the withdrawals paid by a batch in line with it.
+// Members that have moved past the batch, to confirmed, completed or failed,
+// are left alone.
+func (w *WithdrawalService) syncMembers(batch *types.WithdrawalBatch) error {
+	for _, out := range batch.Outputs {
+		tx, err := w.store.GetTransaction(out.WithdrawalID)
+		if err != nil {
+			return fmt.Errorf("failed to get withdrawal %s: %w", out.WithdrawalID, err)
+		}
+
+		switch tx.Status {
+		case types.TransactionStatusQueued, types.TransactionStatusBatched, types.TransactionStatusBroadcast:
+		default:
+			continue
+		}
+		if tx.BatchID == batch.ID && tx.Status == batch.Status && tx.Confirmations == batch.Confirmations &&
+			tx.ConfirmedTxID == batch.ConfirmedTxID && len(tx.FeeBumps) == len(batch.FeeBumps) {
+			continue
+		}
+
+		tx.Status = batch.Status
+		tx.BatchID = batch.ID
+		tx.BitcoinTxID = batch.TxID
+		tx.BitcoinVout = out.Vout
+		tx.FeeShare = out.FeeShare
+		tx.Confirmations = batch.Confirmations
+		tx.ConfirmedTxID = batch.ConfirmedTxID
+		tx.FeeBumps = batch.FeeBumps
+		tx.Error = ""
+		if batch.Status == types.TransactionStatusFailed {
+			tx.Error = batch.Error
+		}
+		tx.Attempts = 0
+		tx.UpdatedAt = time.Now()
+		if err := w.store.SaveTransaction(tx); err != nil {
+			return fmt.Errorf("failed to persist withdrawal %s: %w", tx.ID, err)
+		}
+	}
+	return nil
+}
+
+func batchID(txid string) string {
+	return "batch:" + txid
+}
@@ -0,0 +1,264 @@
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"bitbridge/internal/bitcoin"
+	"bitbridge/internal/store"
+	"bitbridge/internal/store/storetest"
+	"bitbridge/pkg/types"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+type batchCoins []*types.UTXO
+
+func (c batchCoins) GetAllUTXOs() []*types.UTXO {
+	return c
+}
+
+type batchChange string
+
+func (c batchChange) ChangeAddress() (string, error) {
+	return string(c), nil
+}
+
+func batchKey(seed byte) *btcec.PrivateKey {
+	key, _ := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{seed}, 32))
+	return key
+}
+
+func batchAddress(t *testing.T, key *btcec.PrivateKey) string {
+	t.Helper()
+
+	address, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(key.PubKey().SerializeCompressed()), &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("Failed to encode address: %v", err)
+	}
+	return address.EncodeAddress()
+}
+
+func queuedWithdrawal(id string, address string, amount int64, created time.Time) *types.Transaction {
+	return &types.Transaction{
+		ID:        id,
+		Type:      types.TransactionTypeWithdrawal,
+		Status:    types.TransactionStatusQueued,
+		Amount:    amount,
+		ToAddress: address,
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+}
+
+func newTestBatcher(t *testing.T) (*WithdrawalService, *store.BoltStore) {
+	t.Helper()
+
+	repo := storetest.Open(t)
+
+	key := batchKey(1)
+	address := batchAddress(t, key)
+	info, err := bitcoin.ParseAddress(address, &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("Failed to parse address: %v", err)
+	}
+	coins := batchCoins{{
+		TxID:          chainhash.Hash{1}.String(),
+		Amount:        200000,
+		ScriptPubKey:  info.ScriptPubKey,
+		Address:       address,
+		Confirmations: 6,
+		BlockHeight:   100,
+	}}
+
+	signer, err := bitcoin.NewKeyPSBTSigner(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+	builder, err := bitcoin.NewWithdrawalBuilder(bitcoin.BuilderConfig{
+		Coins:   coins,
+		Change:  batchChange(batchAddress(t, batchKey(2))),
+		Signer:  signer,
+		Network: &chaincfg.RegressionNetParams,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create builder: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return &WithdrawalService{
+		builder:          builder,
+		feeRate:          3,
+		batchInterval:    time.Minute,
+		batchSize:        3,
+		store:            repo,
+		requiredConfirms: 6,
+		maxAttempts:      10,
+		ctx:              ctx,
+		cancel:           cancel,
+	}, repo
+}
+
+func TestNextBatchAndBatchDue(t *testing.T) {
+	now := time.Now()
+	withdrawals := []*types.Transaction{
+		queuedWithdrawal("withdrawal:c", "bc1qc", 30000, now.Add(-10*time.Second)),
+		queuedWithdrawal("withdrawal:a", "bc1qa", 10000, now.Add(-30*time.Second)),
+		queuedWithdrawal("withdrawal:b", "bc1qb", 20000, now.Add(-20*time.Second)),
+		queuedWithdrawal("withdrawal:d", "bc1qd", 40000, now.Add(-40*time.Second)),
+		{ID: "withdrawal:e", Status: types.TransactionStatusPending, CreatedAt: now.Add(-time.Hour)},
+	}
+	batches := []*types.WithdrawalBatch{
+		{ID: "batch:1", Status: types.TransactionStatusBatched, Withdrawals: []string{"withdrawal:d"}},
+		{ID: "batch:2", Status: types.TransactionStatusFailed, Withdrawals: []string{"withdrawal:a"}},
+	}
+
+	queued := nextBatch(withdrawals, batches, 2)
+	if len(queued) != 2 || queued[0].ID != "withdrawal:a" || queued[1].ID != "withdrawal:b" {
+		t.Fatalf("Expected the two oldest unbatched withdrawals, got %v", queued)
+	}
+
+	if !batchDue(queued, 2, time.Hour, now) {
+		t.Error("Expected a full batch to be due")
+	}
+	if batchDue(queued[:1], 2, time.Minute, now) {
+		t.Error("Expected a partial batch to wait for the interval")
+	}
+	if !batchDue(queued[:1], 2, 30*time.Second, now) {
+		t.Error("Expected a partial batch to be due once its oldest withdrawal has waited the interval")
+	}
+	if batchDue(nil, 2, 0, now) {
+		t.Error("Expected an empty queue never to be due")
+	}
+}
+
+func TestBuildBatchSharesFeeAndMarksMembers(t *testing.T) {
+	w, repo := newTestBatcher(t)
+
+	created := time.Now().Add(-time.Hour)
+	var queued []*types.Transaction
+	for i, amount := range []int64{50000, 30000, 20000} {
+		tx := queuedWithdrawal(fmt.Sprintf("withdrawal:%d", i), batchAddress(t, batchKey(byte(10+i))), amount, created)
+		if err := repo.SaveTransaction(tx); err != nil {
+			t.Fatalf("Failed to save withdrawal: %v", err)
+		}
+		queued = append(queued, tx)
+	}
+
+	batch, err := w.buildBatch(queued)
+	if err != nil {
+		t.Fatalf("Failed to build batch: %v", err)
+	}
+	if batch.Status != types.TransactionStatusBatched || len(batch.Outputs) != 3 {
+		t.Fatalf("Unexpected batch %+v", batch)
+	}
+
+	// Shares differ by at most a satoshi and add up to the fee exactly
+	var shares int64
+	for i, out := range batch.Outputs {
+		shares += out.FeeShare
+		if out.Amount != queued[i].Amount-out.FeeShare {
+			t.Errorf("Expected output %d to pay %d, got %d", i, queued[i].Amount-out.FeeShare, out.Amount)
+		}
+		if diff := out.FeeShare - batch.Outputs[0].FeeShare; diff < -1 || diff > 0 {
+			t.Errorf("Expected equal fee shares, got %d and %d", batch.Outputs[0].FeeShare, out.FeeShare)
+		}
+	}
+	if shares != batch.Fee {
+		t.Errorf("Expected fee shares to add up to %d, got %d", batch.Fee, shares)
+	}
+
+	stored, err := repo.GetBatch(batch.ID)
+	if err != nil || stored.RawTx == "" || len(stored.Inputs) != 1 {
+		t.Fatalf("Expected the signed batch to be persisted, got %+v (%v)", stored, err)
+	}
+
+	// The batch reserves its inputs and keeps its members out of the queue
+	reserved, err := w.reservedInputs()
+	if err != nil {
+		t.Fatalf("Failed to get reserved inputs: %v", err)
+	}
+	if !reserved[batch.Inputs[0]] {
+		t.Errorf("Expected %s to be reserved", batch.Inputs[0])
+	}
+	if next := nextBatch(queued, []*types.WithdrawalBatch{stored}, 3); len(next) != 0 {
+		t.Errorf("Expected batched withdrawals to leave the queue, got %d", len(next))
+	}
+
+	if err := w.syncMembers(batch); err != nil {
+		t.Fatalf("Failed to mark members: %v", err)
+	}
+	for i, out := range batch.Outputs {
+		tx, err := repo.GetTransaction(out.WithdrawalID)
+		if err != nil {
+			t.Fatalf("Failed to get withdrawal: %v", err)
+		}
+		if tx.Status != types.TransactionStatusBatched || tx.BatchID != batch.ID || tx.BitcoinTxID != batch.TxID {
+			t.Errorf("Expected withdrawal %d to be batched in %s, got %+v", i, batch.ID, tx)
+		}
+		if tx.BitcoinVout != uint32(i) || tx.FeeShare != out.FeeShare {
+			t.Errorf("Expected withdrawal %d paid by output %d with a %d sat share, got output %d share %d",
+				i, i, out.FeeShare, tx.BitcoinVout, tx.FeeShare)
+		}
+	}
+}
+
+func TestAssembleBatchFailsOnlyDustMembers(t *testing.T) {
+	w, repo := newTestBatcher(t)
+
+	created := time.Now().Add(-time.Hour)
+	var queued []*types.Transaction
+	for i, amount := range []int64{50000, 600, 30000} {
+		tx := queuedWithdrawal(fmt.Sprintf("withdrawal:%d", i), batchAddress(t, batchKey(byte(10+i))), amount, created)
+		if err := repo.SaveTransaction(tx); err != nil {
+			t.Fatalf("Failed to save withdrawal: %v", err)
+		}
+		queued = append(queued, tx)
+	}
+
+	// 600 sats cannot cover a share of the fee, the others are paid without it
+	batch := w.assembleBatch(queued)
+	if batch == nil {
+		t.Fatal("Expected a batch without the dust withdrawal")
+	}
+	if len(batch.Withdrawals) != 2 || batch.Withdrawals[0] != "withdrawal:0" || batch.Withdrawals[1] != "withdrawal:2" {
+		t.Errorf("Expected withdrawals 0 and 2 to be batched, got %v", batch.Withdrawals)
+	}
+
+	dust, err := repo.GetTransaction("withdrawal:1")
+	if err != nil {
+		t.Fatalf("Failed to get withdrawal: %v", err)
+	}
+	if dust.Attempts != 1 || dust.Status != types.TransactionStatusQueued || dust.Error == "" {
+		t.Errorf("Expected the dust withdrawal to be charged one attempt, got %+v", dust)
+	}
+	for _, id := range batch.Withdrawals {
+		if tx, err := repo.GetTransaction(id); err != nil || tx.Attempts != 0 {
+			t.Errorf("Expected %s not to be charged an attempt, got %+v (%v)", id, tx, err)
+		}
+	}
+
+	// The batch reserves the only coin, so the next one runs out of funds
+	// without charging its members
+	late := queuedWithdrawal("withdrawal:3", batchAddress(t, batchKey(13)), 40000, created)
+	if err := repo.SaveTransaction(late); err != nil {
+		t.Fatalf("Failed to save withdrawal: %v", err)
+	}
+	if batch := w.assembleBatch([]*types.Transaction{late}); batch != nil {
+		t.Fatalf("Expected no batch without spendable coins, got %+v", batch)
+	}
+	stored, err := repo.GetTransaction(late.ID)
+	if err != nil {
+		t.Fatalf("Failed to get withdrawal: %v", err)
+	}
+	if stored.Attempts != 0 || stored.Status != types.TransactionStatusQueued {
+		t.Errorf("Expected an insufficient funds error not to be charged to the withdrawal, got %+v", stored)
+	}
+}
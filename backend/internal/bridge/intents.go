@@ -0,0 +1,362 @@
+package bridge
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"bitbridge/internal/bitcoin"
+	"bitbridge/internal/store"
+	"bitbridge/pkg/types"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrAddressBound is returned when a deposit address handed out for a
+// recipient already belongs to an intent of another recipient
+var ErrAddressBound = errors.New("deposit address is bound to another recipient")
+
+// DepositAddressSource hands out the deposit address for a recipient and
+// takes back addresses whose intents expired unfunded, so that they no
+// longer count against the gap limit; bitcoin.Service satisfies it
+type DepositAddressSource interface {
+	GenerateDepositAddress(recipient string) (string, error)
+	ReleaseDepositAddress(address string) error
+}
+
+// IntentService binds Bitcoin deposit addresses to Ethereum recipients.
+// Every deposit to an intent's address is minted to its recipient, also
+// after the intent expired: expiry only tells the depositor the quote is
+// stale, the address binding itself is permanent.
+type IntentService struct {
+	addresses DepositAddressSource
+	store     store.Store
+	ttl       time.Duration
+	minAmount int64
+	mu        sync.Mutex
+}
+
+// IntentConfig for the deposit intent service
+type IntentConfig struct {
+	Addresses DepositAddressSource
+	Store     store.Store
+	TTL       time.Duration // time a depositor has to send funds
+	MinAmount int64         // smallest deposit minted, in satoshis
+}
+
+func NewIntentService(config IntentConfig) (*IntentService, error) {
+	if config.Addresses == nil {
+		return nil, fmt.Errorf("deposit address source is required")
+	}
+	if config.Store == nil {
+		return nil, fmt.Errorf("store is required")
+	}
+	if config.TTL == 0 {
+		config.TTL = 24 * time.Hour
+	}
+
+	return &IntentService{
+		addresses: config.Addresses,
+		store:     config.Store,
+		ttl:       config.TTL,
+		minAmount: config.MinAmount,
+	}, nil
+}
+
+// CreateIntent returns an open intent for the recipient. A recipient that
+// still has an unexpired, unfunded intent on its address gets that one back.
+func (s *IntentService) CreateIntent(recipient string) (*types.DepositIntent, error) {
+	if !common.IsHexAddress(recipient) {
+		return nil, fmt.Errorf("invalid recipient address: %s", recipient)
+	}
+	recipient = common.HexToAddress(recipient).Hex()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	address, err := s.addresses.GenerateDepositAddress(recipient)
+	if errors.Is(err, bitcoin.ErrGapLimit) {
+		// Intents that expired unfunded free up room for new addresses
+		if err = s.releaseExpired(); err == nil {
+			address, err = s.addresses.GenerateDepositAddress(recipient)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deposit address: %w", err)
+	}
+
+	existing, err := s.intentsFor(func(intent *types.DepositIntent) bool {
+		return intent.DepositAddress == address
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, intent := range existing {
+		if intent.Recipient != recipient {
+			return nil, fmt.Errorf("%w: %s", ErrAddressBound, address)
+		}
+	}
+	if n := len(existing); n > 0 && existing[n-1].Status == types.IntentStatusPending {
+		return existing[n-1], nil
+	}
+
+	id, err := newIntentID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	intent := &types.DepositIntent{
+		ID:             id,
+		Recipient:      recipient,
+		DepositAddress: address,
+		MinAmount:      s.minAmount,
+		Status:         types.IntentStatusPending,
+		ExpiresAt:      now.Add(s.ttl),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.store.SaveIntent(intent); err != nil {
+		return nil, fmt.Errorf("failed to persist intent: %w", err)
+	}
+
+	log.Printf("Created deposit intent %s: %s -> %s", intent.ID, address, recipient)
+	return intent, nil
+}
+
+// GetIntent returns an intent by ID
+func (s *IntentService) GetIntent(id string) (*types.DepositIntent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent, err := s.store.GetIntent(id)
+	if err != nil {
+		return nil, err
+	}
+	intents, err := s.intentsFor(func(other *types.DepositIntent) bool {
+		return other.DepositAddress == intent.DepositAddress
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range intents {
+		if other.ID == id {
+			return other, nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+// IntentByAddress returns the latest intent for a deposit address
+func (s *IntentService) IntentByAddress(address string) (*types.DepositIntent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intents, err := s.intentsFor(func(intent *types.DepositIntent) bool {
+		return intent.DepositAddress == address
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(intents) == 0 {
+		return nil, store.ErrNotFound
+	}
+	return intents[len(intents)-1], nil
+}
+
+// IntentsByRecipient returns a recipient's intents, oldest first
+func (s *IntentService) IntentsByRecipient(recipient string) ([]*types.DepositIntent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.intentsFor(func(intent *types.DepositIntent) bool {
+		return strings.EqualFold(intent.Recipient, recipient)
+	})
+}
+
+// RecipientResolver mints deposits to the recipient of their address's
+// intent and falls back to fallback for addresses without one. Deposits
+// below the intent's minimum are refused.
+func (s *IntentService) RecipientResolver(fallback RecipientResolver) RecipientResolver {
+	return func(utxo *types.UTXO) (string, error) {
+		intent, err := s.IntentByAddress(utxo.Address)
+		if errors.Is(err, store.ErrNotFound) {
+			return fallback(utxo)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if utxo.Amount < intent.MinAmount {
+			return "", fmt.Errorf("%w: %d sats is less than %d sats for intent %s", ErrBelowMinimum, utxo.Amount, intent.MinAmount, intent.ID)
+		}
+		if intent.Status == types.IntentStatusExpired {
+			log.Printf("Warning: deposit %s:%d arrived after intent %s expired, minting to %s", utxo.TxID, utxo.Vout, intent.ID, intent.Recipient)
+		}
+		return intent.Recipient, nil
+	}
+}
+
+// releaseExpired hands back the addresses whose intents all expired without
+// a deposit
+func (s *IntentService) releaseExpired() error {
+	intents, err := s.intentsFor(func(*types.DepositIntent) bool { return true })
+	if err != nil {
+		return err
+	}
+
+	expired := make(map[string]bool)
+	for _, intent := range intents {
+		if _, seen := expired[intent.DepositAddress]; !seen {
+			expired[intent.DepositAddress] = true
+		}
+		if intent.Status != types.IntentStatusExpired {
+			expired[intent.DepositAddress] = false
+		}
+	}
+
+	for address, unfunded := range expired {
+		if !unfunded {
+			continue
+		}
+		if err := s.addresses.ReleaseDepositAddress(address); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// intentsFor returns the matching intents, oldest first, with their status
+// brought up to date from the deposits on their addresses
+func (s *IntentService) intentsFor(match func(intent *types.DepositIntent) bool) ([]*types.DepositIntent, error) {
+	all, err := s.store.ListIntents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list intents: %w", err)
+	}
+
+	// Deposits are attributed among all intents of an address, so every
+	// intent sharing an address with a match takes part
+	addresses := make(map[string]bool)
+	for _, intent := range all {
+		if match(intent) {
+			addresses[intent.DepositAddress] = true
+		}
+	}
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+
+	byAddress := make(map[string][]*types.DepositIntent)
+	for _, intent := range all {
+		if addresses[intent.DepositAddress] {
+			byAddress[intent.DepositAddress] = append(byAddress[intent.DepositAddress], intent)
+		}
+	}
+
+	deposits, err := s.store.ListTransactions(types.TransactionTypeDeposit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deposits: %w", err)
+	}
+	depositsByAddress := make(map[string][]*types.Transaction)
+	for _, deposit := range deposits {
+		if addresses[deposit.FromAddress] {
+			depositsByAddress[deposit.FromAddress] = append(depositsByAddress[deposit.FromAddress], deposit)
+		}
+	}
+
+	var matched []*types.DepositIntent
+	for address, intents := range byAddress {
+		if err := s.refresh(intents, depositsByAddress[address]); err != nil {
+			return nil, err
+		}
+		for _, intent := range intents {
+			if match(intent) {
+				matched = append(matched, intent)
+			}
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].CreatedAt.Before(matched[j].CreatedAt) })
+	return matched, nil
+}
+
+// refresh assigns each deposit on an address to the latest intent created
+// before it and recomputes the intents' status, persisting any change
+func (s *IntentService) refresh(intents []*types.DepositIntent, deposits []*types.Transaction) error {
+	sort.Slice(intents, func(i, j int) bool { return intents[i].CreatedAt.Before(intents[j].CreatedAt) })
+
+	assigned := make(map[string][]*types.Transaction)
+	for _, deposit := range deposits {
+		owner := intents[0]
+		for _, intent := range intents {
+			if !intent.CreatedAt.After(deposit.CreatedAt) {
+				owner = intent
+			}
+		}
+		assigned[owner.ID] = append(assigned[owner.ID], deposit)
+	}
+
+	now := time.Now()
+	for _, intent := range intents {
+		status, ids, amount := intentStatus(intent, assigned[intent.ID], now)
+		if status == intent.Status && amount == intent.Amount && len(ids) == len(intent.Deposits) {
+			continue
+		}
+
+		intent.Status = status
+		intent.Deposits = ids
+		intent.Amount = amount
+		intent.UpdatedAt = now
+		if err := s.store.SaveIntent(intent); err != nil {
+			return fmt.Errorf("failed to persist intent: %w", err)
+		}
+	}
+	return nil
+}
+
+// intentStatus derives an intent's status from its deposits: completed once
+// any deposit was minted, funded while one is in progress and failed if all
+// of them failed
+func intentStatus(intent *types.DepositIntent, deposits []*types.Transaction, now time.Time) (string, []string, int64) {
+	var ids []string
+	var amount int64
+	completed, inProgress := false, false
+	for _, deposit := range deposits {
+		ids = append(ids, deposit.ID)
+		amount += deposit.Amount
+		switch deposit.Status {
+		case types.TransactionStatusCompleted:
+			completed = true
+		case types.TransactionStatusFailed:
+		default:
+			inProgress = true
+		}
+	}
+	sort.Strings(ids)
+
+	switch {
+	case completed:
+		return types.IntentStatusCompleted, ids, amount
+	case inProgress:
+		return types.IntentStatusFunded, ids, amount
+	case len(deposits) > 0:
+		return types.IntentStatusFailed, ids, amount
+	case now.After(intent.ExpiresAt):
+		return types.IntentStatusExpired, ids, amount
+	default:
+		return types.IntentStatusPending, ids, amount
+	}
+}
+
+func newIntentID() (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", fmt.Errorf("failed to generate intent ID: %w", err)
+	}
+	return "intent:" + hex.EncodeToString(id[:]), nil
+}
@@ -0,0 +1,205 @@
+package bridge
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"bitbridge/internal/bitcoin"
+	"bitbridge/internal/store"
+	"bitbridge/internal/store/storetest"
+	"bitbridge/pkg/types"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// staticAddresses hands out one fixed address per recipient
+type staticAddresses map[string]string
+
+func (a staticAddresses) GenerateDepositAddress(recipient string) (string, error) {
+	return a[strings.ToLower(recipient)], nil
+}
+
+func (a staticAddresses) ReleaseDepositAddress(address string) error {
+	return nil
+}
+
+// derivedAddresses hands out addresses from an account key the way
+// bitcoin.Service does
+type derivedAddresses struct {
+	deriver *bitcoin.AddressDeriver
+}
+
+func (a derivedAddresses) GenerateDepositAddress(recipient string) (string, error) {
+	address, _, err := a.deriver.AddressFor(recipient)
+	return address, err
+}
+
+func (a derivedAddresses) ReleaseDepositAddress(address string) error {
+	return a.deriver.Release(address)
+}
+
+const (
+	alice = "0x00000000000000000000000000000000000000aa"
+	bob   = "0x00000000000000000000000000000000000000bb"
+)
+
+func newTestIntents(t *testing.T, addresses staticAddresses) (*IntentService, *store.BoltStore) {
+	t.Helper()
+
+	repo := storetest.Open(t)
+
+	intents, err := NewIntentService(IntentConfig{Addresses: addresses, Store: repo, TTL: time.Hour, MinAmount: 10000})
+	if err != nil {
+		t.Fatalf("Failed to create intent service: %v", err)
+	}
+	return intents, repo
+}
+
+func TestIntentLifecycle(t *testing.T) {
+	intents, repo := newTestIntents(t, staticAddresses{alice: "bc1qalice"})
+
+	intent, err := intents.CreateIntent(alice)
+	if err != nil {
+		t.Fatalf("Failed to create intent: %v", err)
+	}
+	if intent.DepositAddress != "bc1qalice" || intent.Status != types.IntentStatusPending || intent.MinAmount != 10000 {
+		t.Fatalf("Unexpected intent %+v", intent)
+	}
+
+	again, err := intents.CreateIntent(alice)
+	if err != nil {
+		t.Fatalf("Failed to create intent: %v", err)
+	}
+	if again.ID != intent.ID {
+		t.Errorf("Expected the open intent %s to be returned, got %s", intent.ID, again.ID)
+	}
+
+	resolve := intents.RecipientResolver(AddressRecipientResolver(func(string) (string, bool) { return "", false }))
+	if _, err := resolve(&types.UTXO{Address: "bc1qalice", Amount: 9999}); !errors.Is(err, ErrBelowMinimum) {
+		t.Error("Expected a deposit below the minimum to be refused")
+	}
+	recipient, err := resolve(&types.UTXO{Address: "bc1qalice", Amount: 10000})
+	if err != nil {
+		t.Fatalf("Failed to resolve recipient: %v", err)
+	}
+	if !strings.EqualFold(recipient, alice) {
+		t.Errorf("Expected recipient %s, got %s", alice, recipient)
+	}
+	if _, err := resolve(&types.UTXO{Address: "bc1qunknown", Amount: 10000}); err == nil {
+		t.Error("Expected an address without an intent to fall through to the fallback")
+	}
+
+	deposit := &types.Transaction{
+		ID:          depositID("aa", 0),
+		Type:        types.TransactionTypeDeposit,
+		Status:      types.TransactionStatusConfirmed,
+		FromAddress: "bc1qalice",
+		Amount:      25000,
+		CreatedAt:   time.Now(),
+	}
+	if err := repo.SaveTransaction(deposit); err != nil {
+		t.Fatalf("Failed to save deposit: %v", err)
+	}
+
+	byAddress, err := intents.IntentByAddress("bc1qalice")
+	if err != nil {
+		t.Fatalf("Failed to get intent: %v", err)
+	}
+	if byAddress.Status != types.IntentStatusFunded || byAddress.Amount != 25000 || len(byAddress.Deposits) != 1 {
+		t.Errorf("Expected funded intent with the deposit, got %+v", byAddress)
+	}
+
+	deposit.Status = types.TransactionStatusCompleted
+	if err := repo.SaveTransaction(deposit); err != nil {
+		t.Fatalf("Failed to save deposit: %v", err)
+	}
+	byRecipient, err := intents.IntentsByRecipient(strings.ToUpper(alice))
+	if err != nil {
+		t.Fatalf("Failed to list intents: %v", err)
+	}
+	if len(byRecipient) != 1 || byRecipient[0].Status != types.IntentStatusCompleted {
+		t.Errorf("Expected one completed intent, got %+v", byRecipient)
+	}
+
+	// A completed intent is not handed out again
+	next, err := intents.CreateIntent(alice)
+	if err != nil {
+		t.Fatalf("Failed to create intent: %v", err)
+	}
+	if next.ID == intent.ID || next.Status != types.IntentStatusPending {
+		t.Errorf("Expected a new pending intent, got %+v", next)
+	}
+	stored, err := intents.GetIntent(intent.ID)
+	if err != nil {
+		t.Fatalf("Failed to get intent: %v", err)
+	}
+	if stored.Status != types.IntentStatusCompleted {
+		t.Errorf("Expected the deposit to stay with the first intent, got %s", stored.Status)
+	}
+}
+
+func TestIntentExpiresAndRejectsSharedAddress(t *testing.T) {
+	intents, _ := newTestIntents(t, staticAddresses{alice: "bc1qshared", bob: "bc1qshared"})
+	intents.ttl = -time.Second
+
+	intent, err := intents.CreateIntent(alice)
+	if err != nil {
+		t.Fatalf("Failed to create intent: %v", err)
+	}
+
+	expired, err := intents.GetIntent(intent.ID)
+	if err != nil {
+		t.Fatalf("Failed to get intent: %v", err)
+	}
+	if expired.Status != types.IntentStatusExpired {
+		t.Errorf("Expected expired intent, got %s", expired.Status)
+	}
+
+	if _, err := intents.CreateIntent(bob); !errors.Is(err, ErrAddressBound) {
+		t.Errorf("Expected ErrAddressBound, got %v", err)
+	}
+}
+
+func TestExpiredIntentsDoNotExhaustGapLimit(t *testing.T) {
+	repo := storetest.Open(t)
+
+	deriver, err := bitcoin.NewAddressDeriver(bitcoin.DeriverConfig{
+		AccountKey: "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs",
+		Network:    &chaincfg.MainNetParams,
+		Store:      repo,
+		GapLimit:   1,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create deriver: %v", err)
+	}
+	intents, err := NewIntentService(IntentConfig{Addresses: derivedAddresses{deriver}, Store: repo, TTL: -time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create intent service: %v", err)
+	}
+
+	expired, err := intents.CreateIntent(alice)
+	if err != nil {
+		t.Fatalf("Failed to create intent: %v", err)
+	}
+
+	// The expired, unfunded address makes room for the next recipient
+	intents.ttl = time.Hour
+	pending, err := intents.CreateIntent(bob)
+	if err != nil {
+		t.Fatalf("Expected the expired intent's address to be released, got %v", err)
+	}
+	if pending.DepositAddress == expired.DepositAddress {
+		t.Errorf("Expected a new address for bob, got %s", pending.DepositAddress)
+	}
+
+	// A pending intent still holds its place, and the released address
+	// stays bound to its recipient
+	if _, err := intents.CreateIntent("0x00000000000000000000000000000000000000cc"); !errors.Is(err, bitcoin.ErrGapLimit) {
+		t.Errorf("Expected ErrGapLimit while bob's intent is pending, got %v", err)
+	}
+	if recipient, ok := deriver.RecipientOf(expired.DepositAddress); !ok || !strings.EqualFold(recipient, alice) {
+		t.Errorf("Expected %s to stay assigned to alice, got %q", expired.DepositAddress, recipient)
+	}
+}
@@ -0,0 +1,640 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"bitbridge/internal/contracts"
+	"bitbridge/internal/ethereum"
+	"bitbridge/internal/indexer"
+	"bitbridge/internal/proof"
+	"bitbridge/internal/store"
+	"bitbridge/pkg/types"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrNotUnattributed is returned when resolving a deposit that is not
+// waiting for a recipient
+var ErrNotUnattributed = errors.New("deposit is not unattributed")
+
+// ErrNoRecipient is returned by a RecipientResolver when nothing binds the
+// deposit's address to a recipient
+var ErrNoRecipient = errors.New("no recipient bound to deposit address")
+
+// ErrBelowMinimum is returned by a RecipientResolver when a deposit is too
+// small to be minted
+var ErrBelowMinimum = errors.New("deposit is below the minimum amount")
+
+// RecipientResolver returns the Ethereum address that should receive the
+// token minted for a deposit UTXO
+type RecipientResolver func(utxo *types.UTXO) (string, error)
+
+// AddressRecipientResolver mints deposits to the recipient their address was
+// derived for. Other addresses have no recipient, and their deposits are
+// quarantined as unattributed.
+func AddressRecipientResolver(lookup func(address string) (string, bool)) RecipientResolver {
+	return func(utxo *types.UTXO) (string, error) {
+		if recipient, ok := lookup(utxo.Address); ok {
+			return recipient, nil
+		}
+		return "", fmt.Errorf("%w %s", ErrNoRecipient, utxo.Address)
+	}
+}
+
+// EventSource delivers UTXO events; *indexer.UTXOMonitor satisfies it
+type EventSource interface {
+	AddCallback(callback indexer.UTXOCallback)
+}
+
+// ProofGenerator builds SPV proofs for deposits; *proof.Service satisfies it
+type ProofGenerator interface {
+	GenerateProof(ctx context.Context, req *proof.ProofRequest) (*proof.ProofResponse, error)
+}
+
+// ProofVerifier submits SPV proofs to the SPVVerifier contract;
+// *contracts.Service satisfies it
+type ProofVerifier interface {
+	IsTransactionVerified(ctx context.Context, txHash string) (bool, error)
+	VerifyTransaction(ctx context.Context, req *contracts.VerificationRequest, spvProof *proof.SPVProof) (*contracts.VerificationResponse, error)
+}
+
+// UTXORegistry registers deposits in the UTXORegistry contract;
+// *ethereum.Service satisfies it
+type UTXORegistry interface {
+	GetUTXOStatus(ctx context.Context, btcTxHash string, vout uint32) (*ethereum.UTXORecord, error)
+	RegisterUTXO(ctx context.Context, btcTxHash string, vout uint32, amount *big.Int, btcAddress string, owner common.Address) (common.Address, *ethtypes.Transaction, error)
+}
+
+// Orchestrator turns confirmed Bitcoin deposits into UTXO tokens. Each
+// deposit is tracked as a types.Transaction that moves through
+// pending -> confirmed -> proof_submitted -> completed, and is persisted after
+// every transition so that processing resumes where it stopped.
+type Orchestrator struct {
+	monitor          EventSource
+	proofService     ProofGenerator
+	contractsService ProofVerifier
+	ethereumService  UTXORegistry
+	store            store.Store
+	resolveRecipient RecipientResolver
+	requiredConfirms int
+	maxAttempts      int
+	retryInterval    time.Duration
+	inFlight         map[string]bool
+	proofs           map[string]*proof.SPVProof
+	mu               sync.Mutex
+	ctx              context.Context
+	cancel           context.CancelFunc
+}
+
+// Config for the deposit orchestrator
+type Config struct {
+	Monitor               EventSource
+	ProofService          ProofGenerator
+	ContractsService      ProofVerifier
+	EthereumService       UTXORegistry
+	Store                 store.Store
+	RecipientResolver     RecipientResolver
+	RequiredConfirmations int
+	MaxAttempts           int
+	RetryInterval         time.Duration
+}
+
+func NewOrchestrator(config Config) (*Orchestrator, error) {
+	if config.Monitor == nil || config.ProofService == nil || config.ContractsService == nil || config.EthereumService == nil {
+		return nil, fmt.Errorf("monitor, proof, contracts and ethereum services are required")
+	}
+	if config.Store == nil {
+		return nil, fmt.Errorf("store is required")
+	}
+	if config.RecipientResolver == nil {
+		return nil, fmt.Errorf("recipient resolver is required")
+	}
+	if config.RequiredConfirmations == 0 {
+		config.RequiredConfirmations = 6
+	}
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = 10
+	}
+	if config.RetryInterval == 0 {
+		config.RetryInterval = time.Minute
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Orchestrator{
+		monitor:          config.Monitor,
+		proofService:     config.ProofService,
+		contractsService: config.ContractsService,
+		ethereumService:  config.EthereumService,
+		store:            config.Store,
+		resolveRecipient: config.RecipientResolver,
+		requiredConfirms: config.RequiredConfirmations,
+		maxAttempts:      config.MaxAttempts,
+		retryInterval:    config.RetryInterval,
+		inFlight:         make(map[string]bool),
+		proofs:           make(map[string]*proof.SPVProof),
+		ctx:              ctx,
+		cancel:           cancel,
+	}, nil
+}
+
+// Start subscribes to UTXO events and resumes any unfinished deposits
+func (o *Orchestrator) Start() {
+	log.Println("Starting bridge orchestrator...")
+
+	o.monitor.AddCallback(o.HandleUTXOEvent)
+	go o.retryLoop()
+}
+
+// Stop halts background processing
+func (o *Orchestrator) Stop() {
+	log.Println("Stopping bridge orchestrator...")
+	o.cancel()
+}
+
+// HandleUTXOEvent records new deposits and advances them once they reach
+// the confirmation threshold
+func (o *Orchestrator) HandleUTXOEvent(utxo *types.UTXO, event string) {
+	if event == indexer.EventReorg {
+		o.handleReorg(utxo)
+		return
+	}
+	if event != indexer.EventNew && event != indexer.EventConfirmationUpdate {
+		return
+	}
+	// Change from the bridge's own payouts backs no new tokens
+	if utxo.Change {
+		return
+	}
+
+	tx, err := o.recordDeposit(utxo)
+	if err != nil {
+		log.Printf("Failed to record deposit %s:%d: %v", utxo.TxID, utxo.Vout, err)
+		return
+	}
+
+	o.process(tx.ID)
+}
+
+// GetDeposit returns the deposit transaction for a UTXO
+func (o *Orchestrator) GetDeposit(txid string, vout uint32) (*types.Transaction, error) {
+	return o.store.GetTransaction(depositID(txid, vout))
+}
+
+// ListDeposits returns all deposit transactions
+func (o *Orchestrator) ListDeposits() ([]*types.Transaction, error) {
+	return o.store.ListTransactions(types.TransactionTypeDeposit)
+}
+
+// ListUnattributed returns the quarantined deposits to the shared memo
+// address whose recipient could not be determined
+func (o *Orchestrator) ListUnattributed() ([]*types.Transaction, error) {
+	deposits, err := o.store.ListTransactions(types.TransactionTypeDeposit)
+	if err != nil {
+		return nil, err
+	}
+
+	var unattributed []*types.Transaction
+	for _, tx := range deposits {
+		if tx.Status == types.TransactionStatusUnattributed {
+			unattributed = append(unattributed, tx)
+		}
+	}
+	return unattributed, nil
+}
+
+// ResolveUnattributed binds a quarantined deposit to a recipient and
+// releases it into the deposit pipeline
+func (o *Orchestrator) ResolveUnattributed(id, recipient string) (*types.Transaction, error) {
+	if !common.IsHexAddress(recipient) {
+		return nil, fmt.Errorf("invalid recipient address: %s", recipient)
+	}
+
+	o.mu.Lock()
+	tx, err := o.store.GetTransaction(id)
+	if err != nil {
+		o.mu.Unlock()
+		return nil, err
+	}
+	if tx.Type != types.TransactionTypeDeposit || tx.Status != types.TransactionStatusUnattributed {
+		o.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s is not an unattributed deposit", ErrNotUnattributed, id)
+	}
+
+	tx.ToAddress = common.HexToAddress(recipient).Hex()
+	tx.Status = types.TransactionStatusPending
+	if tx.Confirmations >= tx.RequiredConfirms {
+		tx.Status = types.TransactionStatusConfirmed
+	}
+	tx.Error = ""
+	tx.Attempts = 0
+	tx.UpdatedAt = time.Now()
+	err = o.store.SaveTransaction(tx)
+	o.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist deposit: %w", err)
+	}
+
+	log.Printf("Deposit %s attributed to %s", id, tx.ToAddress)
+	go o.process(id)
+	return tx, nil
+}
+
+// recordDeposit creates the deposit transaction on first sight and keeps
+// its confirmation count up to date
+func (o *Orchestrator) recordDeposit(utxo *types.UTXO) (*types.Transaction, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	id := depositID(utxo.TxID, utxo.Vout)
+	now := time.Now()
+
+	tx, err := o.store.GetTransaction(id)
+	if errors.Is(err, store.ErrNotFound) {
+		tx = &types.Transaction{
+			ID:               id,
+			Type:             types.TransactionTypeDeposit,
+			Status:           types.TransactionStatusPending,
+			BitcoinTxID:      utxo.TxID,
+			BitcoinVout:      utxo.Vout,
+			Amount:           utxo.Amount,
+			FromAddress:      utxo.Address,
+			CreatedAt:        now,
+			RequiredConfirms: o.requiredConfirms,
+		}
+		switch {
+		case utxo.Recipient != "":
+			// Named by an OP_RETURN memo on the shared deposit address
+			tx.ToAddress = utxo.Recipient
+		case utxo.MemoError != "":
+			tx.Status = types.TransactionStatusUnattributed
+			tx.Error = utxo.MemoError
+			log.Printf("Quarantining deposit %s as unattributed: %s", id, utxo.MemoError)
+		default:
+			// Nothing is minted for an address without a binding or for a
+			// deposit below its minimum, so no proof is submitted for it
+			// either
+			if _, err := o.resolveRecipient(utxo); errors.Is(err, ErrNoRecipient) || errors.Is(err, ErrBelowMinimum) {
+				tx.Status = types.TransactionStatusUnattributed
+				tx.Error = err.Error()
+				log.Printf("Quarantining deposit %s as unattributed: %v", id, err)
+			}
+		}
+		log.Printf("Recording new deposit %s (%d sats to %s)", id, utxo.Amount, utxo.Address)
+	} else if err != nil {
+		return nil, err
+	} else if tx.Confirmations == utxo.Confirmations || o.inFlight[id] {
+		// In-flight deposits are owned by process(); saving here could
+		// overwrite a newer status with this stale copy
+		return tx, nil
+	}
+
+	tx.Confirmations = utxo.Confirmations
+	if tx.Status == types.TransactionStatusPending && tx.Confirmations >= tx.RequiredConfirms {
+		tx.Status = types.TransactionStatusConfirmed
+	}
+	tx.UpdatedAt = now
+
+	if err := o.store.SaveTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// handleReorg returns a deposit whose block was orphaned to pending. Once
+// its proof has been submitted the deposit can no longer be rolled back
+// here, so it is only flagged for the operator.
+func (o *Orchestrator) handleReorg(utxo *types.UTXO) {
+	if utxo.BlockHeight != 0 {
+		// Only the spending block was orphaned
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	id := depositID(utxo.TxID, utxo.Vout)
+	tx, err := o.store.GetTransaction(id)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			log.Printf("Failed to load deposit %s after reorg: %v", id, err)
+		}
+		return
+	}
+
+	if o.inFlight[id] || (tx.Status != types.TransactionStatusPending && tx.Status != types.TransactionStatusConfirmed) {
+		log.Printf("Warning: deposit %s was reorged out while %s", id, tx.Status)
+		return
+	}
+
+	tx.Status = types.TransactionStatusPending
+	tx.Confirmations = 0
+	tx.UpdatedAt = time.Now()
+	if err := o.store.SaveTransaction(tx); err != nil {
+		log.Printf("Failed to persist deposit %s after reorg: %v", id, err)
+		return
+	}
+	log.Printf("Deposit %s was reorged out, waiting for it to confirm again", id)
+}
+
+// process runs the deposit pipeline for a transaction unless another
+// goroutine is already working on it
+func (o *Orchestrator) process(id string) {
+	o.mu.Lock()
+	if o.inFlight[id] {
+		o.mu.Unlock()
+		return
+	}
+	o.inFlight[id] = true
+	o.mu.Unlock()
+
+	defer func() {
+		o.mu.Lock()
+		delete(o.inFlight, id)
+		o.mu.Unlock()
+	}()
+
+	tx, err := o.store.GetTransaction(id)
+	if err != nil {
+		log.Printf("Failed to load deposit %s: %v", id, err)
+		return
+	}
+
+	if err := o.advance(tx); err != nil {
+		o.recordFailure(tx, err)
+	}
+}
+
+// advance executes the remaining pipeline steps for a deposit, persisting
+// the transaction after each one
+func (o *Orchestrator) advance(tx *types.Transaction) error {
+	for {
+		var err error
+
+		switch tx.Status {
+		case types.TransactionStatusConfirmed:
+			err = o.submitProof(tx)
+		case types.TransactionStatusProofSubmitted:
+			err = o.registerUTXO(tx)
+		default:
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		// A quarantined deposit keeps the reason it was set aside
+		if tx.Status != types.TransactionStatusUnattributed {
+			tx.Error = ""
+		}
+		tx.Attempts = 0
+		tx.UpdatedAt = time.Now()
+		if err := o.store.SaveTransaction(tx); err != nil {
+			return fmt.Errorf("failed to persist deposit: %w", err)
+		}
+
+		log.Printf("Deposit %s advanced to %s", tx.ID, tx.Status)
+	}
+}
+
+// submitProof generates an SPV proof for the deposit and submits it to the
+// SPVVerifier contract
+func (o *Orchestrator) submitProof(tx *types.Transaction) error {
+	ctx, cancel := context.WithTimeout(o.ctx, 10*time.Minute)
+	defer cancel()
+
+	proofResp, err := o.proofService.GenerateProof(ctx, &proof.ProofRequest{
+		TxHash:                tx.BitcoinTxID,
+		OutputIndex:           tx.BitcoinVout,
+		RequiredConfirmations: int32(tx.RequiredConfirms),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate proof: %w", err)
+	}
+
+	// A previous run may have crashed after the proof was accepted on-chain
+	verified, err := o.contractsService.IsTransactionVerified(ctx, proofResp.Proof.MerkleProof.TxHash)
+	if err != nil {
+		return fmt.Errorf("failed to check verification status: %w", err)
+	}
+
+	if !verified {
+		verifyResp, err := o.contractsService.VerifyTransaction(ctx, &contracts.VerificationRequest{
+			TxHash:      tx.BitcoinTxID,
+			OutputIndex: tx.BitcoinVout,
+			BlockHeight: uint64(proofResp.Proof.BlockHeight),
+		}, proofResp.Proof)
+		if err != nil {
+			return fmt.Errorf("failed to submit proof: %w", err)
+		}
+		if !verifyResp.Verified {
+			return fmt.Errorf("proof rejected by SPV verifier in %s", verifyResp.TransactionHash)
+		}
+		tx.VerifyTxHash = verifyResp.TransactionHash
+	}
+
+	// Kept for registerUTXO, which reads the deposit amount from it
+	o.mu.Lock()
+	o.proofs[tx.ID] = proofResp.Proof
+	o.mu.Unlock()
+
+	tx.Status = types.TransactionStatusProofSubmitted
+	return nil
+}
+
+// registerUTXO registers the deposit in the UTXORegistry, which mints the
+// UTXO token to the depositor
+func (o *Orchestrator) registerUTXO(tx *types.Transaction) error {
+	ctx, cancel := context.WithTimeout(o.ctx, 10*time.Minute)
+	defer cancel()
+
+	record, err := o.ethereumService.GetUTXOStatus(ctx, tx.BitcoinTxID, tx.BitcoinVout)
+	if err != nil {
+		return fmt.Errorf("failed to check UTXO status: %w", err)
+	}
+
+	if record == nil {
+		// Memo deposits and resolved unattributed ones are already bound
+		recipient := tx.ToAddress
+		if recipient == "" {
+			recipient, err = o.resolveRecipient(&types.UTXO{
+				TxID:          tx.BitcoinTxID,
+				Vout:          tx.BitcoinVout,
+				Amount:        tx.Amount,
+				Address:       tx.FromAddress,
+				Confirmations: tx.Confirmations,
+			})
+			if errors.Is(err, ErrNoRecipient) || errors.Is(err, ErrBelowMinimum) {
+				tx.Status = types.TransactionStatusUnattributed
+				tx.Error = err.Error()
+				log.Printf("Quarantining deposit %s as unattributed: %v", tx.ID, err)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to resolve recipient: %w", err)
+			}
+		}
+		if !common.IsHexAddress(recipient) {
+			return fmt.Errorf("invalid recipient address: %s", recipient)
+		}
+		tx.ToAddress = recipient
+
+		amount, err := o.provenAmount(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		tokenAddr, ethTx, err := o.ethereumService.RegisterUTXO(ctx, tx.BitcoinTxID, tx.BitcoinVout, big.NewInt(amount), tx.FromAddress, common.HexToAddress(recipient))
+		if ethTx != nil {
+			tx.EthereumTxHash = ethTx.Hash().Hex()
+		}
+		switch {
+		case err == nil:
+			tx.TokenAddress = tokenAddr.Hex()
+		case errors.Is(err, ethereum.ErrUTXOAlreadyRegistered):
+			// A registration sent by an earlier attempt was mined first
+			record, err = o.ethereumService.GetUTXOStatus(ctx, tx.BitcoinTxID, tx.BitcoinVout)
+			if err != nil {
+				return fmt.Errorf("failed to check UTXO status: %w", err)
+			}
+		default:
+			return fmt.Errorf("failed to register UTXO: %w", err)
+		}
+	}
+
+	if record != nil {
+		tx.TokenAddress = record.TokenAddress.Hex()
+		tx.ToAddress = record.TokenOwner.Hex()
+	}
+	if tx.TokenAddress == "" {
+		return fmt.Errorf("no token recorded for UTXO %s:%d", tx.BitcoinTxID, tx.BitcoinVout)
+	}
+
+	if err := o.saveToken(tx); err != nil {
+		return fmt.Errorf("failed to record token: %w", err)
+	}
+
+	tx.Status = types.TransactionStatusCompleted
+	return nil
+}
+
+// provenAmount reads the deposit amount from the output bound to its SPV
+// proof, so the registered amount is what the Bitcoin transaction actually
+// paid rather than what the indexer reported
+func (o *Orchestrator) provenAmount(ctx context.Context, tx *types.Transaction) (int64, error) {
+	o.mu.Lock()
+	spvProof := o.proofs[tx.ID]
+	delete(o.proofs, tx.ID)
+	o.mu.Unlock()
+
+	if spvProof == nil {
+		// The proof was submitted before a restart or an earlier failed
+		// attempt; the proof service still has it cached
+		proofResp, err := o.proofService.GenerateProof(ctx, &proof.ProofRequest{
+			TxHash:                tx.BitcoinTxID,
+			OutputIndex:           tx.BitcoinVout,
+			RequiredConfirmations: int32(tx.RequiredConfirms),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to generate proof: %w", err)
+		}
+		spvProof = proofResp.Proof
+	}
+
+	txOut, err := proof.VerifyOutputProof(spvProof.Output, spvProof.MerkleProof.TxHash)
+	if err != nil {
+		return 0, fmt.Errorf("failed to verify deposit output: %w", err)
+	}
+
+	if txOut.Value != tx.Amount {
+		log.Printf("Deposit %s amount %d differs from proven output value %d, using proven value", tx.ID, tx.Amount, txOut.Value)
+		tx.Amount = txOut.Value
+	}
+	return txOut.Value, nil
+}
+
+// saveToken records the UTXO token minted for a deposit
+func (o *Orchestrator) saveToken(tx *types.Transaction) error {
+	return o.store.SaveToken(&types.UTXOToken{
+		ID:           fmt.Sprintf("%s:%d", tx.BitcoinTxID, tx.BitcoinVout),
+		TokenAddress: tx.TokenAddress,
+		UTXO: types.UTXO{
+			TxID:          tx.BitcoinTxID,
+			Vout:          tx.BitcoinVout,
+			Amount:        tx.Amount,
+			Address:       tx.FromAddress,
+			Confirmations: tx.Confirmations,
+			CreatedAt:     tx.CreatedAt,
+		},
+		TokenName:    "UTXO_" + tx.BitcoinTxID,
+		TokenSymbol:  fmt.Sprintf("UTXO%d", tx.BitcoinVout),
+		TotalSupply:  tx.Amount,
+		OwnerAddress: tx.ToAddress,
+		Status:       "active",
+		CreatedAt:    time.Now(),
+	})
+}
+
+// recordFailure stores the error on the transaction and gives up once the
+// attempt budget is exhausted
+func (o *Orchestrator) recordFailure(tx *types.Transaction, cause error) {
+	tx.Attempts++
+	tx.Error = cause.Error()
+	tx.UpdatedAt = time.Now()
+
+	if tx.Attempts >= o.maxAttempts {
+		tx.Status = types.TransactionStatusFailed
+		log.Printf("Deposit %s failed after %d attempts: %v", tx.ID, tx.Attempts, cause)
+	} else {
+		log.Printf("Deposit %s step failed (attempt %d/%d): %v", tx.ID, tx.Attempts, o.maxAttempts, cause)
+	}
+
+	if err := o.store.SaveTransaction(tx); err != nil {
+		log.Printf("Failed to persist deposit %s: %v", tx.ID, err)
+	}
+}
+
+// retryLoop resumes deposits that were interrupted by a crash or a failed step
+func (o *Orchestrator) retryLoop() {
+	o.resumePending()
+
+	ticker := time.NewTicker(o.retryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-o.ctx.Done():
+			log.Println("Bridge orchestrator stopped")
+			return
+		case <-ticker.C:
+			o.resumePending()
+		}
+	}
+}
+
+func (o *Orchestrator) resumePending() {
+	deposits, err := o.store.ListTransactions(types.TransactionTypeDeposit)
+	if err != nil {
+		log.Printf("Failed to list deposits: %v", err)
+		return
+	}
+
+	for _, tx := range deposits {
+		if tx.Status == types.TransactionStatusConfirmed || tx.Status == types.TransactionStatusProofSubmitted {
+			o.process(tx.ID)
+		}
+	}
+}
+
+func depositID(txid string, vout uint32) string {
+	return fmt.Sprintf("deposit:%s:%d", txid, vout)
+}
@@ -0,0 +1,381 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"bitbridge/internal/contracts"
+	"bitbridge/internal/ethereum"
+	"bitbridge/internal/indexer"
+	"bitbridge/internal/proof"
+	"bitbridge/internal/store"
+	"bitbridge/internal/store/storetest"
+	"bitbridge/pkg/types"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+type fakeEvents struct{}
+
+func (fakeEvents) AddCallback(callback indexer.UTXOCallback) {}
+
+// fakeProofs proves outputs of transactions it was given
+type fakeProofs struct {
+	mu        sync.Mutex
+	txs       map[string]*wire.MsgTx
+	generated int
+}
+
+func (p *fakeProofs) GenerateProof(ctx context.Context, req *proof.ProofRequest) (*proof.ProofResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.generated++
+	tx, ok := p.txs[req.TxHash]
+	if !ok {
+		return nil, fmt.Errorf("transaction %s not found", req.TxHash)
+	}
+	output, err := proof.NewOutputProof(tx, req.OutputIndex)
+	if err != nil {
+		return nil, err
+	}
+	return &proof.ProofResponse{
+		Proof: &proof.SPVProof{
+			MerkleProof: &proof.MerkleProof{TxHash: req.TxHash},
+			BlockHeight: 100,
+			Output:      output,
+		},
+		Verified: true,
+	}, nil
+}
+
+type fakeVerifier struct {
+	mu       sync.Mutex
+	verified map[string]bool
+	submits  int
+	fail     error
+}
+
+func (v *fakeVerifier) IsTransactionVerified(ctx context.Context, txHash string) (bool, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.verified[txHash], nil
+}
+
+func (v *fakeVerifier) VerifyTransaction(ctx context.Context, req *contracts.VerificationRequest, spvProof *proof.SPVProof) (*contracts.VerificationResponse, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.fail != nil {
+		return nil, v.fail
+	}
+	v.submits++
+	v.verified[req.TxHash] = true
+	return &contracts.VerificationResponse{Verified: true, TransactionHash: fmt.Sprintf("0x%064x", v.submits)}, nil
+}
+
+type fakeRegistry struct {
+	mu      sync.Mutex
+	records map[string]*ethereum.UTXORecord
+	fail    error
+}
+
+func (r *fakeRegistry) GetUTXOStatus(ctx context.Context, btcTxHash string, vout uint32) (*ethereum.UTXORecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.records[fmt.Sprintf("%s:%d", btcTxHash, vout)], nil
+}
+
+func (r *fakeRegistry) RegisterUTXO(ctx context.Context, btcTxHash string, vout uint32, amount *big.Int, btcAddress string, owner common.Address) (common.Address, *ethtypes.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.fail != nil {
+		return common.Address{}, nil, r.fail
+	}
+	token := common.BigToAddress(big.NewInt(int64(len(r.records) + 1)))
+	r.records[fmt.Sprintf("%s:%d", btcTxHash, vout)] = &ethereum.UTXORecord{
+		BitcoinTxID:    btcTxHash,
+		BitcoinVout:    vout,
+		BitcoinAmount:  amount,
+		BitcoinAddress: btcAddress,
+		TokenAddress:   token,
+		TokenOwner:     owner,
+		IsActive:       true,
+	}
+	return token, ethtypes.NewTx(&ethtypes.LegacyTx{Nonce: uint64(len(r.records))}), nil
+}
+
+type orchestratorFixture struct {
+	events   fakeEvents
+	proofs   *fakeProofs
+	verifier *fakeVerifier
+	registry *fakeRegistry
+	repo     *store.BoltStore
+	resolver RecipientResolver
+}
+
+func newOrchestratorFixture(t *testing.T) *orchestratorFixture {
+	t.Helper()
+
+	repo := storetest.Open(t)
+
+	return &orchestratorFixture{
+		events:   fakeEvents{},
+		proofs:   &fakeProofs{txs: make(map[string]*wire.MsgTx)},
+		verifier: &fakeVerifier{verified: make(map[string]bool)},
+		registry: &fakeRegistry{records: make(map[string]*ethereum.UTXORecord)},
+		repo:     repo,
+	}
+}
+
+// orchestrator starts an orchestrator over the fixture, as after a restart
+// when called again
+func (f *orchestratorFixture) orchestrator(t *testing.T) *Orchestrator {
+	t.Helper()
+
+	bound := map[string]string{"bc1qalice": alice}
+	resolver := f.resolver
+	if resolver == nil {
+		resolver = AddressRecipientResolver(func(address string) (string, bool) {
+			recipient, ok := bound[address]
+			return recipient, ok
+		})
+	}
+	o, err := NewOrchestrator(Config{
+		Monitor:               f.events,
+		ProofService:          f.proofs,
+		ContractsService:      f.verifier,
+		EthereumService:       f.registry,
+		Store:                 f.repo,
+		RecipientResolver:     resolver,
+		RequiredConfirmations: 6,
+		MaxAttempts:           3,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create orchestrator: %v", err)
+	}
+	t.Cleanup(o.Stop)
+	return o
+}
+
+// deposit returns a UTXO paying amount to address in a transaction the
+// fake proof service can prove
+func (f *orchestratorFixture) deposit(address string, amount int64, confirmations int) *types.UTXO {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{byte(len(f.proofs.txs) + 1)}, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(amount, []byte{0x00, 0x14, 0xaa}))
+	f.proofs.txs[tx.TxHash().String()] = tx
+
+	return &types.UTXO{
+		TxID:          tx.TxHash().String(),
+		Vout:          0,
+		Amount:        amount,
+		Address:       address,
+		Confirmations: confirmations,
+		BlockHeight:   100,
+	}
+}
+
+func expectDeposit(t *testing.T, o *Orchestrator, utxo *types.UTXO, status string) *types.Transaction {
+	t.Helper()
+
+	tx, err := o.GetDeposit(utxo.TxID, utxo.Vout)
+	if err != nil {
+		t.Fatalf("Failed to get deposit: %v", err)
+	}
+	if tx.Status != status {
+		t.Fatalf("Expected deposit to be %s, got %s (%s)", status, tx.Status, tx.Error)
+	}
+	return tx
+}
+
+func TestOrchestratorMintsConfirmedDeposit(t *testing.T) {
+	f := newOrchestratorFixture(t)
+	o := f.orchestrator(t)
+
+	utxo := f.deposit("bc1qalice", 50000, 1)
+	o.HandleUTXOEvent(utxo, indexer.EventNew)
+	expectDeposit(t, o, utxo, types.TransactionStatusPending)
+	if f.verifier.submits != 0 {
+		t.Fatal("Expected no proof before the deposit is confirmed")
+	}
+
+	utxo.Confirmations = 6
+	o.HandleUTXOEvent(utxo, indexer.EventConfirmationUpdate)
+	tx := expectDeposit(t, o, utxo, types.TransactionStatusCompleted)
+	if f.verifier.submits != 1 || tx.VerifyTxHash == "" {
+		t.Errorf("Expected one proof submission, got %d", f.verifier.submits)
+	}
+	if f.proofs.generated != 1 {
+		t.Errorf("Expected the submitted proof to be reused for registration, got %d proofs", f.proofs.generated)
+	}
+	if tx.EthereumTxHash == "" || tx.TokenAddress == "" || common.HexToAddress(tx.ToAddress) != common.HexToAddress(alice) {
+		t.Errorf("Unexpected completed deposit %+v", tx)
+	}
+
+	token, err := f.repo.GetToken(fmt.Sprintf("%s:%d", utxo.TxID, utxo.Vout))
+	if err != nil {
+		t.Fatalf("Failed to get token: %v", err)
+	}
+	if token.TokenAddress != tx.TokenAddress || token.TotalSupply != 50000 {
+		t.Errorf("Unexpected token %+v", token)
+	}
+}
+
+func TestOrchestratorRetriesAndResumesAfterRestart(t *testing.T) {
+	f := newOrchestratorFixture(t)
+	f.registry.fail = fmt.Errorf("registry unavailable")
+	o := f.orchestrator(t)
+
+	utxo := f.deposit("bc1qalice", 50000, 6)
+	o.HandleUTXOEvent(utxo, indexer.EventNew)
+	tx := expectDeposit(t, o, utxo, types.TransactionStatusProofSubmitted)
+	if tx.Attempts != 1 || tx.Error == "" {
+		t.Errorf("Expected the failed registration to be recorded, got %d attempts (%q)", tx.Attempts, tx.Error)
+	}
+
+	// A fresh orchestrator picks the deposit up where the last one stopped
+	f.registry.fail = nil
+	o.Stop()
+	restarted := f.orchestrator(t)
+	restarted.resumePending()
+
+	tx = expectDeposit(t, restarted, utxo, types.TransactionStatusCompleted)
+	if tx.Attempts != 0 || tx.Error != "" {
+		t.Errorf("Expected the failure to be cleared, got %d attempts (%q)", tx.Attempts, tx.Error)
+	}
+	if f.verifier.submits != 1 {
+		t.Errorf("Expected the proof to be submitted once, got %d", f.verifier.submits)
+	}
+}
+
+func TestOrchestratorGivesUpAfterMaxAttempts(t *testing.T) {
+	f := newOrchestratorFixture(t)
+	f.verifier.fail = fmt.Errorf("verifier unavailable")
+	o := f.orchestrator(t)
+
+	utxo := f.deposit("bc1qalice", 50000, 6)
+	o.HandleUTXOEvent(utxo, indexer.EventNew)
+	o.resumePending()
+	o.resumePending()
+
+	tx := expectDeposit(t, o, utxo, types.TransactionStatusFailed)
+	if tx.Attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", tx.Attempts)
+	}
+
+	o.resumePending()
+	if tx := expectDeposit(t, o, utxo, types.TransactionStatusFailed); tx.Attempts != 3 {
+		t.Errorf("Expected failed deposits not to be retried, got %d attempts", tx.Attempts)
+	}
+}
+
+func TestOrchestratorResetsReorgedDeposit(t *testing.T) {
+	f := newOrchestratorFixture(t)
+	f.verifier.fail = fmt.Errorf("verifier unavailable")
+	o := f.orchestrator(t)
+
+	utxo := f.deposit("bc1qalice", 50000, 6)
+	o.HandleUTXOEvent(utxo, indexer.EventNew)
+	expectDeposit(t, o, utxo, types.TransactionStatusConfirmed)
+
+	// Orphaning the spending block leaves the deposit alone
+	o.HandleUTXOEvent(utxo, indexer.EventReorg)
+	expectDeposit(t, o, utxo, types.TransactionStatusConfirmed)
+
+	reorged := *utxo
+	reorged.BlockHeight = 0
+	reorged.Confirmations = 0
+	o.HandleUTXOEvent(&reorged, indexer.EventReorg)
+	if tx := expectDeposit(t, o, utxo, types.TransactionStatusPending); tx.Confirmations != 0 {
+		t.Errorf("Expected confirmations to be reset, got %d", tx.Confirmations)
+	}
+
+	o.resumePending()
+	if f.verifier.submits != 0 {
+		t.Fatal("Expected a reorged deposit not to be retried before it confirms again")
+	}
+
+	// Once it is mined again the deposit completes
+	f.verifier.fail = nil
+	utxo.Confirmations = 6
+	o.HandleUTXOEvent(utxo, indexer.EventConfirmationUpdate)
+	expectDeposit(t, o, utxo, types.TransactionStatusCompleted)
+
+	// A completed deposit can no longer be rolled back
+	o.HandleUTXOEvent(&reorged, indexer.EventReorg)
+	expectDeposit(t, o, utxo, types.TransactionStatusCompleted)
+}
+
+func TestOrchestratorQuarantinesDepositsBelowMinimum(t *testing.T) {
+	f := newOrchestratorFixture(t)
+	f.resolver = func(utxo *types.UTXO) (string, error) {
+		if utxo.Amount < 10000 {
+			return "", fmt.Errorf("%w: %d sats", ErrBelowMinimum, utxo.Amount)
+		}
+		return alice, nil
+	}
+	o := f.orchestrator(t)
+
+	utxo := f.deposit("bc1qalice", 9999, 6)
+	o.HandleUTXOEvent(utxo, indexer.EventNew)
+	tx := expectDeposit(t, o, utxo, types.TransactionStatusUnattributed)
+	if tx.Error == "" {
+		t.Error("Expected the quarantine reason to be recorded")
+	}
+
+	// Nothing is sent on chain, not even on the next confirmation
+	utxo.Confirmations++
+	o.HandleUTXOEvent(utxo, indexer.EventConfirmationUpdate)
+	time.Sleep(50 * time.Millisecond)
+	expectDeposit(t, o, utxo, types.TransactionStatusUnattributed)
+	if f.verifier.submits != 0 || len(f.registry.records) != 0 {
+		t.Fatal("Expected nothing to be proven or minted for a deposit below the minimum")
+	}
+}
+
+func TestOrchestratorQuarantinesUnboundDeposits(t *testing.T) {
+	f := newOrchestratorFixture(t)
+	o := f.orchestrator(t)
+
+	utxo := f.deposit("bc1qunbound", 50000, 6)
+	o.HandleUTXOEvent(utxo, indexer.EventNew)
+	tx := expectDeposit(t, o, utxo, types.TransactionStatusUnattributed)
+	if tx.Error == "" {
+		t.Error("Expected the quarantine reason to be recorded")
+	}
+	if f.verifier.submits != 0 || len(f.registry.records) != 0 {
+		t.Fatal("Expected nothing to be proven or minted for an unbound deposit")
+	}
+
+	unattributed, err := o.ListUnattributed()
+	if err != nil || len(unattributed) != 1 || unattributed[0].ID != tx.ID {
+		t.Fatalf("Expected the deposit to be listed as unattributed, got %v (%v)", unattributed, err)
+	}
+
+	if _, err := o.ResolveUnattributed(tx.ID, bob); err != nil {
+		t.Fatalf("Failed to resolve deposit: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		tx, err = o.GetDeposit(utxo.TxID, utxo.Vout)
+		if err == nil && tx.Status == types.TransactionStatusCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for the resolved deposit to complete: %+v", tx)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if common.HexToAddress(tx.ToAddress) != common.HexToAddress(bob) {
+		t.Errorf("Expected the deposit to be minted to %s, got %s", bob, tx.ToAddress)
+	}
+}
@@ -0,0 +1,796 @@
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"bitbridge/internal/bitcoin"
+	"bitbridge/internal/ethereum"
+	"bitbridge/internal/store"
+	"bitbridge/pkg/types"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// maxLogRange bounds the block span of a single eth_getLogs request
+const maxLogRange = 2000
+
+const withdrawalCheckpointKey = "withdrawal_scan_checkpoint"
+
+// scanCheckpoint is the last block fully scanned for redemption events
+type scanCheckpoint struct {
+	LastBlock uint64 `json:"last_block"`
+}
+
+// WithdrawalService pays out bitcoin for redeemed UTXO tokens. It tails
+// UTXOToken.UTXORedeemed events, sends the payout and marks the UTXO redeemed
+// in the registry once the payout is confirmed. Payouts are built from the
+// bridge's tracked UTXOs when a builder is configured and sent from the node
+// wallet otherwise. Each withdrawal is tracked as a types.Transaction that
+// moves through pending -> sending -> broadcast -> confirmed -> completed,
+// or through queued and batched instead of sending when payouts are batched.
+// The payout tracker bumps the fee of payouts stuck in broadcast and links
+// every replacement to the withdrawal.
+type WithdrawalService struct {
+	btcClient        *bitcoin.Client
+	builder          *bitcoin.WithdrawalBuilder
+	tracker          *bitcoin.PayoutTracker
+	feeRate          int64
+	feeEstimator     *bitcoin.FeeEstimator
+	feeTarget        int64
+	batchInterval    time.Duration
+	batchSize        int
+	ethereumService  *ethereum.Service
+	store            store.Store
+	requiredConfirms int
+	maxAttempts      int
+	pollInterval     time.Duration
+	finalityDepth    uint64
+	nextBlock        uint64
+	ctx              context.Context
+	cancel           context.CancelFunc
+}
+
+// WithdrawalConfig for the withdrawal service
+type WithdrawalConfig struct {
+	BitcoinClient         *bitcoin.Client
+	Builder               *bitcoin.WithdrawalBuilder // optional, builds payouts from tracked UTXOs instead of the node wallet
+	Tracker               *bitcoin.PayoutTracker     // optional, follows payouts and bumps stuck ones; without it fees are never bumped
+	FeeRate               int64                      // sat/vB for built payouts, and when the fee estimator fails
+	FeeEstimator          *bitcoin.FeeEstimator      // optional, prices built payouts from current fees instead of FeeRate
+	FeeTarget             int64                      // blocks built payouts aim to confirm in, 6 by default
+	BatchInterval         time.Duration              // longest a withdrawal waits to be batched, 0 disables batching
+	BatchSize             int                        // queued withdrawals that flush a batch early, and the most per batch
+	EthereumService       *ethereum.Service
+	Store                 store.Store
+	RequiredConfirmations int
+	MaxAttempts           int
+	PollInterval          time.Duration
+	StartBlock            uint64 // first block to scan; 0 resumes from the store or the chain head
+	FinalityDepth         uint64 // Ethereum blocks to wait before acting on an event
+}
+
+func NewWithdrawalService(config WithdrawalConfig) (*WithdrawalService, error) {
+	if config.BitcoinClient == nil || config.EthereumService == nil {
+		return nil, fmt.Errorf("bitcoin client and ethereum service are required")
+	}
+	if config.Store == nil {
+		return nil, fmt.Errorf("store is required")
+	}
+	if config.RequiredConfirmations == 0 {
+		config.RequiredConfirmations = 6
+	}
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = 10
+	}
+	if config.PollInterval == 0 {
+		config.PollInterval = 15 * time.Second
+	}
+	if config.FeeRate == 0 {
+		config.FeeRate = 2
+	}
+	if config.FeeTarget == 0 {
+		config.FeeTarget = 6
+	}
+	if config.BatchInterval > 0 && config.Builder == nil {
+		return nil, fmt.Errorf("batching withdrawals requires a withdrawal builder")
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 50
+	}
+	if config.Tracker == nil {
+		tracker, err := bitcoin.NewPayoutTracker(bitcoin.TrackerConfig{
+			Chain:   config.BitcoinClient,
+			Builder: config.Builder,
+		})
+		if err != nil {
+			return nil, err
+		}
+		config.Tracker = tracker
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &WithdrawalService{
+		btcClient:        config.BitcoinClient,
+		builder:          config.Builder,
+		tracker:          config.Tracker,
+		feeRate:          config.FeeRate,
+		feeEstimator:     config.FeeEstimator,
+		feeTarget:        config.FeeTarget,
+		batchInterval:    config.BatchInterval,
+		batchSize:        config.BatchSize,
+		ethereumService:  config.EthereumService,
+		store:            config.Store,
+		requiredConfirms: config.RequiredConfirmations,
+		maxAttempts:      config.MaxAttempts,
+		pollInterval:     config.PollInterval,
+		finalityDepth:    config.FinalityDepth,
+		nextBlock:        config.StartBlock,
+		ctx:              ctx,
+		cancel:           cancel,
+	}, nil
+}
+
+// Start begins tailing redemption events and processing withdrawals
+func (w *WithdrawalService) Start() {
+	log.Println("Starting withdrawal service...")
+	go w.pollLoop()
+}
+
+// Stop halts background processing
+func (w *WithdrawalService) Stop() {
+	log.Println("Stopping withdrawal service...")
+	w.cancel()
+}
+
+// GetWithdrawal returns the withdrawal transaction for a redeemed token
+func (w *WithdrawalService) GetWithdrawal(tokenAddress string) (*types.Transaction, error) {
+	return w.store.GetTransaction(withdrawalID(common.HexToAddress(tokenAddress)))
+}
+
+// ListWithdrawals returns all withdrawal transactions
+func (w *WithdrawalService) ListWithdrawals() ([]*types.Transaction, error) {
+	return w.store.ListTransactions(types.TransactionTypeWithdrawal)
+}
+
+// pollLoop scans for new events and advances withdrawals. Everything runs on
+// this single goroutine, so a withdrawal is never processed concurrently.
+func (w *WithdrawalService) pollLoop() {
+	w.resumeInterrupted()
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.scanEvents(); err != nil {
+			log.Printf("Failed to scan redemption events: %v", err)
+		}
+		w.processBatches()
+		w.processWithdrawals()
+		if w.batchInterval > 0 {
+			w.flushBatch()
+		}
+
+		select {
+		case <-w.ctx.Done():
+			log.Println("Withdrawal service stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scanEvents records redemption events up to the finalized head
+func (w *WithdrawalService) scanEvents() error {
+	ctx, cancel := context.WithTimeout(w.ctx, time.Minute)
+	defer cancel()
+
+	head, err := w.ethereumService.GetBlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get block number: %w", err)
+	}
+	if head < w.finalityDepth {
+		return nil
+	}
+	safeHead := head - w.finalityDepth
+
+	if w.nextBlock == 0 {
+		w.nextBlock, err = w.resumeBlock(safeHead)
+		if err != nil {
+			return err
+		}
+	}
+
+	for w.nextBlock <= safeHead {
+		toBlock := w.nextBlock + maxLogRange - 1
+		if toBlock > safeHead {
+			toBlock = safeHead
+		}
+
+		events, err := w.ethereumService.FilterUTXORedeemed(ctx, w.nextBlock, toBlock)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := w.recordWithdrawal(ctx, event); err != nil {
+				return fmt.Errorf("failed to record redemption of %s: %w", event.TokenAddress.Hex(), err)
+			}
+		}
+
+		w.nextBlock = toBlock + 1
+		if err := w.saveCheckpoint(toBlock); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resumeBlock picks the scan start when none is configured: the block after
+// the checkpoint, the block of the newest recorded withdrawal for stores
+// written before checkpoints, or the current head on first run
+func (w *WithdrawalService) resumeBlock(safeHead uint64) (uint64, error) {
+	data, err := w.store.GetState(withdrawalCheckpointKey)
+	if err == nil {
+		var cp scanCheckpoint
+		if err := json.Unmarshal(data, &cp); err != nil {
+			return 0, fmt.Errorf("failed to decode withdrawal checkpoint: %w", err)
+		}
+		return cp.LastBlock + 1, nil
+	}
+	if !errors.Is(err, store.ErrNotFound) {
+		return 0, fmt.Errorf("failed to load withdrawal checkpoint: %w", err)
+	}
+
+	withdrawals, err := w.store.ListTransactions(types.TransactionTypeWithdrawal)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list withdrawals: %w", err)
+	}
+
+	var latest uint64
+	for _, tx := range withdrawals {
+		if tx.EthereumBlock > latest {
+			latest = tx.EthereumBlock
+		}
+	}
+
+	if latest == 0 {
+		return safeHead, nil
+	}
+	return latest, nil
+}
+
+// saveCheckpoint records that every block up to lastBlock has been scanned
+func (w *WithdrawalService) saveCheckpoint(lastBlock uint64) error {
+	data, err := json.Marshal(scanCheckpoint{LastBlock: lastBlock})
+	if err != nil {
+		return err
+	}
+	if err := w.store.SaveState(withdrawalCheckpointKey, data); err != nil {
+		return fmt.Errorf("failed to save withdrawal checkpoint: %w", err)
+	}
+	return nil
+}
+
+// recordWithdrawal stores a new withdrawal for a redemption event. The payout
+// amount comes from the registry record rather than the event.
+func (w *WithdrawalService) recordWithdrawal(ctx context.Context, event *ethereum.UTXORedeemedEvent) error {
+	id := withdrawalID(event.TokenAddress)
+
+	_, err := w.store.GetTransaction(id)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+
+	utxoID, err := w.ethereumService.GetUTXOIDForToken(ctx, event.TokenAddress)
+	if err != nil {
+		return err
+	}
+	record, err := w.ethereumService.GetUTXORecord(ctx, utxoID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tx := &types.Transaction{
+		ID:               id,
+		Type:             types.TransactionTypeWithdrawal,
+		Status:           types.TransactionStatusPending,
+		EthereumTxHash:   event.TxHash.Hex(),
+		EthereumBlock:    event.BlockNumber,
+		Amount:           record.BitcoinAmount.Int64(),
+		FromAddress:      event.Redeemer.Hex(),
+		ToAddress:        event.BitcoinDestination,
+		TokenAddress:     event.TokenAddress.Hex(),
+		SourceUTXO:       fmt.Sprintf("%s:%d", record.BitcoinTxID, record.BitcoinVout),
+		RequiredConfirms: w.requiredConfirms,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	switch {
+	case record.BitcoinTxID != event.BitcoinTxID || record.BitcoinVout != event.Vout:
+		tx.Status = types.TransactionStatusFailed
+		tx.Error = fmt.Sprintf("event UTXO %s:%d does not match registry record", event.BitcoinTxID, event.Vout)
+	case !record.IsActive:
+		tx.Status = types.TransactionStatusFailed
+		tx.Error = "UTXO is already marked redeemed in the registry"
+	default:
+		if err := w.btcClient.ValidateAddress(event.BitcoinDestination); err != nil {
+			tx.Status = types.TransactionStatusFailed
+			tx.Error = fmt.Sprintf("invalid bitcoin destination: %v", err)
+		}
+	}
+
+	if tx.Status == types.TransactionStatusFailed {
+		log.Printf("Rejecting withdrawal %s: %s", id, tx.Error)
+	} else {
+		log.Printf("Recording new withdrawal %s (%d sats to %s)", id, tx.Amount, tx.ToAddress)
+	}
+
+	if err := w.store.SaveTransaction(tx); err != nil {
+		return err
+	}
+
+	if err := w.markTokenBurned(tx, record); err != nil {
+		log.Printf("Failed to update token for withdrawal %s: %v", id, err)
+	}
+
+	return nil
+}
+
+// markTokenBurned updates the stored token for a redeemed UTXO, creating the
+// record if the deposit predates the store
+func (w *WithdrawalService) markTokenBurned(tx *types.Transaction, record *ethereum.UTXORecord) error {
+	token, err := w.store.GetToken(tx.SourceUTXO)
+	if errors.Is(err, store.ErrNotFound) {
+		token = &types.UTXOToken{
+			ID: tx.SourceUTXO,
+			UTXO: types.UTXO{
+				TxID:    record.BitcoinTxID,
+				Vout:    record.BitcoinVout,
+				Amount:  tx.Amount,
+				Address: record.BitcoinAddress,
+			},
+			TotalSupply:  tx.Amount,
+			OwnerAddress: record.TokenOwner.Hex(),
+			CreatedAt:    time.Unix(record.CreatedAt.Int64(), 0),
+		}
+	} else if err != nil {
+		return err
+	}
+
+	burnedAt := tx.CreatedAt
+	token.TokenAddress = tx.TokenAddress
+	token.Status = "burned"
+	token.BurnedAt = &burnedAt
+
+	return w.store.SaveToken(token)
+}
+
+// processWithdrawals advances every withdrawal that is not yet finished
+func (w *WithdrawalService) processWithdrawals() {
+	withdrawals, err := w.store.ListTransactions(types.TransactionTypeWithdrawal)
+	if err != nil {
+		log.Printf("Failed to list withdrawals: %v", err)
+		return
+	}
+
+	for _, tx := range withdrawals {
+		if w.ctx.Err() != nil {
+			return
+		}
+
+		var stepErr error
+		switch tx.Status {
+		case types.TransactionStatusPending:
+			if w.batchInterval > 0 {
+				stepErr = w.queueWithdrawal(tx)
+			} else {
+				stepErr = w.sendPayout(tx)
+			}
+		case types.TransactionStatusQueued:
+			// Left over from a run with batching enabled
+			if w.batchInterval > 0 {
+				continue
+			}
+			stepErr = w.sendPayout(tx)
+		case types.TransactionStatusSending:
+			// Only built payouts wait here, signed but not yet accepted
+			if tx.RawTx == "" {
+				continue
+			}
+			stepErr = w.broadcastPayout(tx)
+		case types.TransactionStatusBroadcast:
+			// Batched payouts are settled per batch
+			if tx.BatchID != "" {
+				continue
+			}
+			stepErr = w.settle(tx)
+		case types.TransactionStatusConfirmed:
+			stepErr = w.redeem(tx)
+		default:
+			continue
+		}
+
+		if stepErr != nil {
+			w.recordFailure(tx, stepErr)
+		}
+	}
+}
+
+// sendPayout broadcasts the bitcoin payout. The withdrawal is persisted as
+// sending first so that a crash mid-broadcast never results in a second
+// payout on restart.
+func (w *WithdrawalService) sendPayout(tx *types.Transaction) error {
+	tx.Status = types.TransactionStatusSending
+	tx.UpdatedAt = time.Now()
+	if err := w.store.SaveTransaction(tx); err != nil {
+		tx.Status = types.TransactionStatusPending
+		return fmt.Errorf("failed to persist withdrawal: %w", err)
+	}
+
+	if w.builder != nil {
+		return w.buildPayout(tx)
+	}
+
+	txid, err := w.btcClient.SendBitcoinSubtractFee(tx.ToAddress, btcutil.Amount(tx.Amount), tx.ID)
+	if err != nil {
+		tx.Status = types.TransactionStatusPending
+		return fmt.Errorf("failed to send payout: %w", err)
+	}
+
+	tx.BitcoinTxID = txid
+	tx.Status = types.TransactionStatusBroadcast
+	tx.Error = ""
+	tx.Attempts = 0
+	tx.UpdatedAt = time.Now()
+	if err := w.store.SaveTransaction(tx); err != nil {
+		return fmt.Errorf("failed to persist withdrawal: %w", err)
+	}
+
+	log.Printf("Withdrawal %s payout broadcast in %s", tx.ID, txid)
+	return nil
+}
+
+// payoutFeeRate returns the sat/vB a new payout pays: the estimate for the
+// fee target, or the configured rate without an estimate
+func (w *WithdrawalService) payoutFeeRate() int64 {
+	if w.feeEstimator == nil {
+		return w.feeRate
+	}
+	rate, err := w.feeEstimator.FeeRate(w.feeTarget)
+	if err != nil {
+		log.Printf("Failed to estimate payout fee, paying %d sat/vB: %v", w.feeRate, err)
+		return w.feeRate
+	}
+	return rate
+}
+
+// buildPayout builds and signs the payout from the tracked UTXO set, with
+// the fee deducted from the amount. The signed transaction is persisted
+// before broadcast; from then on the same transaction is rebroadcast until
+// accepted, so the payout can never be built twice.
+func (w *WithdrawalService) buildPayout(tx *types.Transaction) error {
+	exclude, err := w.reservedInputs()
+	if err != nil {
+		tx.Status = types.TransactionStatusPending
+		return err
+	}
+
+	unsigned, err := w.builder.Build(bitcoin.BuildRequest{
+		Payments:    []bitcoin.Payment{{Address: tx.ToAddress, Amount: tx.Amount}},
+		FeeRate:     w.payoutFeeRate(),
+		SubtractFee: true,
+		Exclude:     exclude,
+	})
+	if err != nil {
+		tx.Status = types.TransactionStatusPending
+		return fmt.Errorf("failed to build payout: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(w.ctx, time.Minute)
+	defer cancel()
+	signed, err := w.builder.Sign(ctx, unsigned)
+	if err != nil {
+		tx.Status = types.TransactionStatusPending
+		return err
+	}
+
+	var raw bytes.Buffer
+	if err := signed.Serialize(&raw); err != nil {
+		tx.Status = types.TransactionStatusPending
+		return fmt.Errorf("failed to serialize payout: %w", err)
+	}
+
+	tx.BitcoinTxID = signed.TxHash().String()
+	tx.BitcoinInputs = unsigned.Outpoints()
+	tx.BitcoinFee = unsigned.Selection.Fee
+	tx.BitcoinChange = unsigned.Selection.Change
+	tx.RawTx = hex.EncodeToString(raw.Bytes())
+	tx.UpdatedAt = time.Now()
+	if err := w.store.SaveTransaction(tx); err != nil {
+		tx.Status = types.TransactionStatusPending
+		return fmt.Errorf("failed to persist withdrawal: %w", err)
+	}
+
+	log.Printf("Withdrawal %s payout %s pays %d sats with a %d sat fee from %d inputs",
+		tx.ID, tx.BitcoinTxID, unsigned.Payments[0].Amount, tx.BitcoinFee, len(tx.BitcoinInputs))
+	return w.broadcastPayout(tx)
+}
+
+// broadcastPayout submits the persisted payout transaction. A node that
+// already has it in its mempool or chain counts as accepted.
+func (w *WithdrawalService) broadcastPayout(tx *types.Transaction) error {
+	raw, err := hex.DecodeString(tx.RawTx)
+	if err != nil {
+		return fmt.Errorf("invalid stored payout: %w", err)
+	}
+	msgTx := wire.NewMsgTx(wire.TxVersion)
+	if err := msgTx.Deserialize(bytes.NewReader(raw)); err != nil {
+		return fmt.Errorf("invalid stored payout: %w", err)
+	}
+
+	if _, err := w.btcClient.BroadcastTransaction(msgTx); err != nil {
+		if _, confErr := w.btcClient.GetTransactionConfirmations(tx.BitcoinTxID); confErr != nil {
+			return fmt.Errorf("failed to broadcast payout: %w", err)
+		}
+	}
+
+	tx.Status = types.TransactionStatusBroadcast
+	tx.Error = ""
+	tx.Attempts = 0
+	tx.UpdatedAt = time.Now()
+	if err := w.store.SaveTransaction(tx); err != nil {
+		return fmt.Errorf("failed to persist withdrawal: %w", err)
+	}
+
+	log.Printf("Withdrawal %s payout broadcast in %s", tx.ID, tx.BitcoinTxID)
+	return nil
+}
+
+// reservedInputs returns the UTXOs spent by payouts and batches that are not
+// final yet, which later payouts must not select again
+func (w *WithdrawalService) reservedInputs() (map[string]bool, error) {
+	withdrawals, err := w.store.ListTransactions(types.TransactionTypeWithdrawal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list withdrawals: %w", err)
+	}
+
+	reserved := make(map[string]bool)
+	for _, other := range withdrawals {
+		switch other.Status {
+		case types.TransactionStatusSending, types.TransactionStatusBroadcast, types.TransactionStatusConfirmed:
+		default:
+			continue
+		}
+		for _, outpoint := range other.BitcoinInputs {
+			reserved[outpoint] = true
+		}
+		reserveChildInputs(reserved, other.FeeBumps)
+	}
+
+	batches, err := w.store.ListBatches()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list withdrawal batches: %w", err)
+	}
+	for _, batch := range batches {
+		if batch.Status != types.TransactionStatusBatched && batch.Status != types.TransactionStatusBroadcast {
+			continue
+		}
+		for _, outpoint := range batch.Inputs {
+			reserved[outpoint] = true
+		}
+		reserveChildInputs(reserved, batch.FeeBumps)
+	}
+	return reserved, nil
+}
+
+// reserveChildInputs reserves the change spent by CPFP children, which the
+// UTXO monitor sees as unspent once the parent confirms without its child
+func reserveChildInputs(reserved map[string]bool, bumps []types.FeeBump) {
+	for _, bump := range bumps {
+		if bump.Spends != "" {
+			reserved[bump.Spends] = true
+		}
+	}
+}
+
+// settle tracks payout confirmations, bumping the fee of a stuck payout,
+// and redeems the withdrawal once the payout is deep enough
+func (w *WithdrawalService) settle(tx *types.Transaction) error {
+	payout := &bitcoin.Payout{
+		TxID:         tx.BitcoinTxID,
+		RawTx:        tx.RawTx,
+		Fee:          tx.BitcoinFee,
+		ChangeOutput: -1,
+		Height:       tx.BroadcastHeight,
+		Bumps:        tx.FeeBumps,
+	}
+	// A built payout pays one recipient, followed by its change
+	if tx.RawTx != "" && tx.BitcoinChange > 0 {
+		payout.ChangeOutput = 1
+	}
+
+	status, trackErr := w.tracker.Track(w.ctx, payout)
+	if status == nil {
+		return trackErr
+	}
+	if status.Confirmations < 0 {
+		tx.Status = types.TransactionStatusFailed
+		tx.Error = fmt.Sprintf("payout %s conflicts with another transaction", status.TxID)
+		tx.FeeBumps = payout.Bumps
+		tx.UpdatedAt = time.Now()
+		log.Printf("Withdrawal %s failed: %s", tx.ID, tx.Error)
+		return w.store.SaveTransaction(tx)
+	}
+
+	confirmedTxID := ""
+	if status.Confirmations > 0 {
+		confirmedTxID = status.TxID
+	}
+	if int(status.Confirmations) != tx.Confirmations || confirmedTxID != tx.ConfirmedTxID ||
+		payout.Height != tx.BroadcastHeight || status.Bumped {
+		tx.Confirmations = int(status.Confirmations)
+		tx.ConfirmedTxID = confirmedTxID
+		tx.BroadcastHeight = payout.Height
+		tx.FeeBumps = payout.Bumps
+		tx.UpdatedAt = time.Now()
+		if err := w.store.SaveTransaction(tx); err != nil {
+			return fmt.Errorf("failed to persist withdrawal: %w", err)
+		}
+	}
+	if trackErr != nil {
+		return trackErr
+	}
+
+	if tx.Confirmations < tx.RequiredConfirms {
+		return nil
+	}
+
+	tx.Status = types.TransactionStatusConfirmed
+	tx.UpdatedAt = time.Now()
+	if err := w.store.SaveTransaction(tx); err != nil {
+		return fmt.Errorf("failed to persist withdrawal: %w", err)
+	}
+
+	return w.redeem(tx)
+}
+
+// redeem marks the UTXO of a withdrawal with a confirmed payout redeemed in
+// the registry. The transaction is sent once and its receipt is checked on
+// later polls, so waiting for it never holds up other withdrawals.
+func (w *WithdrawalService) redeem(tx *types.Transaction) error {
+	ctx, cancel := context.WithTimeout(w.ctx, time.Minute)
+	defer cancel()
+
+	if tx.SettleTxHash != "" {
+		receipt, err := w.ethereumService.TransactionReceipt(ctx, common.HexToHash(tx.SettleTxHash))
+		var revertErr *ethereum.RevertError
+		switch {
+		case err == nil && receipt == nil:
+			return nil
+		case err == nil:
+			// The mined transaction may be a fee-bumped replacement
+			tx.SettleTxHash = receipt.TxHash.Hex()
+			return w.completeWithdrawal(tx)
+		case errors.Is(err, ethereum.ErrTransactionDropped) || errors.As(err, &revertErr):
+			// Something else may have redeemed the UTXO, so the registry is
+			// checked again before the next send
+			tx.SettleTxHash = ""
+			return fmt.Errorf("markUTXORedeemed failed: %w", err)
+		default:
+			return err
+		}
+	}
+
+	utxoID, err := w.ethereumService.GetUTXOIDForToken(ctx, common.HexToAddress(tx.TokenAddress))
+	if err != nil {
+		return err
+	}
+
+	// A previous run may have crashed after markUTXORedeemed was mined
+	record, err := w.ethereumService.GetUTXORecord(ctx, utxoID)
+	if err != nil {
+		return err
+	}
+
+	if !record.IsActive {
+		return w.completeWithdrawal(tx)
+	}
+
+	ethTx, err := w.ethereumService.MarkUTXORedeemed(ctx, utxoID, common.HexToAddress(tx.FromAddress), tx.ToAddress)
+	if err != nil {
+		return err
+	}
+
+	tx.SettleTxHash = ethTx.Hash().Hex()
+	tx.UpdatedAt = time.Now()
+	if err := w.store.SaveTransaction(tx); err != nil {
+		return fmt.Errorf("failed to persist withdrawal: %w", err)
+	}
+
+	log.Printf("Withdrawal %s redemption sent in %s", tx.ID, tx.SettleTxHash)
+	return nil
+}
+
+// completeWithdrawal records a withdrawal whose UTXO is marked redeemed
+func (w *WithdrawalService) completeWithdrawal(tx *types.Transaction) error {
+	tx.Status = types.TransactionStatusCompleted
+	tx.Error = ""
+	tx.Attempts = 0
+	tx.UpdatedAt = time.Now()
+	if err := w.store.SaveTransaction(tx); err != nil {
+		return fmt.Errorf("failed to persist withdrawal: %w", err)
+	}
+
+	log.Printf("Withdrawal %s completed", tx.ID)
+	return nil
+}
+
+// resumeInterrupted fails withdrawals whose payout was interrupted before its
+// txid was recorded. They need an operator to check the wallet for a
+// transaction labelled with the withdrawal ID before retrying. Built payouts
+// that were signed and persisted are rebroadcast instead.
+func (w *WithdrawalService) resumeInterrupted() {
+	withdrawals, err := w.store.ListTransactions(types.TransactionTypeWithdrawal)
+	if err != nil {
+		log.Printf("Failed to list withdrawals: %v", err)
+		return
+	}
+
+	for _, tx := range withdrawals {
+		if tx.Status != types.TransactionStatusSending || tx.RawTx != "" {
+			continue
+		}
+
+		tx.Status = types.TransactionStatusFailed
+		tx.Error = "payout interrupted before its txid was recorded; check the wallet for a transaction labelled " + tx.ID
+		tx.UpdatedAt = time.Now()
+		log.Printf("Withdrawal %s: %s", tx.ID, tx.Error)
+
+		if err := w.store.SaveTransaction(tx); err != nil {
+			log.Printf("Failed to persist withdrawal %s: %v", tx.ID, err)
+		}
+	}
+}
+
+// recordFailure stores the error on the withdrawal. Once the payout has been
+// broadcast the withdrawal keeps retrying, since the bitcoin is already gone
+// and only the registry update is outstanding.
+func (w *WithdrawalService) recordFailure(tx *types.Transaction, cause error) {
+	tx.Attempts++
+	tx.Error = cause.Error()
+	tx.UpdatedAt = time.Now()
+
+	retryable := tx.Status == types.TransactionStatusPending || tx.Status == types.TransactionStatusSending ||
+		tx.Status == types.TransactionStatusQueued
+	if retryable && tx.Attempts >= w.maxAttempts {
+		tx.Status = types.TransactionStatusFailed
+		log.Printf("Withdrawal %s failed after %d attempts: %v", tx.ID, tx.Attempts, cause)
+	} else {
+		log.Printf("Withdrawal %s step failed (attempt %d): %v", tx.ID, tx.Attempts, cause)
+	}
+
+	if err := w.store.SaveTransaction(tx); err != nil {
+		log.Printf("Failed to persist withdrawal %s: %v", tx.ID, err)
+	}
+}
+
+func withdrawalID(token common.Address) string {
+	return "withdrawal:" + strings.ToLower(token.Hex())
+}
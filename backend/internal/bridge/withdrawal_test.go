@@ -0,0 +1,69 @@
+package bridge
+
+import (
+	"testing"
+	"time"
+
+	"bitbridge/internal/store/storetest"
+	"bitbridge/pkg/types"
+)
+
+func TestWithdrawalScanResumesFromCheckpoint(t *testing.T) {
+	repo := storetest.Open(t)
+
+	w := &WithdrawalService{store: repo}
+
+	start, err := w.resumeBlock(100)
+	if err != nil {
+		t.Fatalf("Failed to pick scan start: %v", err)
+	}
+	if start != 100 {
+		t.Errorf("Expected a first run to start at the safe head, got %d", start)
+	}
+
+	// Stores written before checkpoints resume from the newest withdrawal
+	if err := repo.SaveTransaction(&types.Transaction{
+		ID:            "withdrawal:0xaa",
+		Type:          types.TransactionTypeWithdrawal,
+		Status:        types.TransactionStatusCompleted,
+		EthereumBlock: 40,
+		CreatedAt:     time.Now(),
+	}); err != nil {
+		t.Fatalf("Failed to save withdrawal: %v", err)
+	}
+	if start, err = w.resumeBlock(100); err != nil || start != 40 {
+		t.Errorf("Expected to resume from the newest withdrawal at 40, got %d (%v)", start, err)
+	}
+
+	// Blocks scanned after the newest withdrawal are not scanned again
+	if err := w.saveCheckpoint(90); err != nil {
+		t.Fatalf("Failed to save checkpoint: %v", err)
+	}
+	restarted := &WithdrawalService{store: repo}
+	if start, err = restarted.resumeBlock(100); err != nil || start != 91 {
+		t.Errorf("Expected to resume after the checkpoint at 91, got %d (%v)", start, err)
+	}
+}
+
+func TestWithdrawalInterruptedPayoutFails(t *testing.T) {
+	repo := storetest.Open(t)
+	if err := repo.SaveTransaction(&types.Transaction{
+		ID:     "withdrawal:0xaa",
+		Type:   types.TransactionTypeWithdrawal,
+		Status: types.TransactionStatusSending,
+	}); err != nil {
+		t.Fatalf("Failed to save withdrawal: %v", err)
+	}
+
+	// The payout may have left the wallet, so it is never sent again
+	w := &WithdrawalService{store: repo}
+	w.resumeInterrupted()
+
+	tx, err := repo.GetTransaction("withdrawal:0xaa")
+	if err != nil {
+		t.Fatalf("Failed to get withdrawal: %v", err)
+	}
+	if tx.Status != types.TransactionStatusFailed || tx.Error == "" {
+		t.Errorf("Expected the interrupted payout to fail for the operator, got %s (%q)", tx.Status, tx.Error)
+	}
+}
@@ -0,0 +1,68 @@
+// Package bindings holds typed Go bindings for the bridge contracts. The
+// ABI and bytecode live in artifacts/ and are embedded into the binary; the
+// binding sources are generated from them by gen.go.
+package bindings
+
+//go:generate go run gen.go
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+//go:embed artifacts/*.json
+var artifactFS embed.FS
+
+// Artifact is a compiled contract in Hardhat's artifact layout
+type Artifact struct {
+	ContractName string          `json:"contractName"`
+	ABI          json.RawMessage `json:"abi"`
+	Bytecode     string          `json:"bytecode"`
+}
+
+// LoadArtifact returns the embedded artifact for a contract
+func LoadArtifact(contractName string) (*Artifact, error) {
+	data, err := artifactFS.ReadFile("artifacts/" + contractName + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("no artifact for contract %s", contractName)
+	}
+
+	var artifact Artifact
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, fmt.Errorf("invalid artifact for contract %s: %w", contractName, err)
+	}
+	return &artifact, nil
+}
+
+// ParsedABI parses the artifact's ABI
+func (a *Artifact) ParsedABI() (*abi.ABI, error) {
+	parsed, err := abi.JSON(strings.NewReader(string(a.ABI)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ABI for contract %s: %w", a.ContractName, err)
+	}
+	return &parsed, nil
+}
+
+// DeployCode returns the contract's creation bytecode, or an error if the
+// artifact was generated without a compiler and carries only the ABI
+func (a *Artifact) DeployCode() ([]byte, error) {
+	if strings.TrimPrefix(a.Bytecode, "0x") == "" {
+		return nil, fmt.Errorf("artifact for %s has no bytecode; run go generate with solc installed", a.ContractName)
+	}
+	return common.FromHex(a.Bytecode), nil
+}
+
+// mustArtifact is used by the generated bindings to source their metadata
+// from the embedded artifacts
+func mustArtifact(contractName string) *Artifact {
+	artifact, err := LoadArtifact(contractName)
+	if err != nil {
+		panic(err)
+	}
+	return artifact
+}
@@ -0,0 +1,149 @@
+//go:build ignore
+
+// gen refreshes the embedded contract artifacts and regenerates the Go
+// bindings from them. UTXORegistry and UTXOToken are taken from the Hardhat
+// build in frontend/contracts; SPVVerifier is compiled from backend/contracts
+// with solc, which must be installed.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/abigen"
+)
+
+type artifact struct {
+	ContractName string          `json:"contractName"`
+	ABI          json.RawMessage `json:"abi"`
+	Bytecode     string          `json:"bytecode"`
+}
+
+var contracts = []struct {
+	name    string
+	output  string
+	hardhat string // Hardhat artifact path
+	solc    string // Solidity source path, used when hardhat is empty
+}{
+	{name: "UTXORegistry", output: "utxo_registry.go", hardhat: "../../../../frontend/contracts/artifacts/contracts/UTXORegistry.sol/UTXORegistry.json"},
+	{name: "UTXOToken", output: "utxo_token.go", hardhat: "../../../../frontend/contracts/artifacts/contracts/UTXOToken.sol/UTXOToken.json"},
+	{name: "SPVVerifier", output: "spv_verifier.go", solc: "../../../contracts/SPVVerifier.sol"},
+}
+
+var (
+	abiLine = regexp.MustCompile(`(?m)^\tABI: ".*",$`)
+	binLine = regexp.MustCompile(`(?m)^\tBin: ".*",$`)
+)
+
+func main() {
+	for _, contract := range contracts {
+		var (
+			a   *artifact
+			err error
+		)
+		if contract.hardhat != "" {
+			a, err = readArtifact(contract.hardhat)
+		} else {
+			a, err = compile(contract.solc, contract.name)
+		}
+		if err != nil {
+			log.Fatalf("%s: %v", contract.name, err)
+		}
+
+		if err := writeArtifact(a); err != nil {
+			log.Fatalf("%s: %v", contract.name, err)
+		}
+		if err := writeBinding(a, contract.output); err != nil {
+			log.Fatalf("%s: %v", contract.name, err)
+		}
+	}
+}
+
+func readArtifact(path string) (*artifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var a artifact
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("invalid artifact %s: %w", path, err)
+	}
+	return &a, nil
+}
+
+// compile builds the contract with solc. Without a compiler generation
+// fails rather than keeping an artifact that may predate the source.
+func compile(source, name string) (*artifact, error) {
+	if _, err := exec.LookPath("solc"); err != nil {
+		return nil, fmt.Errorf("solc is required to compile %s: %w", source, err)
+	}
+
+	out, err := exec.Command("solc", "--combined-json", "abi,bin", source).Output()
+	if err != nil {
+		return nil, fmt.Errorf("solc failed: %w", err)
+	}
+
+	var combined struct {
+		Contracts map[string]struct {
+			ABI json.RawMessage `json:"abi"`
+			Bin string          `json:"bin"`
+		} `json:"contracts"`
+	}
+	if err := json.Unmarshal(out, &combined); err != nil {
+		return nil, fmt.Errorf("invalid solc output: %w", err)
+	}
+
+	for key, compiled := range combined.Contracts {
+		if !strings.HasSuffix(key, ":"+name) {
+			continue
+		}
+
+		// Older solc versions emit the ABI as a JSON string
+		contractABI := compiled.ABI
+		var encoded string
+		if json.Unmarshal(contractABI, &encoded) == nil {
+			contractABI = json.RawMessage(encoded)
+		}
+		return &artifact{ContractName: name, ABI: contractABI, Bytecode: "0x" + compiled.Bin}, nil
+	}
+	return nil, fmt.Errorf("solc output has no contract %s", name)
+}
+
+func writeArtifact(a *artifact) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join("artifacts", a.ContractName+".json"), append(data, '\n'), 0644)
+}
+
+// writeBinding generates the binding and points its metadata at the
+// embedded artifact instead of inlining the ABI and bytecode
+func writeBinding(a *artifact, output string) error {
+	code, err := abigen.Bind(
+		[]string{a.ContractName},
+		[]string{string(a.ABI)},
+		[]string{strings.TrimPrefix(a.Bytecode, "0x")},
+		nil, "bindings", nil, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	code = abiLine.ReplaceAllString(code, fmt.Sprintf("\tABI: string(mustArtifact(%q).ABI),", a.ContractName))
+	code = binLine.ReplaceAllString(code, fmt.Sprintf("\tBin: mustArtifact(%q).Bytecode,", a.ContractName))
+
+	formatted, err := format.Source([]byte(code))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, formatted, 0644)
+}
@@ -0,0 +1,1323 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package bindings
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// SPVVerifierBlockHeader is an auto generated low-level Go binding around an user-defined struct.
+type SPVVerifierBlockHeader struct {
+	Version    uint32
+	PrevBlock  [32]byte
+	MerkleRoot [32]byte
+	Timestamp  uint32
+	Bits       uint32
+	Nonce      uint32
+	Height     *big.Int
+	Exists     bool
+}
+
+// SPVVerifierMerkleProof is an auto generated low-level Go binding around an user-defined struct.
+type SPVVerifierMerkleProof struct {
+	Proof      [][32]byte
+	Index      *big.Int
+	TotalTxs   *big.Int
+	TxHash     [32]byte
+	MerkleRoot [32]byte
+}
+
+// SPVVerifierMetaData contains all meta data concerning the SPVVerifier contract.
+var SPVVerifierMetaData = &bind.MetaData{
+	ABI: string(mustArtifact("SPVVerifier").ABI),
+	Bin: mustArtifact("SPVVerifier").Bytecode,
+}
+
+// SPVVerifierABI is the input ABI used to generate the binding from.
+// Deprecated: Use SPVVerifierMetaData.ABI instead.
+var SPVVerifierABI = SPVVerifierMetaData.ABI
+
+// SPVVerifierBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use SPVVerifierMetaData.Bin instead.
+var SPVVerifierBin = SPVVerifierMetaData.Bin
+
+// DeploySPVVerifier deploys a new Ethereum contract, binding an instance of SPVVerifier to it.
+func DeploySPVVerifier(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *SPVVerifier, error) {
+	parsed, err := SPVVerifierMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(SPVVerifierBin), backend)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &SPVVerifier{SPVVerifierCaller: SPVVerifierCaller{contract: contract}, SPVVerifierTransactor: SPVVerifierTransactor{contract: contract}, SPVVerifierFilterer: SPVVerifierFilterer{contract: contract}}, nil
+}
+
+// SPVVerifier is an auto generated Go binding around an Ethereum contract.
+type SPVVerifier struct {
+	SPVVerifierCaller     // Read-only binding to the contract
+	SPVVerifierTransactor // Write-only binding to the contract
+	SPVVerifierFilterer   // Log filterer for contract events
+}
+
+// SPVVerifierCaller is an auto generated read-only Go binding around an Ethereum contract.
+type SPVVerifierCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SPVVerifierTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type SPVVerifierTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SPVVerifierFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type SPVVerifierFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SPVVerifierSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type SPVVerifierSession struct {
+	Contract     *SPVVerifier      // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// SPVVerifierCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type SPVVerifierCallerSession struct {
+	Contract *SPVVerifierCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts      // Call options to use throughout this session
+}
+
+// SPVVerifierTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type SPVVerifierTransactorSession struct {
+	Contract     *SPVVerifierTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts      // Transaction auth options to use throughout this session
+}
+
+// SPVVerifierRaw is an auto generated low-level Go binding around an Ethereum contract.
+type SPVVerifierRaw struct {
+	Contract *SPVVerifier // Generic contract binding to access the raw methods on
+}
+
+// SPVVerifierCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type SPVVerifierCallerRaw struct {
+	Contract *SPVVerifierCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// SPVVerifierTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type SPVVerifierTransactorRaw struct {
+	Contract *SPVVerifierTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewSPVVerifier creates a new instance of SPVVerifier, bound to a specific deployed contract.
+func NewSPVVerifier(address common.Address, backend bind.ContractBackend) (*SPVVerifier, error) {
+	contract, err := bindSPVVerifier(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &SPVVerifier{SPVVerifierCaller: SPVVerifierCaller{contract: contract}, SPVVerifierTransactor: SPVVerifierTransactor{contract: contract}, SPVVerifierFilterer: SPVVerifierFilterer{contract: contract}}, nil
+}
+
+// NewSPVVerifierCaller creates a new read-only instance of SPVVerifier, bound to a specific deployed contract.
+func NewSPVVerifierCaller(address common.Address, caller bind.ContractCaller) (*SPVVerifierCaller, error) {
+	contract, err := bindSPVVerifier(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SPVVerifierCaller{contract: contract}, nil
+}
+
+// NewSPVVerifierTransactor creates a new write-only instance of SPVVerifier, bound to a specific deployed contract.
+func NewSPVVerifierTransactor(address common.Address, transactor bind.ContractTransactor) (*SPVVerifierTransactor, error) {
+	contract, err := bindSPVVerifier(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SPVVerifierTransactor{contract: contract}, nil
+}
+
+// NewSPVVerifierFilterer creates a new log filterer instance of SPVVerifier, bound to a specific deployed contract.
+func NewSPVVerifierFilterer(address common.Address, filterer bind.ContractFilterer) (*SPVVerifierFilterer, error) {
+	contract, err := bindSPVVerifier(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &SPVVerifierFilterer{contract: contract}, nil
+}
+
+// bindSPVVerifier binds a generic wrapper to an already deployed contract.
+func bindSPVVerifier(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := SPVVerifierMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_SPVVerifier *SPVVerifierRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _SPVVerifier.Contract.SPVVerifierCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_SPVVerifier *SPVVerifierRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _SPVVerifier.Contract.SPVVerifierTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_SPVVerifier *SPVVerifierRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _SPVVerifier.Contract.SPVVerifierTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_SPVVerifier *SPVVerifierCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _SPVVerifier.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_SPVVerifier *SPVVerifierTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _SPVVerifier.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_SPVVerifier *SPVVerifierTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _SPVVerifier.Contract.contract.Transact(opts, method, params...)
+}
+
+// BitsToTarget is a free data retrieval call binding the contract method 0xceee304b.
+//
+// Solidity: function bitsToTarget(uint32 bits) pure returns(uint256)
+func (_SPVVerifier *SPVVerifierCaller) BitsToTarget(opts *bind.CallOpts, bits uint32) (*big.Int, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "bitsToTarget", bits)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// BitsToTarget is a free data retrieval call binding the contract method 0xceee304b.
+//
+// Solidity: function bitsToTarget(uint32 bits) pure returns(uint256)
+func (_SPVVerifier *SPVVerifierSession) BitsToTarget(bits uint32) (*big.Int, error) {
+	return _SPVVerifier.Contract.BitsToTarget(&_SPVVerifier.CallOpts, bits)
+}
+
+// BitsToTarget is a free data retrieval call binding the contract method 0xceee304b.
+//
+// Solidity: function bitsToTarget(uint32 bits) pure returns(uint256)
+func (_SPVVerifier *SPVVerifierCallerSession) BitsToTarget(bits uint32) (*big.Int, error) {
+	return _SPVVerifier.Contract.BitsToTarget(&_SPVVerifier.CallOpts, bits)
+}
+
+// BlockHeaders is a free data retrieval call binding the contract method 0x78a5b1b0.
+//
+// Solidity: function blockHeaders(bytes32 ) view returns(uint32 version, bytes32 prevBlock, bytes32 merkleRoot, uint32 timestamp, uint32 bits, uint32 nonce, uint256 height, bool exists)
+func (_SPVVerifier *SPVVerifierCaller) BlockHeaders(opts *bind.CallOpts, arg0 [32]byte) (struct {
+	Version    uint32
+	PrevBlock  [32]byte
+	MerkleRoot [32]byte
+	Timestamp  uint32
+	Bits       uint32
+	Nonce      uint32
+	Height     *big.Int
+	Exists     bool
+}, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "blockHeaders", arg0)
+
+	outstruct := new(struct {
+		Version    uint32
+		PrevBlock  [32]byte
+		MerkleRoot [32]byte
+		Timestamp  uint32
+		Bits       uint32
+		Nonce      uint32
+		Height     *big.Int
+		Exists     bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Version = *abi.ConvertType(out[0], new(uint32)).(*uint32)
+	outstruct.PrevBlock = *abi.ConvertType(out[1], new([32]byte)).(*[32]byte)
+	outstruct.MerkleRoot = *abi.ConvertType(out[2], new([32]byte)).(*[32]byte)
+	outstruct.Timestamp = *abi.ConvertType(out[3], new(uint32)).(*uint32)
+	outstruct.Bits = *abi.ConvertType(out[4], new(uint32)).(*uint32)
+	outstruct.Nonce = *abi.ConvertType(out[5], new(uint32)).(*uint32)
+	outstruct.Height = *abi.ConvertType(out[6], new(*big.Int)).(**big.Int)
+	outstruct.Exists = *abi.ConvertType(out[7], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// BlockHeaders is a free data retrieval call binding the contract method 0x78a5b1b0.
+//
+// Solidity: function blockHeaders(bytes32 ) view returns(uint32 version, bytes32 prevBlock, bytes32 merkleRoot, uint32 timestamp, uint32 bits, uint32 nonce, uint256 height, bool exists)
+func (_SPVVerifier *SPVVerifierSession) BlockHeaders(arg0 [32]byte) (struct {
+	Version    uint32
+	PrevBlock  [32]byte
+	MerkleRoot [32]byte
+	Timestamp  uint32
+	Bits       uint32
+	Nonce      uint32
+	Height     *big.Int
+	Exists     bool
+}, error) {
+	return _SPVVerifier.Contract.BlockHeaders(&_SPVVerifier.CallOpts, arg0)
+}
+
+// BlockHeaders is a free data retrieval call binding the contract method 0x78a5b1b0.
+//
+// Solidity: function blockHeaders(bytes32 ) view returns(uint32 version, bytes32 prevBlock, bytes32 merkleRoot, uint32 timestamp, uint32 bits, uint32 nonce, uint256 height, bool exists)
+func (_SPVVerifier *SPVVerifierCallerSession) BlockHeaders(arg0 [32]byte) (struct {
+	Version    uint32
+	PrevBlock  [32]byte
+	MerkleRoot [32]byte
+	Timestamp  uint32
+	Bits       uint32
+	Nonce      uint32
+	Height     *big.Int
+	Exists     bool
+}, error) {
+	return _SPVVerifier.Contract.BlockHeaders(&_SPVVerifier.CallOpts, arg0)
+}
+
+// BlocksByHeight is a free data retrieval call binding the contract method 0x023c10a3.
+//
+// Solidity: function blocksByHeight(uint256 ) view returns(bytes32)
+func (_SPVVerifier *SPVVerifierCaller) BlocksByHeight(opts *bind.CallOpts, arg0 *big.Int) ([32]byte, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "blocksByHeight", arg0)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// BlocksByHeight is a free data retrieval call binding the contract method 0x023c10a3.
+//
+// Solidity: function blocksByHeight(uint256 ) view returns(bytes32)
+func (_SPVVerifier *SPVVerifierSession) BlocksByHeight(arg0 *big.Int) ([32]byte, error) {
+	return _SPVVerifier.Contract.BlocksByHeight(&_SPVVerifier.CallOpts, arg0)
+}
+
+// BlocksByHeight is a free data retrieval call binding the contract method 0x023c10a3.
+//
+// Solidity: function blocksByHeight(uint256 ) view returns(bytes32)
+func (_SPVVerifier *SPVVerifierCallerSession) BlocksByHeight(arg0 *big.Int) ([32]byte, error) {
+	return _SPVVerifier.Contract.BlocksByHeight(&_SPVVerifier.CallOpts, arg0)
+}
+
+// DoubleSha256 is a free data retrieval call binding the contract method 0x223127f2.
+//
+// Solidity: function doubleSha256(bytes data) pure returns(bytes32)
+func (_SPVVerifier *SPVVerifierCaller) DoubleSha256(opts *bind.CallOpts, data []byte) ([32]byte, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "doubleSha256", data)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// DoubleSha256 is a free data retrieval call binding the contract method 0x223127f2.
+//
+// Solidity: function doubleSha256(bytes data) pure returns(bytes32)
+func (_SPVVerifier *SPVVerifierSession) DoubleSha256(data []byte) ([32]byte, error) {
+	return _SPVVerifier.Contract.DoubleSha256(&_SPVVerifier.CallOpts, data)
+}
+
+// DoubleSha256 is a free data retrieval call binding the contract method 0x223127f2.
+//
+// Solidity: function doubleSha256(bytes data) pure returns(bytes32)
+func (_SPVVerifier *SPVVerifierCallerSession) DoubleSha256(data []byte) ([32]byte, error) {
+	return _SPVVerifier.Contract.DoubleSha256(&_SPVVerifier.CallOpts, data)
+}
+
+// GetBlockHashByHeight is a free data retrieval call binding the contract method 0x4f29c663.
+//
+// Solidity: function getBlockHashByHeight(uint256 height) view returns(bytes32)
+func (_SPVVerifier *SPVVerifierCaller) GetBlockHashByHeight(opts *bind.CallOpts, height *big.Int) ([32]byte, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "getBlockHashByHeight", height)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// GetBlockHashByHeight is a free data retrieval call binding the contract method 0x4f29c663.
+//
+// Solidity: function getBlockHashByHeight(uint256 height) view returns(bytes32)
+func (_SPVVerifier *SPVVerifierSession) GetBlockHashByHeight(height *big.Int) ([32]byte, error) {
+	return _SPVVerifier.Contract.GetBlockHashByHeight(&_SPVVerifier.CallOpts, height)
+}
+
+// GetBlockHashByHeight is a free data retrieval call binding the contract method 0x4f29c663.
+//
+// Solidity: function getBlockHashByHeight(uint256 height) view returns(bytes32)
+func (_SPVVerifier *SPVVerifierCallerSession) GetBlockHashByHeight(height *big.Int) ([32]byte, error) {
+	return _SPVVerifier.Contract.GetBlockHashByHeight(&_SPVVerifier.CallOpts, height)
+}
+
+// GetBlockHeader is a free data retrieval call binding the contract method 0x6b4f9b9d.
+//
+// Solidity: function getBlockHeader(bytes32 blockHash) view returns((uint32,bytes32,bytes32,uint32,uint32,uint32,uint256,bool))
+func (_SPVVerifier *SPVVerifierCaller) GetBlockHeader(opts *bind.CallOpts, blockHash [32]byte) (SPVVerifierBlockHeader, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "getBlockHeader", blockHash)
+
+	if err != nil {
+		return *new(SPVVerifierBlockHeader), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(SPVVerifierBlockHeader)).(*SPVVerifierBlockHeader)
+
+	return out0, err
+
+}
+
+// GetBlockHeader is a free data retrieval call binding the contract method 0x6b4f9b9d.
+//
+// Solidity: function getBlockHeader(bytes32 blockHash) view returns((uint32,bytes32,bytes32,uint32,uint32,uint32,uint256,bool))
+func (_SPVVerifier *SPVVerifierSession) GetBlockHeader(blockHash [32]byte) (SPVVerifierBlockHeader, error) {
+	return _SPVVerifier.Contract.GetBlockHeader(&_SPVVerifier.CallOpts, blockHash)
+}
+
+// GetBlockHeader is a free data retrieval call binding the contract method 0x6b4f9b9d.
+//
+// Solidity: function getBlockHeader(bytes32 blockHash) view returns((uint32,bytes32,bytes32,uint32,uint32,uint32,uint256,bool))
+func (_SPVVerifier *SPVVerifierCallerSession) GetBlockHeader(blockHash [32]byte) (SPVVerifierBlockHeader, error) {
+	return _SPVVerifier.Contract.GetBlockHeader(&_SPVVerifier.CallOpts, blockHash)
+}
+
+// GetVerifiedTransactionCount is a free data retrieval call binding the contract method 0x9f96a188.
+//
+// Solidity: function getVerifiedTransactionCount() view returns(uint256)
+func (_SPVVerifier *SPVVerifierCaller) GetVerifiedTransactionCount(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "getVerifiedTransactionCount")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetVerifiedTransactionCount is a free data retrieval call binding the contract method 0x9f96a188.
+//
+// Solidity: function getVerifiedTransactionCount() view returns(uint256)
+func (_SPVVerifier *SPVVerifierSession) GetVerifiedTransactionCount() (*big.Int, error) {
+	return _SPVVerifier.Contract.GetVerifiedTransactionCount(&_SPVVerifier.CallOpts)
+}
+
+// GetVerifiedTransactionCount is a free data retrieval call binding the contract method 0x9f96a188.
+//
+// Solidity: function getVerifiedTransactionCount() view returns(uint256)
+func (_SPVVerifier *SPVVerifierCallerSession) GetVerifiedTransactionCount() (*big.Int, error) {
+	return _SPVVerifier.Contract.GetVerifiedTransactionCount(&_SPVVerifier.CallOpts)
+}
+
+// IsTransactionVerified is a free data retrieval call binding the contract method 0x7b7c009f.
+//
+// Solidity: function isTransactionVerified(bytes32 txHash) view returns(bool)
+func (_SPVVerifier *SPVVerifierCaller) IsTransactionVerified(opts *bind.CallOpts, txHash [32]byte) (bool, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "isTransactionVerified", txHash)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// IsTransactionVerified is a free data retrieval call binding the contract method 0x7b7c009f.
+//
+// Solidity: function isTransactionVerified(bytes32 txHash) view returns(bool)
+func (_SPVVerifier *SPVVerifierSession) IsTransactionVerified(txHash [32]byte) (bool, error) {
+	return _SPVVerifier.Contract.IsTransactionVerified(&_SPVVerifier.CallOpts, txHash)
+}
+
+// IsTransactionVerified is a free data retrieval call binding the contract method 0x7b7c009f.
+//
+// Solidity: function isTransactionVerified(bytes32 txHash) view returns(bool)
+func (_SPVVerifier *SPVVerifierCallerSession) IsTransactionVerified(txHash [32]byte) (bool, error) {
+	return _SPVVerifier.Contract.IsTransactionVerified(&_SPVVerifier.CallOpts, txHash)
+}
+
+// LatestHeight is a free data retrieval call binding the contract method 0xe405bbc3.
+//
+// Solidity: function latestHeight() view returns(uint256)
+func (_SPVVerifier *SPVVerifierCaller) LatestHeight(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "latestHeight")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// LatestHeight is a free data retrieval call binding the contract method 0xe405bbc3.
+//
+// Solidity: function latestHeight() view returns(uint256)
+func (_SPVVerifier *SPVVerifierSession) LatestHeight() (*big.Int, error) {
+	return _SPVVerifier.Contract.LatestHeight(&_SPVVerifier.CallOpts)
+}
+
+// LatestHeight is a free data retrieval call binding the contract method 0xe405bbc3.
+//
+// Solidity: function latestHeight() view returns(uint256)
+func (_SPVVerifier *SPVVerifierCallerSession) LatestHeight() (*big.Int, error) {
+	return _SPVVerifier.Contract.LatestHeight(&_SPVVerifier.CallOpts)
+}
+
+// MerkleDepth is a free data retrieval call binding the contract method 0xcf80396d.
+//
+// Solidity: function merkleDepth(uint256 totalTxs) pure returns(uint256)
+func (_SPVVerifier *SPVVerifierCaller) MerkleDepth(opts *bind.CallOpts, totalTxs *big.Int) (*big.Int, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "merkleDepth", totalTxs)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// MerkleDepth is a free data retrieval call binding the contract method 0xcf80396d.
+//
+// Solidity: function merkleDepth(uint256 totalTxs) pure returns(uint256)
+func (_SPVVerifier *SPVVerifierSession) MerkleDepth(totalTxs *big.Int) (*big.Int, error) {
+	return _SPVVerifier.Contract.MerkleDepth(&_SPVVerifier.CallOpts, totalTxs)
+}
+
+// MerkleDepth is a free data retrieval call binding the contract method 0xcf80396d.
+//
+// Solidity: function merkleDepth(uint256 totalTxs) pure returns(uint256)
+func (_SPVVerifier *SPVVerifierCallerSession) MerkleDepth(totalTxs *big.Int) (*big.Int, error) {
+	return _SPVVerifier.Contract.MerkleDepth(&_SPVVerifier.CallOpts, totalTxs)
+}
+
+// ParseBlockHeader is a free data retrieval call binding the contract method 0x849d926b.
+//
+// Solidity: function parseBlockHeader(bytes headerBytes) pure returns((uint32,bytes32,bytes32,uint32,uint32,uint32,uint256,bool))
+func (_SPVVerifier *SPVVerifierCaller) ParseBlockHeader(opts *bind.CallOpts, headerBytes []byte) (SPVVerifierBlockHeader, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "parseBlockHeader", headerBytes)
+
+	if err != nil {
+		return *new(SPVVerifierBlockHeader), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(SPVVerifierBlockHeader)).(*SPVVerifierBlockHeader)
+
+	return out0, err
+
+}
+
+// ParseBlockHeader is a free data retrieval call binding the contract method 0x849d926b.
+//
+// Solidity: function parseBlockHeader(bytes headerBytes) pure returns((uint32,bytes32,bytes32,uint32,uint32,uint32,uint256,bool))
+func (_SPVVerifier *SPVVerifierSession) ParseBlockHeader(headerBytes []byte) (SPVVerifierBlockHeader, error) {
+	return _SPVVerifier.Contract.ParseBlockHeader(&_SPVVerifier.CallOpts, headerBytes)
+}
+
+// ParseBlockHeader is a free data retrieval call binding the contract method 0x849d926b.
+//
+// Solidity: function parseBlockHeader(bytes headerBytes) pure returns((uint32,bytes32,bytes32,uint32,uint32,uint32,uint256,bool))
+func (_SPVVerifier *SPVVerifierCallerSession) ParseBlockHeader(headerBytes []byte) (SPVVerifierBlockHeader, error) {
+	return _SPVVerifier.Contract.ParseBlockHeader(&_SPVVerifier.CallOpts, headerBytes)
+}
+
+// Relayer is a free data retrieval call binding the contract method 0x8406c079.
+//
+// Solidity: function relayer() view returns(address)
+func (_SPVVerifier *SPVVerifierCaller) Relayer(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "relayer")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Relayer is a free data retrieval call binding the contract method 0x8406c079.
+//
+// Solidity: function relayer() view returns(address)
+func (_SPVVerifier *SPVVerifierSession) Relayer() (common.Address, error) {
+	return _SPVVerifier.Contract.Relayer(&_SPVVerifier.CallOpts)
+}
+
+// Relayer is a free data retrieval call binding the contract method 0x8406c079.
+//
+// Solidity: function relayer() view returns(address)
+func (_SPVVerifier *SPVVerifierCallerSession) Relayer() (common.Address, error) {
+	return _SPVVerifier.Contract.Relayer(&_SPVVerifier.CallOpts)
+}
+
+// ReverseBytes32 is a free data retrieval call binding the contract method 0xac821b6d.
+//
+// Solidity: function reverseBytes32(bytes32 input) pure returns(bytes32)
+func (_SPVVerifier *SPVVerifierCaller) ReverseBytes32(opts *bind.CallOpts, input [32]byte) ([32]byte, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "reverseBytes32", input)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// ReverseBytes32 is a free data retrieval call binding the contract method 0xac821b6d.
+//
+// Solidity: function reverseBytes32(bytes32 input) pure returns(bytes32)
+func (_SPVVerifier *SPVVerifierSession) ReverseBytes32(input [32]byte) ([32]byte, error) {
+	return _SPVVerifier.Contract.ReverseBytes32(&_SPVVerifier.CallOpts, input)
+}
+
+// ReverseBytes32 is a free data retrieval call binding the contract method 0xac821b6d.
+//
+// Solidity: function reverseBytes32(bytes32 input) pure returns(bytes32)
+func (_SPVVerifier *SPVVerifierCallerSession) ReverseBytes32(input [32]byte) ([32]byte, error) {
+	return _SPVVerifier.Contract.ReverseBytes32(&_SPVVerifier.CallOpts, input)
+}
+
+// ReverseBytes4 is a free data retrieval call binding the contract method 0xa99ebfd2.
+//
+// Solidity: function reverseBytes4(bytes4 input) pure returns(bytes4)
+func (_SPVVerifier *SPVVerifierCaller) ReverseBytes4(opts *bind.CallOpts, input [4]byte) ([4]byte, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "reverseBytes4", input)
+
+	if err != nil {
+		return *new([4]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([4]byte)).(*[4]byte)
+
+	return out0, err
+
+}
+
+// ReverseBytes4 is a free data retrieval call binding the contract method 0xa99ebfd2.
+//
+// Solidity: function reverseBytes4(bytes4 input) pure returns(bytes4)
+func (_SPVVerifier *SPVVerifierSession) ReverseBytes4(input [4]byte) ([4]byte, error) {
+	return _SPVVerifier.Contract.ReverseBytes4(&_SPVVerifier.CallOpts, input)
+}
+
+// ReverseBytes4 is a free data retrieval call binding the contract method 0xa99ebfd2.
+//
+// Solidity: function reverseBytes4(bytes4 input) pure returns(bytes4)
+func (_SPVVerifier *SPVVerifierCallerSession) ReverseBytes4(input [4]byte) ([4]byte, error) {
+	return _SPVVerifier.Contract.ReverseBytes4(&_SPVVerifier.CallOpts, input)
+}
+
+// Slice is a free data retrieval call binding the contract method 0xe0041396.
+//
+// Solidity: function slice(bytes data, uint256 start, uint256 length) pure returns(bytes)
+func (_SPVVerifier *SPVVerifierCaller) Slice(opts *bind.CallOpts, data []byte, start *big.Int, length *big.Int) ([]byte, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "slice", data, start, length)
+
+	if err != nil {
+		return *new([]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]byte)).(*[]byte)
+
+	return out0, err
+
+}
+
+// Slice is a free data retrieval call binding the contract method 0xe0041396.
+//
+// Solidity: function slice(bytes data, uint256 start, uint256 length) pure returns(bytes)
+func (_SPVVerifier *SPVVerifierSession) Slice(data []byte, start *big.Int, length *big.Int) ([]byte, error) {
+	return _SPVVerifier.Contract.Slice(&_SPVVerifier.CallOpts, data, start, length)
+}
+
+// Slice is a free data retrieval call binding the contract method 0xe0041396.
+//
+// Solidity: function slice(bytes data, uint256 start, uint256 length) pure returns(bytes)
+func (_SPVVerifier *SPVVerifierCallerSession) Slice(data []byte, start *big.Int, length *big.Int) ([]byte, error) {
+	return _SPVVerifier.Contract.Slice(&_SPVVerifier.CallOpts, data, start, length)
+}
+
+// VerifiedTransactions is a free data retrieval call binding the contract method 0xdfb1dc0a.
+//
+// Solidity: function verifiedTransactions(bytes32 ) view returns(bool)
+func (_SPVVerifier *SPVVerifierCaller) VerifiedTransactions(opts *bind.CallOpts, arg0 [32]byte) (bool, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "verifiedTransactions", arg0)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// VerifiedTransactions is a free data retrieval call binding the contract method 0xdfb1dc0a.
+//
+// Solidity: function verifiedTransactions(bytes32 ) view returns(bool)
+func (_SPVVerifier *SPVVerifierSession) VerifiedTransactions(arg0 [32]byte) (bool, error) {
+	return _SPVVerifier.Contract.VerifiedTransactions(&_SPVVerifier.CallOpts, arg0)
+}
+
+// VerifiedTransactions is a free data retrieval call binding the contract method 0xdfb1dc0a.
+//
+// Solidity: function verifiedTransactions(bytes32 ) view returns(bool)
+func (_SPVVerifier *SPVVerifierCallerSession) VerifiedTransactions(arg0 [32]byte) (bool, error) {
+	return _SPVVerifier.Contract.VerifiedTransactions(&_SPVVerifier.CallOpts, arg0)
+}
+
+// VerifyMerkleProof is a free data retrieval call binding the contract method 0x7ee62f3c.
+//
+// Solidity: function verifyMerkleProof((bytes32[],uint256,uint256,bytes32,bytes32) merkleProof) pure returns(bool)
+func (_SPVVerifier *SPVVerifierCaller) VerifyMerkleProof(opts *bind.CallOpts, merkleProof SPVVerifierMerkleProof) (bool, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "verifyMerkleProof", merkleProof)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// VerifyMerkleProof is a free data retrieval call binding the contract method 0x7ee62f3c.
+//
+// Solidity: function verifyMerkleProof((bytes32[],uint256,uint256,bytes32,bytes32) merkleProof) pure returns(bool)
+func (_SPVVerifier *SPVVerifierSession) VerifyMerkleProof(merkleProof SPVVerifierMerkleProof) (bool, error) {
+	return _SPVVerifier.Contract.VerifyMerkleProof(&_SPVVerifier.CallOpts, merkleProof)
+}
+
+// VerifyMerkleProof is a free data retrieval call binding the contract method 0x7ee62f3c.
+//
+// Solidity: function verifyMerkleProof((bytes32[],uint256,uint256,bytes32,bytes32) merkleProof) pure returns(bool)
+func (_SPVVerifier *SPVVerifierCallerSession) VerifyMerkleProof(merkleProof SPVVerifierMerkleProof) (bool, error) {
+	return _SPVVerifier.Contract.VerifyMerkleProof(&_SPVVerifier.CallOpts, merkleProof)
+}
+
+// Version is a free data retrieval call binding the contract method 0x54fd4d50.
+//
+// Solidity: function version() pure returns(string)
+func (_SPVVerifier *SPVVerifierCaller) Version(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _SPVVerifier.contract.Call(opts, &out, "version")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// Version is a free data retrieval call binding the contract method 0x54fd4d50.
+//
+// Solidity: function version() pure returns(string)
+func (_SPVVerifier *SPVVerifierSession) Version() (string, error) {
+	return _SPVVerifier.Contract.Version(&_SPVVerifier.CallOpts)
+}
+
+// Version is a free data retrieval call binding the contract method 0x54fd4d50.
+//
+// Solidity: function version() pure returns(string)
+func (_SPVVerifier *SPVVerifierCallerSession) Version() (string, error) {
+	return _SPVVerifier.Contract.Version(&_SPVVerifier.CallOpts)
+}
+
+// BatchVerifyProofs is a paid mutator transaction binding the contract method 0xe1783806.
+//
+// Solidity: function batchVerifyProofs(bytes[] headerBytesArray, (bytes32[],uint256,uint256,bytes32,bytes32)[] merkleProofs, uint256[] blockHeights) returns(bool[])
+func (_SPVVerifier *SPVVerifierTransactor) BatchVerifyProofs(opts *bind.TransactOpts, headerBytesArray [][]byte, merkleProofs []SPVVerifierMerkleProof, blockHeights []*big.Int) (*types.Transaction, error) {
+	return _SPVVerifier.contract.Transact(opts, "batchVerifyProofs", headerBytesArray, merkleProofs, blockHeights)
+}
+
+// BatchVerifyProofs is a paid mutator transaction binding the contract method 0xe1783806.
+//
+// Solidity: function batchVerifyProofs(bytes[] headerBytesArray, (bytes32[],uint256,uint256,bytes32,bytes32)[] merkleProofs, uint256[] blockHeights) returns(bool[])
+func (_SPVVerifier *SPVVerifierSession) BatchVerifyProofs(headerBytesArray [][]byte, merkleProofs []SPVVerifierMerkleProof, blockHeights []*big.Int) (*types.Transaction, error) {
+	return _SPVVerifier.Contract.BatchVerifyProofs(&_SPVVerifier.TransactOpts, headerBytesArray, merkleProofs, blockHeights)
+}
+
+// BatchVerifyProofs is a paid mutator transaction binding the contract method 0xe1783806.
+//
+// Solidity: function batchVerifyProofs(bytes[] headerBytesArray, (bytes32[],uint256,uint256,bytes32,bytes32)[] merkleProofs, uint256[] blockHeights) returns(bool[])
+func (_SPVVerifier *SPVVerifierTransactorSession) BatchVerifyProofs(headerBytesArray [][]byte, merkleProofs []SPVVerifierMerkleProof, blockHeights []*big.Int) (*types.Transaction, error) {
+	return _SPVVerifier.Contract.BatchVerifyProofs(&_SPVVerifier.TransactOpts, headerBytesArray, merkleProofs, blockHeights)
+}
+
+// SubmitBlockHeaders is a paid mutator transaction binding the contract method 0x1c68615a.
+//
+// Solidity: function submitBlockHeaders(bytes[] headers, uint256 startHeight) returns()
+func (_SPVVerifier *SPVVerifierTransactor) SubmitBlockHeaders(opts *bind.TransactOpts, headers [][]byte, startHeight *big.Int) (*types.Transaction, error) {
+	return _SPVVerifier.contract.Transact(opts, "submitBlockHeaders", headers, startHeight)
+}
+
+// SubmitBlockHeaders is a paid mutator transaction binding the contract method 0x1c68615a.
+//
+// Solidity: function submitBlockHeaders(bytes[] headers, uint256 startHeight) returns()
+func (_SPVVerifier *SPVVerifierSession) SubmitBlockHeaders(headers [][]byte, startHeight *big.Int) (*types.Transaction, error) {
+	return _SPVVerifier.Contract.SubmitBlockHeaders(&_SPVVerifier.TransactOpts, headers, startHeight)
+}
+
+// SubmitBlockHeaders is a paid mutator transaction binding the contract method 0x1c68615a.
+//
+// Solidity: function submitBlockHeaders(bytes[] headers, uint256 startHeight) returns()
+func (_SPVVerifier *SPVVerifierTransactorSession) SubmitBlockHeaders(headers [][]byte, startHeight *big.Int) (*types.Transaction, error) {
+	return _SPVVerifier.Contract.SubmitBlockHeaders(&_SPVVerifier.TransactOpts, headers, startHeight)
+}
+
+// VerifyProof is a paid mutator transaction binding the contract method 0xee7bbecd.
+//
+// Solidity: function verifyProof(bytes headerBytes, (bytes32[],uint256,uint256,bytes32,bytes32) merkleProof, uint256 blockHeight) returns(bool)
+func (_SPVVerifier *SPVVerifierTransactor) VerifyProof(opts *bind.TransactOpts, headerBytes []byte, merkleProof SPVVerifierMerkleProof, blockHeight *big.Int) (*types.Transaction, error) {
+	return _SPVVerifier.contract.Transact(opts, "verifyProof", headerBytes, merkleProof, blockHeight)
+}
+
+// VerifyProof is a paid mutator transaction binding the contract method 0xee7bbecd.
+//
+// Solidity: function verifyProof(bytes headerBytes, (bytes32[],uint256,uint256,bytes32,bytes32) merkleProof, uint256 blockHeight) returns(bool)
+func (_SPVVerifier *SPVVerifierSession) VerifyProof(headerBytes []byte, merkleProof SPVVerifierMerkleProof, blockHeight *big.Int) (*types.Transaction, error) {
+	return _SPVVerifier.Contract.VerifyProof(&_SPVVerifier.TransactOpts, headerBytes, merkleProof, blockHeight)
+}
+
+// VerifyProof is a paid mutator transaction binding the contract method 0xee7bbecd.
+//
+// Solidity: function verifyProof(bytes headerBytes, (bytes32[],uint256,uint256,bytes32,bytes32) merkleProof, uint256 blockHeight) returns(bool)
+func (_SPVVerifier *SPVVerifierTransactorSession) VerifyProof(headerBytes []byte, merkleProof SPVVerifierMerkleProof, blockHeight *big.Int) (*types.Transaction, error) {
+	return _SPVVerifier.Contract.VerifyProof(&_SPVVerifier.TransactOpts, headerBytes, merkleProof, blockHeight)
+}
+
+// SPVVerifierBlockHeaderStoredIterator is returned from FilterBlockHeaderStored and is used to iterate over the raw logs and unpacked data for BlockHeaderStored events raised by the SPVVerifier contract.
+type SPVVerifierBlockHeaderStoredIterator struct {
+	Event *SPVVerifierBlockHeaderStored // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SPVVerifierBlockHeaderStoredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SPVVerifierBlockHeaderStored)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SPVVerifierBlockHeaderStored)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SPVVerifierBlockHeaderStoredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SPVVerifierBlockHeaderStoredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SPVVerifierBlockHeaderStored represents a BlockHeaderStored event raised by the SPVVerifier contract.
+type SPVVerifierBlockHeaderStored struct {
+	BlockHash   [32]byte
+	BlockHeight *big.Int
+	Raw         types.Log // Blockchain specific contextual infos
+}
+
+// FilterBlockHeaderStored is a free log retrieval operation binding the contract event 0xcf18eb24c9925e262f477d6cf89099ee71c2d16f06f65761b3895eb7337e6941.
+//
+// Solidity: event BlockHeaderStored(bytes32 indexed blockHash, uint256 blockHeight)
+func (_SPVVerifier *SPVVerifierFilterer) FilterBlockHeaderStored(opts *bind.FilterOpts, blockHash [][32]byte) (*SPVVerifierBlockHeaderStoredIterator, error) {
+
+	var blockHashRule []interface{}
+	for _, blockHashItem := range blockHash {
+		blockHashRule = append(blockHashRule, blockHashItem)
+	}
+
+	logs, sub, err := _SPVVerifier.contract.FilterLogs(opts, "BlockHeaderStored", blockHashRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SPVVerifierBlockHeaderStoredIterator{contract: _SPVVerifier.contract, event: "BlockHeaderStored", logs: logs, sub: sub}, nil
+}
+
+// WatchBlockHeaderStored is a free log subscription operation binding the contract event 0xcf18eb24c9925e262f477d6cf89099ee71c2d16f06f65761b3895eb7337e6941.
+//
+// Solidity: event BlockHeaderStored(bytes32 indexed blockHash, uint256 blockHeight)
+func (_SPVVerifier *SPVVerifierFilterer) WatchBlockHeaderStored(opts *bind.WatchOpts, sink chan<- *SPVVerifierBlockHeaderStored, blockHash [][32]byte) (event.Subscription, error) {
+
+	var blockHashRule []interface{}
+	for _, blockHashItem := range blockHash {
+		blockHashRule = append(blockHashRule, blockHashItem)
+	}
+
+	logs, sub, err := _SPVVerifier.contract.WatchLogs(opts, "BlockHeaderStored", blockHashRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SPVVerifierBlockHeaderStored)
+				if err := _SPVVerifier.contract.UnpackLog(event, "BlockHeaderStored", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseBlockHeaderStored is a log parse operation binding the contract event 0xcf18eb24c9925e262f477d6cf89099ee71c2d16f06f65761b3895eb7337e6941.
+//
+// Solidity: event BlockHeaderStored(bytes32 indexed blockHash, uint256 blockHeight)
+func (_SPVVerifier *SPVVerifierFilterer) ParseBlockHeaderStored(log types.Log) (*SPVVerifierBlockHeaderStored, error) {
+	event := new(SPVVerifierBlockHeaderStored)
+	if err := _SPVVerifier.contract.UnpackLog(event, "BlockHeaderStored", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// SPVVerifierHeadersRelayedIterator is returned from FilterHeadersRelayed and is used to iterate over the raw logs and unpacked data for HeadersRelayed events raised by the SPVVerifier contract.
+type SPVVerifierHeadersRelayedIterator struct {
+	Event *SPVVerifierHeadersRelayed // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SPVVerifierHeadersRelayedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SPVVerifierHeadersRelayed)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SPVVerifierHeadersRelayed)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SPVVerifierHeadersRelayedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SPVVerifierHeadersRelayedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SPVVerifierHeadersRelayed represents a HeadersRelayed event raised by the SPVVerifier contract.
+type SPVVerifierHeadersRelayed struct {
+	StartHeight *big.Int
+	EndHeight   *big.Int
+	Raw         types.Log // Blockchain specific contextual infos
+}
+
+// FilterHeadersRelayed is a free log retrieval operation binding the contract event 0x81207236e83c60aff73571b2dec24d8ea8df20f6e052d5ab2e0523cd59249c8e.
+//
+// Solidity: event HeadersRelayed(uint256 startHeight, uint256 endHeight)
+func (_SPVVerifier *SPVVerifierFilterer) FilterHeadersRelayed(opts *bind.FilterOpts) (*SPVVerifierHeadersRelayedIterator, error) {
+
+	logs, sub, err := _SPVVerifier.contract.FilterLogs(opts, "HeadersRelayed")
+	if err != nil {
+		return nil, err
+	}
+	return &SPVVerifierHeadersRelayedIterator{contract: _SPVVerifier.contract, event: "HeadersRelayed", logs: logs, sub: sub}, nil
+}
+
+// WatchHeadersRelayed is a free log subscription operation binding the contract event 0x81207236e83c60aff73571b2dec24d8ea8df20f6e052d5ab2e0523cd59249c8e.
+//
+// Solidity: event HeadersRelayed(uint256 startHeight, uint256 endHeight)
+func (_SPVVerifier *SPVVerifierFilterer) WatchHeadersRelayed(opts *bind.WatchOpts, sink chan<- *SPVVerifierHeadersRelayed) (event.Subscription, error) {
+
+	logs, sub, err := _SPVVerifier.contract.WatchLogs(opts, "HeadersRelayed")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SPVVerifierHeadersRelayed)
+				if err := _SPVVerifier.contract.UnpackLog(event, "HeadersRelayed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseHeadersRelayed is a log parse operation binding the contract event 0x81207236e83c60aff73571b2dec24d8ea8df20f6e052d5ab2e0523cd59249c8e.
+//
+// Solidity: event HeadersRelayed(uint256 startHeight, uint256 endHeight)
+func (_SPVVerifier *SPVVerifierFilterer) ParseHeadersRelayed(log types.Log) (*SPVVerifierHeadersRelayed, error) {
+	event := new(SPVVerifierHeadersRelayed)
+	if err := _SPVVerifier.contract.UnpackLog(event, "HeadersRelayed", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// SPVVerifierProofVerifiedIterator is returned from FilterProofVerified and is used to iterate over the raw logs and unpacked data for ProofVerified events raised by the SPVVerifier contract.
+type SPVVerifierProofVerifiedIterator struct {
+	Event *SPVVerifierProofVerified // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SPVVerifierProofVerifiedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SPVVerifierProofVerified)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SPVVerifierProofVerified)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SPVVerifierProofVerifiedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SPVVerifierProofVerifiedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SPVVerifierProofVerified represents a ProofVerified event raised by the SPVVerifier contract.
+type SPVVerifierProofVerified struct {
+	TxHash      [32]byte
+	BlockHash   [32]byte
+	BlockHeight *big.Int
+	Raw         types.Log // Blockchain specific contextual infos
+}
+
+// FilterProofVerified is a free log retrieval operation binding the contract event 0x5218389e256f588f1b0c542d774ec9ba39106830c105126b25a30eab96afa3be.
+//
+// Solidity: event ProofVerified(bytes32 indexed txHash, bytes32 indexed blockHash, uint256 blockHeight)
+func (_SPVVerifier *SPVVerifierFilterer) FilterProofVerified(opts *bind.FilterOpts, txHash [][32]byte, blockHash [][32]byte) (*SPVVerifierProofVerifiedIterator, error) {
+
+	var txHashRule []interface{}
+	for _, txHashItem := range txHash {
+		txHashRule = append(txHashRule, txHashItem)
+	}
+	var blockHashRule []interface{}
+	for _, blockHashItem := range blockHash {
+		blockHashRule = append(blockHashRule, blockHashItem)
+	}
+
+	logs, sub, err := _SPVVerifier.contract.FilterLogs(opts, "ProofVerified", txHashRule, blockHashRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SPVVerifierProofVerifiedIterator{contract: _SPVVerifier.contract, event: "ProofVerified", logs: logs, sub: sub}, nil
+}
+
+// WatchProofVerified is a free log subscription operation binding the contract event 0x5218389e256f588f1b0c542d774ec9ba39106830c105126b25a30eab96afa3be.
+//
+// Solidity: event ProofVerified(bytes32 indexed txHash, bytes32 indexed blockHash, uint256 blockHeight)
+func (_SPVVerifier *SPVVerifierFilterer) WatchProofVerified(opts *bind.WatchOpts, sink chan<- *SPVVerifierProofVerified, txHash [][32]byte, blockHash [][32]byte) (event.Subscription, error) {
+
+	var txHashRule []interface{}
+	for _, txHashItem := range txHash {
+		txHashRule = append(txHashRule, txHashItem)
+	}
+	var blockHashRule []interface{}
+	for _, blockHashItem := range blockHash {
+		blockHashRule = append(blockHashRule, blockHashItem)
+	}
+
+	logs, sub, err := _SPVVerifier.contract.WatchLogs(opts, "ProofVerified", txHashRule, blockHashRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SPVVerifierProofVerified)
+				if err := _SPVVerifier.contract.UnpackLog(event, "ProofVerified", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseProofVerified is a log parse operation binding the contract event 0x5218389e256f588f1b0c542d774ec9ba39106830c105126b25a30eab96afa3be.
+//
+// Solidity: event ProofVerified(bytes32 indexed txHash, bytes32 indexed blockHash, uint256 blockHeight)
+func (_SPVVerifier *SPVVerifierFilterer) ParseProofVerified(log types.Log) (*SPVVerifierProofVerified, error) {
+	event := new(SPVVerifierProofVerified)
+	if err := _SPVVerifier.contract.UnpackLog(event, "ProofVerified", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -0,0 +1,1349 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package bindings
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// UTXORegistryUTXORecord is an auto generated low-level Go binding around an user-defined struct.
+type UTXORegistryUTXORecord struct {
+	BitcoinTxId    string
+	BitcoinVout    uint32
+	BitcoinAmount  *big.Int
+	BitcoinAddress string
+	TokenAddress   common.Address
+	TokenOwner     common.Address
+	IsActive       bool
+	CreatedAt      *big.Int
+}
+
+// UTXORegistryMetaData contains all meta data concerning the UTXORegistry contract.
+var UTXORegistryMetaData = &bind.MetaData{
+	ABI: string(mustArtifact("UTXORegistry").ABI),
+	Bin: mustArtifact("UTXORegistry").Bytecode,
+}
+
+// UTXORegistryABI is the input ABI used to generate the binding from.
+// Deprecated: Use UTXORegistryMetaData.ABI instead.
+var UTXORegistryABI = UTXORegistryMetaData.ABI
+
+// UTXORegistryBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use UTXORegistryMetaData.Bin instead.
+var UTXORegistryBin = UTXORegistryMetaData.Bin
+
+// DeployUTXORegistry deploys a new Ethereum contract, binding an instance of UTXORegistry to it.
+func DeployUTXORegistry(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *UTXORegistry, error) {
+	parsed, err := UTXORegistryMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(UTXORegistryBin), backend)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &UTXORegistry{UTXORegistryCaller: UTXORegistryCaller{contract: contract}, UTXORegistryTransactor: UTXORegistryTransactor{contract: contract}, UTXORegistryFilterer: UTXORegistryFilterer{contract: contract}}, nil
+}
+
+// UTXORegistry is an auto generated Go binding around an Ethereum contract.
+type UTXORegistry struct {
+	UTXORegistryCaller     // Read-only binding to the contract
+	UTXORegistryTransactor // Write-only binding to the contract
+	UTXORegistryFilterer   // Log filterer for contract events
+}
+
+// UTXORegistryCaller is an auto generated read-only Go binding around an Ethereum contract.
+type UTXORegistryCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// UTXORegistryTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type UTXORegistryTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// UTXORegistryFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type UTXORegistryFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// UTXORegistrySession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type UTXORegistrySession struct {
+	Contract     *UTXORegistry     // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// UTXORegistryCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type UTXORegistryCallerSession struct {
+	Contract *UTXORegistryCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts       // Call options to use throughout this session
+}
+
+// UTXORegistryTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type UTXORegistryTransactorSession struct {
+	Contract     *UTXORegistryTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts       // Transaction auth options to use throughout this session
+}
+
+// UTXORegistryRaw is an auto generated low-level Go binding around an Ethereum contract.
+type UTXORegistryRaw struct {
+	Contract *UTXORegistry // Generic contract binding to access the raw methods on
+}
+
+// UTXORegistryCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type UTXORegistryCallerRaw struct {
+	Contract *UTXORegistryCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// UTXORegistryTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type UTXORegistryTransactorRaw struct {
+	Contract *UTXORegistryTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewUTXORegistry creates a new instance of UTXORegistry, bound to a specific deployed contract.
+func NewUTXORegistry(address common.Address, backend bind.ContractBackend) (*UTXORegistry, error) {
+	contract, err := bindUTXORegistry(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &UTXORegistry{UTXORegistryCaller: UTXORegistryCaller{contract: contract}, UTXORegistryTransactor: UTXORegistryTransactor{contract: contract}, UTXORegistryFilterer: UTXORegistryFilterer{contract: contract}}, nil
+}
+
+// NewUTXORegistryCaller creates a new read-only instance of UTXORegistry, bound to a specific deployed contract.
+func NewUTXORegistryCaller(address common.Address, caller bind.ContractCaller) (*UTXORegistryCaller, error) {
+	contract, err := bindUTXORegistry(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &UTXORegistryCaller{contract: contract}, nil
+}
+
+// NewUTXORegistryTransactor creates a new write-only instance of UTXORegistry, bound to a specific deployed contract.
+func NewUTXORegistryTransactor(address common.Address, transactor bind.ContractTransactor) (*UTXORegistryTransactor, error) {
+	contract, err := bindUTXORegistry(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &UTXORegistryTransactor{contract: contract}, nil
+}
+
+// NewUTXORegistryFilterer creates a new log filterer instance of UTXORegistry, bound to a specific deployed contract.
+func NewUTXORegistryFilterer(address common.Address, filterer bind.ContractFilterer) (*UTXORegistryFilterer, error) {
+	contract, err := bindUTXORegistry(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &UTXORegistryFilterer{contract: contract}, nil
+}
+
+// bindUTXORegistry binds a generic wrapper to an already deployed contract.
+func bindUTXORegistry(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := UTXORegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_UTXORegistry *UTXORegistryRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _UTXORegistry.Contract.UTXORegistryCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_UTXORegistry *UTXORegistryRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _UTXORegistry.Contract.UTXORegistryTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_UTXORegistry *UTXORegistryRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _UTXORegistry.Contract.UTXORegistryTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_UTXORegistry *UTXORegistryCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _UTXORegistry.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_UTXORegistry *UTXORegistryTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _UTXORegistry.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_UTXORegistry *UTXORegistryTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _UTXORegistry.Contract.contract.Transact(opts, method, params...)
+}
+
+// GetUTXO is a free data retrieval call binding the contract method 0xe9cf5944.
+//
+// Solidity: function getUTXO(bytes32 _utxoId) view returns((string,uint32,uint256,string,address,address,bool,uint256))
+func (_UTXORegistry *UTXORegistryCaller) GetUTXO(opts *bind.CallOpts, _utxoId [32]byte) (UTXORegistryUTXORecord, error) {
+	var out []interface{}
+	err := _UTXORegistry.contract.Call(opts, &out, "getUTXO", _utxoId)
+
+	if err != nil {
+		return *new(UTXORegistryUTXORecord), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(UTXORegistryUTXORecord)).(*UTXORegistryUTXORecord)
+
+	return out0, err
+
+}
+
+// GetUTXO is a free data retrieval call binding the contract method 0xe9cf5944.
+//
+// Solidity: function getUTXO(bytes32 _utxoId) view returns((string,uint32,uint256,string,address,address,bool,uint256))
+func (_UTXORegistry *UTXORegistrySession) GetUTXO(_utxoId [32]byte) (UTXORegistryUTXORecord, error) {
+	return _UTXORegistry.Contract.GetUTXO(&_UTXORegistry.CallOpts, _utxoId)
+}
+
+// GetUTXO is a free data retrieval call binding the contract method 0xe9cf5944.
+//
+// Solidity: function getUTXO(bytes32 _utxoId) view returns((string,uint32,uint256,string,address,address,bool,uint256))
+func (_UTXORegistry *UTXORegistryCallerSession) GetUTXO(_utxoId [32]byte) (UTXORegistryUTXORecord, error) {
+	return _UTXORegistry.Contract.GetUTXO(&_UTXORegistry.CallOpts, _utxoId)
+}
+
+// GetUTXOCount is a free data retrieval call binding the contract method 0xa2b3703f.
+//
+// Solidity: function getUTXOCount() view returns(uint256)
+func (_UTXORegistry *UTXORegistryCaller) GetUTXOCount(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _UTXORegistry.contract.Call(opts, &out, "getUTXOCount")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetUTXOCount is a free data retrieval call binding the contract method 0xa2b3703f.
+//
+// Solidity: function getUTXOCount() view returns(uint256)
+func (_UTXORegistry *UTXORegistrySession) GetUTXOCount() (*big.Int, error) {
+	return _UTXORegistry.Contract.GetUTXOCount(&_UTXORegistry.CallOpts)
+}
+
+// GetUTXOCount is a free data retrieval call binding the contract method 0xa2b3703f.
+//
+// Solidity: function getUTXOCount() view returns(uint256)
+func (_UTXORegistry *UTXORegistryCallerSession) GetUTXOCount() (*big.Int, error) {
+	return _UTXORegistry.Contract.GetUTXOCount(&_UTXORegistry.CallOpts)
+}
+
+// GetUTXOId is a free data retrieval call binding the contract method 0xdc639217.
+//
+// Solidity: function getUTXOId(string _bitcoinTxId, uint32 _bitcoinVout) pure returns(bytes32)
+func (_UTXORegistry *UTXORegistryCaller) GetUTXOId(opts *bind.CallOpts, _bitcoinTxId string, _bitcoinVout uint32) ([32]byte, error) {
+	var out []interface{}
+	err := _UTXORegistry.contract.Call(opts, &out, "getUTXOId", _bitcoinTxId, _bitcoinVout)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// GetUTXOId is a free data retrieval call binding the contract method 0xdc639217.
+//
+// Solidity: function getUTXOId(string _bitcoinTxId, uint32 _bitcoinVout) pure returns(bytes32)
+func (_UTXORegistry *UTXORegistrySession) GetUTXOId(_bitcoinTxId string, _bitcoinVout uint32) ([32]byte, error) {
+	return _UTXORegistry.Contract.GetUTXOId(&_UTXORegistry.CallOpts, _bitcoinTxId, _bitcoinVout)
+}
+
+// GetUTXOId is a free data retrieval call binding the contract method 0xdc639217.
+//
+// Solidity: function getUTXOId(string _bitcoinTxId, uint32 _bitcoinVout) pure returns(bytes32)
+func (_UTXORegistry *UTXORegistryCallerSession) GetUTXOId(_bitcoinTxId string, _bitcoinVout uint32) ([32]byte, error) {
+	return _UTXORegistry.Contract.GetUTXOId(&_UTXORegistry.CallOpts, _bitcoinTxId, _bitcoinVout)
+}
+
+// Operators is a free data retrieval call binding the contract method 0x13e7c9d8.
+//
+// Solidity: function operators(address ) view returns(bool)
+func (_UTXORegistry *UTXORegistryCaller) Operators(opts *bind.CallOpts, arg0 common.Address) (bool, error) {
+	var out []interface{}
+	err := _UTXORegistry.contract.Call(opts, &out, "operators", arg0)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// Operators is a free data retrieval call binding the contract method 0x13e7c9d8.
+//
+// Solidity: function operators(address ) view returns(bool)
+func (_UTXORegistry *UTXORegistrySession) Operators(arg0 common.Address) (bool, error) {
+	return _UTXORegistry.Contract.Operators(&_UTXORegistry.CallOpts, arg0)
+}
+
+// Operators is a free data retrieval call binding the contract method 0x13e7c9d8.
+//
+// Solidity: function operators(address ) view returns(bool)
+func (_UTXORegistry *UTXORegistryCallerSession) Operators(arg0 common.Address) (bool, error) {
+	return _UTXORegistry.Contract.Operators(&_UTXORegistry.CallOpts, arg0)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_UTXORegistry *UTXORegistryCaller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _UTXORegistry.contract.Call(opts, &out, "owner")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_UTXORegistry *UTXORegistrySession) Owner() (common.Address, error) {
+	return _UTXORegistry.Contract.Owner(&_UTXORegistry.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_UTXORegistry *UTXORegistryCallerSession) Owner() (common.Address, error) {
+	return _UTXORegistry.Contract.Owner(&_UTXORegistry.CallOpts)
+}
+
+// TokenToUtxo is a free data retrieval call binding the contract method 0x7c8e717b.
+//
+// Solidity: function tokenToUtxo(address ) view returns(bytes32)
+func (_UTXORegistry *UTXORegistryCaller) TokenToUtxo(opts *bind.CallOpts, arg0 common.Address) ([32]byte, error) {
+	var out []interface{}
+	err := _UTXORegistry.contract.Call(opts, &out, "tokenToUtxo", arg0)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// TokenToUtxo is a free data retrieval call binding the contract method 0x7c8e717b.
+//
+// Solidity: function tokenToUtxo(address ) view returns(bytes32)
+func (_UTXORegistry *UTXORegistrySession) TokenToUtxo(arg0 common.Address) ([32]byte, error) {
+	return _UTXORegistry.Contract.TokenToUtxo(&_UTXORegistry.CallOpts, arg0)
+}
+
+// TokenToUtxo is a free data retrieval call binding the contract method 0x7c8e717b.
+//
+// Solidity: function tokenToUtxo(address ) view returns(bytes32)
+func (_UTXORegistry *UTXORegistryCallerSession) TokenToUtxo(arg0 common.Address) ([32]byte, error) {
+	return _UTXORegistry.Contract.TokenToUtxo(&_UTXORegistry.CallOpts, arg0)
+}
+
+// UtxoIds is a free data retrieval call binding the contract method 0x780f05fc.
+//
+// Solidity: function utxoIds(uint256 ) view returns(bytes32)
+func (_UTXORegistry *UTXORegistryCaller) UtxoIds(opts *bind.CallOpts, arg0 *big.Int) ([32]byte, error) {
+	var out []interface{}
+	err := _UTXORegistry.contract.Call(opts, &out, "utxoIds", arg0)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// UtxoIds is a free data retrieval call binding the contract method 0x780f05fc.
+//
+// Solidity: function utxoIds(uint256 ) view returns(bytes32)
+func (_UTXORegistry *UTXORegistrySession) UtxoIds(arg0 *big.Int) ([32]byte, error) {
+	return _UTXORegistry.Contract.UtxoIds(&_UTXORegistry.CallOpts, arg0)
+}
+
+// UtxoIds is a free data retrieval call binding the contract method 0x780f05fc.
+//
+// Solidity: function utxoIds(uint256 ) view returns(bytes32)
+func (_UTXORegistry *UTXORegistryCallerSession) UtxoIds(arg0 *big.Int) ([32]byte, error) {
+	return _UTXORegistry.Contract.UtxoIds(&_UTXORegistry.CallOpts, arg0)
+}
+
+// Utxos is a free data retrieval call binding the contract method 0x3cf18ab9.
+//
+// Solidity: function utxos(bytes32 ) view returns(string bitcoinTxId, uint32 bitcoinVout, uint256 bitcoinAmount, string bitcoinAddress, address tokenAddress, address tokenOwner, bool isActive, uint256 createdAt)
+func (_UTXORegistry *UTXORegistryCaller) Utxos(opts *bind.CallOpts, arg0 [32]byte) (struct {
+	BitcoinTxId    string
+	BitcoinVout    uint32
+	BitcoinAmount  *big.Int
+	BitcoinAddress string
+	TokenAddress   common.Address
+	TokenOwner     common.Address
+	IsActive       bool
+	CreatedAt      *big.Int
+}, error) {
+	var out []interface{}
+	err := _UTXORegistry.contract.Call(opts, &out, "utxos", arg0)
+
+	outstruct := new(struct {
+		BitcoinTxId    string
+		BitcoinVout    uint32
+		BitcoinAmount  *big.Int
+		BitcoinAddress string
+		TokenAddress   common.Address
+		TokenOwner     common.Address
+		IsActive       bool
+		CreatedAt      *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.BitcoinTxId = *abi.ConvertType(out[0], new(string)).(*string)
+	outstruct.BitcoinVout = *abi.ConvertType(out[1], new(uint32)).(*uint32)
+	outstruct.BitcoinAmount = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.BitcoinAddress = *abi.ConvertType(out[3], new(string)).(*string)
+	outstruct.TokenAddress = *abi.ConvertType(out[4], new(common.Address)).(*common.Address)
+	outstruct.TokenOwner = *abi.ConvertType(out[5], new(common.Address)).(*common.Address)
+	outstruct.IsActive = *abi.ConvertType(out[6], new(bool)).(*bool)
+	outstruct.CreatedAt = *abi.ConvertType(out[7], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// Utxos is a free data retrieval call binding the contract method 0x3cf18ab9.
+//
+// Solidity: function utxos(bytes32 ) view returns(string bitcoinTxId, uint32 bitcoinVout, uint256 bitcoinAmount, string bitcoinAddress, address tokenAddress, address tokenOwner, bool isActive, uint256 createdAt)
+func (_UTXORegistry *UTXORegistrySession) Utxos(arg0 [32]byte) (struct {
+	BitcoinTxId    string
+	BitcoinVout    uint32
+	BitcoinAmount  *big.Int
+	BitcoinAddress string
+	TokenAddress   common.Address
+	TokenOwner     common.Address
+	IsActive       bool
+	CreatedAt      *big.Int
+}, error) {
+	return _UTXORegistry.Contract.Utxos(&_UTXORegistry.CallOpts, arg0)
+}
+
+// Utxos is a free data retrieval call binding the contract method 0x3cf18ab9.
+//
+// Solidity: function utxos(bytes32 ) view returns(string bitcoinTxId, uint32 bitcoinVout, uint256 bitcoinAmount, string bitcoinAddress, address tokenAddress, address tokenOwner, bool isActive, uint256 createdAt)
+func (_UTXORegistry *UTXORegistryCallerSession) Utxos(arg0 [32]byte) (struct {
+	BitcoinTxId    string
+	BitcoinVout    uint32
+	BitcoinAmount  *big.Int
+	BitcoinAddress string
+	TokenAddress   common.Address
+	TokenOwner     common.Address
+	IsActive       bool
+	CreatedAt      *big.Int
+}, error) {
+	return _UTXORegistry.Contract.Utxos(&_UTXORegistry.CallOpts, arg0)
+}
+
+// AddOperator is a paid mutator transaction binding the contract method 0x9870d7fe.
+//
+// Solidity: function addOperator(address _operator) returns()
+func (_UTXORegistry *UTXORegistryTransactor) AddOperator(opts *bind.TransactOpts, _operator common.Address) (*types.Transaction, error) {
+	return _UTXORegistry.contract.Transact(opts, "addOperator", _operator)
+}
+
+// AddOperator is a paid mutator transaction binding the contract method 0x9870d7fe.
+//
+// Solidity: function addOperator(address _operator) returns()
+func (_UTXORegistry *UTXORegistrySession) AddOperator(_operator common.Address) (*types.Transaction, error) {
+	return _UTXORegistry.Contract.AddOperator(&_UTXORegistry.TransactOpts, _operator)
+}
+
+// AddOperator is a paid mutator transaction binding the contract method 0x9870d7fe.
+//
+// Solidity: function addOperator(address _operator) returns()
+func (_UTXORegistry *UTXORegistryTransactorSession) AddOperator(_operator common.Address) (*types.Transaction, error) {
+	return _UTXORegistry.Contract.AddOperator(&_UTXORegistry.TransactOpts, _operator)
+}
+
+// MarkUTXORedeemed is a paid mutator transaction binding the contract method 0x57b9e65b.
+//
+// Solidity: function markUTXORedeemed(bytes32 _utxoId, address _redeemer, string _bitcoinDestination) returns()
+func (_UTXORegistry *UTXORegistryTransactor) MarkUTXORedeemed(opts *bind.TransactOpts, _utxoId [32]byte, _redeemer common.Address, _bitcoinDestination string) (*types.Transaction, error) {
+	return _UTXORegistry.contract.Transact(opts, "markUTXORedeemed", _utxoId, _redeemer, _bitcoinDestination)
+}
+
+// MarkUTXORedeemed is a paid mutator transaction binding the contract method 0x57b9e65b.
+//
+// Solidity: function markUTXORedeemed(bytes32 _utxoId, address _redeemer, string _bitcoinDestination) returns()
+func (_UTXORegistry *UTXORegistrySession) MarkUTXORedeemed(_utxoId [32]byte, _redeemer common.Address, _bitcoinDestination string) (*types.Transaction, error) {
+	return _UTXORegistry.Contract.MarkUTXORedeemed(&_UTXORegistry.TransactOpts, _utxoId, _redeemer, _bitcoinDestination)
+}
+
+// MarkUTXORedeemed is a paid mutator transaction binding the contract method 0x57b9e65b.
+//
+// Solidity: function markUTXORedeemed(bytes32 _utxoId, address _redeemer, string _bitcoinDestination) returns()
+func (_UTXORegistry *UTXORegistryTransactorSession) MarkUTXORedeemed(_utxoId [32]byte, _redeemer common.Address, _bitcoinDestination string) (*types.Transaction, error) {
+	return _UTXORegistry.Contract.MarkUTXORedeemed(&_UTXORegistry.TransactOpts, _utxoId, _redeemer, _bitcoinDestination)
+}
+
+// RegisterUTXO is a paid mutator transaction binding the contract method 0x7e85170e.
+//
+// Solidity: function registerUTXO(string _bitcoinTxId, uint32 _bitcoinVout, uint256 _bitcoinAmount, string _bitcoinAddress, address _tokenOwner) returns(address tokenAddress)
+func (_UTXORegistry *UTXORegistryTransactor) RegisterUTXO(opts *bind.TransactOpts, _bitcoinTxId string, _bitcoinVout uint32, _bitcoinAmount *big.Int, _bitcoinAddress string, _tokenOwner common.Address) (*types.Transaction, error) {
+	return _UTXORegistry.contract.Transact(opts, "registerUTXO", _bitcoinTxId, _bitcoinVout, _bitcoinAmount, _bitcoinAddress, _tokenOwner)
+}
+
+// RegisterUTXO is a paid mutator transaction binding the contract method 0x7e85170e.
+//
+// Solidity: function registerUTXO(string _bitcoinTxId, uint32 _bitcoinVout, uint256 _bitcoinAmount, string _bitcoinAddress, address _tokenOwner) returns(address tokenAddress)
+func (_UTXORegistry *UTXORegistrySession) RegisterUTXO(_bitcoinTxId string, _bitcoinVout uint32, _bitcoinAmount *big.Int, _bitcoinAddress string, _tokenOwner common.Address) (*types.Transaction, error) {
+	return _UTXORegistry.Contract.RegisterUTXO(&_UTXORegistry.TransactOpts, _bitcoinTxId, _bitcoinVout, _bitcoinAmount, _bitcoinAddress, _tokenOwner)
+}
+
+// RegisterUTXO is a paid mutator transaction binding the contract method 0x7e85170e.
+//
+// Solidity: function registerUTXO(string _bitcoinTxId, uint32 _bitcoinVout, uint256 _bitcoinAmount, string _bitcoinAddress, address _tokenOwner) returns(address tokenAddress)
+func (_UTXORegistry *UTXORegistryTransactorSession) RegisterUTXO(_bitcoinTxId string, _bitcoinVout uint32, _bitcoinAmount *big.Int, _bitcoinAddress string, _tokenOwner common.Address) (*types.Transaction, error) {
+	return _UTXORegistry.Contract.RegisterUTXO(&_UTXORegistry.TransactOpts, _bitcoinTxId, _bitcoinVout, _bitcoinAmount, _bitcoinAddress, _tokenOwner)
+}
+
+// RemoveOperator is a paid mutator transaction binding the contract method 0xac8a584a.
+//
+// Solidity: function removeOperator(address _operator) returns()
+func (_UTXORegistry *UTXORegistryTransactor) RemoveOperator(opts *bind.TransactOpts, _operator common.Address) (*types.Transaction, error) {
+	return _UTXORegistry.contract.Transact(opts, "removeOperator", _operator)
+}
+
+// RemoveOperator is a paid mutator transaction binding the contract method 0xac8a584a.
+//
+// Solidity: function removeOperator(address _operator) returns()
+func (_UTXORegistry *UTXORegistrySession) RemoveOperator(_operator common.Address) (*types.Transaction, error) {
+	return _UTXORegistry.Contract.RemoveOperator(&_UTXORegistry.TransactOpts, _operator)
+}
+
+// RemoveOperator is a paid mutator transaction binding the contract method 0xac8a584a.
+//
+// Solidity: function removeOperator(address _operator) returns()
+func (_UTXORegistry *UTXORegistryTransactorSession) RemoveOperator(_operator common.Address) (*types.Transaction, error) {
+	return _UTXORegistry.Contract.RemoveOperator(&_UTXORegistry.TransactOpts, _operator)
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_UTXORegistry *UTXORegistryTransactor) RenounceOwnership(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _UTXORegistry.contract.Transact(opts, "renounceOwnership")
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_UTXORegistry *UTXORegistrySession) RenounceOwnership() (*types.Transaction, error) {
+	return _UTXORegistry.Contract.RenounceOwnership(&_UTXORegistry.TransactOpts)
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_UTXORegistry *UTXORegistryTransactorSession) RenounceOwnership() (*types.Transaction, error) {
+	return _UTXORegistry.Contract.RenounceOwnership(&_UTXORegistry.TransactOpts)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_UTXORegistry *UTXORegistryTransactor) TransferOwnership(opts *bind.TransactOpts, newOwner common.Address) (*types.Transaction, error) {
+	return _UTXORegistry.contract.Transact(opts, "transferOwnership", newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_UTXORegistry *UTXORegistrySession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _UTXORegistry.Contract.TransferOwnership(&_UTXORegistry.TransactOpts, newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_UTXORegistry *UTXORegistryTransactorSession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _UTXORegistry.Contract.TransferOwnership(&_UTXORegistry.TransactOpts, newOwner)
+}
+
+// UTXORegistryOperatorAddedIterator is returned from FilterOperatorAdded and is used to iterate over the raw logs and unpacked data for OperatorAdded events raised by the UTXORegistry contract.
+type UTXORegistryOperatorAddedIterator struct {
+	Event *UTXORegistryOperatorAdded // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *UTXORegistryOperatorAddedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(UTXORegistryOperatorAdded)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(UTXORegistryOperatorAdded)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *UTXORegistryOperatorAddedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *UTXORegistryOperatorAddedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// UTXORegistryOperatorAdded represents a OperatorAdded event raised by the UTXORegistry contract.
+type UTXORegistryOperatorAdded struct {
+	Operator common.Address
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterOperatorAdded is a free log retrieval operation binding the contract event 0xac6fa858e9350a46cec16539926e0fde25b7629f84b5a72bffaae4df888ae86d.
+//
+// Solidity: event OperatorAdded(address operator)
+func (_UTXORegistry *UTXORegistryFilterer) FilterOperatorAdded(opts *bind.FilterOpts) (*UTXORegistryOperatorAddedIterator, error) {
+
+	logs, sub, err := _UTXORegistry.contract.FilterLogs(opts, "OperatorAdded")
+	if err != nil {
+		return nil, err
+	}
+	return &UTXORegistryOperatorAddedIterator{contract: _UTXORegistry.contract, event: "OperatorAdded", logs: logs, sub: sub}, nil
+}
+
+// WatchOperatorAdded is a free log subscription operation binding the contract event 0xac6fa858e9350a46cec16539926e0fde25b7629f84b5a72bffaae4df888ae86d.
+//
+// Solidity: event OperatorAdded(address operator)
+func (_UTXORegistry *UTXORegistryFilterer) WatchOperatorAdded(opts *bind.WatchOpts, sink chan<- *UTXORegistryOperatorAdded) (event.Subscription, error) {
+
+	logs, sub, err := _UTXORegistry.contract.WatchLogs(opts, "OperatorAdded")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(UTXORegistryOperatorAdded)
+				if err := _UTXORegistry.contract.UnpackLog(event, "OperatorAdded", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOperatorAdded is a log parse operation binding the contract event 0xac6fa858e9350a46cec16539926e0fde25b7629f84b5a72bffaae4df888ae86d.
+//
+// Solidity: event OperatorAdded(address operator)
+func (_UTXORegistry *UTXORegistryFilterer) ParseOperatorAdded(log types.Log) (*UTXORegistryOperatorAdded, error) {
+	event := new(UTXORegistryOperatorAdded)
+	if err := _UTXORegistry.contract.UnpackLog(event, "OperatorAdded", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// UTXORegistryOperatorRemovedIterator is returned from FilterOperatorRemoved and is used to iterate over the raw logs and unpacked data for OperatorRemoved events raised by the UTXORegistry contract.
+type UTXORegistryOperatorRemovedIterator struct {
+	Event *UTXORegistryOperatorRemoved // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *UTXORegistryOperatorRemovedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(UTXORegistryOperatorRemoved)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(UTXORegistryOperatorRemoved)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *UTXORegistryOperatorRemovedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *UTXORegistryOperatorRemovedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// UTXORegistryOperatorRemoved represents a OperatorRemoved event raised by the UTXORegistry contract.
+type UTXORegistryOperatorRemoved struct {
+	Operator common.Address
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterOperatorRemoved is a free log retrieval operation binding the contract event 0x80c0b871b97b595b16a7741c1b06fed0c6f6f558639f18ccbce50724325dc40d.
+//
+// Solidity: event OperatorRemoved(address operator)
+func (_UTXORegistry *UTXORegistryFilterer) FilterOperatorRemoved(opts *bind.FilterOpts) (*UTXORegistryOperatorRemovedIterator, error) {
+
+	logs, sub, err := _UTXORegistry.contract.FilterLogs(opts, "OperatorRemoved")
+	if err != nil {
+		return nil, err
+	}
+	return &UTXORegistryOperatorRemovedIterator{contract: _UTXORegistry.contract, event: "OperatorRemoved", logs: logs, sub: sub}, nil
+}
+
+// WatchOperatorRemoved is a free log subscription operation binding the contract event 0x80c0b871b97b595b16a7741c1b06fed0c6f6f558639f18ccbce50724325dc40d.
+//
+// Solidity: event OperatorRemoved(address operator)
+func (_UTXORegistry *UTXORegistryFilterer) WatchOperatorRemoved(opts *bind.WatchOpts, sink chan<- *UTXORegistryOperatorRemoved) (event.Subscription, error) {
+
+	logs, sub, err := _UTXORegistry.contract.WatchLogs(opts, "OperatorRemoved")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(UTXORegistryOperatorRemoved)
+				if err := _UTXORegistry.contract.UnpackLog(event, "OperatorRemoved", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOperatorRemoved is a log parse operation binding the contract event 0x80c0b871b97b595b16a7741c1b06fed0c6f6f558639f18ccbce50724325dc40d.
+//
+// Solidity: event OperatorRemoved(address operator)
+func (_UTXORegistry *UTXORegistryFilterer) ParseOperatorRemoved(log types.Log) (*UTXORegistryOperatorRemoved, error) {
+	event := new(UTXORegistryOperatorRemoved)
+	if err := _UTXORegistry.contract.UnpackLog(event, "OperatorRemoved", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// UTXORegistryOwnershipTransferredIterator is returned from FilterOwnershipTransferred and is used to iterate over the raw logs and unpacked data for OwnershipTransferred events raised by the UTXORegistry contract.
+type UTXORegistryOwnershipTransferredIterator struct {
+	Event *UTXORegistryOwnershipTransferred // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *UTXORegistryOwnershipTransferredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(UTXORegistryOwnershipTransferred)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(UTXORegistryOwnershipTransferred)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *UTXORegistryOwnershipTransferredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *UTXORegistryOwnershipTransferredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// UTXORegistryOwnershipTransferred represents a OwnershipTransferred event raised by the UTXORegistry contract.
+type UTXORegistryOwnershipTransferred struct {
+	PreviousOwner common.Address
+	NewOwner      common.Address
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterOwnershipTransferred is a free log retrieval operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_UTXORegistry *UTXORegistryFilterer) FilterOwnershipTransferred(opts *bind.FilterOpts, previousOwner []common.Address, newOwner []common.Address) (*UTXORegistryOwnershipTransferredIterator, error) {
+
+	var previousOwnerRule []interface{}
+	for _, previousOwnerItem := range previousOwner {
+		previousOwnerRule = append(previousOwnerRule, previousOwnerItem)
+	}
+	var newOwnerRule []interface{}
+	for _, newOwnerItem := range newOwner {
+		newOwnerRule = append(newOwnerRule, newOwnerItem)
+	}
+
+	logs, sub, err := _UTXORegistry.contract.FilterLogs(opts, "OwnershipTransferred", previousOwnerRule, newOwnerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &UTXORegistryOwnershipTransferredIterator{contract: _UTXORegistry.contract, event: "OwnershipTransferred", logs: logs, sub: sub}, nil
+}
+
+// WatchOwnershipTransferred is a free log subscription operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_UTXORegistry *UTXORegistryFilterer) WatchOwnershipTransferred(opts *bind.WatchOpts, sink chan<- *UTXORegistryOwnershipTransferred, previousOwner []common.Address, newOwner []common.Address) (event.Subscription, error) {
+
+	var previousOwnerRule []interface{}
+	for _, previousOwnerItem := range previousOwner {
+		previousOwnerRule = append(previousOwnerRule, previousOwnerItem)
+	}
+	var newOwnerRule []interface{}
+	for _, newOwnerItem := range newOwner {
+		newOwnerRule = append(newOwnerRule, newOwnerItem)
+	}
+
+	logs, sub, err := _UTXORegistry.contract.WatchLogs(opts, "OwnershipTransferred", previousOwnerRule, newOwnerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(UTXORegistryOwnershipTransferred)
+				if err := _UTXORegistry.contract.UnpackLog(event, "OwnershipTransferred", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOwnershipTransferred is a log parse operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_UTXORegistry *UTXORegistryFilterer) ParseOwnershipTransferred(log types.Log) (*UTXORegistryOwnershipTransferred, error) {
+	event := new(UTXORegistryOwnershipTransferred)
+	if err := _UTXORegistry.contract.UnpackLog(event, "OwnershipTransferred", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// UTXORegistryUTXORedeemedIterator is returned from FilterUTXORedeemed and is used to iterate over the raw logs and unpacked data for UTXORedeemed events raised by the UTXORegistry contract.
+type UTXORegistryUTXORedeemedIterator struct {
+	Event *UTXORegistryUTXORedeemed // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *UTXORegistryUTXORedeemedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(UTXORegistryUTXORedeemed)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(UTXORegistryUTXORedeemed)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *UTXORegistryUTXORedeemedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *UTXORegistryUTXORedeemedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// UTXORegistryUTXORedeemed represents a UTXORedeemed event raised by the UTXORegistry contract.
+type UTXORegistryUTXORedeemed struct {
+	UtxoId             [32]byte
+	Redeemer           common.Address
+	BitcoinDestination string
+	Raw                types.Log // Blockchain specific contextual infos
+}
+
+// FilterUTXORedeemed is a free log retrieval operation binding the contract event 0xb059164d75322c1e99f5093735531b75793463eeca940fe148bc24d68d806c9e.
+//
+// Solidity: event UTXORedeemed(bytes32 indexed utxoId, address redeemer, string bitcoinDestination)
+func (_UTXORegistry *UTXORegistryFilterer) FilterUTXORedeemed(opts *bind.FilterOpts, utxoId [][32]byte) (*UTXORegistryUTXORedeemedIterator, error) {
+
+	var utxoIdRule []interface{}
+	for _, utxoIdItem := range utxoId {
+		utxoIdRule = append(utxoIdRule, utxoIdItem)
+	}
+
+	logs, sub, err := _UTXORegistry.contract.FilterLogs(opts, "UTXORedeemed", utxoIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &UTXORegistryUTXORedeemedIterator{contract: _UTXORegistry.contract, event: "UTXORedeemed", logs: logs, sub: sub}, nil
+}
+
+// WatchUTXORedeemed is a free log subscription operation binding the contract event 0xb059164d75322c1e99f5093735531b75793463eeca940fe148bc24d68d806c9e.
+//
+// Solidity: event UTXORedeemed(bytes32 indexed utxoId, address redeemer, string bitcoinDestination)
+func (_UTXORegistry *UTXORegistryFilterer) WatchUTXORedeemed(opts *bind.WatchOpts, sink chan<- *UTXORegistryUTXORedeemed, utxoId [][32]byte) (event.Subscription, error) {
+
+	var utxoIdRule []interface{}
+	for _, utxoIdItem := range utxoId {
+		utxoIdRule = append(utxoIdRule, utxoIdItem)
+	}
+
+	logs, sub, err := _UTXORegistry.contract.WatchLogs(opts, "UTXORedeemed", utxoIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(UTXORegistryUTXORedeemed)
+				if err := _UTXORegistry.contract.UnpackLog(event, "UTXORedeemed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseUTXORedeemed is a log parse operation binding the contract event 0xb059164d75322c1e99f5093735531b75793463eeca940fe148bc24d68d806c9e.
+//
+// Solidity: event UTXORedeemed(bytes32 indexed utxoId, address redeemer, string bitcoinDestination)
+func (_UTXORegistry *UTXORegistryFilterer) ParseUTXORedeemed(log types.Log) (*UTXORegistryUTXORedeemed, error) {
+	event := new(UTXORegistryUTXORedeemed)
+	if err := _UTXORegistry.contract.UnpackLog(event, "UTXORedeemed", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// UTXORegistryUTXORegisteredIterator is returned from FilterUTXORegistered and is used to iterate over the raw logs and unpacked data for UTXORegistered events raised by the UTXORegistry contract.
+type UTXORegistryUTXORegisteredIterator struct {
+	Event *UTXORegistryUTXORegistered // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *UTXORegistryUTXORegisteredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(UTXORegistryUTXORegistered)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(UTXORegistryUTXORegistered)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *UTXORegistryUTXORegisteredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *UTXORegistryUTXORegisteredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// UTXORegistryUTXORegistered represents a UTXORegistered event raised by the UTXORegistry contract.
+type UTXORegistryUTXORegistered struct {
+	UtxoId        [32]byte
+	BitcoinTxId   string
+	BitcoinVout   uint32
+	BitcoinAmount *big.Int
+	TokenAddress  common.Address
+	TokenOwner    common.Address
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterUTXORegistered is a free log retrieval operation binding the contract event 0x3527513cb93f232a26bc65d7e9ac6a347d404367162d7cca9efc3bb2820c5ff6.
+//
+// Solidity: event UTXORegistered(bytes32 indexed utxoId, string bitcoinTxId, uint32 bitcoinVout, uint256 bitcoinAmount, address tokenAddress, address tokenOwner)
+func (_UTXORegistry *UTXORegistryFilterer) FilterUTXORegistered(opts *bind.FilterOpts, utxoId [][32]byte) (*UTXORegistryUTXORegisteredIterator, error) {
+
+	var utxoIdRule []interface{}
+	for _, utxoIdItem := range utxoId {
+		utxoIdRule = append(utxoIdRule, utxoIdItem)
+	}
+
+	logs, sub, err := _UTXORegistry.contract.FilterLogs(opts, "UTXORegistered", utxoIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &UTXORegistryUTXORegisteredIterator{contract: _UTXORegistry.contract, event: "UTXORegistered", logs: logs, sub: sub}, nil
+}
+
+// WatchUTXORegistered is a free log subscription operation binding the contract event 0x3527513cb93f232a26bc65d7e9ac6a347d404367162d7cca9efc3bb2820c5ff6.
+//
+// Solidity: event UTXORegistered(bytes32 indexed utxoId, string bitcoinTxId, uint32 bitcoinVout, uint256 bitcoinAmount, address tokenAddress, address tokenOwner)
+func (_UTXORegistry *UTXORegistryFilterer) WatchUTXORegistered(opts *bind.WatchOpts, sink chan<- *UTXORegistryUTXORegistered, utxoId [][32]byte) (event.Subscription, error) {
+
+	var utxoIdRule []interface{}
+	for _, utxoIdItem := range utxoId {
+		utxoIdRule = append(utxoIdRule, utxoIdItem)
+	}
+
+	logs, sub, err := _UTXORegistry.contract.WatchLogs(opts, "UTXORegistered", utxoIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(UTXORegistryUTXORegistered)
+				if err := _UTXORegistry.contract.UnpackLog(event, "UTXORegistered", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseUTXORegistered is a log parse operation binding the contract event 0x3527513cb93f232a26bc65d7e9ac6a347d404367162d7cca9efc3bb2820c5ff6.
+//
+// Solidity: event UTXORegistered(bytes32 indexed utxoId, string bitcoinTxId, uint32 bitcoinVout, uint256 bitcoinAmount, address tokenAddress, address tokenOwner)
+func (_UTXORegistry *UTXORegistryFilterer) ParseUTXORegistered(log types.Log) (*UTXORegistryUTXORegistered, error) {
+	event := new(UTXORegistryUTXORegistered)
+	if err := _UTXORegistry.contract.UnpackLog(event, "UTXORegistered", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
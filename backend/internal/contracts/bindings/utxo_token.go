@@ -0,0 +1,1432 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package bindings
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// UTXOTokenMetaData contains all meta data concerning the UTXOToken contract.
+var UTXOTokenMetaData = &bind.MetaData{
+	ABI: string(mustArtifact("UTXOToken").ABI),
+	Bin: mustArtifact("UTXOToken").Bytecode,
+}
+
+// UTXOTokenABI is the input ABI used to generate the binding from.
+// Deprecated: Use UTXOTokenMetaData.ABI instead.
+var UTXOTokenABI = UTXOTokenMetaData.ABI
+
+// UTXOTokenBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use UTXOTokenMetaData.Bin instead.
+var UTXOTokenBin = UTXOTokenMetaData.Bin
+
+// DeployUTXOToken deploys a new Ethereum contract, binding an instance of UTXOToken to it.
+func DeployUTXOToken(auth *bind.TransactOpts, backend bind.ContractBackend, _name string, _symbol string, _bitcoinTxId string, _bitcoinVout uint32, _bitcoinAmount *big.Int, _bitcoinAddress string, _initialOwner common.Address, _registry common.Address) (common.Address, *types.Transaction, *UTXOToken, error) {
+	parsed, err := UTXOTokenMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(UTXOTokenBin), backend, _name, _symbol, _bitcoinTxId, _bitcoinVout, _bitcoinAmount, _bitcoinAddress, _initialOwner, _registry)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &UTXOToken{UTXOTokenCaller: UTXOTokenCaller{contract: contract}, UTXOTokenTransactor: UTXOTokenTransactor{contract: contract}, UTXOTokenFilterer: UTXOTokenFilterer{contract: contract}}, nil
+}
+
+// UTXOToken is an auto generated Go binding around an Ethereum contract.
+type UTXOToken struct {
+	UTXOTokenCaller     // Read-only binding to the contract
+	UTXOTokenTransactor // Write-only binding to the contract
+	UTXOTokenFilterer   // Log filterer for contract events
+}
+
+// UTXOTokenCaller is an auto generated read-only Go binding around an Ethereum contract.
+type UTXOTokenCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// UTXOTokenTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type UTXOTokenTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// UTXOTokenFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type UTXOTokenFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// UTXOTokenSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type UTXOTokenSession struct {
+	Contract     *UTXOToken        // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// UTXOTokenCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type UTXOTokenCallerSession struct {
+	Contract *UTXOTokenCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts    // Call options to use throughout this session
+}
+
+// UTXOTokenTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type UTXOTokenTransactorSession struct {
+	Contract     *UTXOTokenTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts    // Transaction auth options to use throughout this session
+}
+
+// UTXOTokenRaw is an auto generated low-level Go binding around an Ethereum contract.
+type UTXOTokenRaw struct {
+	Contract *UTXOToken // Generic contract binding to access the raw methods on
+}
+
+// UTXOTokenCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type UTXOTokenCallerRaw struct {
+	Contract *UTXOTokenCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// UTXOTokenTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type UTXOTokenTransactorRaw struct {
+	Contract *UTXOTokenTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewUTXOToken creates a new instance of UTXOToken, bound to a specific deployed contract.
+func NewUTXOToken(address common.Address, backend bind.ContractBackend) (*UTXOToken, error) {
+	contract, err := bindUTXOToken(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &UTXOToken{UTXOTokenCaller: UTXOTokenCaller{contract: contract}, UTXOTokenTransactor: UTXOTokenTransactor{contract: contract}, UTXOTokenFilterer: UTXOTokenFilterer{contract: contract}}, nil
+}
+
+// NewUTXOTokenCaller creates a new read-only instance of UTXOToken, bound to a specific deployed contract.
+func NewUTXOTokenCaller(address common.Address, caller bind.ContractCaller) (*UTXOTokenCaller, error) {
+	contract, err := bindUTXOToken(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &UTXOTokenCaller{contract: contract}, nil
+}
+
+// NewUTXOTokenTransactor creates a new write-only instance of UTXOToken, bound to a specific deployed contract.
+func NewUTXOTokenTransactor(address common.Address, transactor bind.ContractTransactor) (*UTXOTokenTransactor, error) {
+	contract, err := bindUTXOToken(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &UTXOTokenTransactor{contract: contract}, nil
+}
+
+// NewUTXOTokenFilterer creates a new log filterer instance of UTXOToken, bound to a specific deployed contract.
+func NewUTXOTokenFilterer(address common.Address, filterer bind.ContractFilterer) (*UTXOTokenFilterer, error) {
+	contract, err := bindUTXOToken(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &UTXOTokenFilterer{contract: contract}, nil
+}
+
+// bindUTXOToken binds a generic wrapper to an already deployed contract.
+func bindUTXOToken(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := UTXOTokenMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_UTXOToken *UTXOTokenRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _UTXOToken.Contract.UTXOTokenCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_UTXOToken *UTXOTokenRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _UTXOToken.Contract.UTXOTokenTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_UTXOToken *UTXOTokenRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _UTXOToken.Contract.UTXOTokenTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_UTXOToken *UTXOTokenCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _UTXOToken.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_UTXOToken *UTXOTokenTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _UTXOToken.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_UTXOToken *UTXOTokenTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _UTXOToken.Contract.contract.Transact(opts, method, params...)
+}
+
+// Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
+//
+// Solidity: function allowance(address owner, address spender) view returns(uint256)
+func (_UTXOToken *UTXOTokenCaller) Allowance(opts *bind.CallOpts, owner common.Address, spender common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _UTXOToken.contract.Call(opts, &out, "allowance", owner, spender)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
+//
+// Solidity: function allowance(address owner, address spender) view returns(uint256)
+func (_UTXOToken *UTXOTokenSession) Allowance(owner common.Address, spender common.Address) (*big.Int, error) {
+	return _UTXOToken.Contract.Allowance(&_UTXOToken.CallOpts, owner, spender)
+}
+
+// Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
+//
+// Solidity: function allowance(address owner, address spender) view returns(uint256)
+func (_UTXOToken *UTXOTokenCallerSession) Allowance(owner common.Address, spender common.Address) (*big.Int, error) {
+	return _UTXOToken.Contract.Allowance(&_UTXOToken.CallOpts, owner, spender)
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf(address account) view returns(uint256)
+func (_UTXOToken *UTXOTokenCaller) BalanceOf(opts *bind.CallOpts, account common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _UTXOToken.contract.Call(opts, &out, "balanceOf", account)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf(address account) view returns(uint256)
+func (_UTXOToken *UTXOTokenSession) BalanceOf(account common.Address) (*big.Int, error) {
+	return _UTXOToken.Contract.BalanceOf(&_UTXOToken.CallOpts, account)
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf(address account) view returns(uint256)
+func (_UTXOToken *UTXOTokenCallerSession) BalanceOf(account common.Address) (*big.Int, error) {
+	return _UTXOToken.Contract.BalanceOf(&_UTXOToken.CallOpts, account)
+}
+
+// BitcoinAddress is a free data retrieval call binding the contract method 0x7651bc92.
+//
+// Solidity: function bitcoinAddress() view returns(string)
+func (_UTXOToken *UTXOTokenCaller) BitcoinAddress(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _UTXOToken.contract.Call(opts, &out, "bitcoinAddress")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// BitcoinAddress is a free data retrieval call binding the contract method 0x7651bc92.
+//
+// Solidity: function bitcoinAddress() view returns(string)
+func (_UTXOToken *UTXOTokenSession) BitcoinAddress() (string, error) {
+	return _UTXOToken.Contract.BitcoinAddress(&_UTXOToken.CallOpts)
+}
+
+// BitcoinAddress is a free data retrieval call binding the contract method 0x7651bc92.
+//
+// Solidity: function bitcoinAddress() view returns(string)
+func (_UTXOToken *UTXOTokenCallerSession) BitcoinAddress() (string, error) {
+	return _UTXOToken.Contract.BitcoinAddress(&_UTXOToken.CallOpts)
+}
+
+// BitcoinAmount is a free data retrieval call binding the contract method 0x9c31c295.
+//
+// Solidity: function bitcoinAmount() view returns(uint256)
+func (_UTXOToken *UTXOTokenCaller) BitcoinAmount(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _UTXOToken.contract.Call(opts, &out, "bitcoinAmount")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// BitcoinAmount is a free data retrieval call binding the contract method 0x9c31c295.
+//
+// Solidity: function bitcoinAmount() view returns(uint256)
+func (_UTXOToken *UTXOTokenSession) BitcoinAmount() (*big.Int, error) {
+	return _UTXOToken.Contract.BitcoinAmount(&_UTXOToken.CallOpts)
+}
+
+// BitcoinAmount is a free data retrieval call binding the contract method 0x9c31c295.
+//
+// Solidity: function bitcoinAmount() view returns(uint256)
+func (_UTXOToken *UTXOTokenCallerSession) BitcoinAmount() (*big.Int, error) {
+	return _UTXOToken.Contract.BitcoinAmount(&_UTXOToken.CallOpts)
+}
+
+// BitcoinTxId is a free data retrieval call binding the contract method 0x51a02dc6.
+//
+// Solidity: function bitcoinTxId() view returns(string)
+func (_UTXOToken *UTXOTokenCaller) BitcoinTxId(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _UTXOToken.contract.Call(opts, &out, "bitcoinTxId")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// BitcoinTxId is a free data retrieval call binding the contract method 0x51a02dc6.
+//
+// Solidity: function bitcoinTxId() view returns(string)
+func (_UTXOToken *UTXOTokenSession) BitcoinTxId() (string, error) {
+	return _UTXOToken.Contract.BitcoinTxId(&_UTXOToken.CallOpts)
+}
+
+// BitcoinTxId is a free data retrieval call binding the contract method 0x51a02dc6.
+//
+// Solidity: function bitcoinTxId() view returns(string)
+func (_UTXOToken *UTXOTokenCallerSession) BitcoinTxId() (string, error) {
+	return _UTXOToken.Contract.BitcoinTxId(&_UTXOToken.CallOpts)
+}
+
+// BitcoinVout is a free data retrieval call binding the contract method 0x4d612ec5.
+//
+// Solidity: function bitcoinVout() view returns(uint32)
+func (_UTXOToken *UTXOTokenCaller) BitcoinVout(opts *bind.CallOpts) (uint32, error) {
+	var out []interface{}
+	err := _UTXOToken.contract.Call(opts, &out, "bitcoinVout")
+
+	if err != nil {
+		return *new(uint32), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(uint32)).(*uint32)
+
+	return out0, err
+
+}
+
+// BitcoinVout is a free data retrieval call binding the contract method 0x4d612ec5.
+//
+// Solidity: function bitcoinVout() view returns(uint32)
+func (_UTXOToken *UTXOTokenSession) BitcoinVout() (uint32, error) {
+	return _UTXOToken.Contract.BitcoinVout(&_UTXOToken.CallOpts)
+}
+
+// BitcoinVout is a free data retrieval call binding the contract method 0x4d612ec5.
+//
+// Solidity: function bitcoinVout() view returns(uint32)
+func (_UTXOToken *UTXOTokenCallerSession) BitcoinVout() (uint32, error) {
+	return _UTXOToken.Contract.BitcoinVout(&_UTXOToken.CallOpts)
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() view returns(uint8)
+func (_UTXOToken *UTXOTokenCaller) Decimals(opts *bind.CallOpts) (uint8, error) {
+	var out []interface{}
+	err := _UTXOToken.contract.Call(opts, &out, "decimals")
+
+	if err != nil {
+		return *new(uint8), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(uint8)).(*uint8)
+
+	return out0, err
+
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() view returns(uint8)
+func (_UTXOToken *UTXOTokenSession) Decimals() (uint8, error) {
+	return _UTXOToken.Contract.Decimals(&_UTXOToken.CallOpts)
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() view returns(uint8)
+func (_UTXOToken *UTXOTokenCallerSession) Decimals() (uint8, error) {
+	return _UTXOToken.Contract.Decimals(&_UTXOToken.CallOpts)
+}
+
+// GetUTXOInfo is a free data retrieval call binding the contract method 0x5d7b4074.
+//
+// Solidity: function getUTXOInfo() view returns(string txId, uint32 vout, uint256 amount, string btcAddress, bool redeemed)
+func (_UTXOToken *UTXOTokenCaller) GetUTXOInfo(opts *bind.CallOpts) (struct {
+	TxId       string
+	Vout       uint32
+	Amount     *big.Int
+	BtcAddress string
+	Redeemed   bool
+}, error) {
+	var out []interface{}
+	err := _UTXOToken.contract.Call(opts, &out, "getUTXOInfo")
+
+	outstruct := new(struct {
+		TxId       string
+		Vout       uint32
+		Amount     *big.Int
+		BtcAddress string
+		Redeemed   bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.TxId = *abi.ConvertType(out[0], new(string)).(*string)
+	outstruct.Vout = *abi.ConvertType(out[1], new(uint32)).(*uint32)
+	outstruct.Amount = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.BtcAddress = *abi.ConvertType(out[3], new(string)).(*string)
+	outstruct.Redeemed = *abi.ConvertType(out[4], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// GetUTXOInfo is a free data retrieval call binding the contract method 0x5d7b4074.
+//
+// Solidity: function getUTXOInfo() view returns(string txId, uint32 vout, uint256 amount, string btcAddress, bool redeemed)
+func (_UTXOToken *UTXOTokenSession) GetUTXOInfo() (struct {
+	TxId       string
+	Vout       uint32
+	Amount     *big.Int
+	BtcAddress string
+	Redeemed   bool
+}, error) {
+	return _UTXOToken.Contract.GetUTXOInfo(&_UTXOToken.CallOpts)
+}
+
+// GetUTXOInfo is a free data retrieval call binding the contract method 0x5d7b4074.
+//
+// Solidity: function getUTXOInfo() view returns(string txId, uint32 vout, uint256 amount, string btcAddress, bool redeemed)
+func (_UTXOToken *UTXOTokenCallerSession) GetUTXOInfo() (struct {
+	TxId       string
+	Vout       uint32
+	Amount     *big.Int
+	BtcAddress string
+	Redeemed   bool
+}, error) {
+	return _UTXOToken.Contract.GetUTXOInfo(&_UTXOToken.CallOpts)
+}
+
+// IsRedeemed is a free data retrieval call binding the contract method 0x034f6b21.
+//
+// Solidity: function isRedeemed() view returns(bool)
+func (_UTXOToken *UTXOTokenCaller) IsRedeemed(opts *bind.CallOpts) (bool, error) {
+	var out []interface{}
+	err := _UTXOToken.contract.Call(opts, &out, "isRedeemed")
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// IsRedeemed is a free data retrieval call binding the contract method 0x034f6b21.
+//
+// Solidity: function isRedeemed() view returns(bool)
+func (_UTXOToken *UTXOTokenSession) IsRedeemed() (bool, error) {
+	return _UTXOToken.Contract.IsRedeemed(&_UTXOToken.CallOpts)
+}
+
+// IsRedeemed is a free data retrieval call binding the contract method 0x034f6b21.
+//
+// Solidity: function isRedeemed() view returns(bool)
+func (_UTXOToken *UTXOTokenCallerSession) IsRedeemed() (bool, error) {
+	return _UTXOToken.Contract.IsRedeemed(&_UTXOToken.CallOpts)
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() view returns(string)
+func (_UTXOToken *UTXOTokenCaller) Name(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _UTXOToken.contract.Call(opts, &out, "name")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() view returns(string)
+func (_UTXOToken *UTXOTokenSession) Name() (string, error) {
+	return _UTXOToken.Contract.Name(&_UTXOToken.CallOpts)
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() view returns(string)
+func (_UTXOToken *UTXOTokenCallerSession) Name() (string, error) {
+	return _UTXOToken.Contract.Name(&_UTXOToken.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_UTXOToken *UTXOTokenCaller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _UTXOToken.contract.Call(opts, &out, "owner")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_UTXOToken *UTXOTokenSession) Owner() (common.Address, error) {
+	return _UTXOToken.Contract.Owner(&_UTXOToken.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_UTXOToken *UTXOTokenCallerSession) Owner() (common.Address, error) {
+	return _UTXOToken.Contract.Owner(&_UTXOToken.CallOpts)
+}
+
+// Registry is a free data retrieval call binding the contract method 0x7b103999.
+//
+// Solidity: function registry() view returns(address)
+func (_UTXOToken *UTXOTokenCaller) Registry(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _UTXOToken.contract.Call(opts, &out, "registry")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Registry is a free data retrieval call binding the contract method 0x7b103999.
+//
+// Solidity: function registry() view returns(address)
+func (_UTXOToken *UTXOTokenSession) Registry() (common.Address, error) {
+	return _UTXOToken.Contract.Registry(&_UTXOToken.CallOpts)
+}
+
+// Registry is a free data retrieval call binding the contract method 0x7b103999.
+//
+// Solidity: function registry() view returns(address)
+func (_UTXOToken *UTXOTokenCallerSession) Registry() (common.Address, error) {
+	return _UTXOToken.Contract.Registry(&_UTXOToken.CallOpts)
+}
+
+// Symbol is a free data retrieval call binding the contract method 0x95d89b41.
+//
+// Solidity: function symbol() view returns(string)
+func (_UTXOToken *UTXOTokenCaller) Symbol(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _UTXOToken.contract.Call(opts, &out, "symbol")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// Symbol is a free data retrieval call binding the contract method 0x95d89b41.
+//
+// Solidity: function symbol() view returns(string)
+func (_UTXOToken *UTXOTokenSession) Symbol() (string, error) {
+	return _UTXOToken.Contract.Symbol(&_UTXOToken.CallOpts)
+}
+
+// Symbol is a free data retrieval call binding the contract method 0x95d89b41.
+//
+// Solidity: function symbol() view returns(string)
+func (_UTXOToken *UTXOTokenCallerSession) Symbol() (string, error) {
+	return _UTXOToken.Contract.Symbol(&_UTXOToken.CallOpts)
+}
+
+// TotalSupply is a free data retrieval call binding the contract method 0x18160ddd.
+//
+// Solidity: function totalSupply() view returns(uint256)
+func (_UTXOToken *UTXOTokenCaller) TotalSupply(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _UTXOToken.contract.Call(opts, &out, "totalSupply")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// TotalSupply is a free data retrieval call binding the contract method 0x18160ddd.
+//
+// Solidity: function totalSupply() view returns(uint256)
+func (_UTXOToken *UTXOTokenSession) TotalSupply() (*big.Int, error) {
+	return _UTXOToken.Contract.TotalSupply(&_UTXOToken.CallOpts)
+}
+
+// TotalSupply is a free data retrieval call binding the contract method 0x18160ddd.
+//
+// Solidity: function totalSupply() view returns(uint256)
+func (_UTXOToken *UTXOTokenCallerSession) TotalSupply() (*big.Int, error) {
+	return _UTXOToken.Contract.TotalSupply(&_UTXOToken.CallOpts)
+}
+
+// Approve is a paid mutator transaction binding the contract method 0x095ea7b3.
+//
+// Solidity: function approve(address spender, uint256 value) returns(bool)
+func (_UTXOToken *UTXOTokenTransactor) Approve(opts *bind.TransactOpts, spender common.Address, value *big.Int) (*types.Transaction, error) {
+	return _UTXOToken.contract.Transact(opts, "approve", spender, value)
+}
+
+// Approve is a paid mutator transaction binding the contract method 0x095ea7b3.
+//
+// Solidity: function approve(address spender, uint256 value) returns(bool)
+func (_UTXOToken *UTXOTokenSession) Approve(spender common.Address, value *big.Int) (*types.Transaction, error) {
+	return _UTXOToken.Contract.Approve(&_UTXOToken.TransactOpts, spender, value)
+}
+
+// Approve is a paid mutator transaction binding the contract method 0x095ea7b3.
+//
+// Solidity: function approve(address spender, uint256 value) returns(bool)
+func (_UTXOToken *UTXOTokenTransactorSession) Approve(spender common.Address, value *big.Int) (*types.Transaction, error) {
+	return _UTXOToken.Contract.Approve(&_UTXOToken.TransactOpts, spender, value)
+}
+
+// Burn is a paid mutator transaction binding the contract method 0x42966c68.
+//
+// Solidity: function burn(uint256 value) returns()
+func (_UTXOToken *UTXOTokenTransactor) Burn(opts *bind.TransactOpts, value *big.Int) (*types.Transaction, error) {
+	return _UTXOToken.contract.Transact(opts, "burn", value)
+}
+
+// Burn is a paid mutator transaction binding the contract method 0x42966c68.
+//
+// Solidity: function burn(uint256 value) returns()
+func (_UTXOToken *UTXOTokenSession) Burn(value *big.Int) (*types.Transaction, error) {
+	return _UTXOToken.Contract.Burn(&_UTXOToken.TransactOpts, value)
+}
+
+// Burn is a paid mutator transaction binding the contract method 0x42966c68.
+//
+// Solidity: function burn(uint256 value) returns()
+func (_UTXOToken *UTXOTokenTransactorSession) Burn(value *big.Int) (*types.Transaction, error) {
+	return _UTXOToken.Contract.Burn(&_UTXOToken.TransactOpts, value)
+}
+
+// BurnFrom is a paid mutator transaction binding the contract method 0x79cc6790.
+//
+// Solidity: function burnFrom(address account, uint256 value) returns()
+func (_UTXOToken *UTXOTokenTransactor) BurnFrom(opts *bind.TransactOpts, account common.Address, value *big.Int) (*types.Transaction, error) {
+	return _UTXOToken.contract.Transact(opts, "burnFrom", account, value)
+}
+
+// BurnFrom is a paid mutator transaction binding the contract method 0x79cc6790.
+//
+// Solidity: function burnFrom(address account, uint256 value) returns()
+func (_UTXOToken *UTXOTokenSession) BurnFrom(account common.Address, value *big.Int) (*types.Transaction, error) {
+	return _UTXOToken.Contract.BurnFrom(&_UTXOToken.TransactOpts, account, value)
+}
+
+// BurnFrom is a paid mutator transaction binding the contract method 0x79cc6790.
+//
+// Solidity: function burnFrom(address account, uint256 value) returns()
+func (_UTXOToken *UTXOTokenTransactorSession) BurnFrom(account common.Address, value *big.Int) (*types.Transaction, error) {
+	return _UTXOToken.Contract.BurnFrom(&_UTXOToken.TransactOpts, account, value)
+}
+
+// RedeemForBitcoin is a paid mutator transaction binding the contract method 0x42f2a539.
+//
+// Solidity: function redeemForBitcoin(string _bitcoinDestination) returns()
+func (_UTXOToken *UTXOTokenTransactor) RedeemForBitcoin(opts *bind.TransactOpts, _bitcoinDestination string) (*types.Transaction, error) {
+	return _UTXOToken.contract.Transact(opts, "redeemForBitcoin", _bitcoinDestination)
+}
+
+// RedeemForBitcoin is a paid mutator transaction binding the contract method 0x42f2a539.
+//
+// Solidity: function redeemForBitcoin(string _bitcoinDestination) returns()
+func (_UTXOToken *UTXOTokenSession) RedeemForBitcoin(_bitcoinDestination string) (*types.Transaction, error) {
+	return _UTXOToken.Contract.RedeemForBitcoin(&_UTXOToken.TransactOpts, _bitcoinDestination)
+}
+
+// RedeemForBitcoin is a paid mutator transaction binding the contract method 0x42f2a539.
+//
+// Solidity: function redeemForBitcoin(string _bitcoinDestination) returns()
+func (_UTXOToken *UTXOTokenTransactorSession) RedeemForBitcoin(_bitcoinDestination string) (*types.Transaction, error) {
+	return _UTXOToken.Contract.RedeemForBitcoin(&_UTXOToken.TransactOpts, _bitcoinDestination)
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_UTXOToken *UTXOTokenTransactor) RenounceOwnership(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _UTXOToken.contract.Transact(opts, "renounceOwnership")
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_UTXOToken *UTXOTokenSession) RenounceOwnership() (*types.Transaction, error) {
+	return _UTXOToken.Contract.RenounceOwnership(&_UTXOToken.TransactOpts)
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_UTXOToken *UTXOTokenTransactorSession) RenounceOwnership() (*types.Transaction, error) {
+	return _UTXOToken.Contract.RenounceOwnership(&_UTXOToken.TransactOpts)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(address to, uint256 value) returns(bool)
+func (_UTXOToken *UTXOTokenTransactor) Transfer(opts *bind.TransactOpts, to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _UTXOToken.contract.Transact(opts, "transfer", to, value)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(address to, uint256 value) returns(bool)
+func (_UTXOToken *UTXOTokenSession) Transfer(to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _UTXOToken.Contract.Transfer(&_UTXOToken.TransactOpts, to, value)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(address to, uint256 value) returns(bool)
+func (_UTXOToken *UTXOTokenTransactorSession) Transfer(to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _UTXOToken.Contract.Transfer(&_UTXOToken.TransactOpts, to, value)
+}
+
+// TransferFrom is a paid mutator transaction binding the contract method 0x23b872dd.
+//
+// Solidity: function transferFrom(address from, address to, uint256 value) returns(bool)
+func (_UTXOToken *UTXOTokenTransactor) TransferFrom(opts *bind.TransactOpts, from common.Address, to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _UTXOToken.contract.Transact(opts, "transferFrom", from, to, value)
+}
+
+// TransferFrom is a paid mutator transaction binding the contract method 0x23b872dd.
+//
+// Solidity: function transferFrom(address from, address to, uint256 value) returns(bool)
+func (_UTXOToken *UTXOTokenSession) TransferFrom(from common.Address, to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _UTXOToken.Contract.TransferFrom(&_UTXOToken.TransactOpts, from, to, value)
+}
+
+// TransferFrom is a paid mutator transaction binding the contract method 0x23b872dd.
+//
+// Solidity: function transferFrom(address from, address to, uint256 value) returns(bool)
+func (_UTXOToken *UTXOTokenTransactorSession) TransferFrom(from common.Address, to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _UTXOToken.Contract.TransferFrom(&_UTXOToken.TransactOpts, from, to, value)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_UTXOToken *UTXOTokenTransactor) TransferOwnership(opts *bind.TransactOpts, newOwner common.Address) (*types.Transaction, error) {
+	return _UTXOToken.contract.Transact(opts, "transferOwnership", newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_UTXOToken *UTXOTokenSession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _UTXOToken.Contract.TransferOwnership(&_UTXOToken.TransactOpts, newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_UTXOToken *UTXOTokenTransactorSession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _UTXOToken.Contract.TransferOwnership(&_UTXOToken.TransactOpts, newOwner)
+}
+
+// UTXOTokenApprovalIterator is returned from FilterApproval and is used to iterate over the raw logs and unpacked data for Approval events raised by the UTXOToken contract.
+type UTXOTokenApprovalIterator struct {
+	Event *UTXOTokenApproval // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *UTXOTokenApprovalIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(UTXOTokenApproval)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(UTXOTokenApproval)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *UTXOTokenApprovalIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *UTXOTokenApprovalIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// UTXOTokenApproval represents a Approval event raised by the UTXOToken contract.
+type UTXOTokenApproval struct {
+	Owner   common.Address
+	Spender common.Address
+	Value   *big.Int
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterApproval is a free log retrieval operation binding the contract event 0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925.
+//
+// Solidity: event Approval(address indexed owner, address indexed spender, uint256 value)
+func (_UTXOToken *UTXOTokenFilterer) FilterApproval(opts *bind.FilterOpts, owner []common.Address, spender []common.Address) (*UTXOTokenApprovalIterator, error) {
+
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+	var spenderRule []interface{}
+	for _, spenderItem := range spender {
+		spenderRule = append(spenderRule, spenderItem)
+	}
+
+	logs, sub, err := _UTXOToken.contract.FilterLogs(opts, "Approval", ownerRule, spenderRule)
+	if err != nil {
+		return nil, err
+	}
+	return &UTXOTokenApprovalIterator{contract: _UTXOToken.contract, event: "Approval", logs: logs, sub: sub}, nil
+}
+
+// WatchApproval is a free log subscription operation binding the contract event 0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925.
+//
+// Solidity: event Approval(address indexed owner, address indexed spender, uint256 value)
+func (_UTXOToken *UTXOTokenFilterer) WatchApproval(opts *bind.WatchOpts, sink chan<- *UTXOTokenApproval, owner []common.Address, spender []common.Address) (event.Subscription, error) {
+
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+	var spenderRule []interface{}
+	for _, spenderItem := range spender {
+		spenderRule = append(spenderRule, spenderItem)
+	}
+
+	logs, sub, err := _UTXOToken.contract.WatchLogs(opts, "Approval", ownerRule, spenderRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(UTXOTokenApproval)
+				if err := _UTXOToken.contract.UnpackLog(event, "Approval", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseApproval is a log parse operation binding the contract event 0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925.
+//
+// Solidity: event Approval(address indexed owner, address indexed spender, uint256 value)
+func (_UTXOToken *UTXOTokenFilterer) ParseApproval(log types.Log) (*UTXOTokenApproval, error) {
+	event := new(UTXOTokenApproval)
+	if err := _UTXOToken.contract.UnpackLog(event, "Approval", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// UTXOTokenOwnershipTransferredIterator is returned from FilterOwnershipTransferred and is used to iterate over the raw logs and unpacked data for OwnershipTransferred events raised by the UTXOToken contract.
+type UTXOTokenOwnershipTransferredIterator struct {
+	Event *UTXOTokenOwnershipTransferred // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *UTXOTokenOwnershipTransferredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(UTXOTokenOwnershipTransferred)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(UTXOTokenOwnershipTransferred)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *UTXOTokenOwnershipTransferredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *UTXOTokenOwnershipTransferredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// UTXOTokenOwnershipTransferred represents a OwnershipTransferred event raised by the UTXOToken contract.
+type UTXOTokenOwnershipTransferred struct {
+	PreviousOwner common.Address
+	NewOwner      common.Address
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterOwnershipTransferred is a free log retrieval operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_UTXOToken *UTXOTokenFilterer) FilterOwnershipTransferred(opts *bind.FilterOpts, previousOwner []common.Address, newOwner []common.Address) (*UTXOTokenOwnershipTransferredIterator, error) {
+
+	var previousOwnerRule []interface{}
+	for _, previousOwnerItem := range previousOwner {
+		previousOwnerRule = append(previousOwnerRule, previousOwnerItem)
+	}
+	var newOwnerRule []interface{}
+	for _, newOwnerItem := range newOwner {
+		newOwnerRule = append(newOwnerRule, newOwnerItem)
+	}
+
+	logs, sub, err := _UTXOToken.contract.FilterLogs(opts, "OwnershipTransferred", previousOwnerRule, newOwnerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &UTXOTokenOwnershipTransferredIterator{contract: _UTXOToken.contract, event: "OwnershipTransferred", logs: logs, sub: sub}, nil
+}
+
+// WatchOwnershipTransferred is a free log subscription operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_UTXOToken *UTXOTokenFilterer) WatchOwnershipTransferred(opts *bind.WatchOpts, sink chan<- *UTXOTokenOwnershipTransferred, previousOwner []common.Address, newOwner []common.Address) (event.Subscription, error) {
+
+	var previousOwnerRule []interface{}
+	for _, previousOwnerItem := range previousOwner {
+		previousOwnerRule = append(previousOwnerRule, previousOwnerItem)
+	}
+	var newOwnerRule []interface{}
+	for _, newOwnerItem := range newOwner {
+		newOwnerRule = append(newOwnerRule, newOwnerItem)
+	}
+
+	logs, sub, err := _UTXOToken.contract.WatchLogs(opts, "OwnershipTransferred", previousOwnerRule, newOwnerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(UTXOTokenOwnershipTransferred)
+				if err := _UTXOToken.contract.UnpackLog(event, "OwnershipTransferred", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOwnershipTransferred is a log parse operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_UTXOToken *UTXOTokenFilterer) ParseOwnershipTransferred(log types.Log) (*UTXOTokenOwnershipTransferred, error) {
+	event := new(UTXOTokenOwnershipTransferred)
+	if err := _UTXOToken.contract.UnpackLog(event, "OwnershipTransferred", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// UTXOTokenTransferIterator is returned from FilterTransfer and is used to iterate over the raw logs and unpacked data for Transfer events raised by the UTXOToken contract.
+type UTXOTokenTransferIterator struct {
+	Event *UTXOTokenTransfer // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *UTXOTokenTransferIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(UTXOTokenTransfer)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(UTXOTokenTransfer)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *UTXOTokenTransferIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *UTXOTokenTransferIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// UTXOTokenTransfer represents a Transfer event raised by the UTXOToken contract.
+type UTXOTokenTransfer struct {
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+	Raw   types.Log // Blockchain specific contextual infos
+}
+
+// FilterTransfer is a free log retrieval operation binding the contract event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef.
+//
+// Solidity: event Transfer(address indexed from, address indexed to, uint256 value)
+func (_UTXOToken *UTXOTokenFilterer) FilterTransfer(opts *bind.FilterOpts, from []common.Address, to []common.Address) (*UTXOTokenTransferIterator, error) {
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _UTXOToken.contract.FilterLogs(opts, "Transfer", fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return &UTXOTokenTransferIterator{contract: _UTXOToken.contract, event: "Transfer", logs: logs, sub: sub}, nil
+}
+
+// WatchTransfer is a free log subscription operation binding the contract event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef.
+//
+// Solidity: event Transfer(address indexed from, address indexed to, uint256 value)
+func (_UTXOToken *UTXOTokenFilterer) WatchTransfer(opts *bind.WatchOpts, sink chan<- *UTXOTokenTransfer, from []common.Address, to []common.Address) (event.Subscription, error) {
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _UTXOToken.contract.WatchLogs(opts, "Transfer", fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(UTXOTokenTransfer)
+				if err := _UTXOToken.contract.UnpackLog(event, "Transfer", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTransfer is a log parse operation binding the contract event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef.
+//
+// Solidity: event Transfer(address indexed from, address indexed to, uint256 value)
+func (_UTXOToken *UTXOTokenFilterer) ParseTransfer(log types.Log) (*UTXOTokenTransfer, error) {
+	event := new(UTXOTokenTransfer)
+	if err := _UTXOToken.contract.UnpackLog(event, "Transfer", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// UTXOTokenUTXORedeemedIterator is returned from FilterUTXORedeemed and is used to iterate over the raw logs and unpacked data for UTXORedeemed events raised by the UTXOToken contract.
+type UTXOTokenUTXORedeemedIterator struct {
+	Event *UTXOTokenUTXORedeemed // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *UTXOTokenUTXORedeemedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(UTXOTokenUTXORedeemed)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(UTXOTokenUTXORedeemed)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *UTXOTokenUTXORedeemedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *UTXOTokenUTXORedeemedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// UTXOTokenUTXORedeemed represents a UTXORedeemed event raised by the UTXOToken contract.
+type UTXOTokenUTXORedeemed struct {
+	BitcoinTxId        string
+	Vout               uint32
+	Redeemer           common.Address
+	BitcoinDestination string
+	Raw                types.Log // Blockchain specific contextual infos
+}
+
+// FilterUTXORedeemed is a free log retrieval operation binding the contract event 0x5631dc8426c09dc6c00cadfbe351d54ba4baff4a237fe763276a17d3c33808f1.
+//
+// Solidity: event UTXORedeemed(string bitcoinTxId, uint32 vout, address redeemer, string bitcoinDestination)
+func (_UTXOToken *UTXOTokenFilterer) FilterUTXORedeemed(opts *bind.FilterOpts) (*UTXOTokenUTXORedeemedIterator, error) {
+
+	logs, sub, err := _UTXOToken.contract.FilterLogs(opts, "UTXORedeemed")
+	if err != nil {
+		return nil, err
+	}
+	return &UTXOTokenUTXORedeemedIterator{contract: _UTXOToken.contract, event: "UTXORedeemed", logs: logs, sub: sub}, nil
+}
+
+// WatchUTXORedeemed is a free log subscription operation binding the contract event 0x5631dc8426c09dc6c00cadfbe351d54ba4baff4a237fe763276a17d3c33808f1.
+//
+// Solidity: event UTXORedeemed(string bitcoinTxId, uint32 vout, address redeemer, string bitcoinDestination)
+func (_UTXOToken *UTXOTokenFilterer) WatchUTXORedeemed(opts *bind.WatchOpts, sink chan<- *UTXOTokenUTXORedeemed) (event.Subscription, error) {
+
+	logs, sub, err := _UTXOToken.contract.WatchLogs(opts, "UTXORedeemed")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(UTXOTokenUTXORedeemed)
+				if err := _UTXOToken.contract.UnpackLog(event, "UTXORedeemed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseUTXORedeemed is a log parse operation binding the contract event 0x5631dc8426c09dc6c00cadfbe351d54ba4baff4a237fe763276a17d3c33808f1.
+//
+// Solidity: event UTXORedeemed(string bitcoinTxId, uint32 vout, address redeemer, string bitcoinDestination)
+func (_UTXOToken *UTXOTokenFilterer) ParseUTXORedeemed(log types.Log) (*UTXOTokenUTXORedeemed, error) {
+	event := new(UTXOTokenUTXORedeemed)
+	if err := _UTXOToken.contract.UnpackLog(event, "UTXORedeemed", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -2,24 +2,22 @@ package contracts
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"math/big"
-	"strings"
 
-	"github.com/ethereum/go-ethereum"
+	"bitbridge/internal/contracts/bindings"
+	"bitbridge/internal/ethereum"
+
+	goethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 type Deployer struct {
-	client     *ethclient.Client
-	privateKey *ecdsa.PrivateKey
-	chainID    *big.Int
+	client bind.ContractBackend
+	nonces *ethereum.NonceManager
 }
 
 type DeploymentResult struct {
@@ -29,22 +27,14 @@ type DeploymentResult struct {
 	GasUsed         uint64         `json:"gas_used"`
 }
 
-func NewDeployer(client *ethclient.Client, privateKeyHex string, chainID *big.Int) (*Deployer, error) {
-	// Remove 0x prefix if present
-	if strings.HasPrefix(privateKeyHex, "0x") {
-		privateKeyHex = privateKeyHex[2:]
-	}
-
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse private key: %w", err)
-	}
-
+// NewDeployer creates a deployer that sends through the operator key's
+// nonce manager. Any contract backend works, including go-ethereum's
+// simulated one.
+func NewDeployer(client bind.ContractBackend, nonces *ethereum.NonceManager) *Deployer {
 	return &Deployer{
-		client:     client,
-		privateKey: privateKey,
-		chainID:    chainID,
-	}, nil
+		client: client,
+		nonces: nonces,
+	}
 }
 
 // DeploySPVVerifier deploys the SPV Verifier smart contract
@@ -55,20 +45,19 @@ func (d *Deployer) DeploySPVVerifier(ctx context.Context) (*DeploymentResult, er
 		return nil, fmt.Errorf("failed to load contract data: %w", err)
 	}
 
-	// Create transactor
-	auth, err := d.createTransactor(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create transactor: %w", err)
-	}
-
 	// Deploy contract
-	address, tx, _, err := bind.DeployContract(auth, *abi, bytecode, d.client)
+	var address common.Address
+	tx, err := d.nonces.Transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		deployed, tx, _, err := bind.DeployContract(auth, *abi, bytecode, d.client)
+		address = deployed
+		return tx, err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to deploy contract: %w", err)
 	}
 
 	// Wait for transaction to be mined
-	receipt, err := bind.WaitMined(ctx, d.client, tx)
+	receipt, err := d.nonces.WaitMined(ctx, tx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to wait for transaction: %w", err)
 	}
@@ -87,15 +76,11 @@ func (d *Deployer) DeploySPVVerifier(ctx context.Context) (*DeploymentResult, er
 
 // InteractWithSPVVerifier creates a bound contract instance for interaction
 func (d *Deployer) InteractWithSPVVerifier(contractAddress common.Address) (*SPVVerifierContract, error) {
-	// Load ABI
-	_, abiData, err := d.loadContractData("SPVVerifier")
+	contract, err := bindings.NewSPVVerifier(contractAddress, d.client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load contract ABI: %w", err)
+		return nil, fmt.Errorf("failed to bind SPV verifier: %w", err)
 	}
 
-	// Create bound contract
-	contract := bind.NewBoundContract(contractAddress, *abiData, d.client, d.client, d.client)
-
 	return &SPVVerifierContract{
 		contract: contract,
 		address:  contractAddress,
@@ -104,74 +89,40 @@ func (d *Deployer) InteractWithSPVVerifier(contractAddress common.Address) (*SPV
 	}, nil
 }
 
-// createTransactor creates a transactor for contract interactions
-func (d *Deployer) createTransactor(ctx context.Context) (*bind.TransactOpts, error) {
-	publicKey := d.privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return nil, fmt.Errorf("error casting public key to ECDSA")
-	}
-
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-	
-	nonce, err := d.client.PendingNonceAt(ctx, fromAddress)
+// loadContractData loads bytecode and ABI for a contract from the embedded
+// artifacts
+func (d *Deployer) loadContractData(contractName string) ([]byte, *abi.ABI, error) {
+	artifact, err := bindings.LoadArtifact(contractName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nonce: %w", err)
+		return nil, nil, err
 	}
 
-	gasPrice, err := d.client.SuggestGasPrice(ctx)
+	parsed, err := artifact.ParsedABI()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get gas price: %w", err)
+		return nil, nil, err
 	}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(d.privateKey, d.chainID)
+	bytecode, err := artifact.DeployCode()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transactor: %w", err)
+		return nil, nil, err
 	}
 
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)
-	auth.GasLimit = uint64(3000000) // 3M gas limit
-	auth.GasPrice = gasPrice
-
-	return auth, nil
-}
-
-// loadContractData loads bytecode and ABI for a contract
-func (d *Deployer) loadContractData(contractName string) ([]byte, *abi.ABI, error) {
-	// This is a simplified version - in practice, you'd compile Solidity contracts
-	// and load the resulting JSON artifacts
-	
-	// For now, return placeholder data - you'll need to compile the Solidity contract
-	// and extract the bytecode and ABI from the compilation output
-	
-	return nil, nil, fmt.Errorf("contract compilation not implemented - please compile %s.sol manually", contractName)
+	return bytecode, parsed, nil
 }
 
 // SPVVerifierContract represents a bound SPV Verifier contract
 type SPVVerifierContract struct {
-	contract *bind.BoundContract
+	contract *bindings.SPVVerifier
 	address  common.Address
-	client   *ethclient.Client
+	client   bind.ContractBackend
 	deployer *Deployer
 }
 
 // VerifyProof calls the verifyProof function on the smart contract
 func (c *SPVVerifierContract) VerifyProof(ctx context.Context, headerBytes []byte, proof ProofData, blockHeight *big.Int) (*types.Transaction, error) {
-	auth, err := c.deployer.createTransactor(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	// Convert proof data to contract format
-	merkleProof := MerkleProofContract{
-		Proof:      proof.MerkleProof,
-		Index:      big.NewInt(int64(proof.Index)),
-		TxHash:     proof.TxHash,
-		MerkleRoot: proof.MerkleRoot,
-	}
-
-	tx, err := c.contract.Transact(auth, "verifyProof", headerBytes, merkleProof, blockHeight)
+	tx, err := c.deployer.nonces.Transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.VerifyProof(auth, headerBytes, proof.toContract(), blockHeight)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to call verifyProof: %w", err)
 	}
@@ -181,34 +132,25 @@ func (c *SPVVerifierContract) VerifyProof(ctx context.Context, headerBytes []byt
 
 // IsTransactionVerified checks if a transaction has been verified
 func (c *SPVVerifierContract) IsTransactionVerified(ctx context.Context, txHash [32]byte) (bool, error) {
-	var result []interface{}
-	err := c.contract.Call(&bind.CallOpts{Context: ctx}, &result, "isTransactionVerified", txHash)
-	if err != nil {
-		return false, err
-	}
-
-	return result[0].(bool), nil
+	return c.contract.IsTransactionVerified(&bind.CallOpts{Context: ctx}, txHash)
 }
 
 // GetBlockHeader retrieves stored block header
 func (c *SPVVerifierContract) GetBlockHeader(ctx context.Context, blockHash [32]byte) (*BlockHeaderContract, error) {
-	var result []interface{}
-	err := c.contract.Call(&bind.CallOpts{Context: ctx}, &result, "getBlockHeader", blockHash)
+	header, err := c.contract.GetBlockHeader(&bind.CallOpts{Context: ctx}, blockHash)
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse result into BlockHeaderContract
-	// This would need to match the exact return structure from the contract
 	return &BlockHeaderContract{
-		Version:    result[0].(uint32),
-		PrevBlock:  result[1].([32]byte),
-		MerkleRoot: result[2].([32]byte),
-		Timestamp:  result[3].(uint32),
-		Bits:       result[4].(uint32),
-		Nonce:      result[5].(uint32),
-		Height:     result[6].(*big.Int),
-		Exists:     result[7].(bool),
+		Version:    header.Version,
+		PrevBlock:  header.PrevBlock,
+		MerkleRoot: header.MerkleRoot,
+		Timestamp:  header.Timestamp,
+		Bits:       header.Bits,
+		Nonce:      header.Nonce,
+		Height:     header.Height,
+		Exists:     header.Exists,
 	}, nil
 }
 
@@ -217,15 +159,20 @@ func (c *SPVVerifierContract) GetBlockHeader(ctx context.Context, blockHash [32]
 type ProofData struct {
 	MerkleProof [][32]byte `json:"merkle_proof"`
 	Index       uint32     `json:"index"`
+	TotalTxs    uint32     `json:"total_txs"`
 	TxHash      [32]byte   `json:"tx_hash"`
 	MerkleRoot  [32]byte   `json:"merkle_root"`
 }
 
-type MerkleProofContract struct {
-	Proof      [][32]byte `json:"proof"`
-	Index      *big.Int   `json:"index"`
-	TxHash     [32]byte   `json:"txHash"`
-	MerkleRoot [32]byte   `json:"merkleRoot"`
+// toContract converts the proof to the SPVVerifier.MerkleProof struct
+func (p ProofData) toContract() bindings.SPVVerifierMerkleProof {
+	return bindings.SPVVerifierMerkleProof{
+		Proof:      p.MerkleProof,
+		Index:      big.NewInt(int64(p.Index)),
+		TotalTxs:   big.NewInt(int64(p.TotalTxs)),
+		TxHash:     p.TxHash,
+		MerkleRoot: p.MerkleRoot,
+	}
 }
 
 type BlockHeaderContract struct {
@@ -246,13 +193,7 @@ func (d *Deployer) EstimateDeploymentGas(ctx context.Context) (uint64, error) {
 		return 0, fmt.Errorf("failed to load contract data: %w", err)
 	}
 
-	publicKey := d.privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return 0, fmt.Errorf("error casting public key to ECDSA")
-	}
-
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
+	fromAddress := d.nonces.Address()
 
 	// Pack constructor arguments (none for SPVVerifier)
 	input, err := abi.Pack("")
@@ -263,14 +204,14 @@ func (d *Deployer) EstimateDeploymentGas(ctx context.Context) (uint64, error) {
 	data := append(bytecode, input...)
 
 	// Create call message for gas estimation
-	msg := ethereum.CallMsg{
+	msg := goethereum.CallMsg{
 		From: fromAddress,
 		Data: data,
 	}
 
-	gas, err := d.client.EstimateGas(ctx, msg)
+	gas, err := d.nonces.EstimateGas(ctx, msg)
 	if err != nil {
-		return 0, fmt.Errorf("failed to estimate gas: %w", err)
+		return 0, err
 	}
 
 	return gas, nil
@@ -283,23 +224,15 @@ func (c *SPVVerifierContract) GetAddress() common.Address {
 
 // BatchVerifyProofs calls the batch verification function
 func (c *SPVVerifierContract) BatchVerifyProofs(ctx context.Context, headerBytesArray [][]byte, proofs []ProofData, blockHeights []*big.Int) (*types.Transaction, error) {
-	auth, err := c.deployer.createTransactor(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	// Convert proof data to contract format
-	merkleProofs := make([]MerkleProofContract, len(proofs))
+	merkleProofs := make([]bindings.SPVVerifierMerkleProof, len(proofs))
 	for i, proof := range proofs {
-		merkleProofs[i] = MerkleProofContract{
-			Proof:      proof.MerkleProof,
-			Index:      big.NewInt(int64(proof.Index)),
-			TxHash:     proof.TxHash,
-			MerkleRoot: proof.MerkleRoot,
-		}
+		merkleProofs[i] = proof.toContract()
 	}
 
-	tx, err := c.contract.Transact(auth, "batchVerifyProofs", headerBytesArray, merkleProofs, blockHeights)
+	tx, err := c.deployer.nonces.Transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.BatchVerifyProofs(auth, headerBytesArray, merkleProofs, blockHeights)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to call batchVerifyProofs: %w", err)
 	}
@@ -0,0 +1,181 @@
+package contracts
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"bitbridge/internal/contracts/bindings"
+	"bitbridge/internal/ethereum"
+	"bitbridge/internal/proof"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+// loadBlock reads a mainnet block from the proof package's golden blocks
+func loadBlock(t *testing.T, height int32) *wire.MsgBlock {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("..", "proof", "testdata", fmt.Sprintf("block_%d.dat.bz2", height)))
+	if err != nil {
+		t.Fatalf("Failed to open block %d: %v", height, err)
+	}
+	defer f.Close()
+
+	var block wire.MsgBlock
+	if err := block.Deserialize(bzip2.NewReader(f)); err != nil {
+		t.Fatalf("Failed to decode block %d: %v", height, err)
+	}
+	return &block
+}
+
+// newSimulatedNonces starts a simulated chain that mines continuously and
+// returns its backend with a nonce manager for a funded operator key
+func newSimulatedNonces(t *testing.T) (*simulated.Backend, *ethereum.NonceManager) {
+	t.Helper()
+
+	operator, _ := crypto.GenerateKey()
+	funds := new(big.Int).Exp(big.NewInt(10), big.NewInt(21), nil)
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		crypto.PubkeyToAddress(operator.PublicKey): {Balance: funds},
+	})
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				// Empty blocks would only slow down the log index
+				if pending, err := backend.Client().PendingTransactionCount(context.Background()); err == nil && pending > 0 {
+					backend.Commit()
+				}
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		close(done)
+		<-stopped
+		backend.Close()
+	})
+
+	client := ethereum.NewClientWithBackend(backend.Client(), ethereum.NewKeySigner(operator), big.NewInt(1337))
+	return backend, ethereum.NewNonceManager(ethereum.NonceManagerConfig{Client: client})
+}
+
+func TestDeploySPVVerifierOnSimulatedChain(t *testing.T) {
+	artifact, err := bindings.LoadArtifact("SPVVerifier")
+	if err != nil {
+		t.Fatalf("Failed to load artifact: %v", err)
+	}
+	if _, err := artifact.DeployCode(); err != nil {
+		t.Fatalf("SPVVerifier artifact is not compiled: %v", err)
+	}
+
+	backend, nonces := newSimulatedNonces(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	deployer := NewDeployer(backend.Client(), nonces)
+	result, err := deployer.DeploySPVVerifier(ctx)
+	if err != nil {
+		t.Fatalf("Failed to deploy SPVVerifier: %v", err)
+	}
+	contract, err := deployer.InteractWithSPVVerifier(result.ContractAddress)
+	if err != nil {
+		t.Fatalf("Failed to bind SPVVerifier: %v", err)
+	}
+	opts := &bind.CallOpts{Context: ctx}
+
+	// Relay the header the way the relayer does
+	block := loadBlock(t, 170)
+	var header bytes.Buffer
+	if err := block.Header.Serialize(&header); err != nil {
+		t.Fatalf("Failed to serialize header: %v", err)
+	}
+	tx, err := nonces.Transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.contract.SubmitBlockHeaders(auth, [][]byte{header.Bytes()}, big.NewInt(170))
+	})
+	if err != nil {
+		t.Fatalf("Failed to submit header: %v", err)
+	}
+	if receipt, err := nonces.WaitMined(ctx, tx); err != nil || receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("Header submission failed: %v", err)
+	}
+
+	stored, err := contract.contract.GetBlockHashByHeight(opts, big.NewInt(170))
+	if err != nil {
+		t.Fatalf("Failed to read relayed header: %v", err)
+	}
+	if chainhash.Hash(stored) != block.BlockHash() {
+		t.Fatalf("Expected block %s at height 170, got %s", block.BlockHash(), chainhash.Hash(stored))
+	}
+
+	// Prove the second transaction of the block
+	leaves := make([][32]byte, len(block.Transactions))
+	for i, msgTx := range block.Transactions {
+		leaves[i] = msgTx.TxHash()
+	}
+	tree, err := proof.NewMerkleTreeFromHashes(leaves)
+	if err != nil {
+		t.Fatalf("Failed to build merkle tree: %v", err)
+	}
+	merkleProof, err := tree.GenerateProof(1)
+	if err != nil {
+		t.Fatalf("Failed to generate proof: %v", err)
+	}
+	spvProof := &proof.SPVProof{BlockHeader: &block.Header, MerkleProof: merkleProof, BlockHeight: 170}
+
+	service := &Service{deployer: deployer, spvContract: contract, contractAddress: result.ContractAddress}
+	proofData, _, err := service.convertSPVProofToContractFormat(spvProof)
+	if err != nil {
+		t.Fatalf("Failed to convert proof: %v", err)
+	}
+	valid, err := contract.contract.VerifyMerkleProof(opts, proofData.toContract())
+	if err != nil || !valid {
+		t.Fatalf("Expected the merkle proof to check out on chain, got %v (%v)", valid, err)
+	}
+
+	txid := block.Transactions[1].TxHash().String()
+	response, err := service.VerifyTransaction(ctx, &VerificationRequest{TxHash: txid, BlockHeight: 170}, spvProof)
+	if err != nil {
+		t.Fatalf("Failed to verify transaction: %v", err)
+	}
+	if !response.Verified {
+		t.Fatal("Expected verifyProof to succeed")
+	}
+
+	verified, err := service.IsTransactionVerified(ctx, txid)
+	if err != nil || !verified {
+		t.Errorf("Expected %s to be recorded as verified, got %v (%v)", txid, verified, err)
+	}
+
+	// Proofs only verify against the height their header was relayed at
+	if _, err := service.VerifyTransaction(ctx, &VerificationRequest{TxHash: txid, BlockHeight: 171}, spvProof); err == nil {
+		t.Error("Expected a proof against an unrelayed height to be rejected")
+	}
+
+	// A proof for the wrong position does not reach the merkle root
+	tampered := *merkleProof
+	tampered.Index = 0
+	spvProof.MerkleProof = &tampered
+	if _, err := service.VerifyTransaction(ctx, &VerificationRequest{TxHash: txid, BlockHeight: 170}, spvProof); err == nil {
+		t.Error("Expected a proof for the wrong position to be rejected")
+	}
+}
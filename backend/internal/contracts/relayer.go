@@ -0,0 +1,335 @@
+package contracts
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"bitbridge/internal/contracts/bindings"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrChainDiverged is returned when the verifier's header chain differs from
+// Bitcoin below the validated headers, so the relayer cannot find a common
+// ancestor to resubmit from
+var ErrChainDiverged = errors.New("verifier header chain diverged below the validated headers")
+
+// HeaderSource provides validated Bitcoin headers; *headers.Chain
+// satisfies it
+type HeaderSource interface {
+	BestTip() (int32, string, bool)
+	HeaderAt(height int32) (*wire.BlockHeader, bool)
+}
+
+// RelayerConfig for the header relayer
+type RelayerConfig struct {
+	Service      *Service
+	Headers      HeaderSource
+	BatchSize    int
+	PollInterval time.Duration
+	StartHeight  int64 // first height to relay to an empty verifier, 0 for the current tip
+}
+
+// RelayerStatus reports how far the verifier's header chain trails Bitcoin
+type RelayerStatus struct {
+	Running          bool      `json:"running"`
+	BitcoinTip       int32     `json:"bitcoin_tip"`
+	RelayedHeight    int32     `json:"relayed_height"`
+	Lag              int32     `json:"lag"`
+	HeadersSubmitted int       `json:"headers_submitted"`
+	Reorgs           int       `json:"reorgs"`
+	DivergedAt       int32     `json:"diverged_at,omitempty"` // height relaying halted at after ErrChainDiverged
+	LastSubmission   time.Time `json:"last_submission,omitempty"`
+	LastError        string    `json:"last_error,omitempty"`
+}
+
+// Relayer keeps the SPVVerifier's header chain in line with the validated
+// Bitcoin best chain. Headers are submitted in batches; when Bitcoin
+// reorganizes, the relayer walks back to the last height the verifier
+// agrees on and resubmits the competing branch from there.
+type Relayer struct {
+	service      *Service
+	headers      HeaderSource
+	contract     *bindings.SPVVerifier
+	batchSize    int
+	pollInterval time.Duration
+	startHeight  int64
+	relayed      int32 // last height known to match the verifier, -1 until resumed
+	status       RelayerStatus
+	mu           sync.RWMutex
+	ctx          context.Context
+	cancel       context.CancelFunc
+}
+
+// NewRelayer creates a header relayer for the configured SPVVerifier
+func NewRelayer(config RelayerConfig) (*Relayer, error) {
+	if config.Service == nil || config.Headers == nil {
+		return nil, fmt.Errorf("contracts service and header source are required")
+	}
+	if config.Service.config.SPVVerifierAddr == "" {
+		return nil, fmt.Errorf("SPV verifier address not configured")
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 20
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 30 * time.Second
+	}
+
+	address := common.HexToAddress(config.Service.config.SPVVerifierAddr)
+	contract, err := bindings.NewSPVVerifier(address, config.Service.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind SPV verifier: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Relayer{
+		service:      config.Service,
+		headers:      config.Headers,
+		contract:     contract,
+		batchSize:    config.BatchSize,
+		pollInterval: config.PollInterval,
+		startHeight:  config.StartHeight,
+		relayed:      -1,
+		ctx:          ctx,
+		cancel:       cancel,
+	}, nil
+}
+
+// Start relays headers in the background until Stop is called
+func (r *Relayer) Start() {
+	log.Println("Starting header relayer...")
+
+	r.mu.Lock()
+	r.status.Running = true
+	r.mu.Unlock()
+
+	go r.relayLoop()
+}
+
+func (r *Relayer) Stop() {
+	log.Println("Stopping header relayer...")
+	r.cancel()
+}
+
+// Status returns the relayer's lag metrics
+func (r *Relayer) Status() RelayerStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.status
+}
+
+func (r *Relayer) relayLoop() {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		err := r.relay()
+		r.mu.Lock()
+		if err != nil {
+			log.Printf("Header relay failed: %v", err)
+			r.status.LastError = err.Error()
+		} else {
+			r.status.LastError = ""
+		}
+		r.mu.Unlock()
+
+		if errors.Is(err, ErrChainDiverged) {
+			// Resubmitting on top of an unknown branch would extend it, so
+			// relaying stops until the operator reconciles the verifier
+			r.mu.Lock()
+			r.status.Running = false
+			r.mu.Unlock()
+			log.Println("Header relayer halted")
+			return
+		}
+
+		select {
+		case <-r.ctx.Done():
+			r.mu.Lock()
+			r.status.Running = false
+			r.mu.Unlock()
+			log.Println("Header relayer stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay brings the verifier up to the Bitcoin tip
+func (r *Relayer) relay() error {
+	tip, _, ok := r.headers.BestTip()
+	if !ok {
+		return nil
+	}
+	r.updateStatus(tip, 0)
+
+	if r.relayed < 0 {
+		if err := r.resume(tip); err != nil {
+			return err
+		}
+	} else if err := r.rewind(); err != nil {
+		return err
+	}
+
+	for r.relayed < tip {
+		if r.ctx.Err() != nil {
+			return nil
+		}
+
+		start := r.relayed + 1
+		end := start + int32(r.batchSize) - 1
+		if end > tip {
+			end = tip
+		}
+
+		if err := r.submit(start, end); err != nil {
+			return err
+		}
+		r.relayed = end
+		r.updateStatus(tip, int(end-start+1))
+	}
+
+	return nil
+}
+
+// resume finds where a previous run left off using the verifier's own
+// record of relayed hashes
+func (r *Relayer) resume(tip int32) error {
+	latest, err := r.latestHeight()
+	if err != nil {
+		return err
+	}
+
+	if latest == 0 {
+		start := int32(r.startHeight)
+		if start <= 0 || start > tip {
+			start = tip
+		}
+		r.relayed = start - 1
+		log.Printf("Verifier has no relayed headers, starting at block %d", start)
+		return nil
+	}
+
+	if latest > int64(tip) {
+		latest = int64(tip)
+	}
+	r.relayed = int32(latest)
+	if err := r.rewind(); err != nil {
+		return err
+	}
+
+	log.Printf("Resuming header relay after block %d", r.relayed)
+	return nil
+}
+
+// rewind steps r.relayed back until the verifier's hash at that height
+// matches the Bitcoin best chain, so that the next submission replaces the
+// orphaned branch
+func (r *Relayer) rewind() error {
+	from := r.relayed
+
+	for r.relayed >= 0 {
+		header, ok := r.headers.HeaderAt(r.relayed)
+		if !ok {
+			r.mu.Lock()
+			r.status.DivergedAt = r.relayed
+			r.mu.Unlock()
+			return fmt.Errorf("%w: no validated header at height %d to compare with (verifier tip %d)", ErrChainDiverged, r.relayed, from)
+		}
+
+		stored, err := r.blockHashByHeight(r.relayed)
+		if err != nil {
+			return err
+		}
+		if stored == [32]byte(header.BlockHash()) {
+			break
+		}
+		r.relayed--
+	}
+
+	if r.relayed != from {
+		log.Printf("Verifier header chain diverged from Bitcoin at block %d, resubmitting from %d", from, r.relayed+1)
+		r.mu.Lock()
+		r.status.Reorgs++
+		r.mu.Unlock()
+	}
+	return nil
+}
+
+// submit relays the best chain headers from start to end inclusive
+func (r *Relayer) submit(start, end int32) error {
+	headerBytes := make([][]byte, 0, end-start+1)
+	for height := start; height <= end; height++ {
+		header, ok := r.headers.HeaderAt(height)
+		if !ok {
+			return fmt.Errorf("no validated header at height %d", height)
+		}
+
+		var buf bytes.Buffer
+		if err := header.Serialize(&buf); err != nil {
+			return fmt.Errorf("failed to serialize header %d: %w", height, err)
+		}
+		headerBytes = append(headerBytes, buf.Bytes())
+	}
+
+	tx, err := r.service.deployer.nonces.Transact(r.ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return r.contract.SubmitBlockHeaders(auth, headerBytes, big.NewInt(int64(start)))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to call submitBlockHeaders: %w", err)
+	}
+
+	receipt, err := r.service.deployer.nonces.WaitMined(r.ctx, tx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for header submission: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("header submission %s reverted", tx.Hash().Hex())
+	}
+
+	log.Printf("Relayed headers %d-%d in %s", start, end, tx.Hash().Hex())
+	return nil
+}
+
+func (r *Relayer) latestHeight() (int64, error) {
+	latest, err := r.contract.LatestHeight(&bind.CallOpts{Context: r.ctx})
+	if err != nil {
+		return 0, fmt.Errorf("failed to call latestHeight: %w", err)
+	}
+	return latest.Int64(), nil
+}
+
+func (r *Relayer) blockHashByHeight(height int32) ([32]byte, error) {
+	hash, err := r.contract.GetBlockHashByHeight(&bind.CallOpts{Context: r.ctx}, big.NewInt(int64(height)))
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("failed to call getBlockHashByHeight: %w", err)
+	}
+	return hash, nil
+}
+
+func (r *Relayer) updateStatus(tip int32, submitted int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.status.BitcoinTip = tip
+	if r.relayed >= 0 {
+		r.status.RelayedHeight = r.relayed
+		r.status.Lag = tip - r.relayed
+	}
+	if submitted > 0 {
+		r.status.HeadersSubmitted += submitted
+		r.status.LastSubmission = time.Now()
+	}
+}
@@ -0,0 +1,298 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"bitbridge/pkg/config"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// regtestBits is the easiest compact target, so test headers mine instantly
+const regtestBits = 0x207fffff
+
+// fakeHeaders is a HeaderSource over an in-memory best chain starting at
+// base
+type fakeHeaders struct {
+	mu      sync.Mutex
+	base    int32
+	headers []*wire.BlockHeader
+	missing int32 // height HeaderAt pretends not to have, 0 for none
+}
+
+func (f *fakeHeaders) BestTip() (int32, string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.headers) == 0 {
+		return 0, "", false
+	}
+	tip := f.headers[len(f.headers)-1]
+	return f.base + int32(len(f.headers)) - 1, tip.BlockHash().String(), true
+}
+
+func (f *fakeHeaders) HeaderAt(height int32) (*wire.BlockHeader, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if height < f.base || height >= f.base+int32(len(f.headers)) || height == f.missing {
+		return nil, false
+	}
+	return f.headers[height-f.base], true
+}
+
+// mineFrom replaces the chain above height with n freshly mined headers;
+// salt keeps competing branches apart
+func (f *fakeHeaders) mineFrom(t *testing.T, height int32, n int, salt byte) {
+	t.Helper()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.headers = f.headers[:height-f.base+1]
+	prev := f.headers[len(f.headers)-1].BlockHash()
+	for i := 0; i < n; i++ {
+		header := mineHeader(t, prev, salt)
+		f.headers = append(f.headers, header)
+		prev = header.BlockHash()
+	}
+}
+
+// newFakeHeaders returns a best chain of n headers starting at base
+func newFakeHeaders(t *testing.T, base int32, n int, salt byte) *fakeHeaders {
+	t.Helper()
+
+	source := &fakeHeaders{base: base, headers: []*wire.BlockHeader{mineHeader(t, chainhash.Hash{salt}, salt)}}
+	source.mineFrom(t, base, n-1, salt)
+	return source
+}
+
+func mineHeader(t *testing.T, prev chainhash.Hash, salt byte) *wire.BlockHeader {
+	t.Helper()
+
+	target := blockchain.CompactToBig(regtestBits)
+	header := wire.NewBlockHeader(1, &prev, &chainhash.Hash{salt}, regtestBits, 0)
+	header.Timestamp = time.Unix(1700000000, 0)
+	for nonce := uint32(0); nonce < 1<<16; nonce++ {
+		header.Nonce = nonce
+		hash := header.BlockHash()
+		if blockchain.HashToBig(&hash).Cmp(target) <= 0 {
+			return header
+		}
+	}
+
+	t.Fatal("Failed to mine header")
+	return nil
+}
+
+// newRelayerService deploys an SPVVerifier to a simulated chain and returns
+// a contracts service connected to it
+func newRelayerService(t *testing.T) *Service {
+	t.Helper()
+
+	backend, nonces := newSimulatedNonces(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	deployer := NewDeployer(backend.Client(), nonces)
+	result, err := deployer.DeploySPVVerifier(ctx)
+	if err != nil {
+		t.Fatalf("Failed to deploy SPVVerifier: %v", err)
+	}
+	contract, err := deployer.InteractWithSPVVerifier(result.ContractAddress)
+	if err != nil {
+		t.Fatalf("Failed to bind SPVVerifier: %v", err)
+	}
+
+	return &Service{
+		client:          backend.Client(),
+		deployer:        deployer,
+		spvContract:     contract,
+		contractAddress: result.ContractAddress,
+		config:          &config.EthereumConfig{ChainID: 1337, SPVVerifierAddr: result.ContractAddress.Hex()},
+	}
+}
+
+func newTestRelayer(t *testing.T, service *Service, source HeaderSource, batchSize int) *Relayer {
+	t.Helper()
+
+	relayer, err := NewRelayer(RelayerConfig{Service: service, Headers: source, BatchSize: batchSize, StartHeight: 100})
+	if err != nil {
+		t.Fatalf("Failed to create relayer: %v", err)
+	}
+	t.Cleanup(relayer.Stop)
+	return relayer
+}
+
+// expectRelayed checks the verifier's header chain against the source from
+// height from up to its tip
+func expectRelayed(t *testing.T, service *Service, source *fakeHeaders, from int32) {
+	t.Helper()
+
+	contract := service.spvContract.contract
+	tip, _, _ := source.BestTip()
+	latest, err := contract.LatestHeight(&bind.CallOpts{})
+	if err != nil || latest.Int64() != int64(tip) {
+		t.Fatalf("Expected the verifier to be at height %d, got %v (%v)", tip, latest, err)
+	}
+	for height := from; height <= tip; height++ {
+		stored, err := contract.GetBlockHashByHeight(&bind.CallOpts{}, big.NewInt(int64(height)))
+		if err != nil {
+			t.Fatalf("Failed to read height %d: %v", height, err)
+		}
+		header, _ := source.HeaderAt(height)
+		if chainhash.Hash(stored) != header.BlockHash() {
+			t.Fatalf("Expected block %s at height %d, got %s", header.BlockHash(), height, chainhash.Hash(stored))
+		}
+	}
+}
+
+// relayedRanges returns the start and end of every HeadersRelayed event
+func relayedRanges(t *testing.T, service *Service) [][2]int64 {
+	t.Helper()
+
+	events, err := service.spvContract.contract.FilterHeadersRelayed(&bind.FilterOpts{})
+	if err != nil {
+		t.Fatalf("Failed to filter events: %v", err)
+	}
+	defer events.Close()
+
+	var ranges [][2]int64
+	for events.Next() {
+		ranges = append(ranges, [2]int64{events.Event.StartHeight.Int64(), events.Event.EndHeight.Int64()})
+	}
+	return ranges
+}
+
+func TestRelayerSubmitsBatchesAndResumes(t *testing.T) {
+	service := newRelayerService(t)
+	source := newFakeHeaders(t, 100, 25, 1)
+
+	relayer := newTestRelayer(t, service, source, 10)
+	if err := relayer.relay(); err != nil {
+		t.Fatalf("Relay failed: %v", err)
+	}
+	expectRelayed(t, service, source, 100)
+
+	ranges := relayedRanges(t, service)
+	if len(ranges) != 3 || ranges[0] != [2]int64{100, 109} || ranges[1] != [2]int64{110, 119} || ranges[2] != [2]int64{120, 124} {
+		t.Fatalf("Expected batches 100-109, 110-119 and 120-124, got %v", ranges)
+	}
+	status := relayer.Status()
+	if status.HeadersSubmitted != 25 || status.RelayedHeight != 124 || status.Lag != 0 || status.LastSubmission.IsZero() {
+		t.Errorf("Unexpected status %+v", status)
+	}
+
+	// A restarted relayer picks up after the verifier's latest header
+	source.mineFrom(t, 124, 5, 1)
+	restarted := newTestRelayer(t, service, source, 10)
+	if err := restarted.relay(); err != nil {
+		t.Fatalf("Relay failed: %v", err)
+	}
+	expectRelayed(t, service, source, 100)
+
+	ranges = relayedRanges(t, service)
+	if len(ranges) != 4 || ranges[3] != [2]int64{125, 129} {
+		t.Fatalf("Expected the restarted relayer to submit only 125-129, got %v", ranges)
+	}
+}
+
+func TestRelayerReportsLagInContractInfo(t *testing.T) {
+	service := newRelayerService(t)
+	source := newFakeHeaders(t, 100, 5, 1)
+
+	relayer := newTestRelayer(t, service, source, 10)
+	service.SetRelayer(relayer)
+	if err := relayer.relay(); err != nil {
+		t.Fatalf("Relay failed: %v", err)
+	}
+
+	// Headers beyond 106 cannot be submitted yet
+	source.mineFrom(t, 104, 5, 1)
+	source.missing = 107
+	if err := relayer.relay(); err == nil {
+		t.Fatal("Expected relaying past a missing header to fail")
+	}
+
+	status, ok := service.GetContractInfo()["relayer"].(RelayerStatus)
+	if !ok {
+		t.Fatalf("Expected relayer status in contract info, got %v", service.GetContractInfo())
+	}
+	if status.BitcoinTip != 109 || status.RelayedHeight != 104 || status.Lag != 5 {
+		t.Errorf("Expected a lag of 5 behind tip 109, got %+v", status)
+	}
+}
+
+func TestRelayerResubmitsCompetingBranch(t *testing.T) {
+	service := newRelayerService(t)
+	source := newFakeHeaders(t, 100, 10, 1)
+
+	relayer := newTestRelayer(t, service, source, 4)
+	if err := relayer.relay(); err != nil {
+		t.Fatalf("Relay failed: %v", err)
+	}
+	expectRelayed(t, service, source, 100)
+
+	// Bitcoin reorganizes onto a longer branch forking after block 105
+	source.mineFrom(t, 105, 6, 2)
+	if err := relayer.relay(); err != nil {
+		t.Fatalf("Relay failed: %v", err)
+	}
+	expectRelayed(t, service, source, 100)
+
+	ranges := relayedRanges(t, service)
+	if last := ranges[len(ranges)-1]; last != [2]int64{110, 111} || ranges[len(ranges)-2] != [2]int64{106, 109} {
+		t.Fatalf("Expected the branch to be resubmitted from 106, got %v", ranges)
+	}
+	if status := relayer.Status(); status.Reorgs != 1 || status.RelayedHeight != 111 {
+		t.Errorf("Unexpected status after reorg %+v", status)
+	}
+}
+
+func TestRelayerHaltsWhenVerifierDivergedBelowHeaders(t *testing.T) {
+	service := newRelayerService(t)
+	source := newFakeHeaders(t, 100, 5, 1)
+
+	relayer := newTestRelayer(t, service, source, 10)
+	if err := relayer.relay(); err != nil {
+		t.Fatalf("Relay failed: %v", err)
+	}
+
+	// A header source that only knows an unrelated branch from 103 up has
+	// no height left to agree with the verifier on
+	other := newFakeHeaders(t, 103, 4, 3)
+	halted := newTestRelayer(t, service, other, 10)
+	if err := halted.relay(); !errors.Is(err, ErrChainDiverged) {
+		t.Fatalf("Expected ErrChainDiverged, got %v", err)
+	}
+	if status := halted.Status(); status.DivergedAt != 102 {
+		t.Errorf("Expected the divergence to be recorded at 102, got %+v", status)
+	}
+
+	// The loop stops instead of extending the unknown branch
+	looping := newTestRelayer(t, service, other, 10)
+	looping.Start()
+	deadline := time.Now().Add(10 * time.Second)
+	for looping.Status().Running {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the relayer to halt")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status := looping.Status(); status.LastError == "" || status.DivergedAt != 102 {
+		t.Errorf("Expected the halt to be reported, got %+v", status)
+	}
+
+	latest, err := service.spvContract.contract.LatestHeight(&bind.CallOpts{})
+	if err != nil || latest.Int64() != 104 {
+		t.Errorf("Expected the verifier to stay at 104, got %v (%v)", latest, err)
+	}
+}
@@ -1,15 +1,18 @@
 package contracts
 
 import (
+	"bytes"
 	"context"
-	"encoding/hex"
 	"fmt"
 	"math/big"
 	"time"
 
+	"bitbridge/internal/ethereum"
 	"bitbridge/internal/proof"
 	"bitbridge/pkg/config"
 
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -17,18 +20,20 @@ import (
 
 // Service manages smart contract interactions
 type Service struct {
-	client           *ethclient.Client
+	client           bind.ContractBackend
 	deployer         *Deployer
 	spvContract      *SPVVerifierContract
 	contractAddress  common.Address
 	config           *config.EthereumConfig
+	relayer          *Relayer
 }
 
 // ServiceConfig for contracts service
 type ServiceConfig struct {
 	EthereumClient  *ethclient.Client
 	EthereumConfig  *config.EthereumConfig
-	ContractAddress string // Optional - if contract is already deployed
+	Nonces          *ethereum.NonceManager // shared sender for the operator key
+	ContractAddress string                 // Optional - if contract is already deployed
 }
 
 // VerificationRequest represents a request to verify a Bitcoin transaction
@@ -62,16 +67,13 @@ type BatchVerificationResponse struct {
 }
 
 func NewService(config ServiceConfig) (*Service, error) {
-	// Create deployer
-	deployer, err := NewDeployer(
-		config.EthereumClient,
-		config.EthereumConfig.PrivateKey,
-		big.NewInt(config.EthereumConfig.ChainID),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create deployer: %w", err)
+	if config.Nonces == nil {
+		return nil, fmt.Errorf("nonce manager is required")
 	}
 
+	// Create deployer
+	deployer := NewDeployer(config.EthereumClient, config.Nonces)
+
 	service := &Service{
 		client:   config.EthereumClient,
 		deployer: deployer,
@@ -148,7 +150,7 @@ func (s *Service) VerifyTransaction(ctx context.Context, req *VerificationReques
 
 	return &VerificationResponse{
 		Verified:        verified,
-		TransactionHash: tx.Hash().Hex(),
+		TransactionHash: receipt.TxHash.Hex(),
 		BlockNumber:     receipt.BlockNumber.Uint64(),
 		GasUsed:         receipt.GasUsed,
 		ContractAddress: s.contractAddress.Hex(),
@@ -205,7 +207,7 @@ func (s *Service) BatchVerifyTransactions(ctx context.Context, req *BatchVerific
 	for i := range req.Requests {
 		results[i] = VerificationResponse{
 			Verified:        receipt.Status == types.ReceiptStatusSuccessful,
-			TransactionHash: tx.Hash().Hex(),
+			TransactionHash: receipt.TxHash.Hex(),
 			BlockNumber:     receipt.BlockNumber.Uint64(),
 			GasUsed:         receipt.GasUsed / uint64(len(req.Requests)), // Approximate
 			ContractAddress: s.contractAddress.Hex(),
@@ -221,36 +223,51 @@ func (s *Service) BatchVerifyTransactions(ctx context.Context, req *BatchVerific
 	}, nil
 }
 
-// IsTransactionVerified checks if a transaction has been verified on-chain
+// IsTransactionVerified checks if a transaction has been verified on-chain;
+// txHash is the txid in display byte order
 func (s *Service) IsTransactionVerified(ctx context.Context, txHash string) (bool, error) {
 	if s.spvContract == nil {
 		return false, fmt.Errorf("contract not deployed or connected")
 	}
 
-	// Convert hex string to bytes32
-	txHashBytes, err := s.hexStringToBytes32(txHash)
+	// The contract keys verified transactions by the internal byte order
+	// txid, while callers pass the display order
+	hash, err := chainhash.NewHashFromStr(txHash)
 	if err != nil {
 		return false, fmt.Errorf("invalid transaction hash: %w", err)
 	}
 
-	return s.spvContract.IsTransactionVerified(ctx, txHashBytes)
+	return s.spvContract.IsTransactionVerified(ctx, *hash)
+}
+
+// SetRelayer attaches a header relayer whose lag is reported with the
+// contract info
+func (s *Service) SetRelayer(relayer *Relayer) {
+	s.relayer = relayer
 }
 
 // GetContractInfo returns information about the deployed contract
 func (s *Service) GetContractInfo() map[string]interface{} {
+	var info map[string]interface{}
 	if s.spvContract == nil {
-		return map[string]interface{}{
+		info = map[string]interface{}{
 			"deployed": false,
 			"address":  nil,
 		}
+	} else {
+		info = map[string]interface{}{
+			"deployed": true,
+			"address":  s.contractAddress.Hex(),
+			"chain_id": s.config.ChainID,
+			"network":  s.getNetworkName(),
+		}
 	}
 
-	return map[string]interface{}{
-		"deployed": true,
-		"address":  s.contractAddress.Hex(),
-		"chain_id": s.config.ChainID,
-		"network":  s.getNetworkName(),
+	if s.relayer != nil {
+		info["relayer"] = s.relayer.Status()
 	}
+
+	return info
 }
 
 // EstimateVerificationGas estimates gas cost for verification
@@ -265,43 +282,23 @@ func (s *Service) EstimateVerificationGas(ctx context.Context, spvProof *proof.S
 
 // convertSPVProofToContractFormat converts internal proof format to contract format
 func (s *Service) convertSPVProofToContractFormat(spvProof *proof.SPVProof) (*ProofData, []byte, error) {
-	// Convert block header to bytes
-	headerBytes, err := hex.DecodeString(spvProof.TransactionHex) // This should be block header hex
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to decode block header: %w", err)
-	}
-
-	// Convert merkle proof
-	var merkleProofBytes [][32]byte
-	for _, proofElement := range spvProof.MerkleProof.Proof {
-		proofBytes, err := hex.DecodeString(proofElement)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to decode proof element: %w", err)
-		}
-		
-		var bytes32 [32]byte
-		if len(proofBytes) != 32 {
-			return nil, nil, fmt.Errorf("invalid proof element length: %d", len(proofBytes))
-		}
-		copy(bytes32[:], proofBytes)
-		merkleProofBytes = append(merkleProofBytes, bytes32)
+	// Serialize the 80-byte block header
+	var headerBuf bytes.Buffer
+	if err := spvProof.BlockHeader.Serialize(&headerBuf); err != nil {
+		return nil, nil, fmt.Errorf("failed to serialize block header: %w", err)
 	}
+	headerBytes := headerBuf.Bytes()
 
-	// Convert transaction hash
-	txHashBytes, err := s.hexStringToBytes32(spvProof.MerkleProof.TxHash)
+	// Convert merkle proof to the internal byte order the contract hashes
+	txHashBytes, merkleRootBytes, merkleProofBytes, err := spvProof.MerkleProof.InternalHashes()
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to convert tx hash: %w", err)
-	}
-
-	// Convert merkle root
-	merkleRootBytes, err := s.hexStringToBytes32(spvProof.MerkleProof.MerkleRoot)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to convert merkle root: %w", err)
+		return nil, nil, fmt.Errorf("failed to convert merkle proof: %w", err)
 	}
 
 	proofData := &ProofData{
 		MerkleProof: merkleProofBytes,
 		Index:       uint32(spvProof.MerkleProof.Index),
+		TotalTxs:    spvProof.MerkleProof.TotalTxs,
 		TxHash:      txHashBytes,
 		MerkleRoot:  merkleRootBytes,
 	}
@@ -309,49 +306,10 @@ func (s *Service) convertSPVProofToContractFormat(spvProof *proof.SPVProof) (*Pr
 	return proofData, headerBytes, nil
 }
 
-// hexStringToBytes32 converts hex string to [32]byte
-func (s *Service) hexStringToBytes32(hexStr string) ([32]byte, error) {
-	var result [32]byte
-	
-	// Remove 0x prefix if present
-	if len(hexStr) > 2 && hexStr[:2] == "0x" {
-		hexStr = hexStr[2:]
-	}
-	
-	bytes, err := hex.DecodeString(hexStr)
-	if err != nil {
-		return result, err
-	}
-	
-	if len(bytes) != 32 {
-		return result, fmt.Errorf("hex string must be exactly 32 bytes, got %d", len(bytes))
-	}
-	
-	copy(result[:], bytes)
-	return result, nil
-}
-
-// waitForTransaction waits for a transaction to be mined
+// waitForTransaction waits for a transaction, or a fee-bumped replacement
+// of it, to be mined
 func (s *Service) waitForTransaction(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
-	for {
-		receipt, err := s.client.TransactionReceipt(ctx, tx.Hash())
-		if err == nil {
-			return receipt, nil
-		}
-		
-		// If error is not "not found", return it
-		if err.Error() != "not found" {
-			return nil, err
-		}
-		
-		// Wait before checking again
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-time.After(2 * time.Second):
-			continue
-		}
-	}
+	return s.deployer.nonces.WaitMined(ctx, tx)
 }
 
 // getNetworkName returns human-readable network name
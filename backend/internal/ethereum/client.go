@@ -2,87 +2,103 @@ package ethereum
 
 import (
 	"context"
-	"crypto/ecdsa"
-	"log"
 	"math/big"
 
+	goethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
+// Backend is the chain access the client needs. Both *ethclient.Client and
+// go-ethereum's simulated backend provide it.
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	goethereum.BlockNumberReader
+	goethereum.FeeHistoryReader
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+}
+
 type Client struct {
-	client     *ethclient.Client
-	privateKey *ecdsa.PrivateKey
-	address    common.Address
-	chainID    *big.Int
+	client  *ethclient.Client
+	backend Backend
+	signer  Signer
+	chainID *big.Int
 }
 
+// Config selects the RPC endpoint and the signer for the operator account.
+// The first configured of RemoteSignerURL, KeystorePath and PrivateKey is
+// used.
 type Config struct {
-	RpcURL     string
-	PrivateKey string
-	ChainID    int64
+	RpcURL               string
+	PrivateKey           string // raw hex key, for development only
+	KeystorePath         string // encrypted JSON keystore file
+	KeystorePasswordFile string
+	RemoteSignerURL      string // JSON-RPC endpoint serving eth_signTransaction
+	SignerAddress        string // account the remote signer signs for, empty for its first
+	ChainID              int64
 }
 
 func NewClient(config Config) (*Client, error) {
-	client, err := ethclient.Dial(config.RpcURL)
+	signer, err := NewSigner(config)
 	if err != nil {
 		return nil, err
 	}
 
-	privateKey, err := crypto.HexToECDSA(config.PrivateKey)
+	client, err := ethclient.Dial(config.RpcURL)
 	if err != nil {
 		return nil, err
 	}
 
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		log.Fatal("error casting public key to ECDSA")
-	}
-
-	address := crypto.PubkeyToAddress(*publicKeyECDSA)
-	chainID := big.NewInt(config.ChainID)
-
 	return &Client{
-		client:     client,
-		privateKey: privateKey,
-		address:    address,
-		chainID:    chainID,
+		client:  client,
+		backend: client,
+		signer:  signer,
+		chainID: big.NewInt(config.ChainID),
 	}, nil
 }
 
+// NewClientWithBackend creates a client on an existing backend, such as a
+// simulated chain. GetClient returns nil for such a client.
+func NewClientWithBackend(backend Backend, signer Signer, chainID *big.Int) *Client {
+	return &Client{
+		backend: backend,
+		signer:  signer,
+		chainID: chainID,
+	}
+}
+
 func (c *Client) GetBalance(ctx context.Context, address common.Address) (*big.Int, error) {
-	return c.client.BalanceAt(ctx, address, nil)
+	return c.backend.BalanceAt(ctx, address, nil)
 }
 
 func (c *Client) GetNonce(ctx context.Context) (uint64, error) {
-	return c.client.PendingNonceAt(ctx, c.address)
+	return c.backend.PendingNonceAt(ctx, c.GetAddress())
 }
 
 func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
-	return c.client.SendTransaction(ctx, tx)
+	return c.backend.SendTransaction(ctx, tx)
 }
 
 func (c *Client) GetTransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
-	return c.client.TransactionReceipt(ctx, txHash)
+	return c.backend.TransactionReceipt(ctx, txHash)
 }
 
 func (c *Client) GetBlockNumber(ctx context.Context) (uint64, error) {
-	return c.client.BlockNumber(ctx)
+	return c.backend.BlockNumber(ctx)
 }
 
 func (c *Client) Close() {
-	c.client.Close()
+	if c.client != nil {
+		c.client.Close()
+	}
 }
 
 func (c *Client) GetAddress() common.Address {
-	return c.address
-}
-
-func (c *Client) GetPrivateKey() *ecdsa.PrivateKey {
-	return c.privateKey
+	return c.signer.Address()
 }
 
 func (c *Client) GetChainID() *big.Int {
@@ -91,4 +107,8 @@ func (c *Client) GetChainID() *big.Int {
 
 func (c *Client) GetClient() *ethclient.Client {
 	return c.client
+}
+
+func (c *Client) GetBackend() Backend {
+	return c.backend
 }
\ No newline at end of file
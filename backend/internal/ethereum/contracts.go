@@ -2,9 +2,15 @@ package ethereum
 
 import (
 	"context"
+	"fmt"
+	"log"
 	"math/big"
 
+	"bitbridge/internal/contracts/bindings"
+
+	goethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
@@ -21,18 +27,21 @@ type UTXORegistryEvent struct {
 	Amount    *big.Int
 	BTCTxHash string
 	Owner     common.Address
-	Timestamp *big.Int
+	Timestamp *big.Int // not part of the event; left nil
 }
 
 // TokenCreatedEvent represents token creation events
 type TokenCreatedEvent struct {
-	UTXOID      string
-	TokenAddr   common.Address
-	Amount      *big.Int
-	Owner       common.Address
-	Timestamp   *big.Int
+	UTXOID    string
+	TokenAddr common.Address
+	Amount    *big.Int
+	Owner     common.Address
+	Timestamp *big.Int // not part of the event; left nil
 }
 
+// contractNames lists the contracts CallContract can resolve methods in
+var contractNames = []string{"UTXORegistry", "UTXOToken", "SPVVerifier"}
+
 func NewContractManager(client *Client, service *Service) *ContractManager {
 	return &ContractManager{
 		client:  client,
@@ -42,60 +51,184 @@ func NewContractManager(client *Client, service *Service) *ContractManager {
 
 // DeployUTXORegistry deploys the UTXO Registry smart contract
 func (cm *ContractManager) DeployUTXORegistry(ctx context.Context) (common.Address, *types.Transaction, error) {
-	// This would deploy the UTXORegistry contract
-	// For now, return placeholder values
-	// In real implementation, this would use the contract bytecode and ABI
-	
-	return common.Address{}, nil, nil
+	var address common.Address
+	tx, err := cm.service.nonces.Transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		deployed, tx, _, err := bindings.DeployUTXORegistry(auth, cm.client.GetBackend())
+		address = deployed
+		return tx, err
+	})
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("failed to deploy UTXORegistry: %w", err)
+	}
+
+	return address, tx, nil
 }
 
 // DeployTokenFactory deploys the Token Factory smart contract
 func (cm *ContractManager) DeployTokenFactory(ctx context.Context, utxoRegistryAddr common.Address) (common.Address, *types.Transaction, error) {
-	// This would deploy the TokenFactory contract
-	// For now, return placeholder values
-	
-	return common.Address{}, nil, nil
+	// UTXORegistry.registerUTXO deploys each UTXOToken itself
+	return common.Address{}, nil, fmt.Errorf("there is no token factory contract; tokens are created by the UTXORegistry at %s", utxoRegistryAddr.Hex())
 }
 
 // ParseUTXORegistryEvent parses events from UTXO Registry contract
 func (cm *ContractManager) ParseUTXORegistryEvent(log types.Log) (*UTXORegistryEvent, error) {
-	// This would parse contract events using ABI
-	// For now, return placeholder event
-	
-	return &UTXORegistryEvent{}, nil
+	event, err := parseUTXORegistered(log)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UTXORegistryEvent{
+		UTXOID:    fmt.Sprintf("%s:%d", event.BitcoinTxId, event.BitcoinVout),
+		Amount:    event.BitcoinAmount,
+		BTCTxHash: event.BitcoinTxId,
+		Owner:     event.TokenOwner,
+	}, nil
 }
 
 // ParseTokenCreatedEvent parses token creation events
 func (cm *ContractManager) ParseTokenCreatedEvent(log types.Log) (*TokenCreatedEvent, error) {
-	// This would parse contract events using ABI
-	// For now, return placeholder event
-	
-	return &TokenCreatedEvent{}, nil
+	// Tokens are created by the registry, which announces them in its
+	// UTXORegistered event
+	event, err := parseUTXORegistered(log)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenCreatedEvent{
+		UTXOID:    fmt.Sprintf("%s:%d", event.BitcoinTxId, event.BitcoinVout),
+		TokenAddr: event.TokenAddress,
+		Amount:    event.BitcoinAmount,
+		Owner:     event.TokenOwner,
+	}, nil
 }
 
 // GetContractABI returns the ABI for a given contract name
 func (cm *ContractManager) GetContractABI(contractName string) (*abi.ABI, error) {
-	// This would return the actual contract ABI
-	// For now, return nil
-	// In real implementation, ABIs would be embedded or loaded from files
-	
-	return nil, nil
+	artifact, err := bindings.LoadArtifact(contractName)
+	if err != nil {
+		return nil, err
+	}
+
+	return artifact.ParsedABI()
 }
 
-// CallContract makes a call to a smart contract
+// CallContract makes a call to a smart contract. The registry is recognized
+// by its address; any other contract is resolved by the first ABI that
+// declares the method.
 func (cm *ContractManager) CallContract(ctx context.Context, contractAddr common.Address, methodName string, args ...interface{}) ([]interface{}, error) {
-	// This would make an actual contract call
-	// For now, return empty results
-	
-	return nil, nil
+	contractABI, err := cm.abiFor(contractAddr, methodName)
+	if err != nil {
+		return nil, err
+	}
+
+	backend := cm.client.GetBackend()
+	contract := bind.NewBoundContract(contractAddr, *contractABI, backend, backend, backend)
+
+	var result []interface{}
+	if err := contract.Call(&bind.CallOpts{Context: ctx}, &result, methodName, args...); err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", methodName, err)
+	}
+
+	return result, nil
+}
+
+func (cm *ContractManager) abiFor(contractAddr common.Address, methodName string) (*abi.ABI, error) {
+	if cm.service != nil && contractAddr == cm.service.utxoRegistryAddr {
+		return cm.GetContractABI("UTXORegistry")
+	}
+
+	for _, name := range contractNames {
+		contractABI, err := cm.GetContractABI(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := contractABI.Methods[methodName]; ok {
+			return contractABI, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no known contract has method %s", methodName)
 }
 
-// WatchContractEvents sets up event watching for contract events
+func parseUTXORegistered(log types.Log) (*bindings.UTXORegistryUTXORegistered, error) {
+	filterer, err := bindings.NewUTXORegistryFilterer(log.Address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := filterer.ParseUTXORegistered(log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse UTXORegistered event: %w", err)
+	}
+	return event, nil
+}
+
+// WatchContractEvents streams new logs of the named event from a contract.
+// It needs a backend that supports subscriptions, such as a websocket RPC
+// endpoint. The channel is closed when ctx is done or the subscription
+// fails.
 func (cm *ContractManager) WatchContractEvents(ctx context.Context, contractAddr common.Address, eventName string) (chan types.Log, error) {
-	// This would set up event filtering and watching
-	// For now, return a closed channel
-	
-	ch := make(chan types.Log)
-	close(ch)
-	return ch, nil
-}
\ No newline at end of file
+	event, err := cm.eventFor(contractAddr, eventName)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := make(chan types.Log)
+	sub, err := cm.client.GetBackend().SubscribeFilterLogs(ctx, goethereum.FilterQuery{
+		Addresses: []common.Address{contractAddr},
+		Topics:    [][]common.Hash{{event.ID}},
+	}, logs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to %s logs: %w", eventName, err)
+	}
+
+	out := make(chan types.Log)
+	go func() {
+		defer close(out)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case vLog := <-logs:
+				select {
+				case out <- vLog:
+				case <-ctx.Done():
+					return
+				}
+			case err := <-sub.Err():
+				if err != nil {
+					log.Printf("%s subscription failed: %v", eventName, err)
+				}
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (cm *ContractManager) eventFor(contractAddr common.Address, eventName string) (*abi.Event, error) {
+	if cm.service != nil && contractAddr == cm.service.utxoRegistryAddr {
+		registryABI, err := cm.GetContractABI("UTXORegistry")
+		if err != nil {
+			return nil, err
+		}
+		if event, ok := registryABI.Events[eventName]; ok {
+			return &event, nil
+		}
+	}
+
+	for _, name := range contractNames {
+		contractABI, err := cm.GetContractABI(name)
+		if err != nil {
+			return nil, err
+		}
+		if event, ok := contractABI.Events[eventName]; ok {
+			return &event, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no known contract has event %s", eventName)
+}
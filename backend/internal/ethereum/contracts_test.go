@@ -0,0 +1,116 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestParseUTXORegisteredLog(t *testing.T) {
+	cm := &ContractManager{}
+	registryABI, err := cm.GetContractABI("UTXORegistry")
+	if err != nil {
+		t.Fatalf("Failed to load UTXORegistry ABI: %v", err)
+	}
+
+	event := registryABI.Events["UTXORegistered"]
+	txid := "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"
+	tokenAddr := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+	data, err := event.Inputs.NonIndexed().Pack(txid, uint32(1), big.NewInt(1000000000), tokenAddr, owner)
+	if err != nil {
+		t.Fatalf("Failed to pack event data: %v", err)
+	}
+	log := types.Log{
+		Topics: []common.Hash{event.ID, common.HexToHash("0x01")},
+		Data:   data,
+	}
+
+	registered, err := cm.ParseUTXORegistryEvent(log)
+	if err != nil {
+		t.Fatalf("Failed to parse registry event: %v", err)
+	}
+	if registered.UTXOID != txid+":1" || registered.BTCTxHash != txid || registered.Owner != owner {
+		t.Errorf("Unexpected registry event %+v", registered)
+	}
+	if registered.Amount.Cmp(big.NewInt(1000000000)) != 0 {
+		t.Errorf("Expected amount 1000000000, got %s", registered.Amount)
+	}
+
+	created, err := cm.ParseTokenCreatedEvent(log)
+	if err != nil {
+		t.Fatalf("Failed to parse token event: %v", err)
+	}
+	if created.TokenAddr != tokenAddr {
+		t.Errorf("Expected token %s, got %s", tokenAddr.Hex(), created.TokenAddr.Hex())
+	}
+
+	log.Topics[0] = common.HexToHash("0x02")
+	if _, err := cm.ParseUTXORegistryEvent(log); err == nil {
+		t.Error("Expected log with another event signature to be rejected")
+	}
+}
+
+func TestCallContractResolvesMethod(t *testing.T) {
+	cm := &ContractManager{}
+	for method, contract := range map[string]string{
+		"getUTXOCount":          "UTXORegistry",
+		"bitcoinTxId":           "UTXOToken",
+		"isTransactionVerified": "SPVVerifier",
+	} {
+		resolved, err := cm.abiFor(common.Address{}, method)
+		if err != nil {
+			t.Fatalf("Failed to resolve %s: %v", method, err)
+		}
+		expected, _ := cm.GetContractABI(contract)
+		if _, ok := expected.Methods[method]; !ok || len(resolved.Methods) != len(expected.Methods) {
+			t.Errorf("Expected %s to resolve to %s", method, contract)
+		}
+	}
+
+	if _, err := cm.abiFor(common.Address{}, "noSuchMethod"); err == nil {
+		t.Error("Expected error for unknown method")
+	}
+}
+
+func TestWatchContractEventsOnSimulatedChain(t *testing.T) {
+	chain := newSimulatedChain(t)
+	client := chain.client(chain.operator)
+	service := chain.service(chain.operator)
+	cm := NewContractManager(client, service)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	logs, err := cm.WatchContractEvents(ctx, chain.registry, "UTXORegistered")
+	if err != nil {
+		t.Fatalf("Failed to watch events: %v", err)
+	}
+
+	tokenAddr, _, err := service.RegisterUTXO(ctx, testTxID, 0, big.NewInt(1000), "12cbQLTFMXRnSzktFkuoG3eHoMeFtpTu3S", chain.registry)
+	if err != nil {
+		t.Fatalf("Failed to register UTXO: %v", err)
+	}
+
+	select {
+	case vLog := <-logs:
+		created, err := cm.ParseTokenCreatedEvent(vLog)
+		if err != nil {
+			t.Fatalf("Failed to parse watched log: %v", err)
+		}
+		if created.TokenAddr != tokenAddr {
+			t.Errorf("Expected token %s, got %s", tokenAddr.Hex(), created.TokenAddr.Hex())
+		}
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for UTXORegistered log")
+	}
+
+	cancel()
+	for range logs {
+	}
+}
@@ -0,0 +1,89 @@
+package ethereum
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Revert reasons raised by the UTXORegistry
+var (
+	ErrNotOperator           = errors.New("not authorized operator")
+	ErrUTXOAlreadyRegistered = errors.New("UTXO already registered")
+	ErrUTXONotActive         = errors.New("UTXO not active")
+)
+
+// ErrNotSupported is returned by operations the deployed contracts do not
+// offer
+var ErrNotSupported = errors.New("not supported")
+
+var revertReasons = map[string]error{
+	"Not authorized operator": ErrNotOperator,
+	"UTXO already registered": ErrUTXOAlreadyRegistered,
+	"UTXO not active":         ErrUTXONotActive,
+}
+
+// RevertError is returned for a mined transaction that reverted, or with a
+// zero TxHash for one that was not sent because gas estimation reverted.
+// Reason is the decoded revert string or custom error name, empty if the
+// node did not return revert data.
+type RevertError struct {
+	TxHash common.Hash
+	Reason string
+}
+
+func (e *RevertError) Error() string {
+	if e.TxHash == (common.Hash{}) {
+		return fmt.Sprintf("transaction would revert: %s", e.Reason)
+	}
+	if e.Reason == "" {
+		return fmt.Sprintf("transaction %s reverted", e.TxHash.Hex())
+	}
+	return fmt.Sprintf("transaction %s reverted: %s", e.TxHash.Hex(), e.Reason)
+}
+
+// Unwrap maps known revert reasons to their sentinel errors
+func (e *RevertError) Unwrap() error {
+	return revertReasons[e.Reason]
+}
+
+// revertData extracts the revert data carried by a failed eth_call
+func revertData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+
+	encoded, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+
+	data, err := hexutil.Decode(encoded)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// revertReason decodes revert data into an Error(string) message, a panic
+// description or the name of a custom error declared by the registry or
+// token contracts
+func revertReason(data []byte) string {
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason
+	}
+
+	if len(data) >= 4 {
+		for _, contractABI := range []abi.ABI{registryABI, tokenABI} {
+			if abiErr, err := contractABI.ErrorByID([4]byte(data[:4])); err == nil {
+				return abiErr.Name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,130 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	goethereum "github.com/ethereum/go-ethereum"
+)
+
+// FeeSource is the chain access the fee oracle needs; Backend satisfies it
+type FeeSource interface {
+	goethereum.FeeHistoryReader
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+}
+
+// FeeOracleConfig for the EIP-1559 fee oracle
+type FeeOracleConfig struct {
+	Source            FeeSource
+	HistoryBlocks     uint64   // blocks of fee history to sample
+	TipPercentile     float64  // priority fee percentile paid within each block
+	BaseFeeMultiplier int64    // headroom for base fee increases before inclusion
+	MaxFeeCap         *big.Int // nil for no cap
+	MaxTipCap         *big.Int // nil for no cap
+}
+
+// FeeSuggestion is a dynamic fee for the next block
+type FeeSuggestion struct {
+	BaseFee   *big.Int `json:"base_fee"`
+	GasTipCap *big.Int `json:"max_priority_fee_per_gas"`
+	GasFeeCap *big.Int `json:"max_fee_per_gas"`
+}
+
+// FeeOracle suggests EIP-1559 fees from eth_feeHistory. The priority fee is
+// the median over recent blocks of the configured reward percentile and the
+// fee cap leaves room for the base fee to grow over several blocks.
+type FeeOracle struct {
+	source            FeeSource
+	historyBlocks     uint64
+	tipPercentile     float64
+	baseFeeMultiplier int64
+	maxFeeCap         *big.Int
+	maxTipCap         *big.Int
+}
+
+// NewFeeOracle creates a fee oracle
+func NewFeeOracle(config FeeOracleConfig) *FeeOracle {
+	if config.HistoryBlocks == 0 {
+		config.HistoryBlocks = 20
+	}
+	if config.TipPercentile <= 0 || config.TipPercentile > 100 {
+		config.TipPercentile = 50
+	}
+	if config.BaseFeeMultiplier <= 0 {
+		config.BaseFeeMultiplier = 2
+	}
+
+	return &FeeOracle{
+		source:            config.Source,
+		historyBlocks:     config.HistoryBlocks,
+		tipPercentile:     config.TipPercentile,
+		baseFeeMultiplier: config.BaseFeeMultiplier,
+		maxFeeCap:         config.MaxFeeCap,
+		maxTipCap:         config.MaxTipCap,
+	}
+}
+
+// SuggestFees returns the fees for a transaction to be included soon
+func (o *FeeOracle) SuggestFees(ctx context.Context) (*FeeSuggestion, error) {
+	history, err := o.source.FeeHistory(ctx, o.historyBlocks, nil, []float64{o.tipPercentile})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee history: %w", err)
+	}
+	if len(history.BaseFee) == 0 {
+		return nil, fmt.Errorf("fee history returned no base fee")
+	}
+
+	// The last base fee is the one of the next block
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+
+	tip := medianReward(history.Reward)
+	if tip.Sign() == 0 {
+		// Empty blocks report no rewards; ask the node instead
+		if tip, err = o.source.SuggestGasTipCap(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get priority fee: %w", err)
+		}
+	}
+
+	feeCap := new(big.Int).Mul(baseFee, big.NewInt(o.baseFeeMultiplier))
+	feeCap.Add(feeCap, tip)
+	tip, feeCap = o.capFees(tip, feeCap)
+
+	return &FeeSuggestion{
+		BaseFee:   new(big.Int).Set(baseFee),
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+	}, nil
+}
+
+// capFees limits fees to the configured caps
+func (o *FeeOracle) capFees(tip, feeCap *big.Int) (*big.Int, *big.Int) {
+	if o.maxTipCap != nil && tip.Cmp(o.maxTipCap) > 0 {
+		tip = new(big.Int).Set(o.maxTipCap)
+	}
+	if o.maxFeeCap != nil && feeCap.Cmp(o.maxFeeCap) > 0 {
+		feeCap = new(big.Int).Set(o.maxFeeCap)
+	}
+	if tip.Cmp(feeCap) > 0 {
+		tip = new(big.Int).Set(feeCap)
+	}
+	return tip, feeCap
+}
+
+// medianReward returns the median of the single sampled reward percentile
+// over the history's blocks
+func medianReward(rewards [][]*big.Int) *big.Int {
+	samples := make([]*big.Int, 0, len(rewards))
+	for _, reward := range rewards {
+		if len(reward) > 0 && reward[0] != nil {
+			samples = append(samples, reward[0])
+		}
+	}
+	if len(samples) == 0 {
+		return new(big.Int)
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Cmp(samples[j]) < 0 })
+	return new(big.Int).Set(samples[len(samples)/2])
+}
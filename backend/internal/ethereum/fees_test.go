@@ -0,0 +1,77 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	goethereum "github.com/ethereum/go-ethereum"
+)
+
+// staticFees serves a fixed fee history
+type staticFees struct {
+	history *goethereum.FeeHistory
+	tip     *big.Int
+}
+
+func (f *staticFees) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*goethereum.FeeHistory, error) {
+	return f.history, nil
+}
+
+func (f *staticFees) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return f.tip, nil
+}
+
+func gwei(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e9))
+}
+
+func TestFeeOracleSuggestsFromHistory(t *testing.T) {
+	source := &staticFees{
+		history: &goethereum.FeeHistory{
+			Reward:  [][]*big.Int{{gwei(1)}, {gwei(5)}, {gwei(2)}},
+			BaseFee: []*big.Int{gwei(8), gwei(9), gwei(10), gwei(11)},
+		},
+		tip: gwei(7),
+	}
+
+	fees, err := NewFeeOracle(FeeOracleConfig{Source: source}).SuggestFees(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to suggest fees: %v", err)
+	}
+
+	// Median tip of 2 gwei on top of twice the next block's 11 gwei base fee
+	if fees.BaseFee.Cmp(gwei(11)) != 0 || fees.GasTipCap.Cmp(gwei(2)) != 0 || fees.GasFeeCap.Cmp(gwei(24)) != 0 {
+		t.Errorf("Unexpected suggestion: base %s tip %s fee cap %s", fees.BaseFee, fees.GasTipCap, fees.GasFeeCap)
+	}
+
+	capped, err := NewFeeOracle(FeeOracleConfig{
+		Source:    source,
+		MaxFeeCap: gwei(20),
+		MaxTipCap: gwei(1),
+	}).SuggestFees(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to suggest fees: %v", err)
+	}
+	if capped.GasTipCap.Cmp(gwei(1)) != 0 || capped.GasFeeCap.Cmp(gwei(20)) != 0 {
+		t.Errorf("Expected caps to apply, got tip %s fee cap %s", capped.GasTipCap, capped.GasFeeCap)
+	}
+}
+
+func TestFeeOracleFallsBackOnEmptyBlocks(t *testing.T) {
+	source := &staticFees{
+		history: &goethereum.FeeHistory{
+			Reward:  [][]*big.Int{{big.NewInt(0)}, {big.NewInt(0)}},
+			BaseFee: []*big.Int{gwei(1), gwei(1), gwei(1)},
+		},
+		tip: gwei(3),
+	}
+
+	fees, err := NewFeeOracle(FeeOracleConfig{Source: source}).SuggestFees(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to suggest fees: %v", err)
+	}
+	if fees.GasTipCap.Cmp(gwei(3)) != 0 || fees.GasFeeCap.Cmp(gwei(5)) != 0 {
+		t.Errorf("Expected the node's 3 gwei tip, got tip %s fee cap %s", fees.GasTipCap, fees.GasFeeCap)
+	}
+}
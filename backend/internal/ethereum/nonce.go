@@ -0,0 +1,594 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"bitbridge/internal/store"
+
+	goethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const pendingTxsKey = "nonce_manager_pending"
+
+// ErrTransactionDropped is returned when a transaction's nonce was consumed
+// on chain by a transaction the nonce manager did not send
+var ErrTransactionDropped = errors.New("transaction dropped")
+
+// TransactFunc builds and signs one transaction with the options the nonce
+// manager prepared and returns it unsent. Contract bindings can be called
+// with the options as is. A GasLimit left at zero is estimated by the
+// binding and then raised by the manager's safety multiplier.
+type TransactFunc func(auth *bind.TransactOpts) (*types.Transaction, error)
+
+// NonceManagerConfig for the operator key's nonce manager
+type NonceManagerConfig struct {
+	Client             *Client
+	Store              store.StateRepository // optional, keeps tracked transactions across restarts
+	Fees               *FeeOracle            // nil for an oracle with default settings
+	GasLimitMultiplier float64               // applied to gas estimates
+	PollInterval       time.Duration
+	ReplaceAfter       time.Duration // time a transaction may stay pending before its fee is bumped
+	FeeBumpPercent     int           // nodes reject replacements bumped by less than 10%
+}
+
+// trackedTx is a sent transaction that is not yet mined or dropped
+type trackedTx struct {
+	Raw    []byte        `json:"raw"`    // latest signed version
+	Hashes []common.Hash `json:"hashes"` // every version broadcast, oldest first
+	SentAt time.Time     `json:"sent_at"`
+	Bumps  int           `json:"bumps"`
+	tx     *types.Transaction
+}
+
+// NonceManager is the single sender for the operator key. It hands out
+// nonces sequentially, so concurrent callers never reuse one, signs
+// EIP-1559 transactions with the fee oracle's suggestion and tracks every
+// transaction it sent until the nonce is confirmed. Transactions pending for
+// longer than ReplaceAfter are re-signed with higher fees. The next nonce is
+// recovered from the chain on first use and after any failed send.
+type NonceManager struct {
+	client             *Client
+	store              store.StateRepository
+	fees               *FeeOracle
+	gasLimitMultiplier float64
+	pollInterval       time.Duration
+	replaceAfter       time.Duration
+	feeBumpPercent     int
+	next               uint64
+	synced             bool
+	loaded             bool
+	pending            map[uint64]*trackedTx
+	mu                 sync.Mutex
+	ctx                context.Context
+	cancel             context.CancelFunc
+}
+
+// NewNonceManager creates a nonce manager for the client's key
+func NewNonceManager(config NonceManagerConfig) *NonceManager {
+	if config.PollInterval <= 0 {
+		config.PollInterval = 15 * time.Second
+	}
+	if config.ReplaceAfter <= 0 {
+		config.ReplaceAfter = 3 * time.Minute
+	}
+	if config.FeeBumpPercent < 10 {
+		config.FeeBumpPercent = 20
+	}
+	if config.GasLimitMultiplier < 1 {
+		config.GasLimitMultiplier = 1.2
+	}
+	if config.Fees == nil {
+		config.Fees = NewFeeOracle(FeeOracleConfig{Source: config.Client.GetBackend()})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &NonceManager{
+		client:             config.Client,
+		store:              config.Store,
+		fees:               config.Fees,
+		gasLimitMultiplier: config.GasLimitMultiplier,
+		pollInterval:       config.PollInterval,
+		replaceAfter:       config.ReplaceAfter,
+		feeBumpPercent:     config.FeeBumpPercent,
+		pending:            make(map[uint64]*trackedTx),
+		ctx:                ctx,
+		cancel:             cancel,
+	}
+}
+
+// Start begins monitoring sent transactions in the background
+func (m *NonceManager) Start() {
+	log.Println("Starting nonce manager...")
+	go m.pollLoop()
+}
+
+// Stop halts background monitoring
+func (m *NonceManager) Stop() {
+	log.Println("Stopping nonce manager...")
+	m.cancel()
+}
+
+// Address returns the account the manager sends from
+func (m *NonceManager) Address() common.Address {
+	return m.client.GetAddress()
+}
+
+// Transact reserves the next nonce, calls fn with transaction options
+// carrying it and the oracle's fees, and sends the transaction fn built.
+// Calls are serialized; the nonce is only consumed if the send succeeds. A
+// call that would revert fails with a *RevertError.
+func (m *NonceManager) Transact(ctx context.Context, fn TransactFunc) (*types.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.syncLocked(ctx); err != nil {
+		return nil, err
+	}
+
+	fees, err := m.fees.SuggestFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	signer := m.client.signer
+	auth := &bind.TransactOpts{
+		From: signer.Address(),
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != signer.Address() {
+				return nil, bind.ErrNotAuthorized
+			}
+			return signer.SignTx(ctx, tx, m.client.GetChainID())
+		},
+		Context:   ctx,
+		Nonce:     new(big.Int).SetUint64(m.next),
+		Value:     big.NewInt(0),
+		GasTipCap: fees.GasTipCap,
+		GasFeeCap: fees.GasFeeCap,
+		NoSend:    true,
+	}
+
+	tx, err := fn(auth)
+	if err != nil {
+		if data, ok := revertData(err); ok {
+			return nil, &RevertError{Reason: revertReason(data)}
+		}
+		return nil, err
+	}
+
+	if auth.GasLimit == 0 {
+		tx, err = m.resign(ctx, tx, tx.GasTipCap(), tx.GasFeeCap(), m.withMargin(tx.Gas()))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.client.GetBackend().SendTransaction(ctx, tx); err != nil {
+		// The node may know of a nonce this manager does not, such as one
+		// used by another process; re-read it before the next send
+		m.synced = false
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	m.pending[tx.Nonce()] = &trackedTx{
+		Raw:    raw,
+		Hashes: []common.Hash{tx.Hash()},
+		SentAt: time.Now(),
+		tx:     tx,
+	}
+	m.next = tx.Nonce() + 1
+
+	if err := m.persistLocked(); err != nil {
+		log.Printf("Warning: Failed to persist pending transactions: %v", err)
+	}
+
+	return tx, nil
+}
+
+// SendTransaction signs and sends a dynamic-fee transaction with the next
+// nonce. A zero gasLimit is estimated.
+func (m *NonceManager) SendTransaction(ctx context.Context, to common.Address, value *big.Int, gasLimit uint64, data []byte) (*types.Transaction, error) {
+	return m.Transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		if gasLimit == 0 {
+			estimate, err := m.EstimateGas(ctx, goethereum.CallMsg{
+				From:      auth.From,
+				To:        &to,
+				GasTipCap: auth.GasTipCap,
+				GasFeeCap: auth.GasFeeCap,
+				Value:     value,
+				Data:      data,
+			})
+			if err != nil {
+				return nil, err
+			}
+			gasLimit = estimate
+		}
+		auth.GasLimit = gasLimit
+
+		tx, err := auth.Signer(auth.From, types.NewTx(&types.DynamicFeeTx{
+			ChainID:   m.client.GetChainID(),
+			Nonce:     auth.Nonce.Uint64(),
+			GasTipCap: auth.GasTipCap,
+			GasFeeCap: auth.GasFeeCap,
+			Gas:       gasLimit,
+			To:        &to,
+			Value:     value,
+			Data:      data,
+		}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign transaction: %w", err)
+		}
+		return tx, nil
+	})
+}
+
+// EstimateGas estimates the gas a call needs, raised by the safety
+// multiplier
+func (m *NonceManager) EstimateGas(ctx context.Context, msg goethereum.CallMsg) (uint64, error) {
+	gas, err := m.client.GetBackend().EstimateGas(ctx, msg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	return m.withMargin(gas), nil
+}
+
+// SuggestFees returns the fees the next transaction would be sent with
+func (m *NonceManager) SuggestFees(ctx context.Context) (*FeeSuggestion, error) {
+	return m.fees.SuggestFees(ctx)
+}
+
+// WaitMined blocks until a transaction, or a fee-bumped replacement of it,
+// is mined and returns its receipt. It fails with ErrTransactionDropped if
+// the nonce is confirmed by a transaction this manager did not send.
+func (m *NonceManager) WaitMined(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	backend := m.client.GetBackend()
+	hashes := []common.Hash{tx.Hash()}
+	unmatched := 0
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		hashes = appendNew(hashes, m.versions(tx.Nonce()))
+
+		confirmed, err := backend.NonceAt(ctx, m.Address(), nil)
+		if err == nil {
+			for _, hash := range hashes {
+				if receipt, err := backend.TransactionReceipt(ctx, hash); err == nil {
+					return receipt, nil
+				}
+			}
+
+			// Give the node a second poll to index the receipt before
+			// concluding another transaction took the nonce
+			if confirmed > tx.Nonce() {
+				unmatched++
+				if unmatched > 1 {
+					return nil, fmt.Errorf("%w: nonce %d of %s was used by another transaction", ErrTransactionDropped, tx.Nonce(), tx.Hash().Hex())
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// TransactionReceipt returns the receipt of a transaction this manager
+// sent, or of a fee-bumped replacement of it, without waiting. The receipt
+// is nil while the transaction is pending. It fails with
+// ErrTransactionDropped once the transaction is neither mined nor tracked.
+func (m *NonceManager) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	m.mu.Lock()
+	if err := m.loadLocked(); err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	hashes := []common.Hash{hash}
+	tracked := false
+	for _, pending := range m.pending {
+		for _, h := range pending.Hashes {
+			if h == hash {
+				hashes = appendNew(hashes, pending.Hashes)
+				tracked = true
+				break
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	// Nodes may fail lookups while they index, which only matters once
+	// the transaction is no longer tracked
+	var lookupErr error
+	for _, h := range hashes {
+		receipt, err := m.client.GetBackend().TransactionReceipt(ctx, h)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, goethereum.NotFound) {
+			lookupErr = err
+		}
+	}
+
+	if tracked {
+		return nil, nil
+	}
+	if lookupErr != nil {
+		return nil, fmt.Errorf("failed to get receipt: %w", lookupErr)
+	}
+	return nil, fmt.Errorf("%w: %s is neither mined nor pending", ErrTransactionDropped, hash.Hex())
+}
+
+// PendingCount returns the number of sent transactions not yet confirmed
+func (m *NonceManager) PendingCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.pending)
+}
+
+func (m *NonceManager) pollLoop() {
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(m.ctx, time.Minute)
+		if err := m.check(ctx); err != nil {
+			log.Printf("Nonce manager check failed: %v", err)
+		}
+		cancel()
+
+		select {
+		case <-m.ctx.Done():
+			log.Println("Nonce manager stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check forgets transactions whose nonce is confirmed and bumps the fee of
+// those pending for longer than replaceAfter
+func (m *NonceManager) check(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.loadLocked(); err != nil {
+		return err
+	}
+
+	backend := m.client.GetBackend()
+	confirmed, err := backend.NonceAt(ctx, m.Address(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to get confirmed nonce: %w", err)
+	}
+
+	changed := false
+	for nonce, tracked := range m.pending {
+		if nonce < confirmed {
+			if !m.minedLocked(ctx, tracked) {
+				log.Printf("Transaction %s dropped: nonce %d was used by another transaction", tracked.Hashes[0].Hex(), nonce)
+			}
+			delete(m.pending, nonce)
+			changed = true
+			continue
+		}
+
+		if time.Since(tracked.SentAt) < m.replaceAfter {
+			continue
+		}
+		if err := m.bumpLocked(ctx, tracked); err != nil {
+			log.Printf("Failed to replace stuck transaction %s: %v", tracked.tx.Hash().Hex(), err)
+			continue
+		}
+		changed = true
+	}
+
+	if changed {
+		return m.persistLocked()
+	}
+	return nil
+}
+
+// minedLocked reports whether any version of a transaction has a receipt
+func (m *NonceManager) minedLocked(ctx context.Context, tracked *trackedTx) bool {
+	for _, hash := range tracked.Hashes {
+		if _, err := m.client.GetBackend().TransactionReceipt(ctx, hash); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// bumpLocked re-signs a stuck transaction with the same nonce and higher
+// fees and broadcasts it. At the fee caps the transaction is rebroadcast
+// unchanged, in case the node evicted it.
+func (m *NonceManager) bumpLocked(ctx context.Context, tracked *trackedTx) error {
+	tx := tracked.tx
+	tip := bumpFee(tx.GasTipCap(), m.feeBumpPercent)
+	feeCap := bumpFee(tx.GasFeeCap(), m.feeBumpPercent)
+
+	if fees, err := m.fees.SuggestFees(ctx); err == nil {
+		if fees.GasTipCap.Cmp(tip) > 0 {
+			tip = fees.GasTipCap
+		}
+		if fees.GasFeeCap.Cmp(feeCap) > 0 {
+			feeCap = fees.GasFeeCap
+		}
+	}
+	tip, feeCap = m.fees.capFees(tip, feeCap)
+
+	if tip.Cmp(tx.GasTipCap()) <= 0 || feeCap.Cmp(tx.GasFeeCap()) <= 0 {
+		tracked.SentAt = time.Now()
+		return m.client.GetBackend().SendTransaction(ctx, tx)
+	}
+
+	replacement, err := m.resign(ctx, tx, tip, feeCap, tx.Gas())
+	if err != nil {
+		return err
+	}
+	if err := m.client.GetBackend().SendTransaction(ctx, replacement); err != nil {
+		return fmt.Errorf("failed to send replacement: %w", err)
+	}
+
+	raw, err := replacement.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to encode replacement: %w", err)
+	}
+	log.Printf("Replaced stuck transaction %s with %s at max fee %s, priority fee %s", tx.Hash().Hex(), replacement.Hash().Hex(), feeCap, tip)
+
+	tracked.Raw = raw
+	tracked.Hashes = append(tracked.Hashes, replacement.Hash())
+	tracked.SentAt = time.Now()
+	tracked.Bumps++
+	tracked.tx = replacement
+	return nil
+}
+
+// resign signs a dynamic-fee copy of tx with the given fees and gas limit
+func (m *NonceManager) resign(ctx context.Context, tx *types.Transaction, tip, feeCap *big.Int, gas uint64) (*types.Transaction, error) {
+	signed, err := m.client.signer.SignTx(ctx, types.NewTx(&types.DynamicFeeTx{
+		ChainID:    m.client.GetChainID(),
+		Nonce:      tx.Nonce(),
+		GasTipCap:  tip,
+		GasFeeCap:  feeCap,
+		Gas:        gas,
+		To:         tx.To(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	}), m.client.GetChainID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return signed, nil
+}
+
+func (m *NonceManager) withMargin(gas uint64) uint64 {
+	return uint64(float64(gas) * m.gasLimitMultiplier)
+}
+
+// syncLocked loads the tracked transactions and, when the next nonce is not
+// known, reads it from the node. Transactions the node no longer has in
+// its pool are still tracked, so the higher of the two is used.
+func (m *NonceManager) syncLocked(ctx context.Context) error {
+	if err := m.loadLocked(); err != nil {
+		return err
+	}
+	if m.synced {
+		return nil
+	}
+
+	next, err := m.client.GetBackend().PendingNonceAt(ctx, m.Address())
+	if err != nil {
+		return fmt.Errorf("failed to get nonce: %w", err)
+	}
+	for nonce := range m.pending {
+		if nonce >= next {
+			next = nonce + 1
+		}
+	}
+
+	m.next = next
+	m.synced = true
+	return nil
+}
+
+// loadLocked restores the transactions tracked before a restart
+func (m *NonceManager) loadLocked() error {
+	if m.loaded || m.store == nil {
+		return nil
+	}
+
+	data, err := m.store.GetState(pendingTxsKey)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return fmt.Errorf("failed to load pending transactions: %w", err)
+	}
+
+	if data != nil {
+		var tracked []*trackedTx
+		if err := json.Unmarshal(data, &tracked); err != nil {
+			return fmt.Errorf("failed to decode pending transactions: %w", err)
+		}
+		for _, t := range tracked {
+			t.tx = new(types.Transaction)
+			if err := t.tx.UnmarshalBinary(t.Raw); err != nil {
+				return fmt.Errorf("failed to decode pending transaction: %w", err)
+			}
+			m.pending[t.tx.Nonce()] = t
+		}
+		if len(tracked) > 0 {
+			log.Printf("Resumed tracking %d pending transactions", len(tracked))
+		}
+	}
+
+	m.loaded = true
+	return nil
+}
+
+func (m *NonceManager) persistLocked() error {
+	if m.store == nil {
+		return nil
+	}
+
+	tracked := make([]*trackedTx, 0, len(m.pending))
+	for _, t := range m.pending {
+		tracked = append(tracked, t)
+	}
+	sort.Slice(tracked, func(i, j int) bool { return tracked[i].tx.Nonce() < tracked[j].tx.Nonce() })
+
+	data, err := json.Marshal(tracked)
+	if err != nil {
+		return err
+	}
+	return m.store.SaveState(pendingTxsKey, data)
+}
+
+// versions returns the hashes of every version sent with a nonce
+func (m *NonceManager) versions(nonce uint64) []common.Hash {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if tracked, ok := m.pending[nonce]; ok {
+		return append([]common.Hash(nil), tracked.Hashes...)
+	}
+	return nil
+}
+
+func bumpFee(fee *big.Int, percent int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(int64(100+percent)))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+func appendNew(hashes []common.Hash, more []common.Hash) []common.Hash {
+	for _, hash := range more {
+		known := false
+		for _, h := range hashes {
+			if h == hash {
+				known = true
+				break
+			}
+		}
+		if !known {
+			hashes = append(hashes, hash)
+		}
+	}
+	return hashes
+}
@@ -0,0 +1,145 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"bitbridge/internal/store/storetest"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+var recipient = common.HexToAddress("0x00000000000000000000000000000000000000cc")
+
+func TestNonceManagerConcurrentSends(t *testing.T) {
+	chain := newSimulatedChain(t)
+	client := chain.client(chain.operator)
+	nonces := NewNonceManager(NonceManagerConfig{Client: client})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	start, err := client.GetBackend().PendingNonceAt(ctx, client.GetAddress())
+	if err != nil {
+		t.Fatalf("Failed to get nonce: %v", err)
+	}
+
+	const sends = 8
+	txs := make([]*types.Transaction, sends)
+	errs := make([]error, sends)
+	var wg sync.WaitGroup
+	for i := 0; i < sends; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			txs[i], errs[i] = nonces.SendTransaction(ctx, recipient, big.NewInt(1), 21000, nil)
+		}(i)
+	}
+	wg.Wait()
+
+	used := make(map[uint64]bool)
+	for i, tx := range txs {
+		if errs[i] != nil {
+			t.Fatalf("Send %d failed: %v", i, errs[i])
+		}
+		if used[tx.Nonce()] {
+			t.Fatalf("Nonce %d handed out twice", tx.Nonce())
+		}
+		used[tx.Nonce()] = true
+		if _, err := nonces.WaitMined(ctx, tx); err != nil {
+			t.Fatalf("Transaction %d was not mined: %v", i, err)
+		}
+	}
+
+	confirmed, err := client.GetBackend().NonceAt(ctx, client.GetAddress(), nil)
+	if err != nil {
+		t.Fatalf("Failed to get nonce: %v", err)
+	}
+	if confirmed != start+sends {
+		t.Errorf("Expected confirmed nonce %d, got %d", start+sends, confirmed)
+	}
+
+	if err := nonces.check(ctx); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if n := nonces.PendingCount(); n != 0 {
+		t.Errorf("Expected no tracked transactions once mined, got %d", n)
+	}
+}
+
+func TestNonceManagerReplacesStuckTransactionAfterRestart(t *testing.T) {
+	operator, _ := crypto.GenerateKey()
+	funds := new(big.Int).Exp(big.NewInt(10), big.NewInt(21), nil)
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		crypto.PubkeyToAddress(operator.PublicKey): {Balance: funds},
+	})
+	defer backend.Close()
+
+	repo := storetest.Open(t)
+
+	client := NewClientWithBackend(backend.Client(), NewKeySigner(operator), big.NewInt(1337))
+	config := NonceManagerConfig{Client: client, Store: repo, ReplaceAfter: time.Nanosecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Nothing is mined, so the transaction stays pending
+	stuck, err := NewNonceManager(config).SendTransaction(ctx, recipient, big.NewInt(1), 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to send transaction: %v", err)
+	}
+
+	restarted := NewNonceManager(config)
+	next, err := restarted.SendTransaction(ctx, recipient, big.NewInt(2), 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to send transaction: %v", err)
+	}
+	if next.Nonce() != stuck.Nonce()+1 {
+		t.Fatalf("Expected nonce %d after restart, got %d", stuck.Nonce()+1, next.Nonce())
+	}
+	if n := restarted.PendingCount(); n != 2 {
+		t.Fatalf("Expected 2 tracked transactions after restart, got %d", n)
+	}
+
+	if err := restarted.check(ctx); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if polled, err := restarted.TransactionReceipt(ctx, stuck.Hash()); err != nil || polled != nil {
+		t.Fatalf("Expected no receipt while pending, got %v (%v)", polled, err)
+	}
+	backend.Commit()
+
+	receipt, err := restarted.WaitMined(ctx, stuck)
+	if err != nil {
+		t.Fatalf("Stuck transaction was not mined: %v", err)
+	}
+	if receipt.TxHash == stuck.Hash() {
+		t.Fatal("Expected the fee-bumped replacement to be mined")
+	}
+	if polled, err := restarted.TransactionReceipt(ctx, stuck.Hash()); err != nil || polled == nil || polled.TxHash != receipt.TxHash {
+		t.Errorf("Expected the replacement's receipt for the original hash, got %v (%v)", polled, err)
+	}
+	replacement, _, err := backend.Client().TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		t.Fatalf("Failed to get replacement: %v", err)
+	}
+	if replacement.Nonce() != stuck.Nonce() ||
+		replacement.GasTipCap().Cmp(stuck.GasTipCap()) <= 0 || replacement.GasFeeCap().Cmp(stuck.GasFeeCap()) <= 0 {
+		t.Errorf("Replacement nonce %d fees %s/%s do not replace nonce %d fees %s/%s",
+			replacement.Nonce(), replacement.GasTipCap(), replacement.GasFeeCap(),
+			stuck.Nonce(), stuck.GasTipCap(), stuck.GasFeeCap())
+	}
+
+	if err := restarted.check(ctx); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if n := restarted.PendingCount(); n != 0 {
+		t.Errorf("Expected no tracked transactions once mined, got %d", n)
+	}
+}
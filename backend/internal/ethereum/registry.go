@@ -0,0 +1,238 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"bitbridge/internal/contracts/bindings"
+
+	goethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	registryABI = mustParseABI(bindings.UTXORegistryMetaData.ABI)
+	tokenABI    = mustParseABI(bindings.UTXOTokenMetaData.ABI)
+)
+
+// UTXORedeemedEvent is emitted by a UTXOToken when its holder burns the
+// full supply to redeem the underlying bitcoin
+type UTXORedeemedEvent struct {
+	TokenAddress       common.Address
+	BitcoinTxID        string
+	Vout               uint32
+	Redeemer           common.Address
+	BitcoinDestination string
+	BlockNumber        uint64
+	TxHash             common.Hash
+	LogIndex           uint
+}
+
+// UTXORecord mirrors UTXORegistry.UTXORecord
+type UTXORecord struct {
+	BitcoinTxID    string
+	BitcoinVout    uint32
+	BitcoinAmount  *big.Int
+	BitcoinAddress string
+	TokenAddress   common.Address
+	TokenOwner     common.Address
+	IsActive       bool
+	CreatedAt      *big.Int
+}
+
+// UTXOID computes the registry key keccak256(abi.encodePacked(txid, vout))
+func UTXOID(txid string, vout uint32) [32]byte {
+	var voutBytes [4]byte
+	binary.BigEndian.PutUint32(voutBytes[:], vout)
+
+	var id [32]byte
+	copy(id[:], crypto.Keccak256([]byte(txid), voutBytes[:]))
+	return id
+}
+
+// FilterUTXORedeemed returns token redemption events in the given block
+// range. Only events emitted by tokens registered in the UTXORegistry are
+// returned, so a contract that merely emits a matching event cannot trigger
+// a payout.
+func (s *Service) FilterUTXORedeemed(ctx context.Context, fromBlock, toBlock uint64) ([]*UTXORedeemedEvent, error) {
+	eventABI := tokenABI.Events["UTXORedeemed"]
+
+	logs, err := s.client.GetBackend().FilterLogs(ctx, goethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Topics:    [][]common.Hash{{eventABI.ID}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter redemption logs: %w", err)
+	}
+
+	var events []*UTXORedeemedEvent
+	for _, vLog := range logs {
+		if vLog.Removed {
+			continue
+		}
+
+		utxoID, err := s.GetUTXOIDForToken(ctx, vLog.Address)
+		if err != nil {
+			return nil, err
+		}
+		if utxoID == ([32]byte{}) {
+			continue
+		}
+
+		event, err := parseUTXORedeemed(vLog)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// GetUTXOIDForToken returns the registry UTXO ID for a token, or the zero ID
+// if the token was not created by the registry
+func (s *Service) GetUTXOIDForToken(ctx context.Context, token common.Address) ([32]byte, error) {
+	utxoID, err := s.registry().TokenToUtxo(&bind.CallOpts{Context: ctx}, token)
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("failed to call tokenToUtxo: %w", err)
+	}
+
+	return utxoID, nil
+}
+
+// GetUTXORecord returns the registry record for a UTXO ID
+func (s *Service) GetUTXORecord(ctx context.Context, utxoID [32]byte) (*UTXORecord, error) {
+	record, err := s.registry().GetUTXO(&bind.CallOpts{Context: ctx}, utxoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call getUTXO: %w", err)
+	}
+
+	return &UTXORecord{
+		BitcoinTxID:    record.BitcoinTxId,
+		BitcoinVout:    record.BitcoinVout,
+		BitcoinAmount:  record.BitcoinAmount,
+		BitcoinAddress: record.BitcoinAddress,
+		TokenAddress:   record.TokenAddress,
+		TokenOwner:     record.TokenOwner,
+		IsActive:       record.IsActive,
+		CreatedAt:      record.CreatedAt,
+	}, nil
+}
+
+// MarkUTXORedeemed deactivates a UTXO in the registry once its payout has
+// settled on the Bitcoin side
+func (s *Service) MarkUTXORedeemed(ctx context.Context, utxoID [32]byte, redeemer common.Address, btcDestination string) (*types.Transaction, error) {
+	tx, err := s.nonces.Transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return s.registry().MarkUTXORedeemed(auth, utxoID, redeemer, btcDestination)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call markUTXORedeemed: %w", err)
+	}
+
+	return tx, nil
+}
+
+// GetBlockNumber returns the latest Ethereum block number
+func (s *Service) GetBlockNumber(ctx context.Context) (uint64, error) {
+	return s.client.GetBlockNumber(ctx)
+}
+
+// WaitMined blocks until the transaction, or a fee-bumped replacement of
+// it, is mined and returns its receipt. A reverted transaction yields a
+// *RevertError.
+func (s *Service) WaitMined(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	receipt, err := s.nonces.WaitMined(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, s.revertError(ctx, tx, receipt)
+	}
+
+	return receipt, nil
+}
+
+// TransactionReceipt returns the receipt of a transaction sent through the
+// nonce manager, or of its fee-bumped replacement, without waiting. The
+// receipt is nil while the transaction is pending. A reverted transaction
+// yields a *RevertError.
+func (s *Service) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	receipt, err := s.nonces.TransactionReceipt(ctx, hash)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, &RevertError{TxHash: receipt.TxHash}
+	}
+
+	return receipt, nil
+}
+
+// revertError recovers the revert reason of a failed transaction. Receipts
+// do not carry it, so the transaction is replayed as a call on the state of
+// the block it was mined in.
+func (s *Service) revertError(ctx context.Context, tx *types.Transaction, receipt *types.Receipt) error {
+	revertErr := &RevertError{TxHash: receipt.TxHash}
+
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return revertErr
+	}
+
+	_, err = s.client.GetBackend().CallContract(ctx, goethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}, receipt.BlockNumber)
+	if data, ok := revertData(err); ok {
+		revertErr.Reason = revertReason(data)
+	}
+
+	return revertErr
+}
+
+func (s *Service) registry() *bindings.UTXORegistry {
+	// NewUTXORegistry only fails on an invalid ABI, which mustParseABI
+	// has already ruled out
+	registry, _ := bindings.NewUTXORegistry(s.utxoRegistryAddr, s.client.GetBackend())
+	return registry
+}
+
+func parseUTXORedeemed(vLog types.Log) (*UTXORedeemedEvent, error) {
+	values, err := tokenABI.Unpack("UTXORedeemed", vLog.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack UTXORedeemed: %w", err)
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("unexpected UTXORedeemed field count: %d", len(values))
+	}
+
+	return &UTXORedeemedEvent{
+		TokenAddress:       vLog.Address,
+		BitcoinTxID:        values[0].(string),
+		Vout:               values[1].(uint32),
+		Redeemer:           values[2].(common.Address),
+		BitcoinDestination: values[3].(string),
+		BlockNumber:        vLog.BlockNumber,
+		TxHash:             vLog.TxHash,
+		LogIndex:           vLog.Index,
+	}, nil
+}
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(fmt.Sprintf("invalid contract ABI: %v", err))
+	}
+	return parsed
+}
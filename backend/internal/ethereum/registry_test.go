@@ -0,0 +1,254 @@
+package ethereum
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+const testTxID = "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"
+
+type simulatedChain struct {
+	backend  *simulated.Backend
+	operator *ecdsa.PrivateKey
+	outsider *ecdsa.PrivateKey
+	registry common.Address
+}
+
+// newSimulatedChain starts a simulated chain that mines continuously and
+// deploys a UTXORegistry owned by the operator key
+func newSimulatedChain(t *testing.T) *simulatedChain {
+	t.Helper()
+
+	operator, _ := crypto.GenerateKey()
+	outsider, _ := crypto.GenerateKey()
+	funds := new(big.Int).Exp(big.NewInt(10), big.NewInt(21), nil)
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		crypto.PubkeyToAddress(operator.PublicKey): {Balance: funds},
+		crypto.PubkeyToAddress(outsider.PublicKey): {Balance: funds},
+	})
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				backend.Commit()
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		close(done)
+		<-stopped
+		backend.Close()
+	})
+
+	chain := &simulatedChain{backend: backend, operator: operator, outsider: outsider}
+	cm := NewContractManager(chain.client(operator), chain.service(operator))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	address, tx, err := cm.DeployUTXORegistry(ctx)
+	if err != nil {
+		t.Fatalf("Failed to deploy UTXORegistry: %v", err)
+	}
+	if _, err := bind.WaitDeployed(ctx, backend.Client(), tx); err != nil {
+		t.Fatalf("UTXORegistry deployment failed: %v", err)
+	}
+	chain.registry = address
+
+	return chain
+}
+
+func (c *simulatedChain) client(key *ecdsa.PrivateKey) *Client {
+	return NewClientWithBackend(c.backend.Client(), NewKeySigner(key), big.NewInt(1337))
+}
+
+// service returns a Service for key with a nonce manager of its own
+func (c *simulatedChain) service(key *ecdsa.PrivateKey) *Service {
+	client := c.client(key)
+	service, _ := NewService(ServiceConfig{
+		Client:           client,
+		Nonces:           NewNonceManager(NonceManagerConfig{Client: client}),
+		UTXORegistryAddr: c.registry.Hex(),
+	})
+	return service
+}
+
+func TestRegisterUTXOOnSimulatedChain(t *testing.T) {
+	chain := newSimulatedChain(t)
+	service := chain.service(chain.operator)
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	record, err := service.GetUTXOStatus(ctx, testTxID, 0)
+	if err != nil {
+		t.Fatalf("Failed to get UTXO status: %v", err)
+	}
+	if record != nil {
+		t.Fatalf("Expected unregistered UTXO, got %+v", record)
+	}
+
+	tokenAddr, tx, err := service.RegisterUTXO(ctx, testTxID, 0, big.NewInt(1000000000), "12cbQLTFMXRnSzktFkuoG3eHoMeFtpTu3S", owner)
+	if err != nil {
+		t.Fatalf("Failed to register UTXO: %v", err)
+	}
+	if tokenAddr == (common.Address{}) {
+		t.Fatal("Expected token address from UTXORegistered event")
+	}
+
+	// Sent as an EIP-1559 transaction with an estimated gas limit plus margin
+	receipt, err := service.WaitMined(ctx, tx)
+	if err != nil {
+		t.Fatalf("Failed to get receipt: %v", err)
+	}
+	if tx.Type() != types.DynamicFeeTxType {
+		t.Errorf("Expected a dynamic-fee transaction, got type %d", tx.Type())
+	}
+	if tx.Gas() <= receipt.GasUsed || tx.Gas() >= 3000000 {
+		t.Errorf("Expected an estimated gas limit above the %d used, got %d", receipt.GasUsed, tx.Gas())
+	}
+
+	record, err = service.GetUTXOStatus(ctx, testTxID, 0)
+	if err != nil {
+		t.Fatalf("Failed to get UTXO status: %v", err)
+	}
+	if record == nil || record.TokenAddress != tokenAddr || record.TokenOwner != owner || !record.IsActive {
+		t.Fatalf("Unexpected registry record %+v", record)
+	}
+
+	utxoID, err := service.GetUTXOIDForToken(ctx, tokenAddr)
+	if err != nil {
+		t.Fatalf("Failed to get UTXO ID: %v", err)
+	}
+	if utxoID != UTXOID(testTxID, 0) {
+		t.Errorf("Registry UTXO ID %x does not match %x", utxoID, UTXOID(testTxID, 0))
+	}
+
+	// The token has 18 decimals, so one satoshi is 1e10 units
+	balance, err := service.GetTokenBalance(ctx, tokenAddr, owner)
+	if err != nil {
+		t.Fatalf("Failed to get token balance: %v", err)
+	}
+	expected := new(big.Int).Mul(big.NewInt(1000000000), big.NewInt(1e10))
+	if balance.Cmp(expected) != 0 {
+		t.Errorf("Expected balance %s, got %s", expected, balance)
+	}
+
+	_, _, err = service.RegisterUTXO(ctx, testTxID, 0, big.NewInt(1000000000), "12cbQLTFMXRnSzktFkuoG3eHoMeFtpTu3S", owner)
+	if !errors.Is(err, ErrUTXOAlreadyRegistered) {
+		t.Fatalf("Expected ErrUTXOAlreadyRegistered, got %v", err)
+	}
+	var revertErr *RevertError
+	if !errors.As(err, &revertErr) || revertErr.Reason != "UTXO already registered" {
+		t.Errorf("Expected decoded revert reason, got %v", err)
+	}
+}
+
+func TestMarkUTXORedeemedOnSimulatedChain(t *testing.T) {
+	chain := newSimulatedChain(t)
+	service := chain.service(chain.operator)
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, _, err := service.RegisterUTXO(ctx, testTxID, 1, big.NewInt(50000), "12cbQLTFMXRnSzktFkuoG3eHoMeFtpTu3S", owner); err != nil {
+		t.Fatalf("Failed to register UTXO: %v", err)
+	}
+
+	utxoID := UTXOID(testTxID, 1)
+	tx, err := service.MarkUTXORedeemed(ctx, utxoID, owner, "bc1qdestination")
+	if err != nil {
+		t.Fatalf("Failed to send markUTXORedeemed: %v", err)
+	}
+	if _, err := service.WaitMined(ctx, tx); err != nil {
+		t.Fatalf("markUTXORedeemed failed: %v", err)
+	}
+
+	// Gas estimation catches the second redemption before it is sent
+	if _, err := service.MarkUTXORedeemed(ctx, utxoID, owner, "bc1qdestination"); !errors.Is(err, ErrUTXONotActive) {
+		t.Errorf("Expected ErrUTXONotActive for second redemption, got %v", err)
+	}
+
+	record, err := service.GetUTXOStatus(ctx, testTxID, 1)
+	if err != nil {
+		t.Fatalf("Failed to get UTXO status: %v", err)
+	}
+	if record == nil || record.IsActive {
+		t.Errorf("Expected inactive registry record, got %+v", record)
+	}
+}
+
+func TestBurnTokenOnSimulatedChain(t *testing.T) {
+	chain := newSimulatedChain(t)
+	service := chain.service(chain.operator)
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, _, err := service.RegisterUTXO(ctx, testTxID, 2, big.NewInt(50000), "12cbQLTFMXRnSzktFkuoG3eHoMeFtpTu3S", owner); err != nil {
+		t.Fatalf("Failed to register UTXO: %v", err)
+	}
+
+	if _, err := service.BurnToken(ctx, testTxID, 2, owner, "bc1qdestination"); err != nil {
+		t.Fatalf("Failed to burn token: %v", err)
+	}
+
+	record, err := service.GetUTXOStatus(ctx, testTxID, 2)
+	if err != nil {
+		t.Fatalf("Failed to get UTXO status: %v", err)
+	}
+	if record == nil || record.IsActive {
+		t.Errorf("Expected the burn to deactivate the record, got %+v", record)
+	}
+
+	if _, err := service.BurnToken(ctx, testTxID, 2, owner, "bc1qdestination"); !errors.Is(err, ErrUTXONotActive) {
+		t.Errorf("Expected ErrUTXONotActive for a second burn, got %v", err)
+	}
+
+	if _, err := service.CreateToken(ctx, testTxID, 3, big.NewInt(50000)); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported from CreateToken, got %v", err)
+	}
+}
+
+func TestRegisterUTXORequiresOperator(t *testing.T) {
+	chain := newSimulatedChain(t)
+	service := chain.service(chain.outsider)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, tx, err := service.RegisterUTXO(ctx, testTxID, 0, big.NewInt(1000), "12cbQLTFMXRnSzktFkuoG3eHoMeFtpTu3S", crypto.PubkeyToAddress(chain.outsider.PublicKey))
+	if !errors.Is(err, ErrNotOperator) {
+		t.Fatalf("Expected ErrNotOperator, got %v", err)
+	}
+	if tx != nil {
+		t.Error("Expected a call that would revert not to be sent")
+	}
+}
+
+func TestNewServiceRequiresNonceManager(t *testing.T) {
+	if _, err := NewService(ServiceConfig{Client: &Client{}}); err == nil {
+		t.Error("Expected a service without a shared nonce manager to be rejected")
+	}
+}
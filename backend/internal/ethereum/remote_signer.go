@@ -0,0 +1,236 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// SignTxArgs are the eth_signTransaction parameters. A transaction with
+// GasPrice set is signed as a legacy transaction, otherwise as EIP-1559.
+type SignTxArgs struct {
+	From                 common.Address   `json:"from"`
+	To                   *common.Address  `json:"to"`
+	Gas                  hexutil.Uint64   `json:"gas"`
+	GasPrice             *hexutil.Big     `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big     `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big     `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big     `json:"value"`
+	Nonce                hexutil.Uint64   `json:"nonce"`
+	Input                hexutil.Bytes    `json:"input"`
+	AccessList           types.AccessList `json:"accessList,omitempty"`
+	ChainID              *hexutil.Big     `json:"chainId"`
+}
+
+// SignTxResult is the eth_signTransaction result
+type SignTxResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+func signTxArgs(from common.Address, tx *types.Transaction, chainID *big.Int) SignTxArgs {
+	args := SignTxArgs{
+		From:    from,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Input:   tx.Data(),
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.Type() == types.LegacyTxType {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	} else {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+		args.AccessList = tx.AccessList()
+	}
+	return args
+}
+
+// transaction returns the unsigned transaction the arguments describe
+func (args SignTxArgs) transaction() (*types.Transaction, error) {
+	if args.ChainID == nil {
+		return nil, fmt.Errorf("chainId is required")
+	}
+	value := new(big.Int)
+	if args.Value != nil {
+		value = args.Value.ToInt()
+	}
+
+	if args.GasPrice != nil {
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(args.Nonce),
+			GasPrice: args.GasPrice.ToInt(),
+			Gas:      uint64(args.Gas),
+			To:       args.To,
+			Value:    value,
+			Data:     args.Input,
+		}), nil
+	}
+
+	if args.MaxFeePerGas == nil || args.MaxPriorityFeePerGas == nil {
+		return nil, fmt.Errorf("gasPrice or maxFeePerGas and maxPriorityFeePerGas are required")
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:    args.ChainID.ToInt(),
+		Nonce:      uint64(args.Nonce),
+		GasTipCap:  args.MaxPriorityFeePerGas.ToInt(),
+		GasFeeCap:  args.MaxFeePerGas.ToInt(),
+		Gas:        uint64(args.Gas),
+		To:         args.To,
+		Value:      value,
+		Data:       args.Input,
+		AccessList: args.AccessList,
+	}), nil
+}
+
+// RemoteSigner asks an external signer over JSON-RPC eth_signTransaction,
+// the method geth, clef and web3signer serve. The returned transaction is
+// checked to be the one requested and to be signed by the expected account.
+type RemoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// NewRemoteSigner connects to a remote signer. With an empty address the
+// signer's first account from eth_accounts is used.
+func NewRemoteSigner(ctx context.Context, url, address string) (*RemoteSigner, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote signer: %w", err)
+	}
+
+	signer := &RemoteSigner{client: client}
+	if address != "" {
+		if !common.IsHexAddress(address) {
+			client.Close()
+			return nil, fmt.Errorf("invalid signer address: %s", address)
+		}
+		signer.address = common.HexToAddress(address)
+		return signer, nil
+	}
+
+	var accounts []common.Address
+	if err := client.CallContext(ctx, &accounts, "eth_accounts"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to list remote signer accounts: %w", err)
+	}
+	if len(accounts) == 0 {
+		client.Close()
+		return nil, fmt.Errorf("remote signer has no accounts")
+	}
+	signer.address = accounts[0]
+
+	return signer, nil
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	var result json.RawMessage
+	if err := s.client.CallContext(ctx, &result, "eth_signTransaction", signTxArgs(s.address, tx, chainID)); err != nil {
+		return nil, fmt.Errorf("remote signer failed: %w", err)
+	}
+
+	raw, err := rawSignedTx(result)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("failed to decode signed transaction: %w", err)
+	}
+
+	signer := types.LatestSignerForChainID(chainID)
+	if signer.Hash(signed) != signer.Hash(tx) {
+		return nil, fmt.Errorf("remote signer returned a different transaction")
+	}
+	from, err := types.Sender(signer, signed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signature: %w", err)
+	}
+	if from != s.address {
+		return nil, fmt.Errorf("remote signer signed as %s, expected %s", from.Hex(), s.address.Hex())
+	}
+
+	return signed, nil
+}
+
+// Close disconnects from the remote signer
+func (s *RemoteSigner) Close() {
+	s.client.Close()
+}
+
+// rawSignedTx accepts both eth_signTransaction result shapes: geth and clef
+// return an object with the raw transaction, web3signer the raw hex alone
+func rawSignedTx(result json.RawMessage) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(string(result)), "\"") {
+		var raw hexutil.Bytes
+		if err := json.Unmarshal(result, &raw); err != nil {
+			return nil, fmt.Errorf("failed to decode remote signer result: %w", err)
+		}
+		return raw, nil
+	}
+
+	var signed SignTxResult
+	if err := json.Unmarshal(result, &signed); err != nil {
+		return nil, fmt.Errorf("failed to decode remote signer result: %w", err)
+	}
+	if len(signed.Raw) == 0 {
+		return nil, fmt.Errorf("remote signer returned no transaction")
+	}
+	return signed.Raw, nil
+}
+
+// signerAPI serves eth_accounts and eth_signTransaction for one signer
+type signerAPI struct {
+	signer Signer
+}
+
+func (api *signerAPI) Accounts() []common.Address {
+	return []common.Address{api.signer.Address()}
+}
+
+func (api *signerAPI) SignTransaction(ctx context.Context, args SignTxArgs) (*SignTxResult, error) {
+	if args.From != api.signer.Address() {
+		return nil, fmt.Errorf("unknown account %s", args.From.Hex())
+	}
+
+	tx, err := args.transaction()
+	if err != nil {
+		return nil, err
+	}
+
+	signed, err := api.signer.SignTx(ctx, tx, args.ChainID.ToInt())
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &SignTxResult{Raw: raw, Tx: signed}, nil
+}
+
+// NewSignerServer returns a JSON-RPC server that signs with signer, for use
+// as a stand-in remote signer in development and tests. It serves HTTP
+// through rpc.Server.ServeHTTP.
+func NewSignerServer(signer Signer) (*rpc.Server, error) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &signerAPI{signer: signer}); err != nil {
+		return nil, fmt.Errorf("failed to register signer API: %w", err)
+	}
+	return server, nil
+}
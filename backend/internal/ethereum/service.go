@@ -2,8 +2,12 @@ package ethereum
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 
+	"bitbridge/internal/contracts/bindings"
+
+	goethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -11,96 +15,133 @@ import (
 
 type Service struct {
 	client           *Client
+	nonces           *NonceManager
 	utxoRegistryAddr common.Address
 	tokenFactoryAddr common.Address
 }
 
 type ServiceConfig struct {
 	Client           *Client
+	Nonces           *NonceManager // shared sender for the client's key, required
 	UTXORegistryAddr string
 	TokenFactoryAddr string
 }
 
-func NewService(config ServiceConfig) *Service {
+// NewService creates a service that sends through the operator key's shared
+// nonce manager. A manager private to the service would hand out nonces that
+// collide with the other senders of the same key.
+func NewService(config ServiceConfig) (*Service, error) {
+	if config.Nonces == nil {
+		return nil, fmt.Errorf("nonce manager is required")
+	}
+
 	return &Service{
 		client:           config.Client,
+		nonces:           config.Nonces,
 		utxoRegistryAddr: common.HexToAddress(config.UTXORegistryAddr),
 		tokenFactoryAddr: common.HexToAddress(config.TokenFactoryAddr),
-	}
+	}, nil
 }
 
-func (s *Service) CreateToken(ctx context.Context, utxoID string, amount *big.Int, btcTxHash string) (*types.Transaction, error) {
-	_, err := s.getTransactor(ctx)
+// RegisterUTXO registers a Bitcoin output in the UTXORegistry, which deploys
+// its UTXO token and mints the full supply to owner. It waits for the
+// transaction to be mined and returns the token address announced in the
+// UTXORegistered event.
+func (s *Service) RegisterUTXO(ctx context.Context, btcTxHash string, vout uint32, amount *big.Int, btcAddress string, owner common.Address) (common.Address, *types.Transaction, error) {
+	registry := s.registry()
+	tx, err := s.nonces.Transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return registry.RegisterUTXO(auth, btcTxHash, vout, amount, btcAddress, owner)
+	})
 	if err != nil {
-		return nil, err
+		return common.Address{}, nil, fmt.Errorf("failed to call registerUTXO: %w", err)
 	}
 
-	// This would call the TokenFactory smart contract
-	// For now, we'll return a placeholder transaction
-	// In real implementation, this would interact with the deployed TokenFactory contract
-	
-	return nil, nil
-}
-
-func (s *Service) RegisterUTXO(ctx context.Context, utxoID string, amount *big.Int, btcTxHash string, owner common.Address) (*types.Transaction, error) {
-	_, err := s.getTransactor(ctx)
+	receipt, err := s.WaitMined(ctx, tx)
 	if err != nil {
-		return nil, err
+		return common.Address{}, tx, fmt.Errorf("registerUTXO failed: %w", err)
+	}
+
+	for _, vLog := range receipt.Logs {
+		if vLog.Address != s.utxoRegistryAddr {
+			continue
+		}
+		event, err := registry.ParseUTXORegistered(*vLog)
+		if err != nil {
+			continue
+		}
+		return event.TokenAddress, tx, nil
 	}
 
-	// This would call the UTXO Registry smart contract
-	// For now, we'll return a placeholder transaction
-	// In real implementation, this would interact with the deployed UTXORegistry contract
-	
-	return nil, nil
+	return common.Address{}, tx, fmt.Errorf("registerUTXO transaction %s emitted no UTXORegistered event", tx.Hash().Hex())
 }
 
-func (s *Service) BurnToken(ctx context.Context, utxoID string, amount *big.Int) (*types.Transaction, error) {
-	_, err := s.getTransactor(ctx)
+// CreateToken is not supported: the UTXORegistry deploys and mints a UTXO's
+// token itself in registerUTXO, so use RegisterUTXO instead
+func (s *Service) CreateToken(ctx context.Context, btcTxHash string, vout uint32, amount *big.Int) (*types.Transaction, error) {
+	return nil, fmt.Errorf("%w: tokens are created by RegisterUTXO", ErrNotSupported)
+}
+
+// BurnToken retires the token of a Bitcoin output by marking the output
+// redeemed in the registry, and waits for the transaction to be mined. The
+// registry redeems whole outputs, so there is no amount.
+func (s *Service) BurnToken(ctx context.Context, btcTxHash string, vout uint32, redeemer common.Address, btcDestination string) (*types.Transaction, error) {
+	tx, err := s.MarkUTXORedeemed(ctx, UTXOID(btcTxHash, vout), redeemer, btcDestination)
 	if err != nil {
 		return nil, err
 	}
 
-	// This would call the token burn function
-	// For now, we'll return a placeholder transaction
-	
-	return nil, nil
-}
+	if _, err := s.WaitMined(ctx, tx); err != nil {
+		return tx, fmt.Errorf("markUTXORedeemed failed: %w", err)
+	}
 
-func (s *Service) GetUTXOStatus(ctx context.Context, utxoID string) (bool, error) {
-	// This would query the UTXO Registry smart contract
-	// For now, we'll return false
-	
-	return false, nil
+	return tx, nil
 }
 
-func (s *Service) getTransactor(ctx context.Context) (*bind.TransactOpts, error) {
-	nonce, err := s.client.GetNonce(ctx)
+// GetUTXOStatus returns the registry record for a Bitcoin output, or nil if
+// the output has never been registered
+func (s *Service) GetUTXOStatus(ctx context.Context, btcTxHash string, vout uint32) (*UTXORecord, error) {
+	utxoID, err := s.registry().GetUTXOId(&bind.CallOpts{Context: ctx}, btcTxHash, vout)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to call getUTXOId: %w", err)
 	}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(s.client.GetPrivateKey(), s.client.GetChainID())
+	record, err := s.GetUTXORecord(ctx, utxoID)
 	if err != nil {
 		return nil, err
 	}
+	if record.TokenAddress == (common.Address{}) {
+		return nil, nil
+	}
 
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)
-	auth.GasLimit = uint64(3000000)
-	auth.GasPrice = big.NewInt(20000000000) // 20 gwei
-
-	return auth, nil
+	return record, nil
 }
 
+// EstimateGas estimates the gas for a call from the operator key, with the
+// nonce manager's safety margin applied
 func (s *Service) EstimateGas(ctx context.Context, to common.Address, data []byte) (uint64, error) {
-	// Implement gas estimation logic
-	return 21000, nil // Basic transaction gas limit
+	return s.nonces.EstimateGas(ctx, goethereum.CallMsg{
+		From: s.client.GetAddress(),
+		To:   &to,
+		Data: data,
+	})
 }
 
+// SuggestFees returns the EIP-1559 fees operator transactions are sent with
+func (s *Service) SuggestFees(ctx context.Context) (*FeeSuggestion, error) {
+	return s.nonces.SuggestFees(ctx)
+}
+
+// GetTokenBalance returns the ERC-20 balance of owner in a UTXO token
 func (s *Service) GetTokenBalance(ctx context.Context, tokenAddr common.Address, owner common.Address) (*big.Int, error) {
-	// This would query ERC-20 token balance
-	// For now, we'll return zero balance
-	
-	return big.NewInt(0), nil
-}
\ No newline at end of file
+	token, err := bindings.NewUTXOTokenCaller(tokenAddr, s.client.GetBackend())
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := token.BalanceOf(&bind.CallOpts{Context: ctx}, owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call balanceOf: %w", err)
+	}
+
+	return balance, nil
+}
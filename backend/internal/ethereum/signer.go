@@ -0,0 +1,89 @@
+package ethereum
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs transactions for a single account. Every transaction the
+// gateway sends is signed through one, so the key itself never leaves it.
+type Signer interface {
+	Address() common.Address
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// KeySigner signs with a private key held in memory
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKeySigner creates a signer for a private key
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+// NewKeySignerFromHex creates a signer for a hex-encoded private key. Raw
+// keys in the environment are meant for development only.
+func NewKeySignerFromHex(privateKeyHex string) (*KeySigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	return NewKeySigner(key), nil
+}
+
+// NewKeystoreSigner decrypts an encrypted JSON keystore file, as written by
+// geth or clef, with the passphrase in passwordFile
+func NewKeystoreSigner(path, passwordFile string) (*KeySigner, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+
+	password, err := os.ReadFile(passwordFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore password: %w", err)
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, strings.TrimRight(string(password), "\r\n"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %w", err)
+	}
+	return NewKeySigner(key.PrivateKey), nil
+}
+
+func (s *KeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *KeySigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+// NewSigner creates the signer selected by config: a remote signer if a URL
+// is set, else a keystore file, else a raw private key
+func NewSigner(config Config) (Signer, error) {
+	switch {
+	case config.RemoteSignerURL != "":
+		return NewRemoteSigner(context.Background(), config.RemoteSignerURL, config.SignerAddress)
+	case config.KeystorePath != "":
+		return NewKeystoreSigner(config.KeystorePath, config.KeystorePasswordFile)
+	case config.PrivateKey != "":
+		return NewKeySignerFromHex(config.PrivateKey)
+	default:
+		return nil, fmt.Errorf("no signer configured")
+	}
+}
@@ -0,0 +1,88 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestKeystoreSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	dir := t.TempDir()
+
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(key, "correct horse")
+	if err != nil {
+		t.Fatalf("Failed to write keystore: %v", err)
+	}
+
+	passwordFile := filepath.Join(dir, "password")
+	if err := os.WriteFile(passwordFile, []byte("correct horse\n"), 0600); err != nil {
+		t.Fatalf("Failed to write password file: %v", err)
+	}
+
+	signer, err := NewKeystoreSigner(account.URL.Path, passwordFile)
+	if err != nil {
+		t.Fatalf("Failed to load keystore: %v", err)
+	}
+	if signer.Address() != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("Expected address %s, got %s", crypto.PubkeyToAddress(key.PublicKey).Hex(), signer.Address().Hex())
+	}
+
+	if err := os.WriteFile(passwordFile, []byte("wrong"), 0600); err != nil {
+		t.Fatalf("Failed to write password file: %v", err)
+	}
+	if _, err := NewKeystoreSigner(account.URL.Path, passwordFile); err == nil {
+		t.Error("Expected an error for the wrong passphrase")
+	}
+}
+
+func TestRemoteSignerOnSimulatedChain(t *testing.T) {
+	chain := newSimulatedChain(t)
+
+	server, err := NewSignerServer(NewKeySigner(chain.operator))
+	if err != nil {
+		t.Fatalf("Failed to create signer server: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	signer, err := NewRemoteSigner(ctx, httpServer.URL, "")
+	if err != nil {
+		t.Fatalf("Failed to connect to remote signer: %v", err)
+	}
+	defer signer.Close()
+	if signer.Address() != crypto.PubkeyToAddress(chain.operator.PublicKey) {
+		t.Fatalf("Expected the operator account, got %s", signer.Address().Hex())
+	}
+
+	client := NewClientWithBackend(chain.backend.Client(), signer, big.NewInt(1337))
+	nonces := NewNonceManager(NonceManagerConfig{Client: client})
+	tx, err := nonces.SendTransaction(ctx, recipient, big.NewInt(1), 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to send remotely signed transaction: %v", err)
+	}
+	if _, err := nonces.WaitMined(ctx, tx); err != nil {
+		t.Fatalf("Remotely signed transaction was not mined: %v", err)
+	}
+
+	// The stand-in refuses to sign for accounts it does not hold
+	other, err := NewRemoteSigner(ctx, httpServer.URL, crypto.PubkeyToAddress(chain.outsider.PublicKey).Hex())
+	if err != nil {
+		t.Fatalf("Failed to connect to remote signer: %v", err)
+	}
+	defer other.Close()
+	if _, err := other.SignTx(ctx, tx, big.NewInt(1337)); err == nil {
+		t.Error("Expected the remote signer to refuse an unknown account")
+	}
+}
@@ -0,0 +1,572 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/big"
+	"sync"
+	"time"
+
+	"bitbridge/internal/contracts/bindings"
+	"bitbridge/internal/store"
+	"bitbridge/pkg/types"
+
+	goethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	checkpointKey    = "event_indexer_checkpoint"
+	subscriberBuffer = 256
+)
+
+// indexedEvents lists the events recorded for each contract
+var indexedEvents = map[string][]string{
+	"UTXORegistry": {"UTXORegistered", "UTXORedeemed", "OperatorAdded", "OperatorRemoved"},
+	"SPVVerifier":  {"ProofVerified", "BlockHeaderStored"},
+}
+
+// ChainSource is the chain access the indexer needs; ethereum.Backend
+// satisfies it
+type ChainSource interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*ethtypes.Header, error)
+	FilterLogs(ctx context.Context, query goethereum.FilterQuery) ([]ethtypes.Log, error)
+}
+
+// Config for the contract event indexer
+type Config struct {
+	ChainSource     ChainSource
+	Store           store.Store
+	RegistryAddress common.Address // zero to skip UTXORegistry events
+	VerifierAddress common.Address // zero to skip SPVVerifier events
+	StartBlock      uint64         // first block to scan when there is no checkpoint, 0 for the head
+	BatchSize       uint64         // blocks per FilterLogs request
+	PollInterval    time.Duration
+	ReorgDepth      int // scanned ranges remembered for reorg detection
+}
+
+// Filter selects indexed events. Zero fields match every event.
+type Filter struct {
+	Name      string
+	Address   common.Address
+	FromBlock uint64
+	ToBlock   uint64
+}
+
+// Indexer backfills the bridge contracts' logs in bounded block ranges from
+// a stored checkpoint and then tails the chain. The hash at the end of each
+// scanned range is remembered, so a reorg is detected by comparing it with
+// the canonical chain and rolled back to the last range that still matches.
+type Indexer struct {
+	source       ChainSource
+	store        store.Store
+	addresses    []common.Address
+	topics       []common.Hash
+	events       map[eventID]abi.Event
+	ranges       []blockRange // scanned ranges, oldest first
+	startBlock   uint64
+	batchSize    uint64
+	pollInterval time.Duration
+	reorgDepth   int
+	subscribers  map[int]*subscriber
+	nextSubID    int
+	mu           sync.RWMutex
+	ctx          context.Context
+	cancel       context.CancelFunc
+}
+
+type eventID struct {
+	address common.Address
+	topic   common.Hash
+}
+
+type blockRange struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+	Hash string `json:"hash"` // hash of block To
+}
+
+type checkpoint struct {
+	Ranges []blockRange `json:"ranges"`
+}
+
+type subscriber struct {
+	filter Filter
+	ch     chan *types.ContractEvent
+}
+
+// NewIndexer creates an indexer that resumes from the checkpoint in the store
+func NewIndexer(config Config) (*Indexer, error) {
+	if config.ChainSource == nil || config.Store == nil {
+		return nil, fmt.Errorf("chain source and store are required")
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 2000
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 15 * time.Second
+	}
+	if config.ReorgDepth <= 0 {
+		config.ReorgDepth = 64
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	indexer := &Indexer{
+		source:       config.ChainSource,
+		store:        config.Store,
+		events:       make(map[eventID]abi.Event),
+		startBlock:   config.StartBlock,
+		batchSize:    config.BatchSize,
+		pollInterval: config.PollInterval,
+		reorgDepth:   config.ReorgDepth,
+		subscribers:  make(map[int]*subscriber),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+
+	contracts := map[string]common.Address{
+		"UTXORegistry": config.RegistryAddress,
+		"SPVVerifier":  config.VerifierAddress,
+	}
+	for name, address := range contracts {
+		if address == (common.Address{}) {
+			continue
+		}
+		if err := indexer.addContract(name, address); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+	if len(indexer.addresses) == 0 {
+		cancel()
+		return nil, fmt.Errorf("no contract addresses to index")
+	}
+
+	data, err := config.Store.GetState(checkpointKey)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		cancel()
+		return nil, fmt.Errorf("failed to load event indexer checkpoint: %w", err)
+	}
+	if err == nil {
+		var cp checkpoint
+		if err := json.Unmarshal(data, &cp); err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to decode event indexer checkpoint: %w", err)
+		}
+		indexer.ranges = cp.Ranges
+	}
+
+	if last, ok := indexer.lastRange(); ok {
+		log.Printf("Contract event indexer resuming after block %d", last.To)
+	}
+	return indexer, nil
+}
+
+func (ix *Indexer) addContract(name string, address common.Address) error {
+	artifact, err := bindings.LoadArtifact(name)
+	if err != nil {
+		return err
+	}
+	contractABI, err := artifact.ParsedABI()
+	if err != nil {
+		return err
+	}
+
+	for _, eventName := range indexedEvents[name] {
+		event, ok := contractABI.Events[eventName]
+		if !ok {
+			return fmt.Errorf("%s ABI has no event %s", name, eventName)
+		}
+		ix.events[eventID{address, event.ID}] = event
+		ix.topics = append(ix.topics, event.ID)
+	}
+	ix.addresses = append(ix.addresses, address)
+	return nil
+}
+
+// Start begins backfilling and tailing contract events
+func (ix *Indexer) Start() {
+	log.Println("Starting contract event indexer...")
+	go ix.pollLoop()
+}
+
+// Stop halts background indexing
+func (ix *Indexer) Stop() {
+	log.Println("Stopping contract event indexer...")
+	ix.cancel()
+}
+
+// Events returns the indexed events matching filter in block and log order
+func (ix *Indexer) Events(filter Filter) ([]*types.ContractEvent, error) {
+	toBlock := filter.ToBlock
+	if toBlock == 0 {
+		toBlock = math.MaxUint64
+	}
+
+	stored, err := ix.store.ListEvents(filter.FromBlock, toBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contract events: %w", err)
+	}
+
+	var matched []*types.ContractEvent
+	for _, event := range stored {
+		if filter.matches(event) {
+			matched = append(matched, event)
+		}
+	}
+	return matched, nil
+}
+
+// LastBlock returns the last block indexed, or false before the first scan
+func (ix *Indexer) LastBlock() (uint64, bool) {
+	last, ok := ix.lastRange()
+	return last.To, ok
+}
+
+// Subscribe delivers newly indexed events matching filter. Events orphaned
+// by a reorg are delivered again with Removed set. A subscriber that falls
+// more than subscriberBuffer events behind misses events. The returned
+// function unsubscribes and closes the channel.
+func (ix *Indexer) Subscribe(filter Filter) (<-chan *types.ContractEvent, func()) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	id := ix.nextSubID
+	ix.nextSubID++
+	sub := &subscriber{filter: filter, ch: make(chan *types.ContractEvent, subscriberBuffer)}
+	ix.subscribers[id] = sub
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			ix.mu.Lock()
+			defer ix.mu.Unlock()
+
+			delete(ix.subscribers, id)
+			close(sub.ch)
+		})
+	}
+}
+
+func (ix *Indexer) pollLoop() {
+	ticker := time.NewTicker(ix.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := ix.sync(); err != nil {
+			log.Printf("Contract event indexer sync failed: %v", err)
+		}
+
+		select {
+		case <-ix.ctx.Done():
+			log.Println("Contract event indexer stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync rolls back any orphaned ranges and then scans up to the chain head
+func (ix *Indexer) sync() error {
+	for ix.ctx.Err() == nil {
+		ctx, cancel := context.WithTimeout(ix.ctx, time.Minute)
+		head, err := ix.source.BlockNumber(ctx)
+		if err == nil {
+			err = ix.rewind(ctx, head)
+		}
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		reorged, err := ix.scanUpTo(head)
+		if err != nil {
+			return err
+		}
+		if !reorged {
+			return nil
+		}
+	}
+	return nil
+}
+
+// rewind drops scanned ranges from the top of the checkpoint until the last
+// one ends in a block on the canonical chain
+func (ix *Indexer) rewind(ctx context.Context, head uint64) error {
+	for {
+		last, ok := ix.lastRange()
+		if !ok {
+			return nil
+		}
+
+		if last.To <= head {
+			header, err := ix.source.HeaderByNumber(ctx, new(big.Int).SetUint64(last.To))
+			if err != nil {
+				return fmt.Errorf("failed to get block %d: %w", last.To, err)
+			}
+			if header.Hash().Hex() == last.Hash {
+				return nil
+			}
+		}
+
+		if err := ix.disconnectLast(); err != nil {
+			return err
+		}
+	}
+}
+
+// scanUpTo scans ranges after the checkpoint up to head. It reports true if
+// it stopped because the chain changed under it, in which case the caller
+// should rewind and try again.
+func (ix *Indexer) scanUpTo(head uint64) (bool, error) {
+	if _, ok := ix.lastRange(); !ok && ix.startBlock == 0 {
+		ix.startBlock = head
+	}
+
+	for from := ix.nextBlock(); from <= head; from = ix.nextBlock() {
+		if ix.ctx.Err() != nil {
+			return false, nil
+		}
+
+		to := from + ix.batchSize - 1
+		if to > head {
+			to = head
+		}
+
+		reorged, err := ix.scanRange(from, to)
+		if err != nil || reorged {
+			return reorged, err
+		}
+	}
+	return false, nil
+}
+
+// scanRange records the events in blocks from through to. The range must
+// build on the last scanned block, and the block at to must be the same
+// before and after the logs are fetched, so that every log comes from the
+// chain the checkpoint records.
+func (ix *Indexer) scanRange(from, to uint64) (bool, error) {
+	ctx, cancel := context.WithTimeout(ix.ctx, time.Minute)
+	defer cancel()
+
+	if last, ok := ix.lastRange(); ok {
+		first, err := ix.source.HeaderByNumber(ctx, new(big.Int).SetUint64(from))
+		if err != nil {
+			return false, fmt.Errorf("failed to get block %d: %w", from, err)
+		}
+		if first.ParentHash.Hex() != last.Hash {
+			return true, nil
+		}
+	}
+
+	end, err := ix.source.HeaderByNumber(ctx, new(big.Int).SetUint64(to))
+	if err != nil {
+		return false, fmt.Errorf("failed to get block %d: %w", to, err)
+	}
+
+	logs, err := ix.source.FilterLogs(ctx, goethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: ix.addresses,
+		Topics:    [][]common.Hash{ix.topics},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to filter logs in blocks %d-%d: %w", from, to, err)
+	}
+
+	recheck, err := ix.source.HeaderByNumber(ctx, new(big.Int).SetUint64(to))
+	if err != nil {
+		return false, fmt.Errorf("failed to get block %d: %w", to, err)
+	}
+	if recheck.Hash() != end.Hash() {
+		return true, nil
+	}
+
+	var events []*types.ContractEvent
+	for _, vLog := range logs {
+		if vLog.Removed {
+			continue
+		}
+		event, err := ix.decode(vLog)
+		if err != nil {
+			return false, fmt.Errorf("failed to decode log %d in block %d: %w", vLog.Index, vLog.BlockNumber, err)
+		}
+		if event != nil {
+			events = append(events, event)
+		}
+	}
+
+	ix.mu.Lock()
+	err = ix.store.SaveEvents(events)
+	if err == nil {
+		ix.ranges = append(ix.ranges, blockRange{From: from, To: to, Hash: end.Hash().Hex()})
+		if len(ix.ranges) > ix.reorgDepth {
+			ix.ranges = ix.ranges[len(ix.ranges)-ix.reorgDepth:]
+		}
+		err = ix.persist()
+	}
+	ix.mu.Unlock()
+	if err != nil {
+		return false, fmt.Errorf("failed to persist blocks %d-%d: %w", from, to, err)
+	}
+
+	ix.notify(events)
+	return false, nil
+}
+
+// disconnectLast drops the most recently scanned range and the events
+// recorded in it
+func (ix *Indexer) disconnectLast() error {
+	ix.mu.Lock()
+
+	orphaned := ix.ranges[len(ix.ranges)-1]
+	ix.ranges = ix.ranges[:len(ix.ranges)-1]
+	if len(ix.ranges) == 0 {
+		log.Printf("Warning: Ethereum reorg deeper than the %d range window, rescanning from block %d",
+			ix.reorgDepth, orphaned.From)
+		ix.startBlock = orphaned.From
+	}
+	log.Printf("Rewinding orphaned contract events in blocks %d-%d", orphaned.From, orphaned.To)
+
+	removed, err := ix.store.ListEvents(orphaned.From, math.MaxUint64)
+	if err == nil {
+		err = ix.store.DeleteEventsFrom(orphaned.From)
+	}
+	if err == nil {
+		err = ix.persist()
+	}
+	ix.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to roll back blocks %d-%d: %w", orphaned.From, orphaned.To, err)
+	}
+
+	for _, event := range removed {
+		event.Removed = true
+	}
+	ix.notify(removed)
+	return nil
+}
+
+// persist saves the checkpoint. Called with ix.mu held.
+func (ix *Indexer) persist() error {
+	data, err := json.Marshal(checkpoint{Ranges: ix.ranges})
+	if err != nil {
+		return err
+	}
+	return ix.store.SaveState(checkpointKey, data)
+}
+
+// notify hands events to matching subscribers without blocking the indexer
+func (ix *Indexer) notify(events []*types.ContractEvent) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	for _, event := range events {
+		for _, sub := range ix.subscribers {
+			if !sub.filter.matches(event) {
+				continue
+			}
+
+			copied := *event
+			select {
+			case sub.ch <- &copied:
+			default:
+				log.Printf("Contract event subscriber is full, dropping %s in block %d", event.Name, event.BlockNumber)
+			}
+		}
+	}
+}
+
+func (ix *Indexer) decode(vLog ethtypes.Log) (*types.ContractEvent, error) {
+	if len(vLog.Topics) == 0 {
+		return nil, nil
+	}
+	event, ok := ix.events[eventID{vLog.Address, vLog.Topics[0]}]
+	if !ok {
+		return nil, nil
+	}
+
+	data := make(map[string]interface{})
+	if err := event.Inputs.UnpackIntoMap(data, vLog.Data); err != nil {
+		return nil, err
+	}
+
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(data, indexed, vLog.Topics[1:]); err != nil {
+		return nil, err
+	}
+
+	for name, value := range data {
+		data[name] = jsonValue(value)
+	}
+
+	return &types.ContractEvent{
+		Name:        event.Name,
+		Address:     vLog.Address.Hex(),
+		BlockNumber: vLog.BlockNumber,
+		BlockHash:   vLog.BlockHash.Hex(),
+		TxHash:      vLog.TxHash.Hex(),
+		LogIndex:    vLog.Index,
+		Data:        data,
+	}, nil
+}
+
+func (ix *Indexer) lastRange() (blockRange, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	if len(ix.ranges) == 0 {
+		return blockRange{}, false
+	}
+	return ix.ranges[len(ix.ranges)-1], true
+}
+
+func (ix *Indexer) nextBlock() uint64 {
+	if last, ok := ix.lastRange(); ok {
+		return last.To + 1
+	}
+	return ix.startBlock
+}
+
+func (f Filter) matches(event *types.ContractEvent) bool {
+	if f.Name != "" && event.Name != f.Name {
+		return false
+	}
+	if f.Address != (common.Address{}) && common.HexToAddress(event.Address) != f.Address {
+		return false
+	}
+	if event.BlockNumber < f.FromBlock {
+		return false
+	}
+	return f.ToBlock == 0 || event.BlockNumber <= f.ToBlock
+}
+
+// jsonValue renders ABI values the way they are shown to API clients
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case common.Address:
+		return v.Hex()
+	case [32]byte:
+		return common.Hash(v).Hex()
+	case *big.Int:
+		return v.String()
+	case []byte:
+		return hexutil.Encode(v)
+	default:
+		return v
+	}
+}
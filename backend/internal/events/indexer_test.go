@@ -0,0 +1,286 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"bitbridge/internal/contracts/bindings"
+	"bitbridge/internal/store"
+	"bitbridge/internal/store/storetest"
+	"bitbridge/pkg/types"
+
+	goethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	registryAddr = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	verifierAddr = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+)
+
+// fakeChain serves a canonical chain of headers and their logs from memory
+type fakeChain struct {
+	headers []*ethtypes.Header
+	logs    map[common.Hash][]ethtypes.Log
+}
+
+func newFakeChain() *fakeChain {
+	c := &fakeChain{logs: make(map[common.Hash][]ethtypes.Log)}
+	c.extend("genesis")
+	return c
+}
+
+// extend appends a block with the given logs; tag keeps blocks at the same
+// height on different forks distinct
+func (c *fakeChain) extend(tag string, logs ...ethtypes.Log) {
+	header := &ethtypes.Header{
+		Number:     big.NewInt(int64(len(c.headers))),
+		Difficulty: big.NewInt(0),
+		Extra:      []byte(tag),
+	}
+	if len(c.headers) > 0 {
+		header.ParentHash = c.headers[len(c.headers)-1].Hash()
+	}
+
+	for i := range logs {
+		logs[i].BlockNumber = header.Number.Uint64()
+		logs[i].BlockHash = header.Hash()
+		logs[i].TxHash = common.BytesToHash([]byte(fmt.Sprintf("%s-%d-%d", tag, len(c.headers), i)))
+		logs[i].Index = uint(i)
+	}
+
+	c.headers = append(c.headers, header)
+	c.logs[header.Hash()] = logs
+}
+
+// truncate drops blocks above height
+func (c *fakeChain) truncate(height int) {
+	c.headers = c.headers[:height+1]
+}
+
+func (c *fakeChain) BlockNumber(ctx context.Context) (uint64, error) {
+	return uint64(len(c.headers) - 1), nil
+}
+
+func (c *fakeChain) HeaderByNumber(ctx context.Context, number *big.Int) (*ethtypes.Header, error) {
+	if !number.IsUint64() || number.Uint64() >= uint64(len(c.headers)) {
+		return nil, fmt.Errorf("block %s not found", number)
+	}
+	return c.headers[number.Uint64()], nil
+}
+
+func (c *fakeChain) FilterLogs(ctx context.Context, query goethereum.FilterQuery) ([]ethtypes.Log, error) {
+	var matched []ethtypes.Log
+	for n := query.FromBlock.Uint64(); n <= query.ToBlock.Uint64() && n < uint64(len(c.headers)); n++ {
+		for _, vLog := range c.logs[c.headers[n].Hash()] {
+			if containsAddress(query.Addresses, vLog.Address) && containsHash(query.Topics[0], vLog.Topics[0]) {
+				matched = append(matched, vLog)
+			}
+		}
+	}
+	return matched, nil
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+func containsHash(hashes []common.Hash, hash common.Hash) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+func contractABI(t *testing.T, name string) *abi.ABI {
+	t.Helper()
+
+	artifact, err := bindings.LoadArtifact(name)
+	if err != nil {
+		t.Fatalf("Failed to load %s artifact: %v", name, err)
+	}
+	parsed, err := artifact.ParsedABI()
+	if err != nil {
+		t.Fatalf("Failed to parse %s ABI: %v", name, err)
+	}
+	return parsed
+}
+
+func registeredLog(t *testing.T, txid string, amount int64) ethtypes.Log {
+	t.Helper()
+
+	event := contractABI(t, "UTXORegistry").Events["UTXORegistered"]
+	data, err := event.Inputs.NonIndexed().Pack(txid, uint32(0), big.NewInt(amount), common.HexToAddress("0x01"), common.HexToAddress("0x02"))
+	if err != nil {
+		t.Fatalf("Failed to pack UTXORegistered: %v", err)
+	}
+	return ethtypes.Log{
+		Address: registryAddr,
+		Topics:  []common.Hash{event.ID, common.BytesToHash([]byte(txid))},
+		Data:    data,
+	}
+}
+
+func headerStoredLog(t *testing.T, height int64) ethtypes.Log {
+	t.Helper()
+
+	event := contractABI(t, "SPVVerifier").Events["BlockHeaderStored"]
+	data, err := event.Inputs.NonIndexed().Pack(big.NewInt(height))
+	if err != nil {
+		t.Fatalf("Failed to pack BlockHeaderStored: %v", err)
+	}
+	return ethtypes.Log{
+		Address: verifierAddr,
+		Topics:  []common.Hash{event.ID, common.BigToHash(big.NewInt(height))},
+		Data:    data,
+	}
+}
+
+func newTestIndexer(t *testing.T, chain *fakeChain, repo store.Store) *Indexer {
+	t.Helper()
+
+	indexer, err := NewIndexer(Config{
+		ChainSource:     chain,
+		Store:           repo,
+		RegistryAddress: registryAddr,
+		VerifierAddress: verifierAddr,
+		StartBlock:      1,
+		BatchSize:       3,
+		ReorgDepth:      10,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create indexer: %v", err)
+	}
+	return indexer
+}
+
+func TestIndexerBackfillsInBatches(t *testing.T) {
+	chain := newFakeChain()
+	repo := storetest.Open(t)
+
+	chain.extend("main", registeredLog(t, "aa", 5000))
+	chain.extend("main")
+	chain.extend("main", headerStoredLog(t, 800000))
+	chain.extend("main")
+	chain.extend("main", registeredLog(t, "bb", 7000), headerStoredLog(t, 800001))
+
+	// Logs from an unindexed contract are ignored
+	foreign := registeredLog(t, "cc", 1)
+	foreign.Address = common.HexToAddress("0xcc")
+	chain.extend("main", foreign)
+
+	indexer := newTestIndexer(t, chain, repo)
+	if err := indexer.sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	all, err := indexer.Events(Filter{})
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("Expected 4 events, got %d", len(all))
+	}
+
+	registered, err := indexer.Events(Filter{Name: "UTXORegistered"})
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	if len(registered) != 2 || registered[1].Data["bitcoinTxId"] != "bb" || registered[1].Data["bitcoinAmount"] != "7000" {
+		t.Errorf("Unexpected UTXORegistered events: %+v", registered)
+	}
+
+	ranged, err := indexer.Events(Filter{Address: verifierAddr, FromBlock: 4, ToBlock: 5})
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	if len(ranged) != 1 || ranged[0].Name != "BlockHeaderStored" || ranged[0].Data["blockHeight"] != "800001" {
+		t.Errorf("Unexpected verifier events in blocks 4-5: %+v", ranged)
+	}
+
+	if last, ok := indexer.LastBlock(); !ok || last != 6 {
+		t.Errorf("Expected to have indexed through block 6, got %d", last)
+	}
+}
+
+func TestIndexerRewindsOnReorg(t *testing.T) {
+	chain := newFakeChain()
+	repo := storetest.Open(t)
+
+	chain.extend("main")
+	chain.extend("main", registeredLog(t, "aa", 5000))
+	chain.extend("main", registeredLog(t, "bb", 7000))
+
+	indexer := newTestIndexer(t, chain, repo)
+	updates, unsubscribe := indexer.Subscribe(Filter{Name: "UTXORegistered"})
+	defer unsubscribe()
+
+	if err := indexer.sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	for _, txid := range []string{"aa", "bb"} {
+		event := <-updates
+		if event.Data["bitcoinTxId"] != txid || event.Removed {
+			t.Fatalf("Expected new event for %s, got %+v", txid, event)
+		}
+	}
+
+	// Replace blocks 2 and 3 with a longer fork
+	chain.truncate(1)
+	chain.extend("fork", registeredLog(t, "cc", 9000))
+	chain.extend("fork")
+	chain.extend("fork")
+
+	if err := indexer.sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	var removed, added []*types.ContractEvent
+	for len(updates) > 0 {
+		event := <-updates
+		if event.Removed {
+			removed = append(removed, event)
+		} else {
+			added = append(added, event)
+		}
+	}
+	if len(removed) != 2 || len(added) != 1 || added[0].Data["bitcoinTxId"] != "cc" {
+		t.Errorf("Expected aa and bb removed and cc added, got removed %+v added %+v", removed, added)
+	}
+
+	all, err := indexer.Events(Filter{})
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	if len(all) != 1 || all[0].BlockHash != chain.headers[2].Hash().Hex() {
+		t.Errorf("Expected only the fork's event to remain, got %+v", all)
+	}
+
+	// A new indexer resumes from the checkpoint
+	chain.extend("fork", registeredLog(t, "dd", 1000))
+	resumed := newTestIndexer(t, chain, repo)
+	if last, ok := resumed.LastBlock(); !ok || last != 4 {
+		t.Fatalf("Expected checkpoint at block 4, got %d", last)
+	}
+	if err := resumed.sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	all, err = resumed.Events(Filter{})
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	if len(all) != 2 || all[1].Data["bitcoinTxId"] != "dd" {
+		t.Errorf("Expected cc and dd after resuming, got %+v", all)
+	}
+}
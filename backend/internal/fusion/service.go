@@ -16,12 +16,14 @@ import (
 type Service struct {
 	client    *Client
 	ethClient *ethereum.Client
+	nonces    *ethereum.NonceManager
 	chainID   int64
 }
 
 type ServiceConfig struct {
 	Client    *Client
 	EthClient *ethereum.Client
+	Nonces    *ethereum.NonceManager // shared sender for EthClient's key, required
 	ChainID   int64
 }
 
@@ -37,12 +39,19 @@ var (
 	USDC_SEPOLIA = "0x94a9D9AC8a22534E3FaCa9F4e7F2E2cf85d5E4C8"
 )
 
-func NewService(config ServiceConfig) *Service {
+// NewService creates a Fusion+ service that sends swaps through the
+// operator key's shared nonce manager
+func NewService(config ServiceConfig) (*Service, error) {
+	if config.Nonces == nil {
+		return nil, fmt.Errorf("nonce manager is required")
+	}
+
 	return &Service{
 		client:    config.Client,
 		ethClient: config.EthClient,
+		nonces:    config.Nonces,
 		chainID:   config.ChainID,
-	}
+	}, nil
 }
 
 // SwapUTXOToken swaps UTXO token for another token using 1inch
@@ -106,41 +115,15 @@ func (s *Service) ExecuteSwap(ctx context.Context, swapResponse *types.SwapRespo
 		value = big.NewInt(0)
 	}
 
-	gasLimit, err := strconv.ParseUint(swapResponse.Tx.Gas, 10, 64)
-	if err != nil {
-		return nil, fmt.Errorf("invalid gas limit: %s", swapResponse.Tx.Gas)
-	}
-
-	gasPrice, ok := new(big.Int).SetString(swapResponse.Tx.GasPrice, 10)
-	if !ok {
-		return nil, fmt.Errorf("invalid gas price: %s", swapResponse.Tx.GasPrice)
-	}
-
-	// Get nonce
-	nonce, err := s.ethClient.GetNonce(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get nonce: %w", err)
-	}
-
-	// Create transaction
+	// The aggregator's gas and legacy gas price are ignored; the nonce
+	// manager estimates gas and prices the transaction with EIP-1559 fees
 	data := common.FromHex(swapResponse.Tx.Data)
-	tx := ethtypes.NewTransaction(nonce, to, value, gasLimit, gasPrice, data)
-
-	// Sign transaction
-	chainID := s.ethClient.GetChainID()
-	signer := ethtypes.NewEIP155Signer(chainID)
-	signedTx, err := ethtypes.SignTx(tx, signer, s.ethClient.GetPrivateKey())
-	if err != nil {
-		return nil, fmt.Errorf("failed to sign transaction: %w", err)
-	}
-
-	// Send transaction
-	err = s.ethClient.SendTransaction(ctx, signedTx)
+	tx, err := s.nonces.SendTransaction(ctx, to, value, 0, data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send transaction: %w", err)
+		return nil, err
 	}
 
-	return signedTx, nil
+	return tx, nil
 }
 
 // GetBestQuote gets quotes from multiple sources and returns the best one
@@ -0,0 +1,546 @@
+package headers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"bitbridge/internal/store"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+var (
+	// ErrUnknownParent is returned for a header whose parent has not been
+	// connected
+	ErrUnknownParent = errors.New("unknown parent header")
+	// ErrInvalidHeader is returned for a header that fails proof-of-work
+	// or difficulty validation
+	ErrInvalidHeader = errors.New("invalid header")
+)
+
+// maxBranchLength bounds how far back a competing branch is fetched before
+// giving up on connecting it
+const maxBranchLength = 2016
+
+// BlockSource is the chain access the header chain needs; *bitcoin.Client
+// satisfies it
+type BlockSource interface {
+	GetBlockCount() (int64, error)
+	GetBlockHash(height int64) (string, error)
+	GetBlockHeader(hash string) (*wire.BlockHeader, error)
+	GetNetworkParams() *chaincfg.Params
+}
+
+// Config for the header chain
+type Config struct {
+	BlockSource  BlockSource
+	Store        store.HeaderRepository
+	StartHeight  int64 // anchor height, rounded down to a retarget boundary; 0 for the current period
+	PollInterval time.Duration
+}
+
+// Chain syncs block headers from the node and validates each one against
+// its proof-of-work target and the network's difficulty rules before
+// connecting it. Validation starts from an anchor header at a retarget
+// boundary, which is trusted; every header after it must chain back to it.
+// The branch with the most cumulative work is the best chain.
+type Chain struct {
+	source       BlockSource
+	params       *chaincfg.Params
+	store        store.HeaderRepository
+	startHeight  int64
+	pollInterval time.Duration
+	index        map[chainhash.Hash]*node
+	best         []*node // best chain, best[0] is the anchor
+	wake         chan struct{}
+	mu           sync.RWMutex
+	ctx          context.Context
+	cancel       context.CancelFunc
+}
+
+type node struct {
+	header wire.BlockHeader
+	hash   chainhash.Hash
+	height int32
+	work   *big.Int // cumulative work since the anchor
+	parent *node
+}
+
+// NewChain creates a header chain and reconnects any headers persisted by
+// a previous run
+func NewChain(config Config) (*Chain, error) {
+	if config.PollInterval <= 0 {
+		config.PollInterval = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	chain := &Chain{
+		source:       config.BlockSource,
+		params:       config.BlockSource.GetNetworkParams(),
+		store:        config.Store,
+		startHeight:  config.StartHeight,
+		pollInterval: config.PollInterval,
+		index:        make(map[chainhash.Hash]*node),
+		wake:         make(chan struct{}, 1),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+
+	if err := chain.load(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return chain, nil
+}
+
+// Start syncs headers in the background until Stop is called
+func (c *Chain) Start() {
+	log.Println("Starting header chain sync...")
+
+	go c.syncLoop()
+}
+
+func (c *Chain) Stop() {
+	log.Println("Stopping header chain sync...")
+	c.cancel()
+}
+
+// BestTip returns the height and hash of the best validated header
+func (c *Chain) BestTip() (int32, string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.best) == 0 {
+		return 0, "", false
+	}
+	tip := c.best[len(c.best)-1]
+	return tip.height, tip.hash.String(), true
+}
+
+// BestChainHeight returns the height of the block with the given hash if it
+// is on the validated best chain
+func (c *Chain) BestChainHeight(blockHash string) (int32, bool) {
+	hash, err := chainhash.NewHashFromStr(blockHash)
+	if err != nil {
+		return 0, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	n, ok := c.index[*hash]
+	if !ok || !c.onBestChain(n) {
+		return 0, false
+	}
+	return n.height, true
+}
+
+// HeaderAt returns the best chain header at height
+func (c *Chain) HeaderAt(height int32) (*wire.BlockHeader, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.best) == 0 {
+		return nil, false
+	}
+	i := int(height - c.best[0].height)
+	if i < 0 || i >= len(c.best) {
+		return nil, false
+	}
+	header := c.best[i].header
+	return &header, true
+}
+
+// AddHeader validates header against its parent and connects it, moving
+// the best chain if the header's branch now has the most work
+func (c *Chain) AddHeader(header *wire.BlockHeader) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n, err := c.connect(header)
+	if err != nil || n == nil {
+		return err
+	}
+
+	return c.persist(n)
+}
+
+// Notify makes the background sync run now rather than at its next poll
+func (c *Chain) Notify() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// HandleHeader connects a header pushed by the node. One that does not
+// extend a known header, as after missed notifications or a reorg, makes
+// the background sync fetch the branch instead.
+func (c *Chain) HandleHeader(header *wire.BlockHeader) {
+	err := c.AddHeader(header)
+	if errors.Is(err, ErrUnknownParent) {
+		c.Notify()
+		return
+	}
+	if err != nil {
+		log.Printf("Rejected pushed header %s: %v", header.BlockHash(), err)
+	}
+}
+
+func (c *Chain) syncLoop() {
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Sync(); err != nil {
+			log.Printf("Header chain sync failed: %v", err)
+		}
+
+		select {
+		case <-c.ctx.Done():
+			log.Println("Header chain sync stopped")
+			return
+		case <-ticker.C:
+		case <-c.wake:
+		}
+	}
+}
+
+// Sync fetches headers from the node up to its current tip, following the
+// node onto a competing branch if it has reorganized
+func (c *Chain) Sync() error {
+	count, err := c.source.GetBlockCount()
+	if err != nil {
+		return fmt.Errorf("failed to get block count: %v", err)
+	}
+	nodeTip := int32(count)
+
+	tipHeight, _, ok := c.BestTip()
+	if !ok {
+		if err := c.initAnchor(nodeTip); err != nil {
+			return err
+		}
+		tipHeight, _, _ = c.BestTip()
+	}
+
+	// Re-check our tip height too so that a same-height reorg is noticed
+	start := tipHeight
+	if nodeTip < start {
+		start = nodeTip
+	}
+
+	for height := start; height <= nodeTip; height++ {
+		if c.ctx.Err() != nil {
+			return nil
+		}
+
+		hash, err := c.source.GetBlockHash(int64(height))
+		if err != nil {
+			return fmt.Errorf("failed to get block hash at height %d: %v", height, err)
+		}
+		if err := c.fetchBranch(hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchBranch connects the header with the given hash, first fetching and
+// connecting any ancestors that are not yet known
+func (c *Chain) fetchBranch(blockHash string) error {
+	var branch []*wire.BlockHeader
+
+	for {
+		hash, err := chainhash.NewHashFromStr(blockHash)
+		if err != nil {
+			return err
+		}
+		if c.known(*hash) {
+			break
+		}
+		if len(branch) >= maxBranchLength {
+			return fmt.Errorf("branch at %s is longer than %d headers", blockHash, maxBranchLength)
+		}
+
+		header, err := c.source.GetBlockHeader(blockHash)
+		if err != nil {
+			return fmt.Errorf("failed to get block header %s: %v", blockHash, err)
+		}
+		branch = append(branch, header)
+		blockHash = header.PrevBlock.String()
+	}
+
+	for i := len(branch) - 1; i >= 0; i-- {
+		if err := c.AddHeader(branch[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initAnchor trusts the header at the configured start height, rounded down
+// to a retarget boundary so that every later retarget can be checked
+func (c *Chain) initAnchor(nodeTip int32) error {
+	height := int32(c.startHeight)
+	if height <= 0 || height > nodeTip {
+		height = nodeTip
+	}
+	height -= height % c.blocksPerRetarget()
+
+	hash, err := c.source.GetBlockHash(int64(height))
+	if err != nil {
+		return fmt.Errorf("failed to get anchor block hash: %v", err)
+	}
+	header, err := c.source.GetBlockHeader(hash)
+	if err != nil {
+		return fmt.Errorf("failed to get anchor header: %v", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n, err := c.setAnchor(header, height)
+	if err != nil {
+		return err
+	}
+	log.Printf("Header chain anchored at block %d (%s)", height, hash)
+	return c.persist(n)
+}
+
+func (c *Chain) setAnchor(header *wire.BlockHeader, height int32) (*node, error) {
+	if err := c.checkProofOfWork(header); err != nil {
+		return nil, err
+	}
+
+	n := &node{
+		header: *header,
+		hash:   header.BlockHash(),
+		height: height,
+		work:   blockchain.CalcWork(header.Bits),
+	}
+	c.index[n.hash] = n
+	c.best = []*node{n}
+	return n, nil
+}
+
+// connect validates and indexes header. It returns nil if the header was
+// already known. Called with c.mu held.
+func (c *Chain) connect(header *wire.BlockHeader) (*node, error) {
+	hash := header.BlockHash()
+	if _, ok := c.index[hash]; ok {
+		return nil, nil
+	}
+
+	parent, ok := c.index[header.PrevBlock]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownParent, header.PrevBlock)
+	}
+
+	if err := c.checkProofOfWork(header); err != nil {
+		return nil, err
+	}
+	expected := c.nextRequiredBits(parent, header.Timestamp)
+	if header.Bits != expected {
+		return nil, fmt.Errorf("%w: block %s has bits %08x, expected %08x",
+			ErrInvalidHeader, hash, header.Bits, expected)
+	}
+
+	n := &node{
+		header: *header,
+		hash:   hash,
+		height: parent.height + 1,
+		work:   new(big.Int).Add(parent.work, blockchain.CalcWork(header.Bits)),
+		parent: parent,
+	}
+	c.index[hash] = n
+
+	if tip := c.best[len(c.best)-1]; n.work.Cmp(tip.work) > 0 {
+		c.setBestTip(n)
+	}
+	return n, nil
+}
+
+// setBestTip rewrites the best chain from the fork point up to n. Called
+// with c.mu held.
+func (c *Chain) setBestTip(n *node) {
+	var branch []*node
+	for cur := n; !c.onBestChain(cur); cur = cur.parent {
+		branch = append(branch, cur)
+	}
+
+	forkHeight := n.height - int32(len(branch))
+	if tip := c.best[len(c.best)-1]; tip.height > forkHeight {
+		log.Printf("Header chain reorganized at height %d, new tip %d (%s)", forkHeight, n.height, n.hash)
+	}
+
+	c.best = c.best[:forkHeight-c.best[0].height+1]
+	for i := len(branch) - 1; i >= 0; i-- {
+		c.best = append(c.best, branch[i])
+	}
+}
+
+// onBestChain reports whether n is part of the current best chain. Called
+// with c.mu held.
+func (c *Chain) onBestChain(n *node) bool {
+	i := int(n.height - c.best[0].height)
+	return i >= 0 && i < len(c.best) && c.best[i] == n
+}
+
+func (c *Chain) known(hash chainhash.Hash) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.index[hash]
+	return ok
+}
+
+// checkProofOfWork ensures the header's target is within the network limit
+// and that its hash meets the target
+func (c *Chain) checkProofOfWork(header *wire.BlockHeader) error {
+	target := blockchain.CompactToBig(header.Bits)
+	if target.Sign() <= 0 || target.Cmp(c.params.PowLimit) > 0 {
+		return fmt.Errorf("%w: target %064x is outside the network limit", ErrInvalidHeader, target)
+	}
+
+	hash := header.BlockHash()
+	if blockchain.HashToBig(&hash).Cmp(target) > 0 {
+		return fmt.Errorf("%w: block %s does not meet its target", ErrInvalidHeader, hash)
+	}
+	return nil
+}
+
+// nextRequiredBits returns the bits a block built on last must carry,
+// following the same rules as Bitcoin Core
+func (c *Chain) nextRequiredBits(last *node, timestamp time.Time) uint32 {
+	if c.params.PoWNoRetargeting {
+		return c.params.PowLimitBits
+	}
+
+	interval := c.blocksPerRetarget()
+	if (last.height+1)%interval != 0 {
+		if c.params.ReduceMinDifficulty {
+			// Testnet allows a minimum difficulty block once twice the
+			// target spacing has passed without one
+			allowMinTime := last.header.Timestamp.Add(c.params.MinDiffReductionTime)
+			if timestamp.After(allowMinTime) {
+				return c.params.PowLimitBits
+			}
+			return c.lastNonMinimumBits(last)
+		}
+		return last.header.Bits
+	}
+
+	first := last
+	for i := int32(0); i < interval-1 && first.parent != nil; i++ {
+		first = first.parent
+	}
+
+	targetTimespan := int64(c.params.TargetTimespan / time.Second)
+	minTimespan := targetTimespan / c.params.RetargetAdjustmentFactor
+	maxTimespan := targetTimespan * c.params.RetargetAdjustmentFactor
+
+	timespan := last.header.Timestamp.Unix() - first.header.Timestamp.Unix()
+	if timespan < minTimespan {
+		timespan = minTimespan
+	} else if timespan > maxTimespan {
+		timespan = maxTimespan
+	}
+
+	target := blockchain.CompactToBig(last.header.Bits)
+	target.Mul(target, big.NewInt(timespan))
+	target.Div(target, big.NewInt(targetTimespan))
+	if target.Cmp(c.params.PowLimit) > 0 {
+		target.Set(c.params.PowLimit)
+	}
+
+	return blockchain.BigToCompact(target)
+}
+
+// lastNonMinimumBits walks back to the most recent block in the period that
+// was not mined under the testnet minimum difficulty rule
+func (c *Chain) lastNonMinimumBits(n *node) uint32 {
+	interval := c.blocksPerRetarget()
+	for n.parent != nil && n.height%interval != 0 && n.header.Bits == c.params.PowLimitBits {
+		n = n.parent
+	}
+	return n.header.Bits
+}
+
+func (c *Chain) blocksPerRetarget() int32 {
+	return int32(c.params.TargetTimespan / c.params.TargetTimePerBlock)
+}
+
+// persist saves a connected header. Called with c.mu held.
+func (c *Chain) persist(n *node) error {
+	if c.store == nil {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := n.header.Serialize(&buf); err != nil {
+		return err
+	}
+
+	if err := c.store.SaveHeader(&store.HeaderRecord{
+		Hash:   n.hash.String(),
+		Height: n.height,
+		Header: buf.Bytes(),
+	}); err != nil {
+		return fmt.Errorf("failed to persist header %s: %v", n.hash, err)
+	}
+	return nil
+}
+
+// load reconnects persisted headers, re-validating each one. The lowest
+// stored header is the anchor.
+func (c *Chain) load() error {
+	if c.store == nil {
+		return nil
+	}
+
+	records, err := c.store.ListHeaders()
+	if err != nil {
+		return fmt.Errorf("failed to load headers: %v", err)
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Height < records[j].Height })
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, record := range records {
+		var header wire.BlockHeader
+		if err := header.Deserialize(bytes.NewReader(record.Header)); err != nil {
+			return fmt.Errorf("failed to decode header %s: %v", record.Hash, err)
+		}
+
+		if i == 0 {
+			_, err = c.setAnchor(&header, record.Height)
+		} else {
+			_, err = c.connect(&header)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to reconnect header %s: %v", record.Hash, err)
+		}
+	}
+
+	tip := c.best[len(c.best)-1]
+	log.Printf("Restored %d headers, best tip %d (%s)", len(records), tip.height, tip.hash)
+	return nil
+}
@@ -0,0 +1,243 @@
+package headers
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"bitbridge/internal/store"
+	"bitbridge/internal/store/storetest"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// retargetParams retargets every 4 blocks with regtest's easy limit so
+// that tests can mine headers instantly
+func retargetParams() *chaincfg.Params {
+	params := chaincfg.RegressionNetParams
+	params.PoWNoRetargeting = false
+	params.TargetTimePerBlock = 10 * time.Minute
+	params.TargetTimespan = 40 * time.Minute
+	return &params
+}
+
+type fakeSource struct {
+	params  *chaincfg.Params
+	best    []*wire.BlockHeader
+	headers map[string]*wire.BlockHeader
+}
+
+func newFakeSource(params *chaincfg.Params, genesis *wire.BlockHeader) *fakeSource {
+	s := &fakeSource{params: params, headers: make(map[string]*wire.BlockHeader)}
+	s.add(genesis)
+	return s
+}
+
+func (s *fakeSource) add(header *wire.BlockHeader) {
+	s.best = append(s.best, header)
+	s.headers[header.BlockHash().String()] = header
+}
+
+func (s *fakeSource) GetBlockCount() (int64, error) {
+	return int64(len(s.best) - 1), nil
+}
+
+func (s *fakeSource) GetBlockHash(height int64) (string, error) {
+	if height < 0 || height >= int64(len(s.best)) {
+		return "", fmt.Errorf("block height %d out of range", height)
+	}
+	return s.best[height].BlockHash().String(), nil
+}
+
+func (s *fakeSource) GetBlockHeader(hash string) (*wire.BlockHeader, error) {
+	header, ok := s.headers[hash]
+	if !ok {
+		return nil, fmt.Errorf("header %s not found", hash)
+	}
+	return header, nil
+}
+
+func (s *fakeSource) GetNetworkParams() *chaincfg.Params {
+	return s.params
+}
+
+// mine builds a header on prev whose hash meets (or, if valid is false,
+// misses) the target encoded in bits
+func mine(t *testing.T, prev *wire.BlockHeader, bits uint32, timestamp time.Time, valid bool) *wire.BlockHeader {
+	t.Helper()
+
+	var prevHash chainhash.Hash
+	if prev != nil {
+		prevHash = prev.BlockHash()
+	}
+
+	target := blockchain.CompactToBig(bits)
+	header := wire.NewBlockHeader(1, &prevHash, &chainhash.Hash{}, bits, 0)
+	header.Timestamp = timestamp
+
+	for nonce := uint32(0); nonce < 1<<20; nonce++ {
+		header.Nonce = nonce
+		hash := header.BlockHash()
+		if (blockchain.HashToBig(&hash).Cmp(target) <= 0) == valid {
+			return header
+		}
+	}
+
+	t.Fatal("Failed to mine header")
+	return nil
+}
+
+func newTestChain(t *testing.T, source *fakeSource, repo store.HeaderRepository) *Chain {
+	t.Helper()
+
+	chain, err := NewChain(Config{BlockSource: source, Store: repo})
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	return chain
+}
+
+func TestChainValidatesRetarget(t *testing.T) {
+	params := retargetParams()
+	start := time.Unix(1700000000, 0)
+
+	genesis := mine(t, nil, params.PowLimitBits, start, true)
+	source := newFakeSource(params, genesis)
+	chain := newTestChain(t, source, nil)
+	if err := chain.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	// Blocks one minute apart make the period far too fast
+	prev := genesis
+	for i := 1; i < 4; i++ {
+		prev = mine(t, prev, params.PowLimitBits, start.Add(time.Duration(i)*time.Minute), true)
+		if err := chain.AddHeader(prev); err != nil {
+			t.Fatalf("Failed to add header %d: %v", i, err)
+		}
+	}
+
+	timestamp := start.Add(4 * time.Minute)
+	stale := mine(t, prev, params.PowLimitBits, timestamp, true)
+	if err := chain.AddHeader(stale); !errors.Is(err, ErrInvalidHeader) {
+		t.Fatalf("Expected retarget header with old bits to be rejected, got %v", err)
+	}
+
+	// The adjustment is clamped to a factor of 4
+	target := new(big.Int).Div(blockchain.CompactToBig(params.PowLimitBits), big.NewInt(4))
+	retargeted := mine(t, prev, blockchain.BigToCompact(target), timestamp, true)
+	if err := chain.AddHeader(retargeted); err != nil {
+		t.Fatalf("Expected retargeted header to be accepted: %v", err)
+	}
+
+	height, ok := chain.BestChainHeight(retargeted.BlockHash().String())
+	if !ok || height != 4 {
+		t.Errorf("Expected retargeted header at height 4 on best chain, got %d %v", height, ok)
+	}
+}
+
+func TestChainRejectsInsufficientWork(t *testing.T) {
+	params := &chaincfg.RegressionNetParams
+	start := time.Unix(1700000000, 0)
+
+	genesis := mine(t, nil, params.PowLimitBits, start, true)
+	chain := newTestChain(t, newFakeSource(params, genesis), nil)
+	if err := chain.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	forged := mine(t, genesis, params.PowLimitBits, start.Add(time.Minute), false)
+	if err := chain.AddHeader(forged); !errors.Is(err, ErrInvalidHeader) {
+		t.Fatalf("Expected header that misses its target to be rejected, got %v", err)
+	}
+
+	orphan := mine(t, forged, params.PowLimitBits, start.Add(2*time.Minute), true)
+	if err := chain.AddHeader(orphan); !errors.Is(err, ErrUnknownParent) {
+		t.Fatalf("Expected header with unknown parent to be rejected, got %v", err)
+	}
+
+	if _, ok := chain.BestChainHeight(forged.BlockHash().String()); ok {
+		t.Error("Forged header must not be on the best chain")
+	}
+}
+
+func TestChainFollowsMostWork(t *testing.T) {
+	params := &chaincfg.RegressionNetParams
+	start := time.Unix(1700000000, 0)
+
+	genesis := mine(t, nil, params.PowLimitBits, start, true)
+	source := newFakeSource(params, genesis)
+	first := mine(t, genesis, params.PowLimitBits, start.Add(time.Minute), true)
+	second := mine(t, first, params.PowLimitBits, start.Add(2*time.Minute), true)
+	source.add(first)
+	source.add(second)
+
+	repo := storetest.Open(t)
+
+	chain := newTestChain(t, source, repo)
+	if err := chain.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	// The node switches to a longer branch forking after the first block
+	forkSecond := mine(t, first, params.PowLimitBits, start.Add(3*time.Minute), true)
+	forkThird := mine(t, forkSecond, params.PowLimitBits, start.Add(4*time.Minute), true)
+	source.best = source.best[:2]
+	source.add(forkSecond)
+	source.add(forkThird)
+
+	if err := chain.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	height, hash, _ := chain.BestTip()
+	if height != 3 || hash != forkThird.BlockHash().String() {
+		t.Errorf("Expected fork tip at height 3, got %d %s", height, hash)
+	}
+	if _, ok := chain.BestChainHeight(second.BlockHash().String()); ok {
+		t.Error("Orphaned header must not be on the best chain")
+	}
+
+	reloaded := newTestChain(t, source, repo)
+	if height, hash, _ := reloaded.BestTip(); height != 3 || hash != forkThird.BlockHash().String() {
+		t.Errorf("Expected reloaded chain at fork tip, got %d %s", height, hash)
+	}
+}
+
+func TestChainHandlesPushedHeaders(t *testing.T) {
+	params := &chaincfg.RegressionNetParams
+	start := time.Unix(1700000000, 0)
+
+	genesis := mine(t, nil, params.PowLimitBits, start, true)
+	source := newFakeSource(params, genesis)
+	chain := newTestChain(t, source, nil)
+	if err := chain.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	first := mine(t, genesis, params.PowLimitBits, start.Add(time.Minute), true)
+	chain.HandleHeader(first)
+	if height, hash, _ := chain.BestTip(); height != 1 || hash != first.BlockHash().String() {
+		t.Fatalf("Expected pushed header at height 1, got %d %s", height, hash)
+	}
+	if len(chain.wake) != 0 {
+		t.Error("Expected a header extending the tip to connect without a sync")
+	}
+
+	// A missed notification leaves a gap the sync has to fill
+	second := mine(t, first, params.PowLimitBits, start.Add(2*time.Minute), true)
+	third := mine(t, second, params.PowLimitBits, start.Add(3*time.Minute), true)
+	chain.HandleHeader(third)
+	chain.HandleHeader(third)
+	if height, _, _ := chain.BestTip(); height != 1 {
+		t.Errorf("Expected a header with an unknown parent to be held back, got tip %d", height)
+	}
+	if len(chain.wake) != 1 {
+		t.Error("Expected a header with an unknown parent to wake the sync once")
+	}
+}
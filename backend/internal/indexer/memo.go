@@ -0,0 +1,134 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// Deposit memos let depositors pay a single shared bridge address and name
+// the Ethereum recipient in an OP_RETURN output of the same transaction.
+// The payload is
+//
+//	"BTB" | version (1 byte) | recipient (20 bytes) [| checksum (4 bytes)]
+//
+// where the optional checksum is the first four bytes of the double SHA-256
+// of everything before it.
+const (
+	MemoVersion1 = 0x01
+
+	memoRecipientLen = 20
+	memoChecksumLen  = 4
+)
+
+var memoMagic = []byte("BTB")
+
+// Memo decoding errors. ErrNoMemo means the transaction carries no bridge
+// memo at all, the others that it carries one that cannot be trusted.
+var (
+	ErrNoMemo            = errors.New("no deposit memo")
+	ErrMultipleMemos     = errors.New("multiple deposit memos")
+	ErrMemoVersion       = errors.New("unsupported deposit memo version")
+	ErrMemoLength        = errors.New("invalid deposit memo length")
+	ErrMemoChecksum      = errors.New("deposit memo checksum mismatch")
+	ErrMemoZeroRecipient = errors.New("deposit memo names the zero address")
+)
+
+// EncodeMemo returns the memo payload for a recipient
+func EncodeMemo(recipient [20]byte, withChecksum bool) []byte {
+	payload := make([]byte, 0, len(memoMagic)+1+memoRecipientLen+memoChecksumLen)
+	payload = append(payload, memoMagic...)
+	payload = append(payload, MemoVersion1)
+	payload = append(payload, recipient[:]...)
+	if withChecksum {
+		payload = append(payload, memoChecksum(payload)...)
+	}
+	return payload
+}
+
+// MemoScript returns the OP_RETURN output script carrying a memo payload
+func MemoScript(payload []byte) ([]byte, error) {
+	return txscript.NullDataScript(payload)
+}
+
+// DecodeMemo parses a memo payload and returns the recipient as a lowercase
+// 0x-prefixed hex address
+func DecodeMemo(payload []byte) (string, error) {
+	if !bytes.HasPrefix(payload, memoMagic) {
+		return "", ErrNoMemo
+	}
+	body := payload[len(memoMagic):]
+	if len(body) == 0 {
+		return "", ErrMemoLength
+	}
+	if body[0] != MemoVersion1 {
+		return "", fmt.Errorf("%w: %d", ErrMemoVersion, body[0])
+	}
+
+	withoutChecksum := len(memoMagic) + 1 + memoRecipientLen
+	switch len(payload) {
+	case withoutChecksum:
+	case withoutChecksum + memoChecksumLen:
+		if !bytes.Equal(payload[withoutChecksum:], memoChecksum(payload[:withoutChecksum])) {
+			return "", ErrMemoChecksum
+		}
+	default:
+		return "", fmt.Errorf("%w: %d bytes", ErrMemoLength, len(payload))
+	}
+
+	recipient := body[1 : 1+memoRecipientLen]
+	if bytes.Equal(recipient, make([]byte, memoRecipientLen)) {
+		return "", ErrMemoZeroRecipient
+	}
+	return "0x" + hex.EncodeToString(recipient), nil
+}
+
+// TransactionMemo finds and decodes the bridge memo among a transaction's
+// OP_RETURN outputs. OP_RETURN outputs of other protocols are ignored.
+func TransactionMemo(tx *wire.MsgTx) (string, error) {
+	var payloads [][]byte
+	for _, out := range tx.TxOut {
+		payload, ok := nullData(out.PkScript)
+		if ok && bytes.HasPrefix(payload, memoMagic) {
+			payloads = append(payloads, payload)
+		}
+	}
+
+	switch len(payloads) {
+	case 0:
+		return "", ErrNoMemo
+	case 1:
+		return DecodeMemo(payloads[0])
+	default:
+		return "", ErrMultipleMemos
+	}
+}
+
+// nullData returns the data pushed by an OP_RETURN script
+func nullData(script []byte) ([]byte, bool) {
+	if len(script) == 0 || script[0] != txscript.OP_RETURN {
+		return nil, false
+	}
+
+	var data []byte
+	tokenizer := txscript.MakeScriptTokenizer(0, script[1:])
+	for tokenizer.Next() {
+		if tokenizer.Opcode() > txscript.OP_PUSHDATA4 {
+			return nil, false
+		}
+		data = append(data, tokenizer.Data()...)
+	}
+	if tokenizer.Err() != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+func memoChecksum(data []byte) []byte {
+	return chainhash.DoubleHashB(data)[:memoChecksumLen]
+}
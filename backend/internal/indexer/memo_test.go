@@ -0,0 +1,140 @@
+package indexer
+
+import (
+	"errors"
+	"testing"
+
+	"bitbridge/internal/store/storetest"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+var memoRecipient = [20]byte{0xde, 0xad, 0xbe, 0xef, 19: 0x01}
+
+const memoRecipientHex = "0xdeadbeef00000000000000000000000000000001"
+
+func TestMemoRoundTrip(t *testing.T) {
+	for _, withChecksum := range []bool{false, true} {
+		recipient, err := DecodeMemo(EncodeMemo(memoRecipient, withChecksum))
+		if err != nil {
+			t.Fatalf("Failed to decode memo (checksum %v): %v", withChecksum, err)
+		}
+		if recipient != memoRecipientHex {
+			t.Errorf("Expected recipient %s, got %s", memoRecipientHex, recipient)
+		}
+	}
+}
+
+func TestMemoRejectsMalformedPayloads(t *testing.T) {
+	corrupted := EncodeMemo(memoRecipient, true)
+	corrupted[len(corrupted)-1] ^= 0xff
+
+	unknownVersion := EncodeMemo(memoRecipient, false)
+	unknownVersion[3] = 0x02
+
+	tests := []struct {
+		name    string
+		payload []byte
+		want    error
+	}{
+		{"other protocol", []byte("omni payload"), ErrNoMemo},
+		{"unknown version", unknownVersion, ErrMemoVersion},
+		{"truncated", EncodeMemo(memoRecipient, false)[:10], ErrMemoLength},
+		{"bad checksum", corrupted, ErrMemoChecksum},
+		{"zero recipient", EncodeMemo([20]byte{}, true), ErrMemoZeroRecipient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeMemo(tt.payload); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestTransactionMemo(t *testing.T) {
+	memoOutput := func(payload []byte) *wire.TxOut {
+		script, err := MemoScript(payload)
+		if err != nil {
+			t.Fatalf("Failed to build memo script: %v", err)
+		}
+		return wire.NewTxOut(0, script)
+	}
+
+	tx := payTo(t, testAddress(t, 1), 5000)
+	if _, err := TransactionMemo(tx); !errors.Is(err, ErrNoMemo) {
+		t.Errorf("Expected ErrNoMemo, got %v", err)
+	}
+
+	// OP_RETURN outputs of other protocols are skipped
+	tx.AddTxOut(memoOutput([]byte("omni payload")))
+	tx.AddTxOut(memoOutput(EncodeMemo(memoRecipient, true)))
+	recipient, err := TransactionMemo(tx)
+	if err != nil {
+		t.Fatalf("Failed to find memo: %v", err)
+	}
+	if recipient != memoRecipientHex {
+		t.Errorf("Expected recipient %s, got %s", memoRecipientHex, recipient)
+	}
+
+	tx.AddTxOut(memoOutput(EncodeMemo([20]byte{1}, false)))
+	if _, err := TransactionMemo(tx); !errors.Is(err, ErrMultipleMemos) {
+		t.Errorf("Expected ErrMultipleMemos, got %v", err)
+	}
+}
+
+func TestMonitorAttributesMemoDeposits(t *testing.T) {
+	chain := newFakeChain()
+	repo := storetest.Open(t)
+	shared := testAddress(t, 9)
+
+	monitor, err := NewUTXOMonitor(MonitorConfig{
+		BlockSource: chain,
+		Store:       repo,
+		StartHeight: 1,
+		MemoAddress: shared,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+
+	tagged := payTo(t, shared, 5000)
+	script, err := txscript.NullDataScript(EncodeMemo(memoRecipient, true))
+	if err != nil {
+		t.Fatalf("Failed to build memo script: %v", err)
+	}
+	tagged.AddTxOut(wire.NewTxOut(0, script))
+	untagged := payTo(t, shared, 7000)
+	untagged.TxIn[0].PreviousOutPoint.Index = 1
+	chain.extend("main", tagged, untagged)
+
+	if err := monitor.sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	utxo, ok := monitor.GetUTXO(tagged.TxHash().String(), 0)
+	if !ok {
+		t.Fatal("Expected memo deposit to be tracked")
+	}
+	if utxo.Recipient != memoRecipientHex || utxo.MemoError != "" {
+		t.Errorf("Expected recipient %s, got %+v", memoRecipientHex, utxo)
+	}
+
+	utxo, ok = monitor.GetUTXO(untagged.TxHash().String(), 0)
+	if !ok {
+		t.Fatal("Expected deposit without memo to be tracked")
+	}
+	if utxo.Recipient != "" || utxo.MemoError != ErrNoMemo.Error() {
+		t.Errorf("Expected deposit to be unattributed, got %+v", utxo)
+	}
+
+	addresses, err := repo.ListWatchedAddresses()
+	if err != nil {
+		t.Fatalf("Failed to list watched addresses: %v", err)
+	}
+	if len(addresses) != 1 || addresses[0] != shared {
+		t.Errorf("Expected the memo address to be watched, got %v", addresses)
+	}
+}
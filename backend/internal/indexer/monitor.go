@@ -2,52 +2,209 @@ package indexer
 
 import (
 	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
 	"time"
 
-	"bitbridge/internal/bitcoin"
+	"bitbridge/internal/store"
 	"bitbridge/pkg/types"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// Events emitted to UTXO callbacks
+const (
+	EventNew                = "new"
+	EventConfirmationUpdate = "confirmation_update"
+	EventSpent              = "spent"
+	// EventReorg is emitted when a block touching the UTXO is orphaned. A
+	// UTXO whose creating block was orphaned has BlockHeight 0; one whose
+	// spending block was orphaned is unspent again.
+	EventReorg = "reorg"
 )
 
+const checkpointKey = "indexer_checkpoint"
+
+// BlockSource is the chain access the monitor needs; *bitcoin.Client
+// satisfies it
+type BlockSource interface {
+	GetBlockCount() (int64, error)
+	GetBlockHash(height int64) (string, error)
+	GetBlock(hash string) (*wire.MsgBlock, error)
+	GetNetworkParams() *chaincfg.Params
+}
+
+// MonitorConfig for the UTXO monitor
+type MonitorConfig struct {
+	BlockSource  BlockSource
+	Store        store.Store
+	StartHeight  int64 // first block to scan when there is no checkpoint, 0 for the current tip
+	PollInterval time.Duration
+	ReorgDepth   int    // recent blocks remembered for reorg detection
+	MemoAddress  string // shared deposit address whose deposits name their recipient in an OP_RETURN memo
+}
+
+// UTXOMonitor walks the best chain block by block from a stored checkpoint
+// and tracks outputs paying to watched addresses. Recently connected blocks
+// are remembered so that a reorg can be detected through prev-hashes and
+// rolled back to the fork point.
 type UTXOMonitor struct {
-	btcClient      *bitcoin.Client
-	watchAddresses map[string]bool
-	utxoStore      map[string]*types.UTXO
-	callbacks      []UTXOCallback
-	mu             sync.RWMutex
-	ctx            context.Context
-	cancel         context.CancelFunc
+	source       BlockSource
+	params       *chaincfg.Params
+	store        store.Store
+	watchScripts map[string]string // hex scriptPubKey -> address
+	memoScript   string            // hex scriptPubKey of the shared memo address
+	utxoStore    map[string]*types.UTXO
+	blocks       []blockRef // connected blocks, oldest first
+	startHeight  int64
+	pollInterval time.Duration
+	reorgDepth   int
+	callbacks    []*callbackQueue
+	wake         chan struct{}
+	mu           sync.RWMutex
+	ctx          context.Context
+	cancel       context.CancelFunc
 }
 
 type UTXOCallback func(utxo *types.UTXO, event string)
 
-func NewUTXOMonitor(btcClient *bitcoin.Client) *UTXOMonitor {
+type blockRef struct {
+	Height int64  `json:"height"`
+	Hash   string `json:"hash"`
+}
+
+type checkpoint struct {
+	Blocks []blockRef `json:"blocks"`
+}
+
+type utxoEvent struct {
+	utxo  types.UTXO
+	event string
+}
+
+// callbackQueue delivers events to one callback on its own goroutine, in
+// the order they were emitted, so a slow callback holds up neither the
+// monitor nor the other callbacks
+type callbackQueue struct {
+	callback UTXOCallback
+	events   []utxoEvent
+	ready    chan struct{}
+	mu       sync.Mutex
+}
+
+// NewUTXOMonitor creates a monitor that restores its watched addresses,
+// known UTXOs and chain checkpoint from the store
+func NewUTXOMonitor(config MonitorConfig) (*UTXOMonitor, error) {
+	if config.PollInterval <= 0 {
+		config.PollInterval = 10 * time.Second
+	}
+	if config.ReorgDepth <= 0 {
+		config.ReorgDepth = 100
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
-	
-	return &UTXOMonitor{
-		btcClient:      btcClient,
-		watchAddresses: make(map[string]bool),
-		utxoStore:      make(map[string]*types.UTXO),
-		callbacks:      make([]UTXOCallback, 0),
-		ctx:            ctx,
-		cancel:         cancel,
+
+	monitor := &UTXOMonitor{
+		source:       config.BlockSource,
+		params:       config.BlockSource.GetNetworkParams(),
+		store:        config.Store,
+		watchScripts: make(map[string]string),
+		utxoStore:    make(map[string]*types.UTXO),
+		startHeight:  config.StartHeight,
+		pollInterval: config.PollInterval,
+		reorgDepth:   config.ReorgDepth,
+		callbacks:    make([]*callbackQueue, 0),
+		wake:         make(chan struct{}, 1),
+		ctx:          ctx,
+		cancel:       cancel,
 	}
+
+	addresses, err := config.Store.ListWatchedAddresses()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to load watched addresses: %v", err)
+	}
+	for _, address := range addresses {
+		script, err := monitor.addressScript(address)
+		if err != nil {
+			log.Printf("Skipping watched address %s: %v", address, err)
+			continue
+		}
+		monitor.watchScripts[script] = address
+	}
+
+	if config.MemoAddress != "" {
+		script, err := monitor.addressScript(config.MemoAddress)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid memo address: %v", err)
+		}
+		if err := config.Store.AddWatchedAddress(config.MemoAddress); err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to persist memo address: %v", err)
+		}
+		monitor.watchScripts[script] = config.MemoAddress
+		monitor.memoScript = script
+	}
+
+	utxos, err := config.Store.ListUTXOs()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to load UTXOs: %v", err)
+	}
+	for _, utxo := range utxos {
+		monitor.utxoStore[utxoKey(utxo.TxID, utxo.Vout)] = utxo
+	}
+
+	data, err := config.Store.GetState(checkpointKey)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		cancel()
+		return nil, fmt.Errorf("failed to load indexer checkpoint: %v", err)
+	}
+	if err == nil {
+		var cp checkpoint
+		if err := json.Unmarshal(data, &cp); err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to decode indexer checkpoint: %v", err)
+		}
+		monitor.blocks = cp.Blocks
+	}
+
+	if tip, ok := monitor.lastBlock(); ok {
+		log.Printf("Restored %d watched addresses and %d UTXOs, resuming after block %d",
+			len(monitor.watchScripts), len(utxos), tip.Height)
+	} else {
+		log.Printf("Restored %d watched addresses and %d UTXOs", len(monitor.watchScripts), len(utxos))
+	}
+	return monitor, nil
 }
 
+// AddWatchAddress starts matching outputs to address. Only blocks after
+// the current checkpoint are scanned, so outputs confirmed earlier are not
+// picked up.
 func (m *UTXOMonitor) AddWatchAddress(address string) error {
+	script, err := m.addressScript(address)
+	if err != nil {
+		return fmt.Errorf("failed to watch address: %v", err)
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	err := m.btcClient.WatchAddress(address)
-	if err != nil {
-		return fmt.Errorf("failed to watch address: %v", err)
+	if err := m.store.AddWatchedAddress(address); err != nil {
+		return fmt.Errorf("failed to persist watched address: %v", err)
 	}
 
-	m.watchAddresses[address] = true
+	m.watchScripts[script] = address
 	log.Printf("Now watching Bitcoin address: %s", address)
-	
+
 	return nil
 }
 
@@ -55,7 +212,15 @@ func (m *UTXOMonitor) RemoveWatchAddress(address string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	delete(m.watchAddresses, address)
+	if err := m.store.RemoveWatchedAddress(address); err != nil {
+		log.Printf("Failed to remove watched address %s from store: %v", address, err)
+	}
+
+	for script, watched := range m.watchScripts {
+		if watched == address {
+			delete(m.watchScripts, script)
+		}
+	}
 	log.Printf("Stopped watching Bitcoin address: %s", address)
 }
 
@@ -63,12 +228,14 @@ func (m *UTXOMonitor) AddCallback(callback UTXOCallback) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.callbacks = append(m.callbacks, callback)
+	queue := &callbackQueue{callback: callback, ready: make(chan struct{}, 1)}
+	m.callbacks = append(m.callbacks, queue)
+	go queue.run(m.ctx)
 }
 
 func (m *UTXOMonitor) Start() {
 	log.Println("Starting UTXO monitor...")
-	
+
 	go m.monitorLoop()
 }
 
@@ -77,107 +244,454 @@ func (m *UTXOMonitor) Stop() {
 	m.cancel()
 }
 
+// Notify makes the monitor sync now rather than at its next poll, for
+// blocks pushed by the node
+func (m *UTXOMonitor) Notify() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
 func (m *UTXOMonitor) monitorLoop() {
-	ticker := time.NewTicker(30 * time.Second) // Check every 30 seconds
+	ticker := time.NewTicker(m.pollInterval)
 	defer ticker.Stop()
 
 	for {
+		if err := m.sync(); err != nil {
+			log.Printf("UTXO monitor sync failed: %v", err)
+		}
+
 		select {
 		case <-m.ctx.Done():
 			log.Println("UTXO monitor stopped")
 			return
 		case <-ticker.C:
-			m.checkForNewUTXOs()
+		case <-m.wake:
 		}
 	}
 }
 
-func (m *UTXOMonitor) checkForNewUTXOs() {
-	m.mu.RLock()
-	addresses := make([]string, 0, len(m.watchAddresses))
-	for addr := range m.watchAddresses {
-		addresses = append(addresses, addr)
+// sync rolls back any orphaned blocks and then connects blocks up to the
+// node's current tip
+func (m *UTXOMonitor) sync() error {
+	for m.ctx.Err() == nil {
+		tip, err := m.source.GetBlockCount()
+		if err != nil {
+			return fmt.Errorf("failed to get block count: %v", err)
+		}
+
+		if err := m.rewind(tip); err != nil {
+			return err
+		}
+
+		reorged, err := m.connectUpTo(tip)
+		if err != nil {
+			return err
+		}
+		if !reorged {
+			return nil
+		}
 	}
-	m.mu.RUnlock()
+	return nil
+}
 
-	for _, address := range addresses {
-		utxos, err := m.btcClient.GetAddressUTXOs(address)
+// rewind disconnects blocks from the top of the checkpoint until it agrees
+// with the node's best chain
+func (m *UTXOMonitor) rewind(tip int64) error {
+	for {
+		last, ok := m.lastBlock()
+		if !ok {
+			return nil
+		}
+
+		if last.Height <= tip {
+			hash, err := m.source.GetBlockHash(last.Height)
+			if err != nil {
+				return fmt.Errorf("failed to get block hash at height %d: %v", last.Height, err)
+			}
+			if hash == last.Hash {
+				return nil
+			}
+		}
+
+		if err := m.disconnectTip(tip); err != nil {
+			return err
+		}
+	}
+}
+
+// connectUpTo connects blocks after the checkpoint up to tip. It reports
+// true if it stopped because a block did not build on the checkpoint, in
+// which case the caller should rewind and try again.
+func (m *UTXOMonitor) connectUpTo(tip int64) (bool, error) {
+	if _, ok := m.lastBlock(); !ok && m.startHeight <= 0 {
+		m.startHeight = tip
+	}
+
+	for height := m.nextHeight(); height <= tip; height++ {
+		if m.ctx.Err() != nil {
+			return false, nil
+		}
+
+		hash, err := m.source.GetBlockHash(height)
 		if err != nil {
-			log.Printf("Error checking UTXOs for address %s: %v", address, err)
-			continue
+			return false, fmt.Errorf("failed to get block hash at height %d: %v", height, err)
+		}
+		block, err := m.source.GetBlock(hash)
+		if err != nil {
+			return false, fmt.Errorf("failed to get block %s: %v", hash, err)
 		}
 
-		for _, utxo := range utxos {
-			utxoKey := fmt.Sprintf("%s:%d", utxo.TxID, utxo.Vout)
-			
-			m.mu.Lock()
-			existingUTXO, exists := m.utxoStore[utxoKey]
-			
-			if !exists {
-				// New UTXO found
-				newUTXO := &types.UTXO{
-					TxID:         utxo.TxID,
-					Vout:         utxo.Vout,
-					Amount:       int64(utxo.Amount * 100000000), // Convert to satoshis
-					Address:      utxo.Address,
-					ScriptPubKey: utxo.ScriptPubKey,
-					Confirmations: int(utxo.Confirmations),
-					CreatedAt:    time.Now(),
-				}
-				
-				m.utxoStore[utxoKey] = newUTXO
-				m.mu.Unlock()
-				
-				log.Printf("New UTXO detected: %s:%d (%.8f BTC, %d confirmations)", 
-					utxo.TxID, utxo.Vout, utxo.Amount, utxo.Confirmations)
-				
-				// Notify callbacks
-				m.notifyCallbacks(newUTXO, "new")
-				
-			} else {
-				// Update existing UTXO confirmations
-				if existingUTXO.Confirmations != int(utxo.Confirmations) {
-					existingUTXO.Confirmations = int(utxo.Confirmations)
-					m.mu.Unlock()
-					
-					log.Printf("UTXO confirmation updated: %s:%d (%d confirmations)", 
-						utxo.TxID, utxo.Vout, utxo.Confirmations)
-					
-					// Notify callbacks about confirmation update
-					m.notifyCallbacks(existingUTXO, "confirmation_update")
+		if last, ok := m.lastBlock(); ok && block.Header.PrevBlock.String() != last.Hash {
+			log.Printf("Block %d (%s) does not build on %s, checking for reorg", height, hash, last.Hash)
+			return true, nil
+		}
+
+		if err := m.connectBlock(height, hash, block); err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// connectBlock records outputs paying to watched scripts and spends of
+// tracked UTXOs, then advances confirmations and the checkpoint
+func (m *UTXOMonitor) connectBlock(height int64, hash string, block *wire.MsgBlock) error {
+	m.mu.Lock()
+
+	var events []utxoEvent
+	changed := make(map[string]*types.UTXO)
+	now := time.Now()
+
+	for _, tx := range block.Transactions {
+		txid := tx.TxHash().String()
+
+		// Outputs of a transaction spending tracked UTXOs are withdrawal
+		// change, not deposits
+		ownSpend := false
+		for _, in := range tx.TxIn {
+			key := utxoKey(in.PreviousOutPoint.Hash.String(), in.PreviousOutPoint.Index)
+			utxo, ok := m.utxoStore[key]
+			if ok {
+				ownSpend = true
+			}
+			if !ok || utxo.SpentTxID != "" {
+				continue
+			}
+
+			utxo.SpentTxID = txid
+			utxo.SpentHeight = int(height)
+			changed[key] = utxo
+			events = append(events, utxoEvent{*utxo, EventSpent})
+			log.Printf("UTXO spent: %s:%d in %s", utxo.TxID, utxo.Vout, txid)
+		}
+
+		for vout, out := range tx.TxOut {
+			script := hex.EncodeToString(out.PkScript)
+			address, ok := m.watchScripts[script]
+			if !ok {
+				continue
+			}
+
+			key := utxoKey(txid, uint32(vout))
+			if _, exists := m.utxoStore[key]; exists {
+				continue
+			}
+
+			utxo := &types.UTXO{
+				TxID:          txid,
+				Vout:          uint32(vout),
+				Amount:        out.Value,
+				ScriptPubKey:  hex.EncodeToString(out.PkScript),
+				Address:       address,
+				Confirmations: 1,
+				BlockHeight:   int(height),
+				BlockHash:     hash,
+				Change:        ownSpend,
+				CreatedAt:     now,
+			}
+			if script == m.memoScript && !ownSpend {
+				if recipient, err := TransactionMemo(tx); err != nil {
+					utxo.MemoError = err.Error()
+					log.Printf("Deposit %s:%d to the memo address has no usable memo: %v", txid, vout, err)
 				} else {
-					m.mu.Unlock()
+					utxo.Recipient = recipient
 				}
 			}
+			m.utxoStore[key] = utxo
+			changed[key] = utxo
+			events = append(events, utxoEvent{*utxo, EventNew})
+			log.Printf("New UTXO detected: %s:%d (%.8f BTC) in block %d",
+				txid, vout, btcutil.Amount(out.Value).ToBTC(), height)
+		}
+	}
+
+	var pruned []*types.UTXO
+	for key, utxo := range m.utxoStore {
+		if utxo.SpentTxID != "" {
+			// Spends deeper than the reorg window can no longer be undone
+			if int64(utxo.SpentHeight) <= height-int64(m.reorgDepth) {
+				delete(m.utxoStore, key)
+				pruned = append(pruned, utxo)
+			}
+			continue
+		}
+
+		confirmations := int(height) - utxo.BlockHeight + 1
+		if utxo.Confirmations != confirmations {
+			utxo.Confirmations = confirmations
+			changed[key] = utxo
+			events = append(events, utxoEvent{*utxo, EventConfirmationUpdate})
+		}
+	}
+
+	m.blocks = append(m.blocks, blockRef{Height: height, Hash: hash})
+	if len(m.blocks) > m.reorgDepth {
+		m.blocks = append([]blockRef(nil), m.blocks[len(m.blocks)-m.reorgDepth:]...)
+	}
+
+	err := m.persist(changed, pruned)
+	m.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to persist block %d: %v", height, err)
+	}
+
+	m.notifyCallbacks(events)
+	return nil
+}
+
+// disconnectTip rolls back the most recently connected block: UTXOs it
+// created are dropped, UTXOs it spent become unspent again, and the
+// confirmations of everything else go down by one. Disconnecting the last
+// remembered block rolls back further, to the rescan height.
+func (m *UTXOMonitor) disconnectTip(tip int64) error {
+	m.mu.RLock()
+	orphaned := m.blocks[len(m.blocks)-1]
+	deep := len(m.blocks) == 1
+	m.mu.RUnlock()
+
+	rollback := orphaned.Height
+	if deep {
+		var err error
+		rollback, err = m.rescanHeight(orphaned.Height, tip)
+		if err != nil {
+			return err
+		}
+		log.Printf("Warning: reorg deeper than the %d block window, rescanning from block %d",
+			m.reorgDepth, rollback)
+	}
+
+	m.mu.Lock()
+
+	m.blocks = m.blocks[:len(m.blocks)-1]
+	if deep {
+		m.startHeight = rollback
+	}
+	log.Printf("Disconnecting orphaned block %d (%s)", orphaned.Height, orphaned.Hash)
+
+	var events []utxoEvent
+	var removed []*types.UTXO
+	changed := make(map[string]*types.UTXO)
+	height := int(rollback)
+
+	for key, utxo := range m.utxoStore {
+		switch {
+		case utxo.BlockHeight >= height:
+			delete(m.utxoStore, key)
+			removed = append(removed, utxo)
+			utxo.Confirmations = 0
+			utxo.BlockHeight = 0
+			utxo.BlockHash = ""
+			utxo.SpentTxID = ""
+			utxo.SpentHeight = 0
+			events = append(events, utxoEvent{*utxo, EventReorg})
+		case utxo.SpentHeight >= height:
+			utxo.SpentTxID = ""
+			utxo.SpentHeight = 0
+			utxo.Confirmations = height - utxo.BlockHeight
+			changed[key] = utxo
+			events = append(events, utxoEvent{*utxo, EventReorg})
+		case utxo.SpentTxID == "":
+			utxo.Confirmations = height - utxo.BlockHeight
+			changed[key] = utxo
+			events = append(events, utxoEvent{*utxo, EventConfirmationUpdate})
+		}
+	}
+
+	err := m.persist(changed, removed)
+	m.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to persist rollback of block %d: %v", orphaned.Height, err)
+	}
+
+	m.notifyCallbacks(events)
+	return nil
+}
+
+// rescanHeight picks where to rescan from once a reorg went past every
+// remembered block. Nothing older is known to be on the best chain, so each
+// tracked UTXO's block is checked against the node; the rescan starts at the
+// oldest one that was orphaned, and at the oldest spend, since spends are
+// not tied to a block hash.
+func (m *UTXOMonitor) rescanHeight(orphaned int64, tip int64) (int64, error) {
+	rescan := orphaned
+	hashes := make(map[int64]string)
+
+	for _, utxo := range m.GetAllUTXOs() {
+		if utxo.SpentTxID != "" && int64(utxo.SpentHeight) < rescan {
+			rescan = int64(utxo.SpentHeight)
+		}
+
+		height := int64(utxo.BlockHeight)
+		if height >= rescan {
+			continue
+		}
+		if height > tip {
+			rescan = height
+			continue
+		}
+
+		hash, ok := hashes[height]
+		if !ok {
+			var err error
+			hash, err = m.source.GetBlockHash(height)
+			if err != nil {
+				return 0, fmt.Errorf("failed to get block hash at height %d: %v", height, err)
+			}
+			hashes[height] = hash
+		}
+		if hash != utxo.BlockHash {
+			rescan = height
+		}
+	}
+
+	return rescan, nil
+}
+
+// persist writes changed UTXOs, deletes removed ones and saves the
+// checkpoint. Called with m.mu held.
+func (m *UTXOMonitor) persist(changed map[string]*types.UTXO, removed []*types.UTXO) error {
+	for _, utxo := range changed {
+		if err := m.store.SaveUTXO(utxo); err != nil {
+			return err
+		}
+	}
+	for _, utxo := range removed {
+		if err := m.store.DeleteUTXO(utxo.TxID, utxo.Vout); err != nil {
+			return err
 		}
 	}
+
+	data, err := json.Marshal(checkpoint{Blocks: m.blocks})
+	if err != nil {
+		return err
+	}
+	return m.store.SaveState(checkpointKey, data)
 }
 
-func (m *UTXOMonitor) notifyCallbacks(utxo *types.UTXO, event string) {
+func (m *UTXOMonitor) lastBlock() (blockRef, bool) {
 	m.mu.RLock()
-	callbacks := make([]UTXOCallback, len(m.callbacks))
+	defer m.mu.RUnlock()
+
+	if len(m.blocks) == 0 {
+		return blockRef{}, false
+	}
+	return m.blocks[len(m.blocks)-1], true
+}
+
+func (m *UTXOMonitor) nextHeight() int64 {
+	if last, ok := m.lastBlock(); ok {
+		return last.Height + 1
+	}
+	return m.startHeight
+}
+
+func (m *UTXOMonitor) addressScript(address string) (string, error) {
+	addr, err := btcutil.DecodeAddress(address, m.params)
+	if err != nil {
+		return "", fmt.Errorf("invalid address: %v", err)
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(script), nil
+}
+
+// notifyCallbacks queues the events for every callback. Events carry
+// copies of the UTXOs so that callbacks never race with the monitor's own
+// updates.
+func (m *UTXOMonitor) notifyCallbacks(events []utxoEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	m.mu.RLock()
+	callbacks := make([]*callbackQueue, len(m.callbacks))
 	copy(callbacks, m.callbacks)
 	m.mu.RUnlock()
 
-	for _, callback := range callbacks {
-		go func(cb UTXOCallback) {
-			defer func() {
-				if r := recover(); r != nil {
-					log.Printf("UTXO callback panic: %v", r)
-				}
-			}()
-			cb(utxo, event)
-		}(callback)
+	for _, queue := range callbacks {
+		queue.push(events)
+	}
+}
+
+func (q *callbackQueue) push(events []utxoEvent) {
+	q.mu.Lock()
+	q.events = append(q.events, events...)
+	q.mu.Unlock()
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers queued events one at a time until ctx is done
+func (q *callbackQueue) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.ready:
+		}
+
+		for ctx.Err() == nil {
+			q.mu.Lock()
+			if len(q.events) == 0 {
+				q.mu.Unlock()
+				break
+			}
+			e := q.events[0]
+			q.events = q.events[1:]
+			q.mu.Unlock()
+
+			q.deliver(e)
+		}
 	}
 }
 
+func (q *callbackQueue) deliver(e utxoEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("UTXO callback panic: %v", r)
+		}
+	}()
+	q.callback(&e.utxo, e.event)
+}
+
 func (m *UTXOMonitor) GetUTXO(txid string, vout uint32) (*types.UTXO, bool) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	utxoKey := fmt.Sprintf("%s:%d", txid, vout)
-	utxo, exists := m.utxoStore[utxoKey]
-	return utxo, exists
+	utxo, exists := m.utxoStore[utxoKey(txid, vout)]
+	if !exists {
+		return nil, false
+	}
+	copied := *utxo
+	return &copied, true
 }
 
 func (m *UTXOMonitor) GetAllUTXOs() []*types.UTXO {
@@ -186,7 +700,8 @@ func (m *UTXOMonitor) GetAllUTXOs() []*types.UTXO {
 
 	utxos := make([]*types.UTXO, 0, len(m.utxoStore))
 	for _, utxo := range m.utxoStore {
-		utxos = append(utxos, utxo)
+		copied := *utxo
+		utxos = append(utxos, &copied)
 	}
 	return utxos
 }
@@ -198,8 +713,13 @@ func (m *UTXOMonitor) GetUTXOsByAddress(address string) []*types.UTXO {
 	var utxos []*types.UTXO
 	for _, utxo := range m.utxoStore {
 		if utxo.Address == address {
-			utxos = append(utxos, utxo)
+			copied := *utxo
+			utxos = append(utxos, &copied)
 		}
 	}
 	return utxos
-}
\ No newline at end of file
+}
+
+func utxoKey(txid string, vout uint32) string {
+	return fmt.Sprintf("%s:%d", txid, vout)
+}
@@ -0,0 +1,423 @@
+package indexer
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"bitbridge/internal/store"
+	"bitbridge/internal/store/storetest"
+	"bitbridge/pkg/types"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// fakeChain serves a best chain from memory and remembers every block it
+// has ever served so that orphaned blocks can still be fetched by hash
+type fakeChain struct {
+	best   []*wire.MsgBlock
+	blocks map[string]*wire.MsgBlock
+}
+
+func newFakeChain() *fakeChain {
+	c := &fakeChain{blocks: make(map[string]*wire.MsgBlock)}
+	c.extend("genesis")
+	return c
+}
+
+// extend appends a block with the given transactions on top of the best
+// chain; tag keeps blocks at the same height on different forks distinct
+func (c *fakeChain) extend(tag string, txs ...*wire.MsgTx) *wire.MsgBlock {
+	var prev chainhash.Hash
+	if len(c.best) > 0 {
+		prev = c.best[len(c.best)-1].BlockHash()
+	}
+
+	coinbase := wire.NewMsgTx(wire.TxVersion)
+	coinbase.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{}, wire.MaxPrevOutIndex),
+		[]byte(fmt.Sprintf("%s-%d", tag, len(c.best))), nil))
+	coinbase.AddTxOut(wire.NewTxOut(50, []byte{txscript.OP_TRUE}))
+
+	// Only the header is hashed, so commit to the coinbase to keep forks apart
+	merkleRoot := coinbase.TxHash()
+	block := wire.NewMsgBlock(wire.NewBlockHeader(1, &prev, &merkleRoot, 0, 0))
+	block.AddTransaction(coinbase)
+	for _, tx := range txs {
+		block.AddTransaction(tx)
+	}
+
+	c.best = append(c.best, block)
+	c.blocks[block.BlockHash().String()] = block
+	return block
+}
+
+// truncate drops best chain blocks above height
+func (c *fakeChain) truncate(height int) {
+	c.best = c.best[:height+1]
+}
+
+func (c *fakeChain) GetBlockCount() (int64, error) {
+	return int64(len(c.best) - 1), nil
+}
+
+func (c *fakeChain) GetBlockHash(height int64) (string, error) {
+	if height < 0 || height >= int64(len(c.best)) {
+		return "", fmt.Errorf("block height %d out of range", height)
+	}
+	return c.best[height].BlockHash().String(), nil
+}
+
+func (c *fakeChain) GetBlock(hash string) (*wire.MsgBlock, error) {
+	block, ok := c.blocks[hash]
+	if !ok {
+		return nil, fmt.Errorf("block %s not found", hash)
+	}
+	return block, nil
+}
+
+func (c *fakeChain) GetNetworkParams() *chaincfg.Params {
+	return &chaincfg.RegressionNetParams
+}
+
+func payTo(t *testing.T, address string, amount int64, spends ...*wire.OutPoint) *wire.MsgTx {
+	t.Helper()
+
+	addr, err := btcutil.DecodeAddress(address, &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("Failed to decode address: %v", err)
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("Failed to build script: %v", err)
+	}
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	for _, outpoint := range spends {
+		tx.AddTxIn(wire.NewTxIn(outpoint, nil, nil))
+	}
+	if len(spends) == 0 {
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+	}
+	tx.AddTxOut(wire.NewTxOut(amount, script))
+	return tx
+}
+
+func testAddress(t *testing.T, seed byte) string {
+	t.Helper()
+
+	hash := make([]byte, 20)
+	hash[0] = seed
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(hash, &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("Failed to create address: %v", err)
+	}
+	return addr.EncodeAddress()
+}
+
+func newTestMonitor(t *testing.T, chain *fakeChain, repo store.Store) (*UTXOMonitor, chan string) {
+	t.Helper()
+
+	monitor, err := NewUTXOMonitor(MonitorConfig{
+		BlockSource: chain,
+		Store:       repo,
+		StartHeight: 1,
+		ReorgDepth:  10,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	t.Cleanup(monitor.Stop)
+
+	events := make(chan string, 100)
+	monitor.AddCallback(func(utxo *types.UTXO, event string) {
+		events <- event
+	})
+	return monitor, events
+}
+
+func waitForEvent(t *testing.T, events chan string, want string) {
+	t.Helper()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("Timed out waiting for %s event", want)
+		}
+	}
+}
+
+func TestMonitorTracksOutputsAndSpends(t *testing.T) {
+	chain := newFakeChain()
+	repo := storetest.Open(t)
+	address := testAddress(t, 1)
+
+	monitor, events := newTestMonitor(t, chain, repo)
+	if err := monitor.AddWatchAddress(address); err != nil {
+		t.Fatalf("Failed to watch address: %v", err)
+	}
+
+	deposit := payTo(t, address, 5000)
+	chain.extend("main", deposit)
+	chain.extend("main")
+
+	if err := monitor.sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	waitForEvent(t, events, EventNew)
+
+	txid := deposit.TxHash().String()
+	utxo, ok := monitor.GetUTXO(txid, 0)
+	if !ok {
+		t.Fatal("Expected deposit UTXO to be tracked")
+	}
+	if utxo.Amount != 5000 || utxo.BlockHeight != 1 || utxo.Confirmations != 2 {
+		t.Errorf("Unexpected UTXO: %+v", utxo)
+	}
+
+	depositHash := deposit.TxHash()
+	spend := payTo(t, testAddress(t, 2), 4000, wire.NewOutPoint(&depositHash, 0))
+	chain.extend("main", spend)
+
+	if err := monitor.sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	waitForEvent(t, events, EventSpent)
+
+	utxo, _ = monitor.GetUTXO(txid, 0)
+	if utxo.SpentTxID != spend.TxHash().String() || utxo.SpentHeight != 3 {
+		t.Errorf("Expected UTXO to be spent at height 3, got %+v", utxo)
+	}
+
+	stored, err := repo.GetUTXO(txid, 0)
+	if err != nil {
+		t.Fatalf("Failed to load stored UTXO: %v", err)
+	}
+	if stored.SpentTxID == "" {
+		t.Error("Expected spend to be persisted")
+	}
+}
+
+func TestMonitorRollsBackReorg(t *testing.T) {
+	chain := newFakeChain()
+	repo := storetest.Open(t)
+	address := testAddress(t, 1)
+
+	monitor, events := newTestMonitor(t, chain, repo)
+	if err := monitor.AddWatchAddress(address); err != nil {
+		t.Fatalf("Failed to watch address: %v", err)
+	}
+
+	deposit := payTo(t, address, 5000)
+	chain.extend("main", deposit)
+	chain.extend("main")
+
+	if err := monitor.sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	// Replace blocks 1 and 2 with a longer fork that omits the deposit
+	chain.truncate(0)
+	chain.extend("fork")
+	chain.extend("fork")
+	tip := chain.extend("fork")
+
+	if err := monitor.sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	waitForEvent(t, events, EventReorg)
+
+	if _, ok := monitor.GetUTXO(deposit.TxHash().String(), 0); ok {
+		t.Error("Expected orphaned deposit to be dropped")
+	}
+	if _, err := repo.GetUTXO(deposit.TxHash().String(), 0); err == nil {
+		t.Error("Expected orphaned deposit to be deleted from the store")
+	}
+
+	last, _ := monitor.lastBlock()
+	if last.Height != 3 || last.Hash != tip.BlockHash().String() {
+		t.Errorf("Expected checkpoint at fork tip, got %+v", last)
+	}
+}
+
+func TestMonitorRescansAfterReorgDeeperThanWindow(t *testing.T) {
+	chain := newFakeChain()
+	repo := storetest.Open(t)
+	address := testAddress(t, 1)
+
+	monitor, err := NewUTXOMonitor(MonitorConfig{
+		BlockSource: chain,
+		Store:       repo,
+		StartHeight: 1,
+		ReorgDepth:  2,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	if err := monitor.AddWatchAddress(address); err != nil {
+		t.Fatalf("Failed to watch address: %v", err)
+	}
+
+	kept := payTo(t, address, 5000)
+	moved := payTo(t, address, 6000)
+	chain.extend("main", kept)
+	chain.extend("main", moved)
+	chain.extend("main")
+	chain.extend("main")
+	chain.extend("main")
+	if err := monitor.sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	// The fork replaces blocks 2 to 5, more than the monitor remembers,
+	// and mines the second deposit again a block later
+	chain.truncate(1)
+	chain.extend("fork")
+	chain.extend("fork", moved)
+	chain.extend("fork")
+	chain.extend("fork")
+	chain.extend("fork")
+	if err := monitor.sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	utxo, ok := monitor.GetUTXO(kept.TxHash().String(), 0)
+	if !ok || utxo.BlockHeight != 1 || utxo.Confirmations != 6 {
+		t.Errorf("Expected the deposit below the fork to stay at height 1 with 6 confirmations, got %+v", utxo)
+	}
+	utxo, ok = monitor.GetUTXO(moved.TxHash().String(), 0)
+	if !ok || utxo.BlockHeight != 3 || utxo.Confirmations != 4 || utxo.BlockHash != chain.best[3].BlockHash().String() {
+		t.Errorf("Expected the re-mined deposit at fork height 3 with 4 confirmations, got %+v", utxo)
+	}
+}
+
+func TestMonitorDeliversEventsInOrder(t *testing.T) {
+	chain := newFakeChain()
+	repo := storetest.Open(t)
+	address := testAddress(t, 1)
+
+	monitor, _ := newTestMonitor(t, chain, repo)
+	if err := monitor.AddWatchAddress(address); err != nil {
+		t.Fatalf("Failed to watch address: %v", err)
+	}
+
+	// A slow first delivery must not let later events overtake it
+	delivered := make(chan string, 10)
+	monitor.AddCallback(func(utxo *types.UTXO, event string) {
+		if event == EventNew {
+			time.Sleep(20 * time.Millisecond)
+		}
+		delivered <- fmt.Sprintf("%s/%d", event, utxo.Confirmations)
+	})
+
+	chain.extend("main", payTo(t, address, 5000))
+	chain.extend("main")
+	chain.extend("main")
+	if err := monitor.sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	want := []string{"new/1", "confirmation_update/2", "confirmation_update/3"}
+	for _, expected := range want {
+		select {
+		case got := <-delivered:
+			if got != expected {
+				t.Fatalf("Expected %s, got %s", expected, got)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for %s", expected)
+		}
+	}
+}
+
+func TestMonitorResumesFromCheckpoint(t *testing.T) {
+	chain := newFakeChain()
+	repo := storetest.Open(t)
+	address := testAddress(t, 1)
+
+	monitor, _ := newTestMonitor(t, chain, repo)
+	if err := monitor.AddWatchAddress(address); err != nil {
+		t.Fatalf("Failed to watch address: %v", err)
+	}
+
+	chain.extend("main")
+	chain.extend("main")
+	if err := monitor.sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	deposit := payTo(t, address, 7000)
+	chain.extend("main", deposit)
+
+	restarted, _ := newTestMonitor(t, chain, repo)
+	if next := restarted.nextHeight(); next != 3 {
+		t.Fatalf("Expected to resume at height 3, got %d", next)
+	}
+	if err := restarted.sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	if _, ok := restarted.GetUTXO(deposit.TxHash().String(), 0); !ok {
+		t.Error("Expected deposit in block 3 to be picked up after restart")
+	}
+}
+
+func TestMonitorFlagsWithdrawalChange(t *testing.T) {
+	chain := newFakeChain()
+	repo := storetest.Open(t)
+	deposit, change := testAddress(t, 1), testAddress(t, 2)
+
+	monitor, _ := newTestMonitor(t, chain, repo)
+	for _, address := range []string{deposit, change} {
+		if err := monitor.AddWatchAddress(address); err != nil {
+			t.Fatalf("Failed to watch address: %v", err)
+		}
+	}
+
+	funding := payTo(t, deposit, 9000)
+	fundingHash := funding.TxHash()
+	payout := payTo(t, testAddress(t, 3), 5000, wire.NewOutPoint(&fundingHash, 0))
+	changeScript, err := hex.DecodeString(scriptFor(t, change))
+	if err != nil {
+		t.Fatalf("Failed to decode script: %v", err)
+	}
+	payout.AddTxOut(wire.NewTxOut(3500, changeScript))
+	chain.extend("main", funding)
+	chain.extend("main", payout)
+
+	if err := monitor.sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	utxo, ok := monitor.GetUTXO(fundingHash.String(), 0)
+	if !ok || utxo.Change {
+		t.Errorf("Expected the deposit to be tracked as a deposit, got %+v", utxo)
+	}
+	utxo, ok = monitor.GetUTXO(payout.TxHash().String(), 1)
+	if !ok || !utxo.Change {
+		t.Errorf("Expected the payout change to be flagged, got %+v", utxo)
+	}
+}
+
+func scriptFor(t *testing.T, address string) string {
+	t.Helper()
+
+	addr, err := btcutil.DecodeAddress(address, &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("Failed to decode address: %v", err)
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("Failed to build script: %v", err)
+	}
+	return hex.EncodeToString(script)
+}
@@ -1,12 +1,24 @@
 package proof
 
 import (
+	"bytes"
 	"crypto/sha256"
-	"encoding/hex"
+	"errors"
 	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 )
 
-// MerkleProof represents a Merkle proof for a transaction
+// ErrMutatedTree is returned for trees with identical sibling pairs. Because
+// odd levels pair their last hash with itself, such a tree has the same root
+// as a shorter one (CVE-2012-2459), so Bitcoin treats the block as invalid.
+var ErrMutatedTree = errors.New("merkle tree has identical siblings")
+
+// MerkleProof represents a Merkle proof for a transaction. Hashes are hex in
+// display byte order, the way bitcoind prints txids and block hashes;
+// InternalHashes converts them to the byte order the tree is hashed in.
+// TotalTxs fixes the shape of the tree, and with it the proof length and
+// where a hash may be paired with itself.
 type MerkleProof struct {
 	TxHash     string   `json:"tx_hash"`
 	MerkleRoot string   `json:"merkle_root"`
@@ -15,196 +27,227 @@ type MerkleProof struct {
 	TotalTxs   uint32   `json:"total_txs"`
 }
 
-// MerkleTree represents a Merkle tree structure
+// MerkleTree stores each level of a Merkle tree as a flat array of hashes,
+// leaves first and the root last. Following Bitcoin's rule, the last hash of
+// an odd-length level is paired with itself; the duplicate is implied rather
+// than stored. Hashes are kept in internal byte order.
 type MerkleTree struct {
-	Root   *MerkleNode
-	Leaves []*MerkleNode
-}
-
-type MerkleNode struct {
-	Hash   []byte
-	Left   *MerkleNode
-	Right  *MerkleNode
-	IsLeaf bool
-	Index  int
+	levels [][][32]byte
 }
 
-// NewMerkleTree creates a new Merkle tree from transaction hashes
+// NewMerkleTree creates a new Merkle tree from txids in display byte order
 func NewMerkleTree(txHashes []string) (*MerkleTree, error) {
 	if len(txHashes) == 0 {
 		return nil, fmt.Errorf("no transaction hashes provided")
 	}
 
-	// Convert hex strings to bytes and create leaf nodes
-	leaves := make([]*MerkleNode, len(txHashes))
+	leaves := make([][32]byte, len(txHashes))
 	for i, txHash := range txHashes {
-		hashBytes, err := hex.DecodeString(txHash)
+		hash, err := parseHash(txHash)
 		if err != nil {
 			return nil, fmt.Errorf("invalid transaction hash %s: %w", txHash, err)
 		}
-		leaves[i] = &MerkleNode{
-			Hash:   hashBytes,
-			IsLeaf: true,
-			Index:  i,
-		}
-	}
-
-	tree := &MerkleTree{
-		Leaves: leaves,
+		leaves[i] = hash
 	}
 
-	// Build the tree
-	tree.Root = tree.buildTree(leaves)
-	return tree, nil
+	return NewMerkleTreeFromHashes(leaves)
 }
 
-// buildTree recursively builds the Merkle tree
-func (mt *MerkleTree) buildTree(nodes []*MerkleNode) *MerkleNode {
-	if len(nodes) == 1 {
-		return nodes[0]
+// NewMerkleTreeFromHashes builds a tree from leaf hashes in internal byte
+// order, e.g. the chainhash.Hash values returned by MsgTx.TxHash. Each level is
+// computed once, so construction is O(n) hashes and one allocation per
+// level.
+func NewMerkleTreeFromHashes(leaves [][32]byte) (*MerkleTree, error) {
+	if len(leaves) == 0 {
+		return nil, fmt.Errorf("no transaction hashes provided")
 	}
 
-	var nextLevel []*MerkleNode
-
-	// Process pairs of nodes
-	for i := 0; i < len(nodes); i += 2 {
-		left := nodes[i]
-		var right *MerkleNode
-
-		if i+1 < len(nodes) {
-			right = nodes[i+1]
-		} else {
-			// If odd number of nodes, duplicate the last one (Bitcoin's rule)
-			right = nodes[i]
-		}
+	levels := [][][32]byte{leaves}
+	var buf [64]byte
+
+	for level := leaves; len(level) > 1; {
+		next := make([][32]byte, (len(level)+1)/2)
+		for i := range next {
+			left := level[2*i]
+			right := left
+			if 2*i+1 < len(level) {
+				right = level[2*i+1]
+				if right == left {
+					return nil, fmt.Errorf("%w at level %d position %d", ErrMutatedTree, len(levels)-1, 2*i)
+				}
+			}
 
-		// Create parent node
-		parent := &MerkleNode{
-			Hash:  doubleSHA256(append(left.Hash, right.Hash...)),
-			Left:  left,
-			Right: right,
+			copy(buf[:32], left[:])
+			copy(buf[32:], right[:])
+			first := sha256.Sum256(buf[:])
+			next[i] = sha256.Sum256(first[:])
 		}
 
-		nextLevel = append(nextLevel, parent)
+		levels = append(levels, next)
+		level = next
 	}
 
-	return mt.buildTree(nextLevel)
+	return &MerkleTree{levels: levels}, nil
 }
 
 // GenerateProof generates a Merkle proof for a transaction at given index
+// in O(log n) by walking sibling indices up the levels
 func (mt *MerkleTree) GenerateProof(txIndex int) (*MerkleProof, error) {
-	if txIndex < 0 || txIndex >= len(mt.Leaves) {
+	if txIndex < 0 || txIndex >= mt.GetLeafCount() {
 		return nil, fmt.Errorf("transaction index %d out of range", txIndex)
 	}
 
 	proof := &MerkleProof{
-		TxHash:   hex.EncodeToString(mt.Leaves[txIndex].Hash),
-		Index:    uint32(txIndex),
-		TotalTxs: uint32(len(mt.Leaves)),
+		TxHash:     displayHex(mt.levels[0][txIndex]),
+		MerkleRoot: mt.GetMerkleRoot(),
+		Index:      uint32(txIndex),
+		TotalTxs:   uint32(mt.GetLeafCount()),
+		Proof:      make([]string, 0, len(mt.levels)-1),
 	}
 
-	if mt.Root != nil {
-		proof.MerkleRoot = hex.EncodeToString(mt.Root.Hash)
-		proof.Proof = mt.getProofPath(mt.Leaves[txIndex])
+	index := txIndex
+	for _, level := range mt.levels[:len(mt.levels)-1] {
+		sibling := index ^ 1
+		if sibling >= len(level) {
+			sibling = index
+		}
+		proof.Proof = append(proof.Proof, displayHex(level[sibling]))
+		index >>= 1
 	}
 
 	return proof, nil
 }
 
-// getProofPath collects sibling hashes along the path to root
-func (mt *MerkleTree) getProofPath(target *MerkleNode) []string {
-	var proof []string
-	current := target
-
-	// Traverse up the tree collecting sibling hashes
-	for current != mt.Root {
-		parent := mt.findParent(current)
-		if parent == nil {
-			break
+// GenerateProofs generates proofs for several transactions in one pass over
+// the tree levels
+func (mt *MerkleTree) GenerateProofs(txIndices []int) ([]*MerkleProof, error) {
+	root := mt.GetMerkleRoot()
+	depth := len(mt.levels) - 1
+
+	proofs := make([]*MerkleProof, len(txIndices))
+	positions := make([]int, len(txIndices))
+	for i, txIndex := range txIndices {
+		if txIndex < 0 || txIndex >= mt.GetLeafCount() {
+			return nil, fmt.Errorf("transaction index %d out of range", txIndex)
 		}
 
-		// Add sibling hash to proof
-		if parent.Left == current {
-			// Current is left child, add right sibling
-			if parent.Right != nil {
-				proof = append(proof, hex.EncodeToString(parent.Right.Hash))
-			}
-		} else {
-			// Current is right child, add left sibling
-			if parent.Left != nil {
-				proof = append(proof, hex.EncodeToString(parent.Left.Hash))
-			}
+		proofs[i] = &MerkleProof{
+			TxHash:     displayHex(mt.levels[0][txIndex]),
+			MerkleRoot: root,
+			Index:      uint32(txIndex),
+			TotalTxs:   uint32(mt.GetLeafCount()),
+			Proof:      make([]string, 0, depth),
 		}
-
-		current = parent
-	}
-
-	return proof
-}
-
-// findParent finds the parent node of the given node
-func (mt *MerkleTree) findParent(target *MerkleNode) *MerkleNode {
-	return mt.findParentRecursive(mt.Root, target)
-}
-
-func (mt *MerkleTree) findParentRecursive(node, target *MerkleNode) *MerkleNode {
-	if node == nil || node.IsLeaf {
-		return nil
+		positions[i] = txIndex
 	}
 
-	if node.Left == target || node.Right == target {
-		return node
-	}
-
-	if parent := mt.findParentRecursive(node.Left, target); parent != nil {
-		return parent
+	for _, level := range mt.levels[:depth] {
+		for i, index := range positions {
+			sibling := index ^ 1
+			if sibling >= len(level) {
+				sibling = index
+			}
+			proofs[i].Proof = append(proofs[i].Proof, displayHex(level[sibling]))
+			positions[i] = index >> 1
+		}
 	}
 
-	return mt.findParentRecursive(node.Right, target)
+	return proofs, nil
 }
 
-// VerifyProof verifies a Merkle proof
+// VerifyProof verifies a Merkle proof. The proof must be exactly as long as
+// the tree for TotalTxs is deep, and a hash may only be paired with itself
+// where it is the last one on an odd-length level; any other identical pair
+// would come from a mutated tree.
 func VerifyProof(proof *MerkleProof) bool {
-	if proof == nil || len(proof.Proof) == 0 {
+	if proof == nil || proof.TotalTxs == 0 || proof.Index >= proof.TotalTxs {
 		return false
 	}
-
-	txHash, err := hex.DecodeString(proof.TxHash)
-	if err != nil {
+	if len(proof.Proof) != treeDepth(proof.TotalTxs) {
 		return false
 	}
 
-	merkleRoot, err := hex.DecodeString(proof.MerkleRoot)
+	txHash, merkleRoot, path, err := proof.InternalHashes()
 	if err != nil {
 		return false
 	}
 
 	// Start with transaction hash
-	currentHash := txHash
+	currentHash := txHash[:]
 	index := proof.Index
+	width := proof.TotalTxs
 
 	// Process each proof element
-	for _, proofHex := range proof.Proof {
-		proofHash, err := hex.DecodeString(proofHex)
-		if err != nil {
+	for _, proofHash := range path {
+		selfPaired := index%2 == 0 && index == width-1
+		if bytes.Equal(proofHash[:], currentHash) != selfPaired {
 			return false
 		}
 
 		// Determine if current hash is left or right child
 		if index%2 == 0 {
 			// Even index = left child
-			currentHash = doubleSHA256(append(currentHash, proofHash...))
+			currentHash = doubleSHA256(append(currentHash, proofHash[:]...))
 		} else {
 			// Odd index = right child
-			currentHash = doubleSHA256(append(proofHash, currentHash...))
+			currentHash = doubleSHA256(append(proofHash[:], currentHash...))
 		}
 
 		// Move up one level
 		index = index / 2
+		width = (width + 1) / 2
 	}
 
 	// Compare computed hash with merkle root
-	return hex.EncodeToString(currentHash) == hex.EncodeToString(merkleRoot)
+	return bytes.Equal(currentHash, merkleRoot[:])
+}
+
+// treeDepth returns the number of levels above the leaves in a tree of
+// totalTxs transactions
+func treeDepth(totalTxs uint32) int {
+	depth := 0
+	for width := totalTxs; width > 1; width = (width + 1) / 2 {
+		depth++
+	}
+	return depth
+}
+
+// InternalHashes decodes the proof's hashes into internal byte order, which
+// is what the tree is hashed in and what SPVVerifier expects
+func (p *MerkleProof) InternalHashes() (txHash, merkleRoot [32]byte, proof [][32]byte, err error) {
+	if txHash, err = parseHash(p.TxHash); err != nil {
+		return txHash, merkleRoot, nil, fmt.Errorf("invalid tx hash: %w", err)
+	}
+	if merkleRoot, err = parseHash(p.MerkleRoot); err != nil {
+		return txHash, merkleRoot, nil, fmt.Errorf("invalid merkle root: %w", err)
+	}
+
+	proof = make([][32]byte, len(p.Proof))
+	for i, proofHex := range p.Proof {
+		if proof[i], err = parseHash(proofHex); err != nil {
+			return txHash, merkleRoot, nil, fmt.Errorf("invalid proof element %d: %w", i, err)
+		}
+	}
+
+	return txHash, merkleRoot, proof, nil
+}
+
+// parseHash decodes a 32-byte display-order hex hash into internal byte order
+func parseHash(hashHex string) ([32]byte, error) {
+	if len(hashHex) != chainhash.MaxHashStringSize {
+		return [32]byte{}, fmt.Errorf("expected %d hex characters, got %d", chainhash.MaxHashStringSize, len(hashHex))
+	}
+
+	hash, err := chainhash.NewHashFromStr(hashHex)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return *hash, nil
+}
+
+// displayHex encodes an internal-order hash as display-order hex
+func displayHex(hash [32]byte) string {
+	return chainhash.Hash(hash).String()
 }
 
 // doubleSHA256 performs double SHA256 hashing as used in Bitcoin
@@ -216,13 +259,15 @@ func doubleSHA256(data []byte) []byte {
 
 // GetMerkleRoot returns the Merkle root hash
 func (mt *MerkleTree) GetMerkleRoot() string {
-	if mt.Root == nil {
-		return ""
-	}
-	return hex.EncodeToString(mt.Root.Hash)
+	return displayHex(mt.levels[len(mt.levels)-1][0])
 }
 
 // GetLeafCount returns the number of leaf nodes (transactions)
 func (mt *MerkleTree) GetLeafCount() int {
-	return len(mt.Leaves)
-}
\ No newline at end of file
+	return len(mt.levels[0])
+}
+
+// GetDepth returns the number of levels above the leaves
+func (mt *MerkleTree) GetDepth() int {
+	return len(mt.levels) - 1
+}
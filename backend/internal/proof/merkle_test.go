@@ -1,17 +1,21 @@
 package proof
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
+	"errors"
+	"reflect"
 	"testing"
 )
 
 func TestNewMerkleTree(t *testing.T) {
 	// Test with sample transaction hashes
 	txHashes := []string{
-		"a1b2c3d4e5f6789012345678901234567890123456789012345678901234567890",
-		"b2c3d4e5f67890123456789012345678901234567890123456789012345678901234",
-		"c3d4e5f678901234567890123456789012345678901234567890123456789012345678",
-		"d4e5f6789012345678901234567890123456789012345678901234567890123456789012",
+		"a1b2c3d4e5f67890123456789012345678901234567890123456789012345678",
+		"b2c3d4e5f6789012345678901234567890123456789012345678901234567890",
+		"c3d4e5f678901234567890123456789012345678901234567890123456789012",
+		"d4e5f67890123456789012345678901234567890123456789012345678901234",
 	}
 
 	tree, err := NewMerkleTree(txHashes)
@@ -19,28 +23,22 @@ func TestNewMerkleTree(t *testing.T) {
 		t.Fatalf("Failed to create merkle tree: %v", err)
 	}
 
-	if tree.Root == nil {
-		t.Error("Merkle tree root is nil")
+	if tree.GetMerkleRoot() == "" {
+		t.Error("Merkle tree root is empty")
 	}
 
-	if len(tree.Leaves) != len(txHashes) {
-		t.Errorf("Expected %d leaves, got %d", len(txHashes), len(tree.Leaves))
+	if tree.GetLeafCount() != len(txHashes) {
+		t.Errorf("Expected %d leaves, got %d", len(txHashes), tree.GetLeafCount())
 	}
 
-	// Verify all leaves are marked as leaf nodes
-	for i, leaf := range tree.Leaves {
-		if !leaf.IsLeaf {
-			t.Errorf("Leaf %d is not marked as leaf", i)
-		}
-		if leaf.Index != i {
-			t.Errorf("Leaf %d has wrong index: %d", i, leaf.Index)
-		}
+	if tree.GetDepth() != 2 {
+		t.Errorf("Expected depth 2, got %d", tree.GetDepth())
 	}
 }
 
 func TestMerkleTreeWithSingleTransaction(t *testing.T) {
 	txHashes := []string{
-		"a1b2c3d4e5f6789012345678901234567890123456789012345678901234567890",
+		"a1b2c3d4e5f67890123456789012345678901234567890123456789012345678",
 	}
 
 	tree, err := NewMerkleTree(txHashes)
@@ -48,7 +46,7 @@ func TestMerkleTreeWithSingleTransaction(t *testing.T) {
 		t.Fatalf("Failed to create merkle tree: %v", err)
 	}
 
-	if tree.Root != tree.Leaves[0] {
+	if tree.GetMerkleRoot() != txHashes[0] {
 		t.Error("For single transaction, root should be the leaf itself")
 	}
 }
@@ -56,9 +54,9 @@ func TestMerkleTreeWithSingleTransaction(t *testing.T) {
 func TestMerkleTreeWithOddNumberOfTransactions(t *testing.T) {
 	// Test with 3 transactions (odd number)
 	txHashes := []string{
-		"a1b2c3d4e5f6789012345678901234567890123456789012345678901234567890",
-		"b2c3d4e5f67890123456789012345678901234567890123456789012345678901234",
-		"c3d4e5f678901234567890123456789012345678901234567890123456789012345678",
+		"a1b2c3d4e5f67890123456789012345678901234567890123456789012345678",
+		"b2c3d4e5f6789012345678901234567890123456789012345678901234567890",
+		"c3d4e5f678901234567890123456789012345678901234567890123456789012",
 	}
 
 	tree, err := NewMerkleTree(txHashes)
@@ -66,22 +64,41 @@ func TestMerkleTreeWithOddNumberOfTransactions(t *testing.T) {
 		t.Fatalf("Failed to create merkle tree: %v", err)
 	}
 
-	if tree.Root == nil {
-		t.Error("Merkle tree root is nil")
+	// Should handle odd number by duplicating last transaction
+	if tree.GetLeafCount() != 3 {
+		t.Errorf("Expected 3 leaves, got %d", tree.GetLeafCount())
 	}
 
-	// Should handle odd number by duplicating last transaction
-	if len(tree.Leaves) != 3 {
-		t.Errorf("Expected 3 leaves, got %d", len(tree.Leaves))
+	leaves := make([][32]byte, len(txHashes))
+	for i, txHash := range txHashes {
+		leaves[i], _ = parseHash(txHash)
+	}
+	left := doubleSHA256(append(leaves[0][:], leaves[1][:]...))
+	right := doubleSHA256(append(leaves[2][:], leaves[2][:]...))
+	var root [32]byte
+	copy(root[:], doubleSHA256(append(left, right...)))
+	if tree.GetMerkleRoot() != displayHex(root) {
+		t.Error("Odd level should pair the last hash with itself")
+	}
+
+	proof, err := tree.GenerateProof(2)
+	if err != nil {
+		t.Fatalf("Failed to generate proof: %v", err)
+	}
+	if proof.Proof[0] != txHashes[2] {
+		t.Errorf("Expected last transaction to be its own sibling, got %s", proof.Proof[0])
+	}
+	if !VerifyProof(proof) {
+		t.Error("Proof verification failed for last transaction")
 	}
 }
 
 func TestGenerateProof(t *testing.T) {
 	txHashes := []string{
-		"a1b2c3d4e5f6789012345678901234567890123456789012345678901234567890",
-		"b2c3d4e5f67890123456789012345678901234567890123456789012345678901234",
-		"c3d4e5f678901234567890123456789012345678901234567890123456789012345678",
-		"d4e5f6789012345678901234567890123456789012345678901234567890123456789012",
+		"a1b2c3d4e5f67890123456789012345678901234567890123456789012345678",
+		"b2c3d4e5f6789012345678901234567890123456789012345678901234567890",
+		"c3d4e5f678901234567890123456789012345678901234567890123456789012",
+		"d4e5f67890123456789012345678901234567890123456789012345678901234",
 	}
 
 	tree, err := NewMerkleTree(txHashes)
@@ -120,10 +137,10 @@ func TestGenerateProof(t *testing.T) {
 
 func TestVerifyProof(t *testing.T) {
 	txHashes := []string{
-		"a1b2c3d4e5f6789012345678901234567890123456789012345678901234567890",
-		"b2c3d4e5f67890123456789012345678901234567890123456789012345678901234",
-		"c3d4e5f678901234567890123456789012345678901234567890123456789012345678",
-		"d4e5f6789012345678901234567890123456789012345678901234567890123456789012",
+		"a1b2c3d4e5f67890123456789012345678901234567890123456789012345678",
+		"b2c3d4e5f6789012345678901234567890123456789012345678901234567890",
+		"c3d4e5f678901234567890123456789012345678901234567890123456789012",
+		"d4e5f67890123456789012345678901234567890123456789012345678901234",
 	}
 
 	tree, err := NewMerkleTree(txHashes)
@@ -159,8 +176,8 @@ func TestVerifyProofWithInvalidData(t *testing.T) {
 	// Test with invalid hex
 	invalidProof := &MerkleProof{
 		TxHash:     "invalid_hex",
-		MerkleRoot: "a1b2c3d4e5f6789012345678901234567890123456789012345678901234567890",
-		Proof:      []string{"b2c3d4e5f67890123456789012345678901234567890123456789012345678901234"},
+		MerkleRoot: "a1b2c3d4e5f67890123456789012345678901234567890123456789012345678",
+		Proof:      []string{"b2c3d4e5f6789012345678901234567890123456789012345678901234567890"},
 		Index:      0,
 	}
 	if VerifyProof(invalidProof) {
@@ -187,8 +204,8 @@ func TestDoubleSHA256(t *testing.T) {
 
 func TestMerkleTreeGetMethods(t *testing.T) {
 	txHashes := []string{
-		"a1b2c3d4e5f6789012345678901234567890123456789012345678901234567890",
-		"b2c3d4e5f67890123456789012345678901234567890123456789012345678901234",
+		"a1b2c3d4e5f67890123456789012345678901234567890123456789012345678",
+		"b2c3d4e5f6789012345678901234567890123456789012345678901234567890",
 	}
 
 	tree, err := NewMerkleTree(txHashes)
@@ -209,9 +226,187 @@ func TestMerkleTreeGetMethods(t *testing.T) {
 	}
 }
 
+func TestGenerateProofs(t *testing.T) {
+	tree, err := NewMerkleTreeFromHashes(testLeaves(37))
+	if err != nil {
+		t.Fatalf("Failed to create merkle tree: %v", err)
+	}
+
+	indices := []int{0, 5, 17, 36}
+	proofs, err := tree.GenerateProofs(indices)
+	if err != nil {
+		t.Fatalf("Failed to generate proofs: %v", err)
+	}
+
+	for i, index := range indices {
+		single, err := tree.GenerateProof(index)
+		if err != nil {
+			t.Fatalf("Failed to generate proof for tx %d: %v", index, err)
+		}
+		if !reflect.DeepEqual(proofs[i], single) {
+			t.Errorf("Batch proof for tx %d differs from single proof", index)
+		}
+		if !VerifyProof(proofs[i]) {
+			t.Errorf("Proof verification failed for transaction %d", index)
+		}
+	}
+
+	if _, err := tree.GenerateProofs([]int{1, 37}); err == nil {
+		t.Error("Expected error for invalid index")
+	}
+}
+
+func TestNewMerkleTreeRejectsMutatedTree(t *testing.T) {
+	leaves := testLeaves(4)
+
+	// Repeating the last transaction of an odd block keeps the root
+	honest, err := NewMerkleTreeFromHashes(leaves[:3])
+	if err != nil {
+		t.Fatalf("Failed to create merkle tree: %v", err)
+	}
+	_, err = NewMerkleTreeFromHashes([][32]byte{leaves[0], leaves[1], leaves[2], leaves[2]})
+	if !errors.Is(err, ErrMutatedTree) {
+		t.Errorf("Expected duplicated last leaf to be rejected, got %v", err)
+	}
+
+	// So does repeating a whole subtree one level up
+	_, err = NewMerkleTreeFromHashes(append(append([][32]byte{}, leaves...), leaves...))
+	if !errors.Is(err, ErrMutatedTree) {
+		t.Errorf("Expected duplicated subtree to be rejected, got %v", err)
+	}
+
+	if honest.GetLeafCount() != 3 {
+		t.Errorf("Expected 3 leaves, got %d", honest.GetLeafCount())
+	}
+}
+
+func TestVerifyProofRejectsMutatedProof(t *testing.T) {
+	tree, err := NewMerkleTreeFromHashes(testLeaves(3))
+	if err != nil {
+		t.Fatalf("Failed to create merkle tree: %v", err)
+	}
+
+	proof, err := tree.GenerateProof(2)
+	if err != nil {
+		t.Fatalf("Failed to generate proof: %v", err)
+	}
+	if !VerifyProof(proof) {
+		t.Fatal("Honest proof for the last transaction should verify")
+	}
+
+	// The same path presented as the fourth transaction of a four
+	// transaction block hashes to the same root
+	mutated := *proof
+	mutated.Index = 3
+	mutated.TotalTxs = 4
+	if VerifyProof(&mutated) {
+		t.Error("Proof pairing a leaf with itself outside an odd level must not verify")
+	}
+
+	// A proof whose length does not match the tree depth is rejected
+	short := *proof
+	short.TotalTxs = 2
+	short.Index = 0
+	if VerifyProof(&short) {
+		t.Error("Proof with the wrong transaction count must not verify")
+	}
+
+	// The sibling of a transaction that is not last may not equal it
+	proof, err = tree.GenerateProof(0)
+	if err != nil {
+		t.Fatalf("Failed to generate proof: %v", err)
+	}
+	proof.Proof[0] = proof.TxHash
+	if VerifyProof(proof) {
+		t.Error("Proof pairing identical siblings must not verify")
+	}
+}
+
+func TestVerifySingleTransactionProof(t *testing.T) {
+	tree, err := NewMerkleTreeFromHashes(testLeaves(1))
+	if err != nil {
+		t.Fatalf("Failed to create merkle tree: %v", err)
+	}
+
+	proof, err := tree.GenerateProof(0)
+	if err != nil {
+		t.Fatalf("Failed to generate proof: %v", err)
+	}
+	if len(proof.Proof) != 0 || !VerifyProof(proof) {
+		t.Error("A coinbase-only block should prove with an empty path")
+	}
+}
+
+func TestNewMerkleTreeRejectsShortHash(t *testing.T) {
+	_, err := NewMerkleTree([]string{"a1b2c3d4"})
+	if err == nil {
+		t.Error("Expected error for hash that is not 32 bytes")
+	}
+}
+
 func TestEmptyTransactionList(t *testing.T) {
 	_, err := NewMerkleTree([]string{})
 	if err == nil {
 		t.Error("Expected error for empty transaction list")
 	}
-}
\ No newline at end of file
+}
+
+// testLeaves returns n distinct leaf hashes
+func testLeaves(n int) [][32]byte {
+	leaves := make([][32]byte, n)
+	for i := range leaves {
+		var seed [8]byte
+		binary.LittleEndian.PutUint64(seed[:], uint64(i))
+		leaves[i] = sha256.Sum256(seed[:])
+	}
+	return leaves
+}
+
+// 4096 transactions is roughly a full block of small transactions
+const benchmarkTxCount = 4096
+
+func BenchmarkNewMerkleTree(b *testing.B) {
+	leaves := testLeaves(benchmarkTxCount)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := NewMerkleTreeFromHashes(leaves); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGenerateProof(b *testing.B) {
+	tree, err := NewMerkleTreeFromHashes(testLeaves(benchmarkTxCount))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := tree.GenerateProof(i % benchmarkTxCount); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGenerateProofs(b *testing.B) {
+	tree, err := NewMerkleTreeFromHashes(testLeaves(benchmarkTxCount))
+	if err != nil {
+		b.Fatal(err)
+	}
+	indices := make([]int, 0, benchmarkTxCount/16)
+	for i := 0; i < benchmarkTxCount; i += 16 {
+		indices = append(indices, i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := tree.GenerateProofs(indices); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
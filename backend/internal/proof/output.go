@@ -0,0 +1,84 @@
+package proof
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// OutputProof binds one transaction output to an SPV proof. RawTx is the
+// transaction serialized without witness data, so it hashes to the txid the
+// Merkle proof commits to; Value and ScriptPubKey are what VerifyOutputProof
+// reads back out of it.
+type OutputProof struct {
+	RawTx        string `json:"raw_tx"`
+	OutputIndex  uint32 `json:"output_index"`
+	Value        int64  `json:"value"`
+	ScriptPubKey string `json:"script_pubkey"`
+}
+
+// NewOutputProof builds the output proof for tx's output at outputIndex
+func NewOutputProof(tx *wire.MsgTx, outputIndex uint32) (*OutputProof, error) {
+	if int(outputIndex) >= len(tx.TxOut) {
+		return nil, fmt.Errorf("output index %d out of range", outputIndex)
+	}
+
+	var buf bytes.Buffer
+	if err := tx.SerializeNoWitness(&buf); err != nil {
+		return nil, fmt.Errorf("failed to serialize transaction: %w", err)
+	}
+
+	txOut := tx.TxOut[outputIndex]
+	return &OutputProof{
+		RawTx:        hex.EncodeToString(buf.Bytes()),
+		OutputIndex:  outputIndex,
+		Value:        txOut.Value,
+		ScriptPubKey: hex.EncodeToString(txOut.PkScript),
+	}, nil
+}
+
+// VerifyOutputProof parses the raw transaction, checks that it hashes to
+// txHash (display byte order) and that the claimed value and script are
+// those of the output at OutputIndex. It returns the parsed output.
+func VerifyOutputProof(output *OutputProof, txHash string) (*wire.TxOut, error) {
+	if output == nil {
+		return nil, fmt.Errorf("no output proof")
+	}
+
+	rawTx, err := hex.DecodeString(output.RawTx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw transaction hex: %w", err)
+	}
+
+	reader := bytes.NewReader(rawTx)
+	var tx wire.MsgTx
+	if err := tx.DeserializeNoWitness(reader); err != nil {
+		return nil, fmt.Errorf("failed to parse raw transaction: %w", err)
+	}
+	if reader.Len() != 0 {
+		return nil, fmt.Errorf("raw transaction has %d trailing bytes", reader.Len())
+	}
+	if len(rawTx) == 64 {
+		return nil, Err64ByteTransaction
+	}
+
+	if tx.TxHash().String() != txHash {
+		return nil, fmt.Errorf("raw transaction hashes to %s, expected %s", tx.TxHash(), txHash)
+	}
+
+	if int(output.OutputIndex) >= len(tx.TxOut) {
+		return nil, fmt.Errorf("output index %d out of range", output.OutputIndex)
+	}
+	txOut := tx.TxOut[output.OutputIndex]
+
+	if txOut.Value != output.Value {
+		return nil, fmt.Errorf("output value mismatch: proof claims %d, transaction pays %d", output.Value, txOut.Value)
+	}
+	if hex.EncodeToString(txOut.PkScript) != output.ScriptPubKey {
+		return nil, fmt.Errorf("output script mismatch")
+	}
+
+	return txOut, nil
+}
@@ -0,0 +1,94 @@
+package proof
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestOutputProofAgainstMainnetBlock(t *testing.T) {
+	block := loadBlock(t, 170)
+	tx := block.Transactions[1]
+
+	// Satoshi's 10 BTC payment to Hal Finney
+	output, err := NewOutputProof(tx, 0)
+	if err != nil {
+		t.Fatalf("Failed to build output proof: %v", err)
+	}
+
+	txOut, err := VerifyOutputProof(output, tx.TxHash().String())
+	if err != nil {
+		t.Fatalf("Output proof did not verify: %v", err)
+	}
+	if txOut.Value != 1000000000 || output.Value != 1000000000 {
+		t.Errorf("Expected 10 BTC output, got %d", txOut.Value)
+	}
+
+	// A different transaction's bytes must not pass for this txid
+	other, err := NewOutputProof(block.Transactions[0], 0)
+	if err != nil {
+		t.Fatalf("Failed to build output proof: %v", err)
+	}
+	if _, err := VerifyOutputProof(other, tx.TxHash().String()); err == nil {
+		t.Error("Expected raw transaction with another txid to be rejected")
+	}
+
+	inflated := *output
+	inflated.Value *= 2
+	if _, err := VerifyOutputProof(&inflated, tx.TxHash().String()); err == nil {
+		t.Error("Expected inflated output value to be rejected")
+	}
+
+	swapped := *output
+	swapped.OutputIndex = 1
+	if _, err := VerifyOutputProof(&swapped, tx.TxHash().String()); err == nil {
+		t.Error("Expected value of another output to be rejected")
+	}
+
+	trailing := *output
+	trailing.RawTx += "00"
+	if _, err := VerifyOutputProof(&trailing, tx.TxHash().String()); err == nil {
+		t.Error("Expected raw transaction with trailing bytes to be rejected")
+	}
+
+	if _, err := NewOutputProof(tx, 2); err == nil {
+		t.Error("Expected error for output index out of range")
+	}
+}
+
+func TestOutputProofStripsWitness(t *testing.T) {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, wire.TxWitness{make([]byte, 72), make([]byte, 33)}))
+	tx.AddTxOut(wire.NewTxOut(50000, append([]byte{0x00, 0x14}, make([]byte, 20)...)))
+
+	output, err := NewOutputProof(tx, 0)
+	if err != nil {
+		t.Fatalf("Failed to build output proof: %v", err)
+	}
+	if len(output.RawTx)/2 != tx.SerializeSizeStripped() {
+		t.Errorf("Expected %d-byte stripped transaction, got %d bytes", tx.SerializeSizeStripped(), len(output.RawTx)/2)
+	}
+
+	txOut, err := VerifyOutputProof(output, tx.TxHash().String())
+	if err != nil {
+		t.Fatalf("Output proof did not verify: %v", err)
+	}
+	if txOut.Value != 50000 {
+		t.Errorf("Expected value 50000, got %d", txOut.Value)
+	}
+}
+
+func TestOutputProofRejects64ByteTransaction(t *testing.T) {
+	tx := wire.NewMsgTx(1)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 7}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(1000, []byte{0x51, 0x51, 0x51, 0x51}))
+
+	output, err := NewOutputProof(tx, 0)
+	if err != nil {
+		t.Fatalf("Failed to build output proof: %v", err)
+	}
+	if _, err := VerifyOutputProof(output, tx.TxHash().String()); !errors.Is(err, Err64ByteTransaction) {
+		t.Errorf("Expected 64-byte transaction to be rejected, got %v", err)
+	}
+}
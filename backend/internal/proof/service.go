@@ -2,11 +2,14 @@ package proof
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log"
 	"sync"
 	"time"
 
 	"bitbridge/internal/bitcoin"
+	"bitbridge/internal/store"
 
 	"github.com/btcsuite/btcd/rpcclient"
 )
@@ -27,22 +30,26 @@ type ServiceConfig struct {
 	MinConfirmations  int32
 	MaxCacheSize      int
 	CacheExpiration   time.Duration
+	Store             store.ProofRepository // optional; proofs are kept in memory only when nil
+	HeaderChain       HeaderChain
 }
 
-// ProofCache implements a simple LRU cache for proofs
+// ProofCache implements a simple LRU cache for proofs, written through to
+// the proof store when one is configured
 type ProofCache struct {
 	mutex    sync.RWMutex
 	proofs   map[string]*CachedProof
 	maxSize  int
 	expiry   time.Duration
+	store    store.ProofRepository
 }
 
 // CachedProof wraps an SPV proof with metadata
 type CachedProof struct {
-	Proof     *SPVProof
-	CreatedAt time.Time
-	AccessedAt time.Time
-	UseCount  int
+	Proof      *SPVProof `json:"proof"`
+	CreatedAt  time.Time `json:"created_at"`
+	AccessedAt time.Time `json:"accessed_at"`
+	UseCount   int       `json:"use_count"`
 }
 
 // ProofRequest represents a request for SPV proof generation
@@ -75,13 +82,16 @@ func NewService(config ServiceConfig) *Service {
 	generator := NewGenerator(Config{
 		BitcoinClient: config.BitcoinClient,
 		RPCClient:     config.RPCClient,
+		HeaderChain:   config.HeaderChain,
 	})
 
 	cache := &ProofCache{
 		proofs:  make(map[string]*CachedProof),
 		maxSize: config.MaxCacheSize,
 		expiry:  config.CacheExpiration,
+		store:   config.Store,
 	}
+	cache.load()
 
 	service := &Service{
 		generator:        generator,
@@ -103,8 +113,10 @@ func (s *Service) GenerateProof(ctx context.Context, req *ProofRequest) (*ProofR
 	
 	// Check cache first
 	if cached := s.cache.Get(cacheKey); cached != nil {
-		// Verify cached proof still meets confirmation requirements
-		if req.RequiredConfirmations == 0 || cached.Proof.Confirmations >= req.RequiredConfirmations {
+		// Verify cached proof still meets confirmation requirements, binds
+		// the output and that its block has not been reorganized out
+		if (req.RequiredConfirmations == 0 || cached.Proof.Confirmations >= req.RequiredConfirmations) &&
+			cached.Proof.Output != nil && s.generator.VerifyProof(cached.Proof) == nil {
 			return &ProofResponse{
 				Proof:       cached.Proof,
 				Verified:    true,
@@ -160,7 +172,7 @@ func (s *Service) GetProofForContract(ctx context.Context, req *ProofRequest) (m
 		return nil, err
 	}
 
-	return s.generator.FormatProofForContract(proofResp.Proof), nil
+	return s.generator.FormatProofForContract(proofResp.Proof)
 }
 
 // GetCacheStats returns cache statistics
@@ -196,9 +208,26 @@ func (s *Service) GetCacheStats() map[string]interface{} {
 func (s *Service) ClearCache() {
 	s.cache.mutex.Lock()
 	defer s.cache.mutex.Unlock()
+
+	for key := range s.cache.proofs {
+		s.cache.deleteStored(key)
+	}
 	s.cache.proofs = make(map[string]*CachedProof)
 }
 
+// RefreshConfirmations brings the confirmations of cached proofs up to a
+// new header chain tip, so proofs reach their required confirmations
+// without being regenerated. Proofs whose block left the best chain are
+// dropped.
+func (s *Service) RefreshConfirmations(tipHeight int32) {
+	if s.generator.headerChain == nil {
+		return
+	}
+	if dropped := s.cache.refresh(s.generator.headerChain, tipHeight); dropped > 0 {
+		log.Printf("Dropped %d cached proofs for blocks no longer on the best chain", dropped)
+	}
+}
+
 // ProofCache methods
 
 // Get retrieves a proof from cache
@@ -233,12 +262,60 @@ func (pc *ProofCache) Set(key string, proof *SPVProof) {
 		pc.evictOldest()
 	}
 
-	pc.proofs[key] = &CachedProof{
+	cached := &CachedProof{
 		Proof:      proof,
 		CreatedAt:  time.Now(),
 		AccessedAt: time.Now(),
 		UseCount:   1,
 	}
+	pc.proofs[key] = cached
+
+	if pc.store != nil {
+		data, err := json.Marshal(cached)
+		if err != nil {
+			log.Printf("Failed to encode proof %s: %v", key, err)
+			return
+		}
+		if err := pc.store.SaveProof(key, data); err != nil {
+			log.Printf("Failed to persist proof %s: %v", key, err)
+		}
+	}
+}
+
+// load restores unexpired proofs from the proof store
+func (pc *ProofCache) load() {
+	if pc.store == nil {
+		return
+	}
+
+	stored, err := pc.store.ListProofs()
+	if err != nil {
+		log.Printf("Failed to load cached proofs: %v", err)
+		return
+	}
+
+	for key, data := range stored {
+		var cached CachedProof
+		if err := json.Unmarshal(data, &cached); err != nil || time.Since(cached.CreatedAt) > pc.expiry {
+			pc.deleteStored(key)
+			continue
+		}
+
+		if len(pc.proofs) >= pc.maxSize {
+			pc.evictOldest()
+		}
+		pc.proofs[key] = &cached
+	}
+}
+
+// deleteStored removes a proof from the proof store
+func (pc *ProofCache) deleteStored(key string) {
+	if pc.store == nil {
+		return
+	}
+	if err := pc.store.DeleteProof(key); err != nil {
+		log.Printf("Failed to delete stored proof %s: %v", key, err)
+	}
 }
 
 // evictOldest removes the oldest entry from cache
@@ -255,7 +332,37 @@ func (pc *ProofCache) evictOldest() {
 
 	if oldestKey != "" {
 		delete(pc.proofs, oldestKey)
+		pc.deleteStored(oldestKey)
+	}
+}
+
+// refresh updates cached proofs to tipHeight and drops those off the best
+// chain. Updated confirmations are kept in memory only; stored proofs keep
+// the count they were generated with. Entries are replaced rather than
+// modified, since callers may still hold them.
+func (pc *ProofCache) refresh(chain HeaderChain, tipHeight int32) int {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	dropped := 0
+	for key, cached := range pc.proofs {
+		height, ok := chain.BestChainHeight(cached.Proof.BlockHash)
+		if !ok || height != cached.Proof.BlockHeight {
+			delete(pc.proofs, key)
+			pc.deleteStored(key)
+			dropped++
+			continue
+		}
+
+		if confirmations := tipHeight - height + 1; confirmations > cached.Proof.Confirmations {
+			proof := *cached.Proof
+			proof.Confirmations = confirmations
+			updated := *cached
+			updated.Proof = &proof
+			pc.proofs[key] = &updated
+		}
 	}
+	return dropped
 }
 
 // cleanup removes expired entries
@@ -267,6 +374,7 @@ func (pc *ProofCache) cleanup() {
 	for key, cached := range pc.proofs {
 		if now.Sub(cached.CreatedAt) > pc.expiry {
 			delete(pc.proofs, key)
+			pc.deleteStored(key)
 		}
 	}
 }
@@ -0,0 +1,94 @@
+package proof
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"bitbridge/internal/store/storetest"
+)
+
+func TestProofCacheRefreshFollowsHeaderChain(t *testing.T) {
+	cache := &ProofCache{
+		proofs:  make(map[string]*CachedProof),
+		maxSize: 10,
+		expiry:  time.Hour,
+	}
+	cache.Set("deep:0", &SPVProof{BlockHash: "deep", BlockHeight: 100, Confirmations: 1})
+	cache.Set("orphan:0", &SPVProof{BlockHash: "orphan", BlockHeight: 104, Confirmations: 1})
+	cache.Set("moved:0", &SPVProof{BlockHash: "moved", BlockHeight: 103, Confirmations: 2})
+	held := cache.Get("deep:0")
+
+	chain := fakeHeaderChain{"deep": 100, "moved": 102}
+	if dropped := cache.refresh(chain, 105); dropped != 2 {
+		t.Errorf("Expected proofs off the best chain to be dropped, dropped %d", dropped)
+	}
+
+	cached := cache.Get("deep:0")
+	if cached == nil || cached.Proof.Confirmations != 6 {
+		t.Fatalf("Expected 6 confirmations at tip 105, got %+v", cached)
+	}
+	if held.Proof.Confirmations != 1 {
+		t.Error("Expected proofs already handed out to be left alone")
+	}
+	if cache.Get("orphan:0") != nil || cache.Get("moved:0") != nil {
+		t.Error("Expected orphaned proofs to be gone")
+	}
+}
+
+func TestProofCacheStoreRoundTrip(t *testing.T) {
+	repo := storetest.Open(t)
+
+	block := loadBlock(t, 2812)
+	generator := NewGenerator(Config{
+		HeaderChain: fakeHeaderChain{block.BlockHash().String(): 2812},
+	})
+
+	txHashes := make([][32]byte, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		txHashes[i] = tx.TxHash()
+	}
+	tree, err := NewMerkleTreeFromHashes(txHashes)
+	if err != nil {
+		t.Fatalf("Failed to build tree: %v", err)
+	}
+
+	// Proofs are persisted by one cache and read back by the next, as
+	// across a restart
+	cache := &ProofCache{proofs: make(map[string]*CachedProof), maxSize: 10, expiry: time.Hour, store: repo}
+	for i, tx := range block.Transactions {
+		merkleProof, err := tree.GenerateProof(i)
+		if err != nil {
+			t.Fatalf("Failed to generate proof for tx %d: %v", i, err)
+		}
+		output, err := NewOutputProof(tx, 0)
+		if err != nil {
+			t.Fatalf("Failed to build output proof for tx %d: %v", i, err)
+		}
+		cache.Set(fmt.Sprintf("%s:0", tx.TxHash()), &SPVProof{
+			BlockHeader:   &block.Header,
+			MerkleProof:   merkleProof,
+			Transaction:   tx,
+			BlockHeight:   2812,
+			Confirmations: 6,
+			BlockHash:     block.BlockHash().String(),
+			Output:        output,
+		})
+	}
+
+	restored := &ProofCache{proofs: make(map[string]*CachedProof), maxSize: 10, expiry: time.Hour, store: repo}
+	restored.load()
+
+	for i, tx := range block.Transactions {
+		cached := restored.Get(fmt.Sprintf("%s:0", tx.TxHash()))
+		if cached == nil {
+			t.Fatalf("Proof for tx %d was not restored from the store", i)
+		}
+		if err := generator.VerifyProof(cached.Proof); err != nil {
+			t.Errorf("Restored proof for tx %d did not verify: %v", i, err)
+		}
+		if cached.Proof.Transaction.TxHash() != tx.TxHash() || cached.Proof.BlockHeader.BlockHash() != block.BlockHash() {
+			t.Errorf("Restored proof for tx %d does not match the block", i)
+		}
+	}
+}
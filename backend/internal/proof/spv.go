@@ -3,6 +3,7 @@ package proof
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
 
 	"bitbridge/internal/bitcoin"
@@ -12,6 +13,12 @@ import (
 	"github.com/btcsuite/btcd/wire"
 )
 
+// Err64ByteTransaction is returned for transactions whose non-witness
+// serialization is 64 bytes. Such a transaction hashes like an inner Merkle
+// node, so its proof could be passed off as one for the two hashes it
+// concatenates, or the other way around.
+var Err64ByteTransaction = errors.New("64-byte transactions cannot be proven")
+
 // SPVProof represents a complete SPV proof for a Bitcoin transaction
 type SPVProof struct {
 	BlockHeader    *wire.BlockHeader `json:"block_header"`
@@ -21,24 +28,34 @@ type SPVProof struct {
 	Confirmations  int32             `json:"confirmations"`
 	BlockHash      string            `json:"block_hash"`
 	TransactionHex string            `json:"transaction_hex"`
+	Output         *OutputProof      `json:"output,omitempty"` // set for proofs of a specific UTXO
+}
+
+// HeaderChain reports which blocks are on the validated best chain;
+// *headers.Chain satisfies it
+type HeaderChain interface {
+	BestChainHeight(blockHash string) (int32, bool)
 }
 
 // Generator handles SPV proof generation
 type Generator struct {
 	bitcoinClient *bitcoin.Client
 	rpcClient     *rpcclient.Client
+	headerChain   HeaderChain
 }
 
 // Config for SPV proof generator
 type Config struct {
 	BitcoinClient *bitcoin.Client
 	RPCClient     *rpcclient.Client
+	HeaderChain   HeaderChain
 }
 
 func NewGenerator(config Config) *Generator {
 	return &Generator{
 		bitcoinClient: config.BitcoinClient,
 		rpcClient:     config.RPCClient,
+		headerChain:   config.HeaderChain,
 	}
 }
 
@@ -78,12 +95,12 @@ func (g *Generator) GenerateProof(txHashStr string) (*SPVProof, error) {
 		return nil, fmt.Errorf("failed to get block: %w", err)
 	}
 
-	// Extract transaction hashes
-	txHashes := make([]string, len(block.Transactions))
+	// Extract transaction hashes in internal byte order
+	txHashes := make([][32]byte, len(block.Transactions))
 	txIndex := -1
 	for i, tx := range block.Transactions {
-		txHashes[i] = tx.TxHash().String()
-		if tx.TxHash().String() == txHashStr {
+		txHashes[i] = tx.TxHash()
+		if txHashes[i] == *txHash {
 			txIndex = i
 		}
 	}
@@ -93,10 +110,13 @@ func (g *Generator) GenerateProof(txHashStr string) (*SPVProof, error) {
 	}
 
 	// Build Merkle tree
-	merkleTree, err := NewMerkleTree(txHashes)
+	merkleTree, err := NewMerkleTreeFromHashes(txHashes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build merkle tree: %w", err)
 	}
+	if merkleTree.GetMerkleRoot() != blockHeader.MerkleRoot.String() {
+		return nil, fmt.Errorf("computed merkle root %s does not match block header %s", merkleTree.GetMerkleRoot(), blockHeader.MerkleRoot)
+	}
 
 	// Generate Merkle proof
 	merkleProof, err := merkleTree.GenerateProof(txIndex)
@@ -106,6 +126,9 @@ func (g *Generator) GenerateProof(txHashStr string) (*SPVProof, error) {
 
 	// Get the actual transaction
 	tx := block.Transactions[txIndex]
+	if tx.SerializeSizeStripped() == 64 {
+		return nil, fmt.Errorf("transaction %s: %w", txHashStr, Err64ByteTransaction)
+	}
 
 	// Get current block height for confirmations
 	bestBlockHash, err := g.rpcClient.GetBestBlockHash()
@@ -118,7 +141,8 @@ func (g *Generator) GenerateProof(txHashStr string) (*SPVProof, error) {
 		return nil, fmt.Errorf("failed to get best block: %w", err)
 	}
 
-	confirmations := bestBlock.Height - int64(txResult.Confirmations) + 1
+	confirmations := int64(txResult.Confirmations)
+	blockHeight := bestBlock.Height - confirmations + 1
 
 	// Serialize transaction to hex
 	txHex, err := g.serializeTransaction(tx)
@@ -130,7 +154,7 @@ func (g *Generator) GenerateProof(txHashStr string) (*SPVProof, error) {
 		BlockHeader:    blockHeader,
 		MerkleProof:    merkleProof,
 		Transaction:    tx,
-		BlockHeight:    int32(bestBlock.Height - confirmations + 1),
+		BlockHeight:    int32(blockHeight),
 		Confirmations:  int32(confirmations),
 		BlockHash:      blockHash.String(),
 		TransactionHex: txHex,
@@ -139,6 +163,12 @@ func (g *Generator) GenerateProof(txHashStr string) (*SPVProof, error) {
 
 // VerifyProof verifies an SPV proof
 func (g *Generator) VerifyProof(proof *SPVProof) error {
+	// TotalTxs is not committed to by the header, so the proof length alone
+	// cannot tell a leaf from an inner node
+	if proof.Transaction.SerializeSizeStripped() == 64 {
+		return fmt.Errorf("transaction %s: %w", proof.MerkleProof.TxHash, Err64ByteTransaction)
+	}
+
 	// Verify Merkle proof
 	if !VerifyProof(proof.MerkleProof) {
 		return fmt.Errorf("invalid merkle proof")
@@ -162,6 +192,25 @@ func (g *Generator) VerifyProof(proof *SPVProof) error {
 		return fmt.Errorf("transaction hash mismatch")
 	}
 
+	// Verify the header has valid work and is on the best chain
+	if g.headerChain == nil {
+		return fmt.Errorf("no header chain to validate block %s against", proof.BlockHash)
+	}
+	height, ok := g.headerChain.BestChainHeight(proof.BlockHash)
+	if !ok {
+		return fmt.Errorf("block %s is not on the validated best chain", proof.BlockHash)
+	}
+	if height != proof.BlockHeight {
+		return fmt.Errorf("block height mismatch: proof claims %d, header chain has %d", proof.BlockHeight, height)
+	}
+
+	// Verify the claimed output is really paid by the proven transaction
+	if proof.Output != nil {
+		if _, err := VerifyOutputProof(proof.Output, proof.MerkleProof.TxHash); err != nil {
+			return fmt.Errorf("invalid output proof: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -172,9 +221,10 @@ func (g *Generator) GetProofForUTXO(txHash string, outputIndex uint32) (*SPVProo
 		return nil, err
 	}
 
-	// Verify the output exists
-	if int(outputIndex) >= len(proof.Transaction.TxOut) {
-		return nil, fmt.Errorf("output index %d out of range", outputIndex)
+	// Bind the output's value and script to the proof
+	proof.Output, err = NewOutputProof(proof.Transaction, outputIndex)
+	if err != nil {
+		return nil, err
 	}
 
 	return proof, nil
@@ -228,19 +278,46 @@ func (g *Generator) GetProofSize(proof *SPVProof) int {
 	return size
 }
 
-// FormatProofForContract formats proof data for smart contract consumption
-func (g *Generator) FormatProofForContract(proof *SPVProof) map[string]interface{} {
+// FormatProofForContract formats proof data for smart contract consumption.
+// SPVVerifier hashes raw bytes, so the tx hash, merkle root and proof
+// elements are hex in internal byte order rather than display order.
+func (g *Generator) FormatProofForContract(proof *SPVProof) (map[string]interface{}, error) {
 	// Convert block header to hex
 	var headerBuf bytes.Buffer
-	proof.BlockHeader.Serialize(&headerBuf)
+	if err := proof.BlockHeader.Serialize(&headerBuf); err != nil {
+		return nil, fmt.Errorf("failed to serialize header: %w", err)
+	}
+
+	txHash, merkleRoot, path, err := proof.MerkleProof.InternalHashes()
+	if err != nil {
+		return nil, fmt.Errorf("invalid merkle proof: %w", err)
+	}
 
-	return map[string]interface{}{
+	merkleProof := make([]string, len(path))
+	for i, proofHash := range path {
+		merkleProof[i] = hex.EncodeToString(proofHash[:])
+	}
+
+	formatted := map[string]interface{}{
 		"blockHeader":   hex.EncodeToString(headerBuf.Bytes()),
-		"merkleProof":   proof.MerkleProof.Proof,
-		"txHash":        proof.MerkleProof.TxHash,
+		"merkleProof":   merkleProof,
+		"txHash":        hex.EncodeToString(txHash[:]),
 		"txIndex":       proof.MerkleProof.Index,
-		"merkleRoot":    proof.MerkleProof.MerkleRoot,
+		"totalTxs":      proof.MerkleProof.TotalTxs,
+		"treeDepth":     treeDepth(proof.MerkleProof.TotalTxs),
+		"merkleRoot":    hex.EncodeToString(merkleRoot[:]),
 		"blockHeight":   proof.BlockHeight,
 		"confirmations": proof.Confirmations,
 	}
-}
\ No newline at end of file
+
+	// The raw transaction lets the chain side hash it to txHash and read
+	// the output itself rather than trusting value and script
+	if proof.Output != nil {
+		formatted["rawTx"] = proof.Output.RawTx
+		formatted["outputIndex"] = proof.Output.OutputIndex
+		formatted["outputValue"] = proof.Output.Value
+		formatted["scriptPubKey"] = proof.Output.ScriptPubKey
+	}
+
+	return formatted, nil
+}
@@ -0,0 +1,303 @@
+package proof
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// goldenBlocks are mainnet blocks stored as raw bzip2-compressed
+// serializations in testdata, chosen to cover even and odd tree levels
+var goldenBlocks = []struct {
+	height     int32
+	hash       string
+	merkleRoot string
+	txCount    int
+}{
+	{170, "00000000d1145790a8694403d4063f323d499e655c83426834d4ce2f8dd4a2ee", "7dac2c5666815c17a3b36427de37bb9d2e2c5ccec3f8633eb91a4205cb4c10ff", 2},
+	{586, "000000000d0d23516c5efd3af4eb951603bb30b2c93884b522a318b30e918ee7", "197b3d968ce463aa5da7d8eeba8af35eba80ded4e4fe6808e6cc0dd1c069594d", 3},
+	{2812, "0000000049a63b4dda3a43450c19d085d6c28bfb4cbb2e0576815d7f31919c5d", "289a86c44c4698fd8f181929dc2dd3c25820c959eab28980b27bb3cf8fcacb65", 6},
+	{277647, "0000000000000000054a714e580b16c583701712ab91060e92dbde6eb1e052a8", "36ac31298eb05c23be1f775d635104705e4560c6532b95c158023c6dc9af06c3", 213},
+}
+
+func loadBlock(t *testing.T, height int32) *wire.MsgBlock {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", fmt.Sprintf("block_%d.dat.bz2", height)))
+	if err != nil {
+		t.Fatalf("Failed to open block %d: %v", height, err)
+	}
+	defer f.Close()
+
+	var block wire.MsgBlock
+	if err := block.Deserialize(bzip2.NewReader(f)); err != nil {
+		t.Fatalf("Failed to decode block %d: %v", height, err)
+	}
+	return &block
+}
+
+type fakeHeaderChain map[string]int32
+
+func (c fakeHeaderChain) BestChainHeight(blockHash string) (int32, bool) {
+	height, ok := c[blockHash]
+	return height, ok
+}
+
+func TestMerkleProofsAgainstMainnetBlocks(t *testing.T) {
+	for _, golden := range goldenBlocks {
+		block := loadBlock(t, golden.height)
+		if block.BlockHash().String() != golden.hash {
+			t.Fatalf("Block %d has hash %s, expected %s", golden.height, block.BlockHash(), golden.hash)
+		}
+		if len(block.Transactions) != golden.txCount {
+			t.Fatalf("Block %d has %d transactions, expected %d", golden.height, len(block.Transactions), golden.txCount)
+		}
+
+		txids := make([]string, len(block.Transactions))
+		for i, tx := range block.Transactions {
+			txids[i] = tx.TxHash().String()
+		}
+
+		// txids are given in display order, exactly as bitcoind prints them
+		tree, err := NewMerkleTree(txids)
+		if err != nil {
+			t.Fatalf("Failed to build tree for block %d: %v", golden.height, err)
+		}
+		if tree.GetMerkleRoot() != golden.merkleRoot || tree.GetMerkleRoot() != block.Header.MerkleRoot.String() {
+			t.Fatalf("Block %d root %s does not match header %s", golden.height, tree.GetMerkleRoot(), block.Header.MerkleRoot)
+		}
+
+		for i, txid := range txids {
+			proof, err := tree.GenerateProof(i)
+			if err != nil {
+				t.Fatalf("Failed to generate proof for block %d tx %d: %v", golden.height, i, err)
+			}
+			if proof.TxHash != txid {
+				t.Errorf("Block %d tx %d proof has tx hash %s, expected %s", golden.height, i, proof.TxHash, txid)
+			}
+			if !VerifyProof(proof) {
+				t.Errorf("Proof verification failed for block %d tx %d", golden.height, i)
+			}
+		}
+	}
+}
+
+func TestBlock170Proof(t *testing.T) {
+	block := loadBlock(t, 170)
+	tree, err := NewMerkleTree([]string{
+		block.Transactions[0].TxHash().String(),
+		block.Transactions[1].TxHash().String(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to build tree: %v", err)
+	}
+
+	// The first bitcoin payment, from Satoshi to Hal Finney
+	proof, err := tree.GenerateProof(1)
+	if err != nil {
+		t.Fatalf("Failed to generate proof: %v", err)
+	}
+	if proof.TxHash != "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16" {
+		t.Errorf("Unexpected tx hash %s", proof.TxHash)
+	}
+	if len(proof.Proof) != 1 || proof.Proof[0] != "b1fea52486ce0c62bb442b530a3f0132b826c74e473d1f2c220bfa78111c5082" {
+		t.Errorf("Expected the coinbase txid as the only sibling, got %v", proof.Proof)
+	}
+
+	// The same hashes read in the wrong byte order must not verify
+	reversed := *proof
+	reversed.TxHash = reverseHex(proof.TxHash)
+	reversed.Proof = []string{reverseHex(proof.Proof[0])}
+	reversed.MerkleRoot = reverseHex(proof.MerkleRoot)
+	if VerifyProof(&reversed) {
+		t.Error("Proof with byte-reversed hashes must not verify")
+	}
+}
+
+func TestGeneratorVerifyProofAgainstHeader(t *testing.T) {
+	block := loadBlock(t, 586)
+	generator := NewGenerator(Config{
+		HeaderChain: fakeHeaderChain{block.BlockHash().String(): 586},
+	})
+
+	txHashes := make([][32]byte, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		txHashes[i] = tx.TxHash()
+	}
+	tree, err := NewMerkleTreeFromHashes(txHashes)
+	if err != nil {
+		t.Fatalf("Failed to build tree: %v", err)
+	}
+
+	for i, tx := range block.Transactions {
+		merkleProof, err := tree.GenerateProof(i)
+		if err != nil {
+			t.Fatalf("Failed to generate proof for tx %d: %v", i, err)
+		}
+
+		spvProof := &SPVProof{
+			BlockHeader: &block.Header,
+			MerkleProof: merkleProof,
+			Transaction: tx,
+			BlockHeight: 586,
+			BlockHash:   block.BlockHash().String(),
+		}
+		if err := generator.VerifyProof(spvProof); err != nil {
+			t.Errorf("Proof for tx %d did not verify against its header: %v", i, err)
+		}
+
+		spvProof.Output, err = NewOutputProof(tx, 0)
+		if err != nil {
+			t.Fatalf("Failed to build output proof for tx %d: %v", i, err)
+		}
+		if err := generator.VerifyProof(spvProof); err != nil {
+			t.Errorf("Output proof for tx %d did not verify: %v", i, err)
+		}
+
+		spvProof.Output.Value++
+		if err := generator.VerifyProof(spvProof); err == nil {
+			t.Errorf("Expected tampered output value for tx %d to be rejected", i)
+		}
+	}
+}
+
+func TestFormatProofForContract(t *testing.T) {
+	block := loadBlock(t, 2812)
+	txHashes := make([][32]byte, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		txHashes[i] = tx.TxHash()
+	}
+	tree, err := NewMerkleTreeFromHashes(txHashes)
+	if err != nil {
+		t.Fatalf("Failed to build tree: %v", err)
+	}
+
+	merkleProof, err := tree.GenerateProof(5)
+	if err != nil {
+		t.Fatalf("Failed to generate proof: %v", err)
+	}
+
+	generator := NewGenerator(Config{})
+	formatted, err := generator.FormatProofForContract(&SPVProof{
+		BlockHeader: &block.Header,
+		MerkleProof: merkleProof,
+		BlockHeight: 2812,
+	})
+	if err != nil {
+		t.Fatalf("Failed to format proof: %v", err)
+	}
+
+	// Walk the proof the way SPVVerifier does, on raw bytes
+	current := mustDecodeHex(t, formatted["txHash"].(string))
+	index := formatted["txIndex"].(uint32)
+	for _, element := range formatted["merkleProof"].([]string) {
+		sibling := mustDecodeHex(t, element)
+		if index%2 == 0 {
+			current = doubleSHA256(append(current, sibling...))
+		} else {
+			current = doubleSHA256(append(sibling, current...))
+		}
+		index /= 2
+	}
+
+	// The root must match the bytes serialized in the header itself
+	var header bytes.Buffer
+	if err := block.Header.Serialize(&header); err != nil {
+		t.Fatalf("Failed to serialize header: %v", err)
+	}
+	headerRoot := header.Bytes()[36:68]
+
+	if !bytes.Equal(current, headerRoot) {
+		t.Errorf("Contract proof root %x does not match header root %x", current, headerRoot)
+	}
+	if formatted["totalTxs"] != uint32(6) || formatted["treeDepth"] != 3 {
+		t.Errorf("Expected 6 transactions at depth 3, got %v at %v", formatted["totalTxs"], formatted["treeDepth"])
+	}
+	if formatted["merkleRoot"] != hex.EncodeToString(headerRoot) {
+		t.Errorf("Expected contract merkle root %x, got %s", headerRoot, formatted["merkleRoot"])
+	}
+}
+
+func TestGeneratorRejects64ByteTransaction(t *testing.T) {
+	// A transaction with one input, one output and a 4-byte output script
+	// serializes to exactly 64 bytes
+	tx := wire.NewMsgTx(1)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 7}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(1000, []byte{0x51, 0x51, 0x51, 0x51}))
+	if tx.SerializeSizeStripped() != 64 {
+		t.Fatalf("Expected a 64-byte transaction, got %d", tx.SerializeSizeStripped())
+	}
+
+	var raw bytes.Buffer
+	if err := tx.SerializeNoWitness(&raw); err != nil {
+		t.Fatalf("Failed to serialize transaction: %v", err)
+	}
+
+	// Build a block whose first two txids are the halves of that
+	// transaction, so its txid equals their parent node
+	var left, right [32]byte
+	copy(left[:], raw.Bytes()[:32])
+	copy(right[:], raw.Bytes()[32:])
+	leaves := append([][32]byte{left, right}, testLeaves(2)...)
+	tree, err := NewMerkleTreeFromHashes(leaves)
+	if err != nil {
+		t.Fatalf("Failed to build tree: %v", err)
+	}
+	if displayHex(tree.levels[1][0]) != tx.TxHash().String() {
+		t.Fatal("Expected the transaction to hash like the inner node")
+	}
+
+	// Claiming the inner node is a leaf of a two-transaction block yields a
+	// Merkle proof that checks out on its own
+	forged := &MerkleProof{
+		TxHash:     tx.TxHash().String(),
+		MerkleRoot: tree.GetMerkleRoot(),
+		Proof:      []string{displayHex(tree.levels[1][1])},
+		Index:      0,
+		TotalTxs:   2,
+	}
+	if !VerifyProof(forged) {
+		t.Fatal("Expected the forged Merkle path to hash to the root")
+	}
+
+	header := wire.NewBlockHeader(1, &chainhash.Hash{}, (*chainhash.Hash)(&tree.levels[2][0]), 0x207fffff, 0)
+	generator := NewGenerator(Config{
+		HeaderChain: fakeHeaderChain{header.BlockHash().String(): 1},
+	})
+	err = generator.VerifyProof(&SPVProof{
+		BlockHeader: header,
+		MerkleProof: forged,
+		Transaction: tx,
+		BlockHeight: 1,
+		BlockHash:   header.BlockHash().String(),
+	})
+	if !errors.Is(err, Err64ByteTransaction) {
+		t.Errorf("Expected 64-byte transaction to be rejected, got %v", err)
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("Invalid hex %s: %v", s, err)
+	}
+	return b
+}
+
+func reverseHex(s string) string {
+	b, _ := hex.DecodeString(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,417 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"bitbridge/pkg/types"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	bucketMeta         = []byte("meta")
+	bucketUTXOs        = []byte("utxos")
+	bucketTokens       = []byte("tokens")
+	bucketTransactions = []byte("transactions")
+	bucketAddresses    = []byte("addresses")
+	bucketProofs       = []byte("proofs")
+	bucketState        = []byte("state")
+	bucketHeaders      = []byte("headers")
+	bucketEvents       = []byte("events")
+	bucketIntents      = []byte("intents")
+	bucketBatches      = []byte("batches")
+)
+
+// BoltStore is a Store backed by an embedded bbolt database file
+type BoltStore struct {
+	db *bolt.DB
+}
+
+var _ Store = (*BoltStore)(nil)
+
+// OpenBolt opens (or creates) the database at path and applies any pending
+// schema migrations
+func OpenBolt(path string) (*BoltStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// Close closes the underlying database
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// SchemaVersion returns the applied schema version
+func (s *BoltStore) SchemaVersion() (uint64, error) {
+	var version uint64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		version = schemaVersion(tx)
+		return nil
+	})
+	return version, err
+}
+
+// UTXOs
+
+func (s *BoltStore) SaveUTXO(utxo *types.UTXO) error {
+	return s.put(bucketUTXOs, utxoKey(utxo.TxID, utxo.Vout), utxo)
+}
+
+func (s *BoltStore) GetUTXO(txid string, vout uint32) (*types.UTXO, error) {
+	var utxo types.UTXO
+	if err := s.get(bucketUTXOs, utxoKey(txid, vout), &utxo); err != nil {
+		return nil, err
+	}
+	return &utxo, nil
+}
+
+func (s *BoltStore) ListUTXOs() ([]*types.UTXO, error) {
+	var utxos []*types.UTXO
+	err := s.forEach(bucketUTXOs, func(_, value []byte) error {
+		var utxo types.UTXO
+		if err := json.Unmarshal(value, &utxo); err != nil {
+			return err
+		}
+		utxos = append(utxos, &utxo)
+		return nil
+	})
+	return utxos, err
+}
+
+func (s *BoltStore) DeleteUTXO(txid string, vout uint32) error {
+	return s.delete(bucketUTXOs, utxoKey(txid, vout))
+}
+
+// Tokens
+
+func (s *BoltStore) SaveToken(token *types.UTXOToken) error {
+	return s.put(bucketTokens, []byte(token.ID), token)
+}
+
+func (s *BoltStore) GetToken(id string) (*types.UTXOToken, error) {
+	var token types.UTXOToken
+	if err := s.get(bucketTokens, []byte(id), &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (s *BoltStore) ListTokens() ([]*types.UTXOToken, error) {
+	var tokens []*types.UTXOToken
+	err := s.forEach(bucketTokens, func(_, value []byte) error {
+		var token types.UTXOToken
+		if err := json.Unmarshal(value, &token); err != nil {
+			return err
+		}
+		tokens = append(tokens, &token)
+		return nil
+	})
+	return tokens, err
+}
+
+// Transactions
+
+func (s *BoltStore) SaveTransaction(tx *types.Transaction) error {
+	return s.put(bucketTransactions, []byte(tx.ID), tx)
+}
+
+func (s *BoltStore) GetTransaction(id string) (*types.Transaction, error) {
+	var tx types.Transaction
+	if err := s.get(bucketTransactions, []byte(id), &tx); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// ListTransactions returns all transactions of the given type, or every
+// transaction when txType is empty
+func (s *BoltStore) ListTransactions(txType string) ([]*types.Transaction, error) {
+	var transactions []*types.Transaction
+	err := s.forEach(bucketTransactions, func(_, value []byte) error {
+		var tx types.Transaction
+		if err := json.Unmarshal(value, &tx); err != nil {
+			return err
+		}
+		if txType == "" || tx.Type == txType {
+			transactions = append(transactions, &tx)
+		}
+		return nil
+	})
+	return transactions, err
+}
+
+// Watched addresses
+
+func (s *BoltStore) AddWatchedAddress(address string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketAddresses)
+		if bucket.Get([]byte(address)) != nil {
+			return nil
+		}
+
+		addedAt, err := time.Now().UTC().MarshalText()
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(address), addedAt)
+	})
+}
+
+func (s *BoltStore) RemoveWatchedAddress(address string) error {
+	return s.delete(bucketAddresses, []byte(address))
+}
+
+func (s *BoltStore) ListWatchedAddresses() ([]string, error) {
+	var addresses []string
+	err := s.forEach(bucketAddresses, func(key, _ []byte) error {
+		addresses = append(addresses, string(key))
+		return nil
+	})
+	return addresses, err
+}
+
+// Proofs
+
+func (s *BoltStore) SaveProof(key string, data []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketProofs).Put([]byte(key), data)
+	})
+}
+
+func (s *BoltStore) GetProof(key string) ([]byte, error) {
+	var data []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(bucketProofs).Get([]byte(key))
+		if value == nil {
+			return ErrNotFound
+		}
+		data = append([]byte(nil), value...)
+		return nil
+	})
+	return data, err
+}
+
+func (s *BoltStore) DeleteProof(key string) error {
+	return s.delete(bucketProofs, []byte(key))
+}
+
+func (s *BoltStore) ListProofs() (map[string][]byte, error) {
+	proofs := make(map[string][]byte)
+	err := s.forEach(bucketProofs, func(key, value []byte) error {
+		proofs[string(key)] = append([]byte(nil), value...)
+		return nil
+	})
+	return proofs, err
+}
+
+// State
+
+func (s *BoltStore) SaveState(key string, data []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketState).Put([]byte(key), data)
+	})
+}
+
+func (s *BoltStore) GetState(key string) ([]byte, error) {
+	var data []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(bucketState).Get([]byte(key))
+		if value == nil {
+			return ErrNotFound
+		}
+		data = append([]byte(nil), value...)
+		return nil
+	})
+	return data, err
+}
+
+// Headers
+
+func (s *BoltStore) SaveHeader(record *HeaderRecord) error {
+	return s.put(bucketHeaders, []byte(record.Hash), record)
+}
+
+func (s *BoltStore) ListHeaders() ([]*HeaderRecord, error) {
+	var records []*HeaderRecord
+	err := s.forEach(bucketHeaders, func(_, value []byte) error {
+		var record HeaderRecord
+		if err := json.Unmarshal(value, &record); err != nil {
+			return err
+		}
+		records = append(records, &record)
+		return nil
+	})
+	return records, err
+}
+
+// Contract events
+
+func (s *BoltStore) SaveEvents(events []*types.ContractEvent) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketEvents)
+		for _, event := range events {
+			data, err := json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("failed to encode %s record: %w", bucketEvents, err)
+			}
+			if err := bucket.Put(eventKey(event.BlockNumber, event.LogIndex), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListEvents returns the events in blocks fromBlock through toBlock
+func (s *BoltStore) ListEvents(fromBlock, toBlock uint64) ([]*types.ContractEvent, error) {
+	var events []*types.ContractEvent
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(bucketEvents).Cursor()
+		end := eventKey(toBlock, ^uint(0))
+		for key, value := cursor.Seek(eventKey(fromBlock, 0)); key != nil && bytes.Compare(key, end) <= 0; key, value = cursor.Next() {
+			var event types.ContractEvent
+			if err := json.Unmarshal(value, &event); err != nil {
+				return fmt.Errorf("failed to decode %s record: %w", bucketEvents, err)
+			}
+			events = append(events, &event)
+		}
+		return nil
+	})
+	return events, err
+}
+
+// DeleteEventsFrom removes the events in block and every later block
+func (s *BoltStore) DeleteEventsFrom(block uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(bucketEvents).Cursor()
+		for key, _ := cursor.Seek(eventKey(block, 0)); key != nil; key, _ = cursor.Seek(eventKey(block, 0)) {
+			if err := cursor.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Deposit intents
+
+func (s *BoltStore) SaveIntent(intent *types.DepositIntent) error {
+	return s.put(bucketIntents, []byte(intent.ID), intent)
+}
+
+func (s *BoltStore) GetIntent(id string) (*types.DepositIntent, error) {
+	var intent types.DepositIntent
+	if err := s.get(bucketIntents, []byte(id), &intent); err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+func (s *BoltStore) ListIntents() ([]*types.DepositIntent, error) {
+	var intents []*types.DepositIntent
+	err := s.forEach(bucketIntents, func(_, value []byte) error {
+		var intent types.DepositIntent
+		if err := json.Unmarshal(value, &intent); err != nil {
+			return err
+		}
+		intents = append(intents, &intent)
+		return nil
+	})
+	return intents, err
+}
+
+// Withdrawal batches
+
+func (s *BoltStore) SaveBatch(batch *types.WithdrawalBatch) error {
+	return s.put(bucketBatches, []byte(batch.ID), batch)
+}
+
+func (s *BoltStore) GetBatch(id string) (*types.WithdrawalBatch, error) {
+	var batch types.WithdrawalBatch
+	if err := s.get(bucketBatches, []byte(id), &batch); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (s *BoltStore) ListBatches() ([]*types.WithdrawalBatch, error) {
+	var batches []*types.WithdrawalBatch
+	err := s.forEach(bucketBatches, func(_, value []byte) error {
+		var batch types.WithdrawalBatch
+		if err := json.Unmarshal(value, &batch); err != nil {
+			return err
+		}
+		batches = append(batches, &batch)
+		return nil
+	})
+	return batches, err
+}
+
+// put JSON-encodes value and stores it under key
+func (s *BoltStore) put(bucket, key []byte, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s record: %w", bucket, err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put(key, data)
+	})
+}
+
+// get decodes the JSON record stored under key into value
+func (s *BoltStore) get(bucket, key []byte, value interface{}) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucket).Get(key)
+		if data == nil {
+			return ErrNotFound
+		}
+		if err := json.Unmarshal(data, value); err != nil {
+			return fmt.Errorf("failed to decode %s record: %w", bucket, err)
+		}
+		return nil
+	})
+}
+
+func (s *BoltStore) delete(bucket, key []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Delete(key)
+	})
+}
+
+func (s *BoltStore) forEach(bucket []byte, fn func(key, value []byte) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).ForEach(fn)
+	})
+}
+
+func utxoKey(txid string, vout uint32) []byte {
+	return []byte(fmt.Sprintf("%s:%d", txid, vout))
+}
+
+// eventKey orders events by block number and then log index
+func eventKey(block uint64, logIndex uint) []byte {
+	key := make([]byte, 12)
+	binary.BigEndian.PutUint64(key[:8], block)
+	binary.BigEndian.PutUint32(key[8:], uint32(logIndex))
+	return key
+}
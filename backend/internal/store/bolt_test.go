@@ -0,0 +1,339 @@
+package store
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"bitbridge/pkg/types"
+)
+
+func openTestStore(t *testing.T) (*BoltStore, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "bridge.db")
+	s, err := OpenBolt(path)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	return s, path
+}
+
+func TestMigrationsApplied(t *testing.T) {
+	s, _ := openTestStore(t)
+
+	version, err := s.SchemaVersion()
+	if err != nil {
+		t.Fatalf("Failed to read schema version: %v", err)
+	}
+
+	latest := migrations[len(migrations)-1].version
+	if version != latest {
+		t.Errorf("Expected schema version %d, got %d", latest, version)
+	}
+}
+
+func TestTransactionsPersistAcrossReopen(t *testing.T) {
+	s, path := openTestStore(t)
+
+	deposit := &types.Transaction{ID: "deposit:aa:0", Type: types.TransactionTypeDeposit, Status: types.TransactionStatusPending}
+	withdrawal := &types.Transaction{ID: "withdrawal:0x01", Type: types.TransactionTypeWithdrawal, Status: types.TransactionStatusBroadcast}
+
+	for _, tx := range []*types.Transaction{deposit, withdrawal} {
+		if err := s.SaveTransaction(tx); err != nil {
+			t.Fatalf("Failed to save transaction: %v", err)
+		}
+	}
+	s.Close()
+
+	reopened, err := OpenBolt(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	defer reopened.Close()
+
+	got, err := reopened.GetTransaction(deposit.ID)
+	if err != nil {
+		t.Fatalf("Failed to get transaction: %v", err)
+	}
+	if got.Status != deposit.Status {
+		t.Errorf("Expected status %s, got %s", deposit.Status, got.Status)
+	}
+
+	deposits, err := reopened.ListTransactions(types.TransactionTypeDeposit)
+	if err != nil {
+		t.Fatalf("Failed to list transactions: %v", err)
+	}
+	if len(deposits) != 1 || deposits[0].ID != deposit.ID {
+		t.Errorf("Expected only %s, got %v", deposit.ID, deposits)
+	}
+
+	all, err := reopened.ListTransactions("")
+	if err != nil {
+		t.Fatalf("Failed to list transactions: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected 2 transactions, got %d", len(all))
+	}
+
+	if _, err := reopened.GetTransaction("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUTXOs(t *testing.T) {
+	s, _ := openTestStore(t)
+
+	utxo := &types.UTXO{TxID: "aa", Vout: 1, Amount: 5000, Address: "tb1qexample", Confirmations: 2}
+	if err := s.SaveUTXO(utxo); err != nil {
+		t.Fatalf("Failed to save UTXO: %v", err)
+	}
+
+	utxo.Confirmations = 3
+	if err := s.SaveUTXO(utxo); err != nil {
+		t.Fatalf("Failed to update UTXO: %v", err)
+	}
+
+	got, err := s.GetUTXO("aa", 1)
+	if err != nil {
+		t.Fatalf("Failed to get UTXO: %v", err)
+	}
+	if got.Confirmations != 3 || got.Amount != 5000 {
+		t.Errorf("Unexpected UTXO: %+v", got)
+	}
+
+	if err := s.DeleteUTXO("aa", 1); err != nil {
+		t.Fatalf("Failed to delete UTXO: %v", err)
+	}
+	utxos, err := s.ListUTXOs()
+	if err != nil {
+		t.Fatalf("Failed to list UTXOs: %v", err)
+	}
+	if len(utxos) != 0 {
+		t.Errorf("Expected no UTXOs, got %d", len(utxos))
+	}
+}
+
+func TestTokens(t *testing.T) {
+	s, _ := openTestStore(t)
+
+	token := &types.UTXOToken{ID: "aa:0", TokenAddress: "0x01", TotalSupply: 5000, Status: "active"}
+	if err := s.SaveToken(token); err != nil {
+		t.Fatalf("Failed to save token: %v", err)
+	}
+
+	got, err := s.GetToken("aa:0")
+	if err != nil {
+		t.Fatalf("Failed to get token: %v", err)
+	}
+	if got.TokenAddress != token.TokenAddress {
+		t.Errorf("Expected token address %s, got %s", token.TokenAddress, got.TokenAddress)
+	}
+
+	tokens, err := s.ListTokens()
+	if err != nil {
+		t.Fatalf("Failed to list tokens: %v", err)
+	}
+	if len(tokens) != 1 {
+		t.Errorf("Expected 1 token, got %d", len(tokens))
+	}
+}
+
+func TestWatchedAddresses(t *testing.T) {
+	s, _ := openTestStore(t)
+
+	for _, address := range []string{"tb1qfirst", "tb1qsecond", "tb1qfirst"} {
+		if err := s.AddWatchedAddress(address); err != nil {
+			t.Fatalf("Failed to add address: %v", err)
+		}
+	}
+
+	addresses, err := s.ListWatchedAddresses()
+	if err != nil {
+		t.Fatalf("Failed to list addresses: %v", err)
+	}
+	if len(addresses) != 2 {
+		t.Errorf("Expected 2 addresses, got %v", addresses)
+	}
+
+	if err := s.RemoveWatchedAddress("tb1qfirst"); err != nil {
+		t.Fatalf("Failed to remove address: %v", err)
+	}
+	addresses, _ = s.ListWatchedAddresses()
+	if len(addresses) != 1 || addresses[0] != "tb1qsecond" {
+		t.Errorf("Expected only tb1qsecond, got %v", addresses)
+	}
+}
+
+func TestProofs(t *testing.T) {
+	s, _ := openTestStore(t)
+
+	if err := s.SaveProof("aa:0", []byte(`{"proof":1}`)); err != nil {
+		t.Fatalf("Failed to save proof: %v", err)
+	}
+
+	data, err := s.GetProof("aa:0")
+	if err != nil {
+		t.Fatalf("Failed to get proof: %v", err)
+	}
+	if string(data) != `{"proof":1}` {
+		t.Errorf("Unexpected proof data: %s", data)
+	}
+
+	proofs, err := s.ListProofs()
+	if err != nil {
+		t.Fatalf("Failed to list proofs: %v", err)
+	}
+	if len(proofs) != 1 {
+		t.Errorf("Expected 1 proof, got %d", len(proofs))
+	}
+
+	if err := s.DeleteProof("aa:0"); err != nil {
+		t.Fatalf("Failed to delete proof: %v", err)
+	}
+	if _, err := s.GetProof("aa:0"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestState(t *testing.T) {
+	s, _ := openTestStore(t)
+
+	if _, err := s.GetState("indexer"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	if err := s.SaveState("indexer", []byte(`{"height":10}`)); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+
+	data, err := s.GetState("indexer")
+	if err != nil {
+		t.Fatalf("Failed to get state: %v", err)
+	}
+	if string(data) != `{"height":10}` {
+		t.Errorf("Unexpected state data: %s", data)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	s, _ := openTestStore(t)
+
+	record := &HeaderRecord{Hash: "00aa", Height: 2016, Header: make([]byte, 80)}
+	if err := s.SaveHeader(record); err != nil {
+		t.Fatalf("Failed to save header: %v", err)
+	}
+
+	records, err := s.ListHeaders()
+	if err != nil {
+		t.Fatalf("Failed to list headers: %v", err)
+	}
+	if len(records) != 1 || records[0].Height != 2016 || len(records[0].Header) != 80 {
+		t.Errorf("Unexpected headers: %+v", records)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	s, _ := openTestStore(t)
+
+	var events []*types.ContractEvent
+	for _, ref := range []struct {
+		block    uint64
+		logIndex uint
+	}{{5, 1}, {5, 0}, {7, 3}, {300, 0}, {70000, 2}} {
+		events = append(events, &types.ContractEvent{Name: "UTXORegistered", BlockNumber: ref.block, LogIndex: ref.logIndex})
+	}
+	if err := s.SaveEvents(events); err != nil {
+		t.Fatalf("Failed to save events: %v", err)
+	}
+
+	listed, err := s.ListEvents(5, 300)
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	if len(listed) != 4 || listed[0].LogIndex != 0 || listed[1].LogIndex != 1 || listed[3].BlockNumber != 300 {
+		t.Errorf("Expected events 5/0, 5/1, 7/3 and 300/0 in order, got %+v", listed)
+	}
+
+	if err := s.DeleteEventsFrom(7); err != nil {
+		t.Fatalf("Failed to delete events: %v", err)
+	}
+	listed, err = s.ListEvents(0, ^uint64(0))
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	if len(listed) != 2 || listed[1].BlockNumber != 5 {
+		t.Errorf("Expected only block 5 events to remain, got %+v", listed)
+	}
+}
+
+func TestIntents(t *testing.T) {
+	s, _ := openTestStore(t)
+
+	intent := &types.DepositIntent{ID: "intent-1", Recipient: "0xaa", DepositAddress: "bc1qexample", MinAmount: 10000, Status: types.IntentStatusPending}
+	if err := s.SaveIntent(intent); err != nil {
+		t.Fatalf("Failed to save intent: %v", err)
+	}
+
+	got, err := s.GetIntent("intent-1")
+	if err != nil {
+		t.Fatalf("Failed to get intent: %v", err)
+	}
+	if got.DepositAddress != intent.DepositAddress || got.MinAmount != intent.MinAmount {
+		t.Errorf("Expected intent %+v, got %+v", intent, got)
+	}
+
+	if _, err := s.GetIntent("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	intents, err := s.ListIntents()
+	if err != nil {
+		t.Fatalf("Failed to list intents: %v", err)
+	}
+	if len(intents) != 1 {
+		t.Errorf("Expected 1 intent, got %d", len(intents))
+	}
+}
+
+func TestBatches(t *testing.T) {
+	s, _ := openTestStore(t)
+
+	batch := &types.WithdrawalBatch{
+		ID:          "batch:abcd",
+		Status:      types.TransactionStatusBatched,
+		Withdrawals: []string{"withdrawal:0x01", "withdrawal:0x02"},
+		Outputs: []types.BatchOutput{
+			{WithdrawalID: "withdrawal:0x01", Vout: 0, Amount: 49900, FeeShare: 100},
+			{WithdrawalID: "withdrawal:0x02", Vout: 1, Amount: 29901, FeeShare: 99},
+		},
+		TxID:         "abcd",
+		Fee:          199,
+		ChangeOutput: -1,
+	}
+	if err := s.SaveBatch(batch); err != nil {
+		t.Fatalf("Failed to save batch: %v", err)
+	}
+
+	got, err := s.GetBatch("batch:abcd")
+	if err != nil {
+		t.Fatalf("Failed to get batch: %v", err)
+	}
+	if len(got.Outputs) != 2 || got.Outputs[1].FeeShare != 99 || got.ChangeOutput != -1 {
+		t.Errorf("Expected batch %+v, got %+v", batch, got)
+	}
+
+	if _, err := s.GetBatch("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	batches, err := s.ListBatches()
+	if err != nil {
+		t.Fatalf("Failed to list batches: %v", err)
+	}
+	if len(batches) != 1 {
+		t.Errorf("Expected 1 batch, got %d", len(batches))
+	}
+}
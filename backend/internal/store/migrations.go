@@ -0,0 +1,120 @@
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var keySchemaVersion = []byte("schema_version")
+
+// migration upgrades the database schema by one version. Migrations run in
+// order inside a single transaction each and must never be edited once
+// released; add a new one instead.
+type migration struct {
+	version     uint64
+	description string
+	apply       func(tx *bolt.Tx) error
+}
+
+var migrations = []migration{
+	{
+		version:     1,
+		description: "create utxo, token, transaction, address and proof buckets",
+		apply: func(tx *bolt.Tx) error {
+			for _, name := range [][]byte{bucketUTXOs, bucketTokens, bucketTransactions, bucketAddresses, bucketProofs} {
+				if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		version:     2,
+		description: "create component state bucket",
+		apply: func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(bucketState)
+			return err
+		},
+	},
+	{
+		version:     3,
+		description: "create block header bucket",
+		apply: func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(bucketHeaders)
+			return err
+		},
+	},
+	{
+		version:     4,
+		description: "create contract event bucket",
+		apply: func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(bucketEvents)
+			return err
+		},
+	},
+	{
+		version:     5,
+		description: "create deposit intent bucket",
+		apply: func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(bucketIntents)
+			return err
+		},
+	},
+	{
+		version:     6,
+		description: "create withdrawal batch bucket",
+		apply: func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(bucketBatches)
+			return err
+		},
+	},
+}
+
+// migrate applies every migration newer than the stored schema version
+func migrate(db *bolt.DB) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(bucketMeta); err != nil {
+			return fmt.Errorf("failed to create meta bucket: %w", err)
+		}
+
+		current := schemaVersion(tx)
+		latest := migrations[len(migrations)-1].version
+		if current > latest {
+			return fmt.Errorf("database schema version %d is newer than supported version %d", current, latest)
+		}
+
+		for _, m := range migrations {
+			if m.version <= current {
+				continue
+			}
+
+			if err := m.apply(tx); err != nil {
+				return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.description, err)
+			}
+			if err := setSchemaVersion(tx, m.version); err != nil {
+				return err
+			}
+			log.Printf("Applied store migration %d: %s", m.version, m.description)
+		}
+
+		return nil
+	})
+}
+
+func schemaVersion(tx *bolt.Tx) uint64 {
+	value := tx.Bucket(bucketMeta).Get(keySchemaVersion)
+	if len(value) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(value)
+}
+
+func setSchemaVersion(tx *bolt.Tx, version uint64) error {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, version)
+	return tx.Bucket(bucketMeta).Put(keySchemaVersion, value)
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"errors"
+
+	"bitbridge/pkg/types"
+)
+
+// ErrNotFound is returned when a record is not in the store
+var ErrNotFound = errors.New("record not found")
+
+// UTXORepository persists UTXOs seen on watched addresses
+type UTXORepository interface {
+	SaveUTXO(utxo *types.UTXO) error
+	GetUTXO(txid string, vout uint32) (*types.UTXO, error)
+	ListUTXOs() ([]*types.UTXO, error)
+	DeleteUTXO(txid string, vout uint32) error
+}
+
+// TokenRepository persists UTXO tokens, keyed by their ID
+type TokenRepository interface {
+	SaveToken(token *types.UTXOToken) error
+	GetToken(id string) (*types.UTXOToken, error)
+	ListTokens() ([]*types.UTXOToken, error)
+}
+
+// TransactionRepository persists bridge transactions so that in-flight
+// deposits and withdrawals survive a gateway restart
+type TransactionRepository interface {
+	SaveTransaction(tx *types.Transaction) error
+	GetTransaction(id string) (*types.Transaction, error)
+	ListTransactions(txType string) ([]*types.Transaction, error)
+}
+
+// AddressRepository persists the set of watched Bitcoin addresses
+type AddressRepository interface {
+	AddWatchedAddress(address string) error
+	RemoveWatchedAddress(address string) error
+	ListWatchedAddresses() ([]string, error)
+}
+
+// ProofRepository persists serialized SPV proofs. Proofs are stored as
+// opaque bytes so that this package does not depend on the proof package.
+type ProofRepository interface {
+	SaveProof(key string, data []byte) error
+	GetProof(key string) ([]byte, error)
+	DeleteProof(key string) error
+	ListProofs() (map[string][]byte, error)
+}
+
+// StateRepository persists small named blobs of component state, such as
+// the indexer's chain checkpoint
+type StateRepository interface {
+	SaveState(key string, data []byte) error
+	GetState(key string) ([]byte, error)
+}
+
+// HeaderRecord is a raw 80-byte Bitcoin block header and its height
+type HeaderRecord struct {
+	Hash   string `json:"hash"`
+	Height int32  `json:"height"`
+	Header []byte `json:"header"`
+}
+
+// HeaderRepository persists validated Bitcoin block headers, keyed by hash
+type HeaderRepository interface {
+	SaveHeader(record *HeaderRecord) error
+	ListHeaders() ([]*HeaderRecord, error)
+}
+
+// EventRepository persists decoded contract events in block and log order
+type EventRepository interface {
+	SaveEvents(events []*types.ContractEvent) error
+	ListEvents(fromBlock, toBlock uint64) ([]*types.ContractEvent, error)
+	DeleteEventsFrom(block uint64) error
+}
+
+// IntentRepository persists deposit intents, keyed by their ID
+type IntentRepository interface {
+	SaveIntent(intent *types.DepositIntent) error
+	GetIntent(id string) (*types.DepositIntent, error)
+	ListIntents() ([]*types.DepositIntent, error)
+}
+
+// BatchRepository persists withdrawal batches, keyed by their ID
+type BatchRepository interface {
+	SaveBatch(batch *types.WithdrawalBatch) error
+	GetBatch(id string) (*types.WithdrawalBatch, error)
+	ListBatches() ([]*types.WithdrawalBatch, error)
+}
+
+// Store combines all repositories behind a single handle
+type Store interface {
+	UTXORepository
+	TokenRepository
+	TransactionRepository
+	AddressRepository
+	ProofRepository
+	StateRepository
+	HeaderRepository
+	EventRepository
+	IntentRepository
+	BatchRepository
+	Close() error
+}
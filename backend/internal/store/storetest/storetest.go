@@ -0,0 +1,23 @@
+// Package storetest provides the bolt store fixture shared by the tests of
+// packages that persist through internal/store
+package storetest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"bitbridge/internal/store"
+)
+
+// Open opens an empty bolt store in a temporary directory and closes it
+// when the test finishes
+func Open(t testing.TB) *store.BoltStore {
+	t.Helper()
+
+	repo, err := store.OpenBolt(filepath.Join(t.TempDir(), "bridge.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
@@ -10,28 +12,57 @@ type Config struct {
 	Bitcoin  BitcoinConfig
 	Ethereum EthereumConfig
 	Fusion   FusionConfig
+	Bridge   BridgeConfig
 }
 
 type ServerConfig struct {
-	Port string
+	Port       string
+	AdminToken string // bearer token for /v1/admin endpoints, empty to disable them
 }
 
 type BitcoinConfig struct {
-	RPCHost     string
-	RPCPort     int
-	RPCUser     string
-	RPCPassword string
-	Network     string // mainnet, testnet, regtest
+	RPCHost           string
+	RPCPort           int
+	RPCUser           string
+	RPCPassword       string
+	Network           string        // mainnet, testnet, regtest
+	HeaderStartHeight int64         // block to anchor header validation at, 0 for the current difficulty period
+	DepositAccountKey string        // account xpub/zpub or wpkh()/tr() descriptor to derive deposit addresses from, empty to use the node wallet
+	DepositScriptType string        // p2wpkh or p2tr, for plain xpub/tpub account keys
+	DepositGapLimit   int           // unused deposit addresses allowed after the last funded one
+	MemoAddress       string        // shared deposit address for deposits naming their recipient in an OP_RETURN memo, empty to disable
+	PSBTWithdrawals   bool          // build payouts from tracked UTXOs and sign them as PSBTs with the node wallet; needs DepositAccountKey
+	WithdrawalFeeRate int64         // sat/vB for built payouts
+	DustLimit         int64         // smallest output built payouts create, in satoshis
+	PayoutStuckBlocks int           // blocks a payout may stay unconfirmed before its fee is bumped, 0 never bumps
+	MaxFeeRate        int64         // sat/vB fee bumps never go above
+	FeeFloor          int64         // lowest sat/vB the fee estimator returns
+	FeeCeiling        int64         // highest sat/vB the fee estimator returns
+	FeeCacheTTL       time.Duration // how long fee estimates are reused
+	FeeTarget         int64         // blocks built payouts aim to confirm in
+	ZMQEndpoints      []string      // tcp:// addresses of the node's zmqpub notifiers, empty to only poll
+	ZMQPollInterval   time.Duration // how often the indexer and header chain still poll while ZMQ notifications arrive
 }
 
 type EthereumConfig struct {
-	RPCEndpoint        string
-	ChainID            int64
-	PrivateKey         string
-	UTXORegistryAddr   string
-	TokenFactoryAddr   string
-	FusionPlusAddr     string
-	SPVVerifierAddr    string
+	RPCEndpoint           string
+	ChainID               int64
+	PrivateKey            string // raw hex key, for development only
+	KeystorePath          string // encrypted JSON keystore for the operator key
+	KeystorePasswordFile  string
+	RemoteSignerURL       string // JSON-RPC eth_signTransaction endpoint holding the operator key
+	SignerAddress         string // operator account at the remote signer, empty for its first
+	UTXORegistryAddr      string
+	TokenFactoryAddr      string
+	FusionPlusAddr        string
+	SPVVerifierAddr       string
+	TxReplaceAfter        time.Duration // time an operator transaction may stay pending before its fee is bumped
+	TxFeeBumpPercent      int
+	FeeHistoryBlocks      int     // blocks of eth_feeHistory sampled for the priority fee
+	PriorityFeePercentile float64 // reward percentile paid as priority fee
+	MaxFeeGwei            float64 // cap on max fee per gas, 0 for none
+	MaxPriorityFeeGwei    float64 // cap on max priority fee per gas, 0 for none
+	GasLimitMultiplier    float64 // safety margin applied to gas estimates
 }
 
 type FusionConfig struct {
@@ -40,35 +71,109 @@ type FusionConfig struct {
 	Enabled bool
 }
 
+type BridgeConfig struct {
+	DataDir                 string
+	RequiredConfirmations   int
+	MaxAttempts             int
+	RetryInterval           time.Duration
+	IntentTTL               time.Duration // time a deposit intent stays open for funding
+	MinDepositAmount        int64         // smallest deposit minted for an intent, in satoshis
+	WithdrawalStartBlock    int64         // Ethereum block to start scanning redemptions from, 0 to resume
+	WithdrawalBatchInterval time.Duration // longest a withdrawal waits to be batched, 0 pays each withdrawal on its own
+	WithdrawalBatchSize     int           // withdrawals that flush a batch early, and the most paid by one transaction
+	FinalityDepth           int           // Ethereum blocks to wait before acting on an event
+	EventPollInterval       time.Duration
+	IndexerStartHeight      int64 // Bitcoin block to start indexing from when there is no checkpoint, 0 for the tip
+	IndexerPollInterval     time.Duration
+	ReorgDepth              int // Bitcoin blocks remembered for reorg rollback
+	RelayBatchSize          int // headers per SPVVerifier submission
+	RelayPollInterval       time.Duration
+	EventStartBlock         int64 // Ethereum block to start indexing contract events from when there is no checkpoint, 0 for the head
+	EventReorgDepth         int   // scanned Ethereum block ranges remembered for reorg rollback
+}
+
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
+			Port:       getEnv("SERVER_PORT", "8080"),
+			AdminToken: getEnv("SERVER_ADMIN_TOKEN", ""),
 		},
 		Bitcoin: BitcoinConfig{
-			RPCHost:     getEnv("BITCOIN_RPC_HOST", "localhost"),
-			RPCPort:     getEnvInt("BITCOIN_RPC_PORT", 18332), // testnet default
-			RPCUser:     getEnv("BITCOIN_RPC_USER", ""),
-			RPCPassword: getEnv("BITCOIN_RPC_PASSWORD", ""),
-			Network:     getEnv("BITCOIN_NETWORK", "testnet"),
+			RPCHost:           getEnv("BITCOIN_RPC_HOST", "localhost"),
+			RPCPort:           getEnvInt("BITCOIN_RPC_PORT", 18332), // testnet default
+			RPCUser:           getEnv("BITCOIN_RPC_USER", ""),
+			RPCPassword:       getEnv("BITCOIN_RPC_PASSWORD", ""),
+			Network:           getEnv("BITCOIN_NETWORK", "testnet"),
+			HeaderStartHeight: getEnvInt64("BITCOIN_HEADER_START_HEIGHT", 0),
+			DepositAccountKey: getEnv("BITCOIN_DEPOSIT_ACCOUNT_KEY", ""),
+			DepositScriptType: getEnv("BITCOIN_DEPOSIT_SCRIPT_TYPE", "p2wpkh"),
+			DepositGapLimit:   getEnvInt("BITCOIN_DEPOSIT_GAP_LIMIT", 1000),
+			MemoAddress:       getEnv("BITCOIN_MEMO_ADDRESS", ""),
+			PSBTWithdrawals:   getEnvBool("BITCOIN_PSBT_WITHDRAWALS", false),
+			WithdrawalFeeRate: getEnvInt64("BITCOIN_WITHDRAWAL_FEE_RATE", 2),
+			DustLimit:         getEnvInt64("BITCOIN_DUST_LIMIT", 546),
+			PayoutStuckBlocks: getEnvInt("BITCOIN_PAYOUT_STUCK_BLOCKS", 6),
+			MaxFeeRate:        getEnvInt64("BITCOIN_MAX_FEE_RATE", 200),
+			FeeFloor:          getEnvInt64("BITCOIN_FEE_FLOOR", 1),
+			FeeCeiling:        getEnvInt64("BITCOIN_FEE_CEILING", 500),
+			FeeCacheTTL:       getEnvDuration("BITCOIN_FEE_CACHE_TTL", time.Minute),
+			FeeTarget:         getEnvInt64("BITCOIN_FEE_TARGET", 6),
+			ZMQEndpoints:      getEnvList("BITCOIN_ZMQ_ENDPOINTS"),
+			ZMQPollInterval:   getEnvDuration("BITCOIN_ZMQ_POLL_INTERVAL", 5*time.Minute),
 		},
 		Ethereum: EthereumConfig{
-			RPCEndpoint:      getEnv("ETHEREUM_RPC_ENDPOINT", "https://sepolia.infura.io/v3/YOUR_PROJECT_ID"),
-			ChainID:          getEnvInt64("ETHEREUM_CHAIN_ID", 11155111), // Sepolia
-			PrivateKey:       getEnv("ETHEREUM_PRIVATE_KEY", ""),
-			UTXORegistryAddr: getEnv("UTXO_REGISTRY_ADDRESS", ""),
-			TokenFactoryAddr: getEnv("TOKEN_FACTORY_ADDRESS", ""),
-			FusionPlusAddr:   getEnv("FUSION_PLUS_ADDRESS", ""),
-			SPVVerifierAddr:  getEnv("SPV_VERIFIER_ADDRESS", ""),
+			RPCEndpoint:           getEnv("ETHEREUM_RPC_ENDPOINT", "https://sepolia.infura.io/v3/YOUR_PROJECT_ID"),
+			ChainID:               getEnvInt64("ETHEREUM_CHAIN_ID", 11155111), // Sepolia
+			PrivateKey:            getEnv("ETHEREUM_PRIVATE_KEY", ""),
+			KeystorePath:          getEnv("ETHEREUM_KEYSTORE_PATH", ""),
+			KeystorePasswordFile:  getEnv("ETHEREUM_KEYSTORE_PASSWORD_FILE", ""),
+			RemoteSignerURL:       getEnv("ETHEREUM_REMOTE_SIGNER_URL", ""),
+			SignerAddress:         getEnv("ETHEREUM_SIGNER_ADDRESS", ""),
+			UTXORegistryAddr:      getEnv("UTXO_REGISTRY_ADDRESS", ""),
+			TokenFactoryAddr:      getEnv("TOKEN_FACTORY_ADDRESS", ""),
+			FusionPlusAddr:        getEnv("FUSION_PLUS_ADDRESS", ""),
+			SPVVerifierAddr:       getEnv("SPV_VERIFIER_ADDRESS", ""),
+			TxReplaceAfter:        getEnvDuration("ETHEREUM_TX_REPLACE_AFTER", 3*time.Minute),
+			TxFeeBumpPercent:      getEnvInt("ETHEREUM_TX_FEE_BUMP_PERCENT", 20),
+			FeeHistoryBlocks:      getEnvInt("ETHEREUM_FEE_HISTORY_BLOCKS", 20),
+			PriorityFeePercentile: getEnvFloat("ETHEREUM_PRIORITY_FEE_PERCENTILE", 50),
+			MaxFeeGwei:            getEnvFloat("ETHEREUM_MAX_FEE_GWEI", 0),
+			MaxPriorityFeeGwei:    getEnvFloat("ETHEREUM_MAX_PRIORITY_FEE_GWEI", 0),
+			GasLimitMultiplier:    getEnvFloat("ETHEREUM_GAS_LIMIT_MULTIPLIER", 1.2),
 		},
 		Fusion: FusionConfig{
 			BaseURL: getEnv("FUSION_BASE_URL", "https://api.1inch.dev"),
 			APIKey:  getEnv("FUSION_API_KEY", ""),
 			Enabled: getEnvBool("FUSION_ENABLED", true),
 		},
+		Bridge: BridgeConfig{
+			DataDir:                 getEnv("BRIDGE_DATA_DIR", "./data"),
+			RequiredConfirmations:   getEnvInt("BRIDGE_REQUIRED_CONFIRMATIONS", 6),
+			MaxAttempts:             getEnvInt("BRIDGE_MAX_ATTEMPTS", 10),
+			RetryInterval:           getEnvDuration("BRIDGE_RETRY_INTERVAL", time.Minute),
+			IntentTTL:               getEnvDuration("BRIDGE_INTENT_TTL", 24*time.Hour),
+			MinDepositAmount:        getEnvInt64("BRIDGE_MIN_DEPOSIT_SATS", 10000),
+			WithdrawalStartBlock:    getEnvInt64("BRIDGE_WITHDRAWAL_START_BLOCK", 0),
+			WithdrawalBatchInterval: getEnvDuration("BRIDGE_WITHDRAWAL_BATCH_INTERVAL", 0),
+			WithdrawalBatchSize:     getEnvInt("BRIDGE_WITHDRAWAL_BATCH_SIZE", 50),
+			FinalityDepth:           getEnvInt("BRIDGE_FINALITY_DEPTH", 12),
+			EventPollInterval:       getEnvDuration("BRIDGE_EVENT_POLL_INTERVAL", 15*time.Second),
+			IndexerStartHeight:      getEnvInt64("BRIDGE_INDEXER_START_HEIGHT", 0),
+			IndexerPollInterval:     getEnvDuration("BRIDGE_INDEXER_POLL_INTERVAL", 10*time.Second),
+			ReorgDepth:              getEnvInt("BRIDGE_REORG_DEPTH", 100),
+			RelayBatchSize:          getEnvInt("BRIDGE_RELAY_BATCH_SIZE", 20),
+			RelayPollInterval:       getEnvDuration("BRIDGE_RELAY_POLL_INTERVAL", 30*time.Second),
+			EventStartBlock:         getEnvInt64("BRIDGE_EVENT_START_BLOCK", 0),
+			EventReorgDepth:         getEnvInt("BRIDGE_EVENT_REORG_DEPTH", 64),
+		},
 	}
 }
 
+// HasSigner reports whether an operator signer is configured
+func (c *EthereumConfig) HasSigner() bool {
+	return c.RemoteSignerURL != "" || c.KeystorePath != "" || c.PrivateKey != ""
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -94,6 +199,15 @@ func getEnvInt64(key string, defaultValue int64) int64 {
 	return defaultValue
 }
 
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
@@ -101,4 +215,24 @@ func getEnvBool(key string, defaultValue bool) bool {
 		}
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// getEnvList splits a comma-separated variable, skipping empty entries
+func getEnvList(key string) []string {
+	var list []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
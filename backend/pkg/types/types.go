@@ -13,6 +13,12 @@ type UTXO struct {
 	Address      string    `json:"address"`
 	Confirmations int      `json:"confirmations"`
 	BlockHeight  int       `json:"block_height"`
+	BlockHash    string    `json:"block_hash,omitempty"`
+	SpentTxID    string    `json:"spent_txid,omitempty"`
+	SpentHeight  int       `json:"spent_height,omitempty"`
+	Recipient    string    `json:"recipient,omitempty"`  // Ethereum recipient named by an OP_RETURN memo
+	MemoError    string    `json:"memo_error,omitempty"` // why a shared-address deposit has no usable memo
+	Change       bool      `json:"change,omitempty"`     // created by a transaction spending bridge UTXOs, not a deposit
 	CreatedAt    time.Time `json:"created_at"`
 }
 
@@ -34,7 +40,7 @@ type UTXOToken struct {
 type Transaction struct {
 	ID              string    `json:"id"`
 	Type            string    `json:"type"`         // deposit, withdrawal, swap
-	Status          string    `json:"status"`       // pending, confirmed, failed
+	Status          string    `json:"status"`       // pending, unattributed, queued, batched, confirmed, proof_submitted, sending, broadcast, completed, failed
 	BitcoinTxID     string    `json:"bitcoin_txid,omitempty"`
 	EthereumTxHash  string    `json:"ethereum_tx_hash,omitempty"`
 	Amount          int64     `json:"amount"`       // satoshis
@@ -44,6 +50,141 @@ type Transaction struct {
 	UpdatedAt       time.Time `json:"updated_at"`
 	Confirmations   int       `json:"confirmations"`
 	RequiredConfirms int      `json:"required_confirms"`
+	BitcoinVout     uint32    `json:"bitcoin_vout"`
+	VerifyTxHash    string    `json:"verify_tx_hash,omitempty"` // SPVVerifier proof submission
+	TokenAddress    string    `json:"token_address,omitempty"`
+	SourceUTXO      string    `json:"source_utxo,omitempty"`    // txid:vout redeemed by a withdrawal
+	SettleTxHash    string    `json:"settle_tx_hash,omitempty"` // UTXORegistry.markUTXORedeemed
+	EthereumBlock   uint64    `json:"ethereum_block,omitempty"`
+	BitcoinInputs   []string  `json:"bitcoin_inputs,omitempty"` // txid:vout spent by a built payout
+	BitcoinFee      int64     `json:"bitcoin_fee,omitempty"`    // satoshis paid to miners by the payout
+	RawTx           string    `json:"raw_tx,omitempty"`         // signed payout, kept for rebroadcast
+	BitcoinChange   int64     `json:"bitcoin_change,omitempty"` // satoshis returned by a built payout in its last output
+	BroadcastHeight int64     `json:"broadcast_height,omitempty"` // Bitcoin tip when the payout was first seen broadcast
+	FeeBumps        []FeeBump `json:"fee_bumps,omitempty"`      // replacements and children of a stuck payout
+	ConfirmedTxID   string    `json:"confirmed_txid,omitempty"` // payout or replacement that confirmed
+	BatchID         string    `json:"batch_id,omitempty"`       // batched payout paying this withdrawal
+	FeeShare        int64     `json:"fee_share,omitempty"`      // satoshis of the batch fee deducted from this withdrawal
+	Attempts        int       `json:"attempts"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// Transaction types
+const (
+	TransactionTypeDeposit    = "deposit"
+	TransactionTypeWithdrawal = "withdrawal"
+	TransactionTypeSwap       = "swap"
+)
+
+// Transaction statuses. Deposits move pending -> confirmed -> proof_submitted
+// -> completed and withdrawals move pending -> sending -> broadcast ->
+// confirmed -> completed; any step may end in failed. With batching enabled,
+// withdrawals move pending -> queued -> batched -> broadcast -> confirmed ->
+// completed instead. Deposits to the shared memo address without a usable
+// memo wait in unattributed until an operator names the recipient.
+const (
+	TransactionStatusPending        = "pending"
+	TransactionStatusUnattributed   = "unattributed"
+	TransactionStatusQueued         = "queued"
+	TransactionStatusBatched        = "batched"
+	TransactionStatusConfirmed      = "confirmed"
+	TransactionStatusProofSubmitted = "proof_submitted"
+	TransactionStatusSending        = "sending"
+	TransactionStatusBroadcast      = "broadcast"
+	TransactionStatusCompleted      = "completed"
+	TransactionStatusFailed         = "failed"
+)
+
+// DepositIntent binds a Bitcoin deposit address to the Ethereum recipient
+// that deposits to it are minted to. Status is derived from the deposit
+// transactions seen on the address.
+type DepositIntent struct {
+	ID             string    `json:"id"`
+	Recipient      string    `json:"recipient"`
+	DepositAddress string    `json:"deposit_address"`
+	MinAmount      int64     `json:"min_amount"` // satoshis
+	Status         string    `json:"status"`     // pending, expired, funded, completed, failed
+	Deposits       []string  `json:"deposits,omitempty"`
+	Amount         int64     `json:"amount"` // satoshis deposited
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Deposit intent statuses. An intent is pending until a deposit arrives or
+// it expires; a deposit makes it funded until the token is minted.
+const (
+	IntentStatusPending   = "pending"
+	IntentStatusExpired   = "expired"
+	IntentStatusFunded    = "funded"
+	IntentStatusCompleted = "completed"
+	IntentStatusFailed    = "failed"
+)
+
+// WithdrawalBatch is a single payout transaction paying several queued
+// withdrawals, one output each. The signed transaction is kept so it can be
+// rebroadcast until accepted.
+type WithdrawalBatch struct {
+	ID              string        `json:"id"`
+	Status          string        `json:"status"` // batched, broadcast, confirmed, failed
+	Withdrawals     []string      `json:"withdrawals"`
+	Outputs         []BatchOutput `json:"outputs"`
+	TxID            string        `json:"txid"`
+	RawTx           string        `json:"raw_tx,omitempty"`
+	Inputs          []string      `json:"inputs"`           // txid:vout spent by the batch
+	Fee             int64         `json:"fee"`              // satoshis paid to miners
+	FeeRate         int64         `json:"fee_rate"`         // sat/vB
+	ChangeOutput    int           `json:"change_output"`    // -1 without change
+	Change          int64         `json:"change,omitempty"` // satoshis
+	Confirmations   int           `json:"confirmations"`
+	BroadcastHeight int64         `json:"broadcast_height,omitempty"` // Bitcoin tip when the batch was first seen broadcast
+	FeeBumps        []FeeBump     `json:"fee_bumps,omitempty"`
+	ConfirmedTxID   string        `json:"confirmed_txid,omitempty"` // batch transaction or replacement that confirmed
+	Error           string        `json:"error,omitempty"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+// BatchOutput is the output of a batch paying one withdrawal
+type BatchOutput struct {
+	WithdrawalID string `json:"withdrawal_id"`
+	Vout         uint32 `json:"vout"`
+	Address      string `json:"address"`
+	Amount       int64  `json:"amount"`    // satoshis paid, after the fee share
+	FeeShare     int64  `json:"fee_share"` // satoshis deducted towards the fee
+}
+
+// FeeBump is a transaction broadcast to get a stuck payout confirmed: a
+// BIP125 replacement of the payout, or a child spending its change output
+type FeeBump struct {
+	Method    string    `json:"method"` // rbf or cpfp
+	TxID      string    `json:"txid"`
+	Replaces  string    `json:"replaces"`         // txid replaced, or the parent of a child
+	Spends    string    `json:"spends,omitempty"` // txid:vout of the change a child spends
+	FeeRate   int64     `json:"fee_rate"`         // sat/vB, of parent and child together for cpfp; 0 when the node wallet picked it
+	Fee       int64     `json:"fee"`              // satoshis paid by this transaction
+	RawTx     string    `json:"raw_tx,omitempty"`
+	Height    int64     `json:"height"` // Bitcoin tip when broadcast
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Fee bump methods
+const (
+	FeeBumpRBF  = "rbf"
+	FeeBumpCPFP = "cpfp"
+)
+
+// ContractEvent is a decoded bridge contract log. Data holds the event
+// arguments by name, with addresses, hashes and integers as strings.
+type ContractEvent struct {
+	Name        string                 `json:"name"`
+	Address     string                 `json:"address"`
+	BlockNumber uint64                 `json:"block_number"`
+	BlockHash   string                 `json:"block_hash"`
+	TxHash      string                 `json:"tx_hash"`
+	LogIndex    uint                   `json:"log_index"`
+	Data        map[string]interface{} `json:"data"`
+	Removed     bool                   `json:"removed,omitempty"` // orphaned by a reorg
 }
 
 // SwapRequest represents a request to swap tokens via 1inch